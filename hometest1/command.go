@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"hometest1/core/module"
+	"hometest1/migration"
+	"io"
+	"log"
+	"os"
+	"strconv"
+
+	"gorm.io/gorm"
+)
+
+const usage = `Usage: hometest1 [-loadDotEnv] [command]
+
+Commands:
+  serve             serve http, default command
+  migrate up        apply pending migrations
+  migrate down [n]  roll back the latest n migrations, default 1
+  migrate status    list migrations and when they are applied
+  seed              insert sample data that does not exist yet
+  catalog import [-dry-run] <file>
+                    create or update products, stock and promotions from csv
+  catalog export [file]
+                    write products, stock and promotions as csv, default to stdout
+`
+
+// run command of the binary, return error if command is unknown or failed
+func runCommand(db *gorm.DB, catalogUC module.CatalogUsecase, args []string) error {
+	// data of memory driver is lost when the command exits
+	if db == nil {
+		return fmt.Errorf("command %s needs a database, memory driver only serves http", args[0])
+	}
+	ctx := context.Background()
+	switch args[0] {
+	case "migrate":
+		if len(args) < 2 {
+			return fmt.Errorf("migrate needs up, down or status\n\n%s", usage)
+		}
+		migrator, err := migration.New(db)
+		if err != nil {
+			return err
+		}
+		return runMigrate(ctx, migrator, args[1:])
+	case "seed":
+		result, err := migration.Seed(ctx, db)
+		if err != nil {
+			return err
+		}
+		log.Printf("Seeded %d products, %d promotions and %d tax regions", result.Products, result.Promotions, result.TaxRegions)
+		return nil
+	case "catalog":
+		if len(args) < 2 {
+			return fmt.Errorf("catalog needs import or export\n\n%s", usage)
+		}
+		return runCatalog(ctx, catalogUC, args[1:])
+	}
+	return fmt.Errorf("unknown command %s\n\n%s", args[0], usage)
+}
+
+func runMigrate(ctx context.Context, migrator *migration.Migrator, args []string) error {
+	switch args[0] {
+	case "up":
+		return migrateUp(ctx, migrator)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			var err error
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				return fmt.Errorf("number of migrations to roll back must be positive number")
+			}
+		}
+		migrations, err := migrator.Down(ctx, steps)
+		for _, m := range migrations {
+			log.Printf("Rolled back %04d_%s", m.Version, m.Name)
+		}
+		if err == nil && len(migrations) == 0 {
+			log.Println("No migration to roll back")
+		}
+		return err
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			applied := "pending"
+			if status.AppliedAt != nil {
+				applied = "applied at " + status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d_%s\t%s\n", status.Version, status.Name, applied)
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown migrate command %s\n\n%s", args[0], usage)
+}
+
+func runCatalog(ctx context.Context, catalogUC module.CatalogUsecase, args []string) error {
+	switch args[0] {
+	case "import":
+		flags := flag.NewFlagSet("catalog import", flag.ContinueOnError)
+		dryRun := flags.Bool("dry-run", false, "validate the file without writing")
+		err := flags.Parse(args[1:])
+		if err != nil {
+			return err
+		}
+		if flags.NArg() != 1 {
+			return fmt.Errorf("catalog import needs a csv file\n\n%s", usage)
+		}
+		file, err := os.Open(flags.Arg(0))
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		result, err := catalogUC.Import(ctx, file, *dryRun)
+		if err != nil {
+			return err
+		}
+		for _, rowError := range result.Errors {
+			fmt.Printf("line %d\t%s\t%s\n", rowError.Line, rowError.Serial, rowError.Message)
+		}
+		log.Printf("Read %d rows, products: %d created, %d updated, %d unchanged, promotions: %d created, %d updated, %d unchanged",
+			result.Rows,
+			result.Products.Created, result.Products.Updated, result.Products.Unchanged,
+			result.Promotions.Created, result.Promotions.Updated, result.Promotions.Unchanged)
+		switch {
+		case !result.Applied && len(result.Errors) > 0:
+			return fmt.Errorf("nothing is imported, %d rows are invalid", len(result.Errors))
+		case len(result.Errors) > 0:
+			return fmt.Errorf("%d rows are failed, other rows are imported", len(result.Errors))
+		case result.DryRun:
+			log.Println("Dry run, nothing is imported")
+		}
+		return nil
+	case "export":
+		var w io.Writer = os.Stdout
+		if len(args) > 1 {
+			file, err := os.Create(args[1])
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+			w = file
+		}
+		return catalogUC.Export(ctx, w)
+	}
+	return fmt.Errorf("unknown catalog command %s\n\n%s", args[0], usage)
+}
+
+// apply pending migrations and log them
+func migrateUp(ctx context.Context, migrator *migration.Migrator) error {
+	migrations, err := migrator.Up(ctx)
+	for _, m := range migrations {
+		log.Printf("Applied %04d_%s", m.Version, m.Name)
+	}
+	if err == nil && len(migrations) == 0 {
+		log.Println("Database is up to date")
+	}
+	return err
+}
@@ -1,11 +1,48 @@
 package config
 
 import (
+	"time"
+
 	"github.com/kelseyhightower/envconfig"
 )
 
 type Config struct {
 	HttpPort string `envconfig:"HTTP_PORT" default:"8080"`
+	// AdminApiKey is bearer token for admin endpoints, admin endpoints are rejected if empty
+	AdminApiKey string `envconfig:"ADMIN_API_KEY" default:""`
+	// ReservationSweepInterval is how often expired reservations are marked, eg: 1m
+	ReservationSweepInterval time.Duration `envconfig:"RESERVATION_SWEEP_INTERVAL" default:"1m"`
+	// RequestTimeout is deadline of each request, database query is cancelled after it, eg: 10s
+	RequestTimeout time.Duration `envconfig:"REQUEST_TIMEOUT" default:"10s"`
+	// AutoMigrate applies pending migrations before serving http, eg: sqlite :memory: database
+	AutoMigrate bool `envconfig:"DB_AUTO_MIGRATE" default:"false"`
+	// StockAlertWebhookURL receives low stock alert as json post, alert is logged if it is empty
+	StockAlertWebhookURL string `envconfig:"STOCK_ALERT_WEBHOOK_URL" default:""`
+	// StockAlertTimeout is deadline of each webhook request, eg: 5s
+	StockAlertTimeout time.Duration `envconfig:"STOCK_ALERT_TIMEOUT" default:"5s"`
+	// StockAlertRetryAttempts is number of attempts to send an alert, including the first one
+	StockAlertRetryAttempts int `envconfig:"STOCK_ALERT_RETRY_ATTEMPTS" default:"3"`
+	// StockAlertRetryBackoff is delay before the second attempt, doubled after every attempt, eg: 1s
+	StockAlertRetryBackoff time.Duration `envconfig:"STOCK_ALERT_RETRY_BACKOFF" default:"1s"`
+	// OutboxSinks receive checkout events, comma separated of stdout, file and webhook.
+	// Events are kept in outbox table until a sink is set
+	OutboxSinks []string `envconfig:"OUTBOX_SINKS" default:""`
+	// OutboxWebhookURL receives events as json post, required by webhook sink
+	OutboxWebhookURL string `envconfig:"OUTBOX_WEBHOOK_URL" default:""`
+	// OutboxFilePath is appended with one json line for every event by file sink
+	OutboxFilePath string `envconfig:"OUTBOX_FILE_PATH" default:"outbox.jsonl"`
+	// OutboxTimeout is deadline of each webhook request, eg: 5s
+	OutboxTimeout time.Duration `envconfig:"OUTBOX_TIMEOUT" default:"5s"`
+	// OutboxPollInterval is how often due events are delivered, eg: 1s
+	OutboxPollInterval time.Duration `envconfig:"OUTBOX_POLL_INTERVAL" default:"1s"`
+	// OutboxBatchSize is max events delivered in one poll
+	OutboxBatchSize int `envconfig:"OUTBOX_BATCH_SIZE" default:"100"`
+	// OutboxLease is how long an event is taken by a dispatcher, it is delivered again if not finished, eg: 1m
+	OutboxLease time.Duration `envconfig:"OUTBOX_LEASE" default:"1m"`
+	// OutboxRetryBackoff is delay after the first failed delivery, doubled after every failure, eg: 1s
+	OutboxRetryBackoff time.Duration `envconfig:"OUTBOX_RETRY_BACKOFF" default:"1s"`
+	// OutboxMaxBackoff is max delay between deliveries of failed event, eg: 1h
+	OutboxMaxBackoff time.Duration `envconfig:"OUTBOX_MAX_BACKOFF" default:"1h"`
 }
 
 func Get() Config {
@@ -1,26 +1,34 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"hometest1/core/entity"
 	"log"
 	"time"
 
+	mysqldriver "github.com/go-sql-driver/mysql"
 	"github.com/kelseyhightower/envconfig"
-	"gorm.io/driver/mysql"
+	gormmysql "gorm.io/driver/mysql"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 	"gorm.io/gorm/schema"
 )
 
-// mysql config
+// database config, only config of the driver is used
 type database struct {
+	// Driver is database driver, one of mysql, sqlite, postgres or memory
+	Driver string `envconfig:"DB_DRIVER" default:"mysql"`
+	// LogMode is toggle to enable/disable log query in your service by default false, used by every driver
+	MysqlLogMode int `envconfig:"MYSQL_LOG_MODE" default:"0"`
+
 	// SSLMode to enable/disable SSL connection
 	MysqlSSLMode bool `envconfig:"MYSQL_SSL_MODE" default:"true"`
-	// MaxIdleConnection to set max idle connection pooling
+	// MaxIdleConnection to set max idle connection pooling, also used by postgres
 	MysqlMaxIdleConnection int `envconfig:"MYSQL_MAX_IDLE_CONNECTION" default:"10"`
-	// MaxOpenConnection to set max open connection pooling
+	// MaxOpenConnection to set max open connection pooling, also used by postgres
 	MysqlMaxOpenConnection int `envconfig:"MYSQL_MAX_OPEN_CONNECTION" default:"50"`
-	// MaxLifetimeConnectionn to set max lifetime of pooling | minutes unit
+	// MaxLifetimeConnectionn to set max lifetime of pooling | minutes unit, also used by postgres
 	MysqlMaxLifetimeConnection int `envconfig:"MYSQL_MAX_LIFETIME_CONNECTION" default:"10"`
 	// Host is host of mysql service
 	MysqlHost string `envconfig:"MYSQL_HOST" default:"localhost"`
@@ -32,23 +40,96 @@ type database struct {
 	MysqlDBName string `envconfig:"MYSQL_DB_NAME" default:""`
 	// Password is password of used Username in mysql service
 	MysqlPassword string `envconfig:"MYSQL_PASSWORD" default:""`
-	// LogMode is toggle to enable/disable log query in your service by default false
-	MysqlLogMode int `envconfig:"MYSQL_LOG_MODE" default:"0"`
 	// ParseTime to parse to local time
 	MysqlParseTime bool `envconfig:"MYSQL_PARSE_TIME" default:"true"`
 	// Charset to define charset of database
 	MysqlCharset string `envconfig:"MYSQL_CHARSET" default:"utf8mb4"`
 	// Charset to define charset of database
 	MysqlLoc string `envconfig:"MYSQL_LOC" default:"Local"`
+	// LockWaitTimeout is how long a query waits for locked row, eg: stock locked by another checkout | seconds unit
+	MysqlLockWaitTimeout int `envconfig:"MYSQL_LOCK_WAIT_TIMEOUT" default:"5"`
+
+	// SqlitePath is file of sqlite database, use :memory: for temporary database
+	SqlitePath string `envconfig:"SQLITE_PATH" default:"hometest1.db"`
+	// SqliteBusyTimeout is how long a query waits for locked database | milliseconds unit
+	SqliteBusyTimeout int `envconfig:"SQLITE_BUSY_TIMEOUT" default:"5000"`
+
+	// Host is host of postgres service
+	PostgresHost string `envconfig:"POSTGRES_HOST" default:"localhost"`
+	// Port is port of postgres service
+	PostgresPort string `envconfig:"POSTGRES_PORT" default:"5432"`
+	// Username is name of registered user in postgres service
+	PostgresUsername string `envconfig:"POSTGRES_USERNAME" default:""`
+	// DBName is name of registered database in postgres service
+	PostgresDBName string `envconfig:"POSTGRES_DB_NAME" default:""`
+	// Password is password of used Username in postgres service
+	PostgresPassword string `envconfig:"POSTGRES_PASSWORD" default:""`
+	// SSLMode is sslmode of postgres connection, eg: disable, require
+	PostgresSSLMode string `envconfig:"POSTGRES_SSL_MODE" default:"disable"`
+	// LockTimeout is how long a query waits for locked row | seconds unit
+	PostgresLockTimeout int `envconfig:"POSTGRES_LOCK_TIMEOUT" default:"5"`
+}
+
+// supported database drivers
+const (
+	DriverMysql    = "mysql"
+	DriverSqlite   = "sqlite"
+	DriverPostgres = "postgres"
+	// data is kept by memory repositories and lost when the service stops
+	DriverMemory = "memory"
+)
+
+// lock wait timeout and deadlock error numbers of mysql
+var lockErrNumbers = map[uint16]bool{
+	1205: true,
+	1213: true,
+}
+
+// mysqlDialector translates lock wait timeout and deadlock into entity.ErrLockTimeout,
+// other errors are translated by mysql dialector
+type mysqlDialector struct {
+	*gormmysql.Dialector
+}
+
+func (d mysqlDialector) Translate(err error) error {
+	var mysqlErr *mysqldriver.MySQLError
+	if errors.As(err, &mysqlErr) && lockErrNumbers[mysqlErr.Number] {
+		return fmt.Errorf("%w: %s", entity.ErrLockTimeout, mysqlErr.Message)
+	}
+	return d.Dialector.Translate(err)
 }
 
+// Connect open database of DB_DRIVER, return nil for memory driver that has no database
 func Connect() *gorm.DB {
 	var dbConfig database
 	envconfig.MustProcess("", &dbConfig)
 
+	gormConfig := &gorm.Config{
+		Logger: logger.Default.LogMode(logger.LogLevel(dbConfig.MysqlLogMode)),
+		NamingStrategy: schema.NamingStrategy{
+			SingularTable: true,
+		},
+		// translate duplicate key error into gorm.ErrDuplicatedKey, and lock error into entity.ErrLockTimeout
+		TranslateError: true,
+	}
+
+	switch dbConfig.Driver {
+	case DriverMysql:
+		return connectMysql(dbConfig, gormConfig)
+	case DriverSqlite:
+		return connectSqlite(dbConfig, gormConfig)
+	case DriverPostgres:
+		return connectPostgres(dbConfig, gormConfig)
+	case DriverMemory:
+		return nil
+	}
+	panic(fmt.Sprintf("unknown DB_DRIVER %q, use %s, %s, %s or %s", dbConfig.Driver, DriverMysql, DriverSqlite, DriverPostgres, DriverMemory))
+}
+
+func connectMysql(dbConfig database, gormConfig *gorm.Config) *gorm.DB {
 	// construct connection string
 	dsn := fmt.Sprintf(
-		"%s:%s@tcp(%s:%s)/%s?charset=%s&parseTime=%+v&loc=%s",
+		"%s:%s@tcp(%s:%s)/%s?charset=%s&parseTime=%+v&loc=%s&innodb_lock_wait_timeout=%d",
 		dbConfig.MysqlUsername,
 		dbConfig.MysqlPassword,
 		dbConfig.MysqlHost,
@@ -56,16 +137,12 @@ func Connect() *gorm.DB {
 		dbConfig.MysqlDBName,
 		dbConfig.MysqlCharset,
 		dbConfig.MysqlParseTime,
-		dbConfig.MysqlLoc)
+		dbConfig.MysqlLoc,
+		dbConfig.MysqlLockWaitTimeout)
 	log.Println(dsn)
 
 	// open mysql connection
-	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.LogLevel(dbConfig.MysqlLogMode)),
-		NamingStrategy: schema.NamingStrategy{
-			SingularTable: true,
-		},
-	})
+	db, err := gorm.Open(mysqlDialector{gormmysql.Open(dsn).(*gormmysql.Dialector)}, gormConfig)
 	if err != nil {
 		panic(err)
 	}
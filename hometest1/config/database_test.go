@@ -0,0 +1,92 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"hometest1/core/entity"
+	"hometest1/migration"
+
+	mysqldriver "github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	sqlite3 "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func Test_MysqlDialectorTranslate(t *testing.T) {
+	d := mysqlDialector{mysql.Open("").(*mysql.Dialector)}
+
+	t.Run("positive, lock wait timeout and deadlock", func(t *testing.T) {
+		for _, number := range []uint16{1205, 1213} {
+			err := d.Translate(&mysqldriver.MySQLError{Number: number, Message: "Lock wait timeout exceeded"})
+			assert.True(t, errors.Is(err, entity.ErrLockTimeout))
+		}
+	})
+
+	t.Run("positive, duplicate key is translated by mysql dialector", func(t *testing.T) {
+		err := d.Translate(&mysqldriver.MySQLError{Number: 1062})
+		assert.Equal(t, gorm.ErrDuplicatedKey, err)
+	})
+}
+
+func Test_SqliteDialectorTranslate(t *testing.T) {
+	d := sqliteDialector{sqlite.Open("").(*sqlite.Dialector)}
+
+	t.Run("positive, busy and locked database", func(t *testing.T) {
+		for _, code := range []sqlite3.ErrNo{sqlite3.ErrBusy, sqlite3.ErrLocked} {
+			err := d.Translate(sqlite3.Error{Code: code})
+			assert.True(t, errors.Is(err, entity.ErrLockTimeout))
+		}
+	})
+
+	t.Run("positive, duplicate key is translated by sqlite dialector", func(t *testing.T) {
+		err := d.Translate(sqlite3.Error{Code: sqlite3.ErrConstraint, ExtendedCode: sqlite3.ErrConstraintUnique})
+		assert.Equal(t, gorm.ErrDuplicatedKey, err)
+	})
+}
+
+func Test_PostgresDialectorTranslate(t *testing.T) {
+	d := postgresDialector{postgres.Open("").(*postgres.Dialector)}
+
+	t.Run("positive, lock not available and deadlock", func(t *testing.T) {
+		for _, code := range []string{"55P03", "40P01"} {
+			err := d.Translate(&pgconn.PgError{Code: code, Message: "canceling statement due to lock timeout"})
+			assert.True(t, errors.Is(err, entity.ErrLockTimeout))
+		}
+	})
+
+	t.Run("positive, duplicate key is translated by postgres dialector", func(t *testing.T) {
+		err := d.Translate(&pgconn.PgError{Code: "23505"})
+		assert.Equal(t, gorm.ErrDuplicatedKey, err)
+	})
+}
+
+func Test_ConnectSqlite(t *testing.T) {
+	t.Setenv("DB_DRIVER", DriverSqlite)
+	t.Setenv("SQLITE_PATH", filepath.Join(t.TempDir(), "test.db"))
+
+	t.Run("positive, data is kept on reconnect", func(t *testing.T) {
+		db := Connect()
+		migrator, err := migration.New(db)
+		assert.Nil(t, err)
+		_, err = migrator.Up(context.Background())
+		assert.Nil(t, err)
+		err = db.Create(&entity.Product{Serial: "A-1", Name: "Product", Price: entity.NewMoney(1050)}).Error
+		assert.Nil(t, err)
+
+		db = Connect()
+		var product entity.Product
+		err = db.Where("serial = ?", "A-1").Take(&product).Error
+		assert.Nil(t, err)
+		assert.Equal(t, entity.NewMoney(1050), product.Price)
+
+		err = db.Create(&entity.Product{Serial: "A-1"}).Error
+		assert.Equal(t, gorm.ErrDuplicatedKey, err)
+	})
+}
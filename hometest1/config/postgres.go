@@ -0,0 +1,62 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"hometest1/core/entity"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// lock not available and deadlock error codes of postgres
+var lockErrCodes = map[string]bool{
+	"55P03": true,
+	"40P01": true,
+}
+
+// postgresDialector translates lock timeout and deadlock into entity.ErrLockTimeout,
+// other errors are translated by postgres dialector
+type postgresDialector struct {
+	*postgres.Dialector
+}
+
+func (d postgresDialector) Translate(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && lockErrCodes[pgErr.Code] {
+		return fmt.Errorf("%w: %s", entity.ErrLockTimeout, pgErr.Message)
+	}
+	return d.Dialector.Translate(err)
+}
+
+func connectPostgres(dbConfig database, gormConfig *gorm.Config) *gorm.DB {
+	// lock_timeout is set on every connection, like innodb_lock_wait_timeout of mysql
+	dsn := fmt.Sprintf(
+		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s lock_timeout=%d",
+		dbConfig.PostgresHost,
+		dbConfig.PostgresPort,
+		dbConfig.PostgresUsername,
+		dbConfig.PostgresPassword,
+		dbConfig.PostgresDBName,
+		dbConfig.PostgresSSLMode,
+		dbConfig.PostgresLockTimeout*1000)
+	// password is not logged
+	log.Printf("postgres %s@%s:%s/%s", dbConfig.PostgresUsername, dbConfig.PostgresHost, dbConfig.PostgresPort, dbConfig.PostgresDBName)
+
+	db, err := gorm.Open(postgresDialector{postgres.Open(dsn).(*postgres.Dialector)}, gormConfig)
+	if err != nil {
+		panic(err)
+	}
+
+	// set configuration pooling connection
+	postgresDb, _ := db.DB()
+	postgresDb.SetMaxOpenConns(dbConfig.MysqlMaxOpenConnection)
+	postgresDb.SetConnMaxLifetime(time.Duration(dbConfig.MysqlMaxLifetimeConnection) * time.Minute)
+	postgresDb.SetMaxIdleConns(dbConfig.MysqlMaxIdleConnection)
+
+	return db
+}
@@ -0,0 +1,47 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"hometest1/core/entity"
+
+	sqlite3 "github.com/mattn/go-sqlite3"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// sqliteDialector translates busy and locked database into entity.ErrLockTimeout,
+// other errors are translated by sqlite dialector
+type sqliteDialector struct {
+	*sqlite.Dialector
+}
+
+func (d sqliteDialector) Translate(err error) error {
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) && (sqliteErr.Code == sqlite3.ErrBusy || sqliteErr.Code == sqlite3.ErrLocked) {
+		return fmt.Errorf("%w: %s", entity.ErrLockTimeout, sqliteErr.Error())
+	}
+	return d.Dialector.Translate(err)
+}
+
+// sqlite has no row lock, write transaction is begun immediately so checkouts are validated one by one.
+// Sqlite driver needs cgo
+func connectSqlite(dbConfig database, gormConfig *gorm.Config) *gorm.DB {
+	dsn := fmt.Sprintf("%s?_busy_timeout=%d&_txlock=immediate&_foreign_keys=on",
+		dbConfig.SqlitePath,
+		dbConfig.SqliteBusyTimeout)
+	log.Println(dsn)
+
+	db, err := gorm.Open(sqliteDialector{sqlite.Open(dsn).(*sqlite.Dialector)}, gormConfig)
+	if err != nil {
+		panic(err)
+	}
+
+	// single connection, so :memory: database is shared and writers wait in the pool instead of failing busy
+	sqliteDb, _ := db.DB()
+	sqliteDb.SetMaxOpenConns(1)
+
+	return db
+}
@@ -0,0 +1,61 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type BundleType int
+
+const (
+	UndefinedBundleType BundleType = iota
+	// set of products for a fixed price, eg: MacBook Pro + Alexa Speaker for 5450.00
+	BundleFixedPrice
+	// any match quantity items of the product group for the price of promo value items
+	BundleGroupPrice
+)
+
+// Bundle is promotion that matches across several checkout items
+type Bundle struct {
+	ID   int64
+	Type BundleType
+	Name string
+	// price of a set, only for fixed price bundle
+	Price Money
+	// number of items in a group, only for group price bundle
+	MatchQuantity int
+	// paid quantity of a group, only for group price bundle
+	PromoValue int
+	// higher priority takes the items first
+	Priority int
+	// promotion period, nil means unbounded
+	StartsAt  *time.Time
+	EndsAt    *time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt
+	Items     []*BundleItem `gorm:"-"`
+}
+
+// Status return bundle status at the time, same as Promotion.Status
+func (b *Bundle) Status(now time.Time) PromotionStatus {
+	return (&Promotion{StartsAt: b.StartsAt, EndsAt: b.EndsAt}).Status(now)
+}
+
+// BundleItem is product of a bundle
+type BundleItem struct {
+	ID        int64
+	BundleID  int64
+	ProductID int64
+	// quantity in a set of fixed price bundle, group price bundle ignores it
+	Quantity int
+	// filled by usecase, not stored in table bundle_item
+	Product *Product `gorm:"-"`
+}
+
+type BundleList struct {
+	Bundles []*Bundle
+	Total   int64
+	Page    int
+	Limit   int
+}
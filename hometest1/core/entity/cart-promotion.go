@@ -0,0 +1,59 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type CartPromotionType int
+
+const (
+	UndefinedCartType CartPromotionType = iota
+	// percent discount of checkout sub total
+	CartDiscountInPercent
+	// fixed amount discount
+	CartDiscountAmount
+	// free items of a product
+	CartFreeItem
+)
+
+// CartPromotion is promotion of the whole checkout, evaluated after promotions of each product
+type CartPromotion struct {
+	ID   int64
+	Type CartPromotionType
+	// minimum checkout sub total price to get the promotion
+	MinSpend Money
+	// discount percent, or number of free items
+	PromoValue int
+	// discount amount of fixed amount discount
+	PromoAmount    Money
+	PromoProductID int64
+	// higher priority is applied first
+	Priority int
+	Stacking PromotionStacking
+	// promotion period, nil means unbounded
+	StartsAt  *time.Time
+	EndsAt    *time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt
+}
+
+// Status return promotion status at the time, same as Promotion.Status
+func (p *CartPromotion) Status(now time.Time) PromotionStatus {
+	return (&Promotion{StartsAt: p.StartsAt, EndsAt: p.EndsAt}).Status(now)
+}
+
+// Cart promotion with its free item product
+type CartPromotionDetail struct {
+	*CartPromotion
+	// nil if promotion has no free item product
+	PromoProduct *Product
+}
+
+type CartPromotionList struct {
+	Promotions []*CartPromotionDetail
+	Total      int64
+	Page       int
+	Limit      int
+}
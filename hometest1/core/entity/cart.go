@@ -0,0 +1,32 @@
+package entity
+
+import "time"
+
+type CartStatus int
+
+const (
+	CartOpen CartStatus = iota + 1
+	CartCheckedOut
+)
+
+type Cart struct {
+	ID        int64
+	Status    CartStatus
+	UpdatedAt time.Time
+	Items     []*CartItem `gorm:"-"`
+}
+
+type CartItem struct {
+	ID        int64
+	CartID    int64
+	ProductID int64
+	Quantity  int
+	UpdatedAt time.Time
+}
+
+// Cart with its current price calculation
+type CartDetail struct {
+	*Cart
+	// nil if cart is empty
+	Quote *CheckoutQuote
+}
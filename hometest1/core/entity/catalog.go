@@ -0,0 +1,43 @@
+package entity
+
+// CatalogRow is a row of catalog csv, product columns are repeated
+// when the product has more than one promotion
+type CatalogRow struct {
+	// line number in the csv file, header is line 1
+	Line     int
+	Serial   string
+	Name     string
+	Price    Money
+	TaxClass string
+	// stock level after import, not a delta
+	Quantity int
+	// UndefinedType if the row has no promotion
+	PromoType          PromotionType
+	PromoMatchQuantity int
+	PromoValue         int
+	PromoProductSerial string
+}
+
+// CatalogRowError is invalid or failed row of catalog import
+type CatalogRowError struct {
+	Line    int
+	Serial  string
+	Message string
+}
+
+// CatalogImportCount is number of rows by the change they make
+type CatalogImportCount struct {
+	Created   int
+	Updated   int
+	Unchanged int
+}
+
+type CatalogImport struct {
+	DryRun bool
+	// false if nothing is written, because of dry run or invalid rows
+	Applied    bool
+	Rows       int
+	Products   CatalogImportCount
+	Promotions CatalogImportCount
+	Errors     []*CatalogRowError
+}
@@ -1,5 +1,7 @@
 package entity
 
+import "sort"
+
 type MapProductSerialQuantity map[string]int
 
 func (e MapProductSerialQuantity) PluckSerial() []string {
@@ -10,14 +12,144 @@ func (e MapProductSerialQuantity) PluckSerial() []string {
 	return result
 }
 
+// return sorted serials that are not found in the products
+func (e MapProductSerialQuantity) UnknownSerials(products []*Product) []string {
+	found := make(map[string]bool)
+	for _, product := range products {
+		found[product.Serial] = true
+	}
+
+	var result []string
+	for serial := range e {
+		if !found[serial] {
+			result = append(result, serial)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
 type CheckoutItem struct {
 	Product       *Product
 	Quantity      int
-	SubTotalPrice float64
+	SubTotalPrice Money
+	// number of items in quantity that is given for free by promotion
+	FreeQuantity int
+	// last promotion applied to this item, 0 if no promotion
+	PromotionID int64
+	// bundle discount allocated to this item, already reduced from sub total price
+	BundleID       int64
+	BundleDiscount Money
+	// promotions and bundles that reduce price of this item, or give this item for free
+	AppliedPromotions []*AppliedPromotion
+}
+
+// PromotionSource is kind of promotion evaluated on checkout
+type PromotionSource string
+
+const (
+	SourcePromotion     PromotionSource = "promotion"
+	SourceBundle        PromotionSource = "bundle"
+	SourceCartPromotion PromotionSource = "cartPromotion"
+)
+
+// AppliedPromotion explains a promotion evaluated on checkout
+type AppliedPromotion struct {
+	Source PromotionSource
+	ID     int64
+	// type of the promotion source, eg: PromotionType of product promotion
+	Type        int
+	Description string
+	// saved amount of applied promotion, free items are valued in product price
+	Saved Money
+	// only for promotion that is not met, eg: buy 1 more
+	Reason string
+}
+
+// CheckoutDiscount is discount line of the whole checkout, given by cart promotion
+type CheckoutDiscount struct {
+	CartPromotionID int64
+	Type            CartPromotionType
+	Description     string
+	Amount          Money
+	// coupon that unlocks the cart promotion, empty if promotion has no coupon
+	CouponCode string
+}
+
+// CheckoutOptions is optional input of checkout besides the product serials
+type CheckoutOptions struct {
+	// proceed with known products and report unknown serials
+	Lenient     bool
+	CouponCodes []string
+	// customer reference for per customer coupon limit
+	CustomerID string
+	// submit checkout using stock held by the reservation
+	ReservationID int64
+	// cart that is checked out, it is closed in the same transaction as the order
+	CartID int64
+	// code of tax region, default region is used if it is empty
+	TaxRegion string
 }
 
 type Checkout struct {
-	Items      []*CheckoutItem
-	TotalItem  int
-	TotalPrice float64
+	// filled after checkout submitted
+	OrderID int64
+	// reservation that is confirmed by the checkout
+	ReservationID int64
+	// open cart that is closed by the checkout, 0 if checkout is not from a cart
+	CartID    int64
+	Items     []*CheckoutItem
+	TotalItem int
+	// total price of items minus discounts
+	TotalPrice Money
+	// discount lines, only set if any cart promotion is applied
+	Discounts     []*CheckoutDiscount
+	DiscountPrice Money
+	// serials skipped on lenient checkout because the product is not found
+	UnknownSerials []string
+	CustomerID     string
+	// coupons redeemed by the discount lines, redemption is written on submit
+	Coupons []*Coupon
+	// promotions, bundles and cart promotions that are evaluated but not applied
+	NotMetPromotions []*AppliedPromotion
+	// tax lines per tax class, computed after promotions. Empty if checkout has no tax region
+	TaxRegion    string
+	TaxPriceMode TaxPriceMode
+	Taxes        []*CheckoutTax
+	// total tax, already added to total price on exclusive mode, already included on inclusive mode
+	TaxPrice Money
+	// stock of checkout products after submitted, map[int64] = product id
+	RemainingStock map[int64]int
+}
+
+// OrderDiscounts return promotions applied to the items and discount lines, to be stored with the order
+func (c *Checkout) OrderDiscounts(orderID int64) []*OrderDiscount {
+	var result []*OrderDiscount
+	for _, item := range c.Items {
+		for _, applied := range item.AppliedPromotions {
+			result = append(result, &OrderDiscount{
+				OrderID:   orderID,
+				ProductID: item.Product.ID,
+				Source:    applied.Source,
+				SourceID:  applied.ID,
+				Amount:    applied.Saved,
+			})
+		}
+	}
+	for _, discount := range c.Discounts {
+		result = append(result, &OrderDiscount{
+			OrderID:  orderID,
+			Source:   SourceCartPromotion,
+			SourceID: discount.CartPromotionID,
+			Amount:   discount.Amount,
+		})
+	}
+	return result
+}
+
+type CheckoutQuote struct {
+	*Checkout
+	// current stock of checkout items
+	// map[int64] = product id, int = available quantity
+	AvailableQuantity map[int64]int
 }
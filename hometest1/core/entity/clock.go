@@ -0,0 +1,6 @@
+package entity
+
+import "time"
+
+// Clock return current time, it can be replaced with fixed time on test
+type Clock func() time.Time
@@ -0,0 +1,57 @@
+package entity
+
+import (
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Coupon is code that unlocks a cart promotion on checkout.
+// Cart promotion linked to a coupon is only applied when the coupon code is submitted
+type Coupon struct {
+	ID              int64
+	Code            string
+	CartPromotionID int64
+	// max redemptions of all customers, 0 means unlimited
+	MaxRedemptions int
+	// max redemptions of each customer, 0 means unlimited
+	PerCustomerLimit int
+	// number of redemptions, updated on checkout
+	RedeemedCount int
+	// nil means never expired
+	ExpiresAt *time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt
+}
+
+// IsExpired return true if coupon cannot be redeemed anymore at the time
+func (c *Coupon) IsExpired(now time.Time) bool {
+	return c.ExpiresAt != nil && !now.Before(*c.ExpiresAt)
+}
+
+// IsExhausted return true if coupon has reached its max redemptions
+func (c *Coupon) IsExhausted() bool {
+	return c.MaxRedemptions > 0 && c.RedeemedCount >= c.MaxRedemptions
+}
+
+// CouponRedemption is record of a coupon redeemed on an order
+type CouponRedemption struct {
+	ID         int64
+	CouponID   int64
+	OrderID    int64
+	CustomerID string
+	CreatedAt  time.Time
+}
+
+type CouponList struct {
+	Coupons []*Coupon
+	Total   int64
+	Page    int
+	Limit   int
+}
+
+// NormalizeCouponCode make coupon code case insensitive, eg: " save10 " => "SAVE10"
+func NormalizeCouponCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
@@ -1,10 +1,74 @@
 package entity
 
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+)
+
 const (
 	ProductNotFound string = "product not found"
 	EmptyQuantity   string = "empty quantity"
+	CartNotFound    string = "cart not found"
+	CartClosed      string = "cart already checked out"
+	EmptyCart       string = "cart is empty"
+	OrderNotFound   string = "order not found"
+	SerialExists    string = "product serial already exists"
+	UnknownSerials  string = "unknown product serials"
+	// product of cart item is deleted
+	ProductNotAvailable string = "product no longer available"
+	// promotion validation
+	PromotionNotFound    string = "promotion not found"
+	InvalidPromotionType string = "invalid promotion type"
+	FreeItemPromoted     string = "product is set as free item, it cannot be promoted"
+	PromotedFreeItem     string = "product has promotion, it cannot be set as free item"
+	FreeItemCycle        string = "free item promotion creates a cycle"
+	InvalidPromoPeriod   string = "promotion end time must be after start time"
+	InvalidStacking      string = "invalid promotion stacking mode"
+	// cart promotion validation
+	CartPromotionNotFound string = "cart promotion not found"
+	// coupon validation
+	CouponNotFound     string = "coupon not found"
+	CouponCodeExists   string = "coupon code already exists"
+	CouponExpired      string = "coupon is expired"
+	CouponExhausted    string = "coupon has reached its redemption limit"
+	CouponCustomerUsed string = "coupon has reached its redemption limit for the customer"
+	CustomerRequired   string = "customer id is required to redeem the coupon"
+	// bundle validation
+	BundleNotFound      string = "bundle not found"
+	InvalidBundleType   string = "invalid bundle type"
+	DuplicateBundleItem string = "bundle has duplicate product"
+	// idempotency key validation
+	InvalidIdempotencyKey    string = "idempotency key must be between 1 and 255 characters"
+	IdempotencyKeyMismatch   string = "idempotency key is already used with a different request"
+	IdempotencyKeyInProgress string = "request with the idempotency key is still in progress"
+	// reservation validation
+	ReservationNotFound   string = "reservation not found"
+	ReservationNotActive  string = "reservation is already confirmed or released"
+	ReservationHasExpired string = "reservation is expired"
+	InvalidReservationTTL string = "reservation time must be between 1 and 60 minutes"
+	// order return validation
+	OrderClosed       string = "order is already returned or cancelled"
+	EmptyReturn       string = "return has no item"
+	ProductNotOrdered string = "product is not in the order"
+	OrderChanged      string = "order is changed by another return, please retry"
+	// tax validation
+	TaxRegionNotFound   string = "tax region not found"
+	InvalidTaxPriceMode string = "invalid tax price mode"
+	DuplicateTaxClass   string = "tax region has duplicate tax class"
+	// stock alert
+	InvalidStockThreshold string = "stock threshold must be zero or more"
+	// catalog import
+	InvalidCatalog string = "invalid catalog csv"
+	// request deadline and row lock
+	RequestTimeout string = "request is timed out, please retry"
+	LockTimeout    string = "item is locked by another request, please retry"
 )
 
+// ErrLockTimeout is database error of lock wait timeout or deadlock, translated by the database driver
+var ErrLockTimeout = errors.New("lock wait timeout exceeded")
+
 type Err struct {
 	message string
 	code    int
@@ -25,3 +89,28 @@ func (e Err) GetMessage() string {
 func NewError(msg string, code int) Err {
 	return Err{message: msg, code: code}
 }
+
+// NewInternalError wrap unexpected error as internal server error.
+// Lock timeout is conflict and timed out or cancelled request is service unavailable, so client can retry
+func NewInternalError(err error) Err {
+	switch {
+	case errors.Is(err, ErrLockTimeout):
+		return NewError(LockTimeout, http.StatusConflict)
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled):
+		return NewError(RequestTimeout, http.StatusServiceUnavailable)
+	}
+	return NewError(err.Error(), http.StatusInternalServerError)
+}
+
+// UnknownSerialsErr is checkout error listing every serial that is not found in product
+type UnknownSerialsErr struct {
+	Err
+	Serials []string
+}
+
+func NewUnknownSerialsError(serials []string, code int) UnknownSerialsErr {
+	return UnknownSerialsErr{
+		Err:     NewError(UnknownSerials+": "+strings.Join(serials, ", "), code),
+		Serials: serials,
+	}
+}
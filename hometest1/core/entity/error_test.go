@@ -0,0 +1,30 @@
+package entity_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"hometest1/core/entity"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_NewInternalError(t *testing.T) {
+	t.Run("positive, lock timeout is conflict", func(t *testing.T) {
+		err := fmt.Errorf("%w: %s", entity.ErrLockTimeout, "Lock wait timeout exceeded; try restarting transaction")
+		assert.Equal(t, entity.NewError(entity.LockTimeout, http.StatusConflict), entity.NewInternalError(err))
+	})
+
+	t.Run("positive, timed out request is service unavailable", func(t *testing.T) {
+		assert.Equal(t, entity.NewError(entity.RequestTimeout, http.StatusServiceUnavailable), entity.NewInternalError(context.DeadlineExceeded))
+		assert.Equal(t, entity.NewError(entity.RequestTimeout, http.StatusServiceUnavailable), entity.NewInternalError(context.Canceled))
+	})
+
+	t.Run("positive, other error is internal server error", func(t *testing.T) {
+		err := entity.NewInternalError(errors.New("connection refused"))
+		assert.Equal(t, entity.NewError("connection refused", http.StatusInternalServerError), err)
+	})
+}
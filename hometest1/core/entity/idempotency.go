@@ -0,0 +1,21 @@
+package entity
+
+import "time"
+
+// IdempotencyKey is request of client idempotency key, with its response once the request is completed
+type IdempotencyKey struct {
+	ID  int64
+	Key string
+	// hash of request method, path and body
+	RequestHash string
+	// 0 if request is still in progress
+	ResponseCode int
+	ResponseBody string
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// IsCompleted return true if response of the request is stored
+func (k *IdempotencyKey) IsCompleted() bool {
+	return k.ResponseCode != 0
+}
@@ -0,0 +1,210 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type Currency string
+
+const (
+	USD Currency = "USD"
+	// currency of all prices stored in database
+	DefaultCurrency Currency = USD
+	// number of minor unit digits, eg: 2 digits for cent
+	minorUnitDigits int   = 2
+	minorUnitScale  int64 = 100
+)
+
+// decimal number with optional sign and fraction, eg: "-5399.99"
+var moneyPattern = regexp.MustCompile(`^-?\d+(\.\d+)?$`)
+
+// Money is exact amount of money in minor unit (eg: cent) with its currency.
+// In database it is stored as decimal(10,2), in json it is written as decimal number
+type Money struct {
+	Amount   int64
+	Currency Currency
+}
+
+// create money from minor unit amount
+func NewMoney(amount int64) Money {
+	return Money{Amount: amount, Currency: DefaultCurrency}
+}
+
+// parse decimal string, eg: "5399.99", into money
+func ParseMoney(s string) (Money, error) {
+	s = strings.TrimSpace(s)
+	if !moneyPattern.MatchString(s) {
+		return Money{}, fmt.Errorf("invalid money value %q", s)
+	}
+	negative := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+
+	whole, fraction, _ := strings.Cut(s, ".")
+	if len(fraction) > minorUnitDigits {
+		// only allow trailing zero beyond minor unit, eg: "49.990"
+		if strings.TrimRight(fraction[minorUnitDigits:], "0") != "" {
+			return Money{}, fmt.Errorf("money value %q has more than %d decimal digits", s, minorUnitDigits)
+		}
+		fraction = fraction[:minorUnitDigits]
+	}
+	fraction += strings.Repeat("0", minorUnitDigits-len(fraction))
+
+	major, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil {
+		return Money{}, fmt.Errorf("invalid money value %q", s)
+	}
+	minor, err := strconv.ParseInt(fraction, 10, 64)
+	if err != nil {
+		return Money{}, fmt.Errorf("invalid money value %q", s)
+	}
+
+	amount := major*minorUnitScale + minor
+	if negative {
+		amount = -amount
+	}
+	return NewMoney(amount), nil
+}
+
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+// sum both money, currency must be same
+func (m Money) Add(o Money) Money {
+	return Money{Amount: m.Amount + o.Amount, Currency: m.mustSameCurrency(o)}
+}
+
+// subtract money, currency must be same
+func (m Money) Sub(o Money) Money {
+	return Money{Amount: m.Amount - o.Amount, Currency: m.mustSameCurrency(o)}
+}
+
+// multiply money by quantity
+func (m Money) Mul(quantity int) Money {
+	return Money{Amount: m.Amount * int64(quantity), Currency: m.currency()}
+}
+
+// Percent return percent portion of the money.
+// The result is rounded half up (away from zero) to the minor unit,
+// eg: 10% of 0.15 is 0.02
+func (m Money) Percent(percent int) Money {
+	value := m.Amount * int64(percent)
+	result := value / 100
+	if remainder := value % 100; remainder >= 50 {
+		result++
+	} else if remainder <= -50 {
+		result--
+	}
+	return Money{Amount: result, Currency: m.currency()}
+}
+
+// Prorate return portion part/whole of the money, eg: order level discount of returned items.
+// The result is rounded half up (away from zero) to the minor unit, whole must not be zero
+func (m Money) Prorate(part, whole Money) Money {
+	return m.Fraction(part.Amount, whole.Amount)
+}
+
+// Fraction return numerator/denominator of the money, eg: tax rate in basis point is fraction of 10000.
+// The result is rounded half up (away from zero) to the minor unit, denominator must not be zero
+func (m Money) Fraction(numerator, denominator int64) Money {
+	value := new(big.Int).Mul(big.NewInt(m.Amount), big.NewInt(numerator))
+	divisor := big.NewInt(denominator)
+	negative := value.Sign()*divisor.Sign() < 0
+	result, remainder := new(big.Int).QuoRem(value, divisor, new(big.Int))
+	// round half up, compare twice the remainder with divisor as absolute value
+	remainder.Abs(remainder).Lsh(remainder, 1)
+	if remainder.Cmp(divisor.Abs(divisor)) >= 0 {
+		if negative {
+			result.Sub(result, big.NewInt(1))
+		} else {
+			result.Add(result, big.NewInt(1))
+		}
+	}
+	return Money{Amount: result.Int64(), Currency: m.currency()}
+}
+
+// String return decimal string, eg: "5399.99"
+func (m Money) String() string {
+	sign := ""
+	amount := m.Amount
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	return fmt.Sprintf("%s%d.%0*d", sign, amount/minorUnitScale, minorUnitDigits, amount%minorUnitScale)
+}
+
+// MarshalJSON write money as decimal number, eg: 5399.99
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON read money from decimal number or decimal string
+func (m *Money) UnmarshalJSON(data []byte) error {
+	value := strings.Trim(string(data), `"`)
+	if value == "null" {
+		return nil
+	}
+	result, err := ParseMoney(value)
+	if err != nil {
+		return err
+	}
+	*m = result
+	return nil
+}
+
+// Scan implements sql.Scanner for decimal column
+func (m *Money) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*m = NewMoney(0)
+	case []byte:
+		return m.scanString(string(v))
+	case string:
+		return m.scanString(v)
+	case int64:
+		*m = NewMoney(v * minorUnitScale)
+	case float64:
+		// double column, round to nearest minor unit
+		*m = NewMoney(int64(math.Round(v * float64(minorUnitScale))))
+	default:
+		return fmt.Errorf("cannot scan %T into Money", value)
+	}
+	return nil
+}
+
+func (m *Money) scanString(value string) error {
+	result, err := ParseMoney(value)
+	if err != nil {
+		return err
+	}
+	*m = result
+	return nil
+}
+
+// Value implements driver.Valuer, write money as decimal string
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
+
+// zero value money has no currency, it is treated as default currency
+func (m Money) currency() Currency {
+	if m.Currency == "" {
+		return DefaultCurrency
+	}
+	return m.Currency
+}
+
+// calculating different currencies is programming error
+func (m Money) mustSameCurrency(o Money) Currency {
+	if m.currency() != o.currency() {
+		panic(fmt.Sprintf("money currency mismatch: %s and %s", m.currency(), o.currency()))
+	}
+	return m.currency()
+}
@@ -0,0 +1,96 @@
+package entity_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"hometest1/core/entity"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ParseMoney(t *testing.T) {
+	t.Run("positive", func(t *testing.T) {
+		for input, expected := range map[string]int64{
+			"5399.99": 539999,
+			"109.5":   10950,
+			"30":      3000,
+			"0.07":    7,
+			"49.990":  4999,
+			"-1.25":   -125,
+		} {
+			resp, err := entity.ParseMoney(input)
+			assert.Nil(t, err, input)
+			assert.Equal(t, entity.NewMoney(expected), resp, input)
+		}
+	})
+
+	t.Run("negative", func(t *testing.T) {
+		for _, input := range []string{"", "abc", "1.999", "1.-5", "-", "--5", "1.+5", "+5", "-+5", ".5", "5.", "1.2.3", "1,5", "1e2", " 5 5"} {
+			_, err := entity.ParseMoney(input)
+			assert.NotNil(t, err, input)
+		}
+	})
+}
+
+func Test_MoneyPercent(t *testing.T) {
+	// 10% of 328.50 is exactly 32.85
+	assert.Equal(t, entity.NewMoney(3285), entity.NewMoney(32850).Percent(10))
+	// 10% of 0.15 is 0.015, rounded half up
+	assert.Equal(t, entity.NewMoney(2), entity.NewMoney(15).Percent(10))
+	// 10% of 0.14 is 0.014, rounded down
+	assert.Equal(t, entity.NewMoney(1), entity.NewMoney(14).Percent(10))
+	// rounded away from zero
+	assert.Equal(t, entity.NewMoney(-2), entity.NewMoney(-15).Percent(10))
+}
+
+func Test_MoneyProrate(t *testing.T) {
+	// 90.00 paid for 100.00 worth of items, returned 50.00 is refunded 45.00
+	assert.Equal(t, entity.NewMoney(4500), entity.NewMoney(9000).Prorate(entity.NewMoney(5000), entity.NewMoney(10000)))
+	// 1/3 of 1.00 is 0.333, rounded down
+	assert.Equal(t, entity.NewMoney(33), entity.NewMoney(100).Prorate(entity.NewMoney(1), entity.NewMoney(3)))
+	// 1/2 of 0.01 is 0.005, rounded half up
+	assert.Equal(t, entity.NewMoney(1), entity.NewMoney(1).Prorate(entity.NewMoney(1), entity.NewMoney(2)))
+	// rounded away from zero
+	assert.Equal(t, entity.NewMoney(-1), entity.NewMoney(-1).Prorate(entity.NewMoney(1), entity.NewMoney(2)))
+	// large amount does not overflow
+	assert.Equal(t, entity.NewMoney(9999999999), entity.NewMoney(9999999999).Prorate(entity.NewMoney(9999999999), entity.NewMoney(9999999999)))
+}
+
+func Test_MoneyFraction(t *testing.T) {
+	// 20% tax of 49.99 is 9.998, rounded half up
+	assert.Equal(t, entity.NewMoney(1000), entity.NewMoney(4999).Fraction(2000, 10000))
+	// 20% tax included in 59.99 is 9.998
+	assert.Equal(t, entity.NewMoney(1000), entity.NewMoney(5999).Fraction(2000, 12000))
+	// 7.25% tax of 10.00 is 0.725, rounded half up
+	assert.Equal(t, entity.NewMoney(73), entity.NewMoney(1000).Fraction(725, 10000))
+}
+
+func Test_MoneyJSON(t *testing.T) {
+	data, err := json.Marshal(map[string]entity.Money{"price": entity.NewMoney(29565)})
+	assert.Nil(t, err)
+	assert.Equal(t, `{"price":295.65}`, string(data))
+
+	var result struct {
+		Price entity.Money `json:"price"`
+	}
+	err = json.Unmarshal([]byte(`{"price":5399.99}`), &result)
+	assert.Nil(t, err)
+	assert.Equal(t, entity.NewMoney(539999), result.Price)
+}
+
+func Test_MoneyScan(t *testing.T) {
+	var m entity.Money
+
+	// mysql decimal column
+	assert.Nil(t, m.Scan([]byte("109.50")))
+	assert.Equal(t, entity.NewMoney(10950), m)
+
+	// double column
+	assert.Nil(t, m.Scan(float64(49.99)))
+	assert.Equal(t, entity.NewMoney(4999), m)
+
+	value, err := entity.NewMoney(5).Value()
+	assert.Nil(t, err)
+	assert.Equal(t, "0.05", value)
+}
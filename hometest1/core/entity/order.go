@@ -0,0 +1,139 @@
+package entity
+
+import (
+	"strconv"
+	"time"
+)
+
+type OrderStatus int
+
+const (
+	UndefinedOrderStatus OrderStatus = iota
+	OrderPlaced
+	// some items are returned, the others are kept
+	OrderPartiallyReturned
+	// all items are returned
+	OrderReturned
+	OrderCancelled
+)
+
+func (s OrderStatus) String() string {
+	switch s {
+	case OrderPlaced:
+		return "placed"
+	case OrderPartiallyReturned:
+		return "partially_returned"
+	case OrderReturned:
+		return "returned"
+	case OrderCancelled:
+		return "cancelled"
+	default:
+		return "undefined"
+	}
+}
+
+type Order struct {
+	ID         int64
+	Status     OrderStatus
+	TotalItem  int
+	TotalPrice Money
+	// total discount of cart promotions
+	DiscountPrice Money
+	// total refund of returns and cancellation
+	RefundedPrice Money
+	// tax of the order, empty region if checkout has no tax
+	TaxRegion    string
+	TaxPriceMode TaxPriceMode
+	TaxPrice     Money
+	CreatedAt    time.Time
+	Items        []*OrderItem `gorm:"-"`
+	Taxes        []*OrderTax  `gorm:"-"`
+	// promotions applied to the order, kept items are priced again with them on return
+	Discounts []*OrderDiscount `gorm:"-"`
+}
+
+// IsClosed return true if all items of the order are returned or cancelled
+func (o *Order) IsClosed() bool {
+	return o.Status == OrderReturned || o.Status == OrderCancelled
+}
+
+// price paid for the order minus refunds
+func (o *Order) NetPrice() Money {
+	return o.TotalPrice.Sub(o.RefundedPrice)
+}
+
+type OrderItem struct {
+	ID            int64
+	OrderID       int64
+	ProductID     int64
+	UnitPrice     Money
+	Quantity      int
+	SubTotalPrice Money
+	// applied promotion, 0 if no promotion
+	PromotionID int64
+	// applied bundle, 0 if no bundle
+	BundleID int64
+	// quantity given back by returns and cancellation
+	ReturnedQuantity int
+	// filled by usecase, not stored in table order_item
+	Product *Product `gorm:"-"`
+}
+
+// quantity that customer still keeps
+func (i *OrderItem) KeptQuantity() int {
+	return i.Quantity - i.ReturnedQuantity
+}
+
+// OrderTax is tax line of a tax class in the order
+type OrderTax struct {
+	ID           int64
+	OrderID      int64
+	TaxClass     string
+	Rate         int
+	TaxablePrice Money
+	TaxPrice     Money
+}
+
+// OrderDiscount is snapshot of a promotion, bundle or cart promotion applied to the order.
+// Product id is 0 for discount of the whole order
+type OrderDiscount struct {
+	ID        int64
+	OrderID   int64
+	ProductID int64
+	Source    PromotionSource
+	SourceID  int64
+	// saved amount when the order is placed
+	Amount Money
+}
+
+type OrderList struct {
+	Orders []*Order
+	Total  int64
+	Page   int
+	Limit  int
+}
+
+// OrderReturn is a return or cancellation of order items, with its refund
+type OrderReturn struct {
+	ID      int64
+	OrderID int64
+	// returned value of the items, after promotions of kept items are recomputed
+	RefundPrice Money
+	CreatedAt   time.Time
+	Items       []*OrderReturnItem `gorm:"-"`
+	// order that the refund is computed from, repository rejects the return if the order is changed
+	Order *Order `gorm:"-"`
+}
+
+type OrderReturnItem struct {
+	ID            int64
+	OrderReturnID int64
+	OrderItemID   int64
+	ProductID     int64
+	Quantity      int
+}
+
+// reference of stock movement from an order return
+func OrderReturnReference(orderReturnID int64) string {
+	return "return:" + strconv.FormatInt(orderReturnID, 10)
+}
@@ -0,0 +1,90 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// OrderPlacedEvent is event type of submitted checkout
+const OrderPlacedEvent = "OrderPlaced"
+
+// Outbox is event written in the same transaction with its change, dispatcher delivers it at least once
+type Outbox struct {
+	ID        int64
+	EventType string
+	// id of the changed row, eg: order id
+	AggregateID int64
+	// json data of the event
+	Payload string
+	// number of failed deliveries
+	Attempts int
+	// event is not delivered before this time, it is retry backoff or lease of the dispatcher
+	NextAttemptAt time.Time
+	LastError     string
+	// nil until the event is delivered
+	DeliveredAt *time.Time
+	CreatedAt   time.Time
+}
+
+type orderPlacedPayload struct {
+	OrderID       int64                     `json:"orderId"`
+	CustomerID    string                    `json:"customerId"`
+	ReservationID int64                     `json:"reservationId"`
+	TotalItem     int                       `json:"totalItem"`
+	TotalPrice    Money                     `json:"totalPrice"`
+	DiscountPrice Money                     `json:"discountPrice"`
+	TaxRegion     string                    `json:"taxRegion"`
+	TaxPrice      Money                     `json:"taxPrice"`
+	Coupons       []string                  `json:"coupons"`
+	Items         []*orderPlacedItemPayload `json:"items"`
+}
+
+type orderPlacedItemPayload struct {
+	Serial        string `json:"serial"`
+	Name          string `json:"name"`
+	UnitPrice     Money  `json:"unitPrice"`
+	Quantity      int    `json:"quantity"`
+	FreeQuantity  int    `json:"freeQuantity"`
+	SubTotalPrice Money  `json:"subTotalPrice"`
+}
+
+// NewOrderPlacedOutbox create OrderPlaced event of submitted checkout, order id must be already set
+func NewOrderPlacedOutbox(checkout *Checkout, createdAt time.Time) (*Outbox, error) {
+	payload := orderPlacedPayload{
+		OrderID:       checkout.OrderID,
+		CustomerID:    checkout.CustomerID,
+		ReservationID: checkout.ReservationID,
+		TotalItem:     checkout.TotalItem,
+		TotalPrice:    checkout.TotalPrice,
+		DiscountPrice: checkout.DiscountPrice,
+		TaxRegion:     checkout.TaxRegion,
+		TaxPrice:      checkout.TaxPrice,
+		Coupons:       []string{},
+		Items:         []*orderPlacedItemPayload{},
+	}
+	for _, coupon := range checkout.Coupons {
+		payload.Coupons = append(payload.Coupons, coupon.Code)
+	}
+	for _, item := range checkout.Items {
+		payload.Items = append(payload.Items, &orderPlacedItemPayload{
+			Serial:        item.Product.Serial,
+			Name:          item.Product.Name,
+			UnitPrice:     item.Product.Price,
+			Quantity:      item.Quantity,
+			FreeQuantity:  item.FreeQuantity,
+			SubTotalPrice: item.SubTotalPrice,
+		})
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Outbox{
+		EventType:     OrderPlacedEvent,
+		AggregateID:   checkout.OrderID,
+		Payload:       string(data),
+		NextAttemptAt: createdAt,
+		CreatedAt:     createdAt,
+	}, nil
+}
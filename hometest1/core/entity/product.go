@@ -1,13 +1,20 @@
 package entity
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type Product struct {
-	ID        int64
-	Serial    string
-	Name      string
-	Price     float64
+	ID     int64
+	Serial string
+	Name   string
+	Price  Money
+	// tax class to look up tax rate of checkout region, eg: standard
+	TaxClass  string
 	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt
 }
 
 type ProductQuantity struct {
@@ -16,3 +23,16 @@ type ProductQuantity struct {
 	Quantity  int
 	UpdatedAt time.Time
 }
+
+// Product with its current stock
+type ProductStock struct {
+	*Product
+	Quantity int
+}
+
+type ProductList struct {
+	Products []*ProductStock
+	Total    int64
+	Page     int
+	Limit    int
+}
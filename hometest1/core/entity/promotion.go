@@ -16,6 +16,22 @@ const (
 	FreeItem
 )
 
+// PromotionStacking is how promotion is combined with other promotions of the same product
+type PromotionStacking int
+
+const (
+	// applied together with other stackable promotions and the best of best-of promotions
+	Stackable PromotionStacking = iota
+	// applied alone, only when it gives the lowest price
+	Exclusive
+	// only the best of best-of promotions is applied, on top of stackable promotions
+	BestOf
+)
+
+func (s PromotionStacking) IsValid() bool {
+	return s >= Stackable && s <= BestOf
+}
+
 type Promotion struct {
 	ID             int64
 	Type           PromotionType
@@ -23,6 +39,47 @@ type Promotion struct {
 	MatchQuantity  int
 	PromoValue     int
 	PromoProductID int64
-	UpdatedAt      time.Time
-	DeletedAt      gorm.DeletedAt
+	// higher priority is applied first
+	Priority int
+	Stacking PromotionStacking
+	// promotion period, nil means unbounded
+	StartsAt  *time.Time
+	EndsAt    *time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt
+}
+
+type PromotionStatus string
+
+const (
+	PromotionScheduled PromotionStatus = "scheduled"
+	PromotionActive    PromotionStatus = "active"
+	PromotionExpired   PromotionStatus = "expired"
+)
+
+// Status return promotion status at the time,
+// promotion is active from starts at (inclusive) until ends at (exclusive)
+func (p *Promotion) Status(now time.Time) PromotionStatus {
+	if p.StartsAt != nil && now.Before(*p.StartsAt) {
+		return PromotionScheduled
+	}
+	if p.EndsAt != nil && !now.Before(*p.EndsAt) {
+		return PromotionExpired
+	}
+	return PromotionActive
+}
+
+// Promotion with its products
+type PromotionDetail struct {
+	*Promotion
+	Product *Product
+	// nil if promotion has no free item product
+	PromoProduct *Product
+}
+
+type PromotionList struct {
+	Promotions []*PromotionDetail
+	Total      int64
+	Page       int
+	Limit      int
 }
@@ -0,0 +1,21 @@
+package entity_test
+
+import (
+	"testing"
+	"time"
+
+	"hometest1/core/entity"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_PromotionStatus(t *testing.T) {
+	now := time.Date(2023, 5, 16, 12, 0, 0, 0, time.UTC)
+	before := now.Add(-time.Hour)
+	after := now.Add(time.Hour)
+
+	assert.Equal(t, entity.PromotionActive, (&entity.Promotion{}).Status(now))
+	assert.Equal(t, entity.PromotionActive, (&entity.Promotion{StartsAt: &now, EndsAt: &after}).Status(now))
+	assert.Equal(t, entity.PromotionScheduled, (&entity.Promotion{StartsAt: &after}).Status(now))
+	assert.Equal(t, entity.PromotionExpired, (&entity.Promotion{StartsAt: &before, EndsAt: &now}).Status(now))
+}
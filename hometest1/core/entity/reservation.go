@@ -0,0 +1,65 @@
+package entity
+
+import "time"
+
+type ReservationStatus int
+
+const (
+	UndefinedReservationStatus ReservationStatus = iota
+	// stock is held until expires at
+	ReservationActive
+	// checkout is submitted, held stock is sold
+	ReservationConfirmed
+	ReservationReleased
+	ReservationExpired
+)
+
+func (s ReservationStatus) String() string {
+	switch s {
+	case ReservationActive:
+		return "active"
+	case ReservationConfirmed:
+		return "confirmed"
+	case ReservationReleased:
+		return "released"
+	case ReservationExpired:
+		return "expired"
+	default:
+		return "undefined"
+	}
+}
+
+// Reservation holds stock of checkout items while the customer is paying
+type Reservation struct {
+	ID         int64
+	Status     ReservationStatus
+	CustomerID string
+	// only for confirmed reservation
+	OrderID   int64
+	ExpiresAt time.Time
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Items     []*ReservationItem `gorm:"-"`
+}
+
+// IsActive return true if the reservation still holds the stock
+func (r *Reservation) IsActive(now time.Time) bool {
+	return r.Status == ReservationActive && r.ExpiresAt.After(now)
+}
+
+type ReservationItem struct {
+	ID            int64
+	ReservationID int64
+	ProductID     int64
+	// held quantity, including free items of promotions
+	Quantity int
+	// scanned quantity, checkout is rendered again from it on confirm
+	OrderedQuantity int
+	Product         *Product `gorm:"-"`
+}
+
+// ReservationDetail is reservation with its rendered checkout
+type ReservationDetail struct {
+	*Reservation
+	Checkout *Checkout
+}
@@ -0,0 +1,78 @@
+package entity
+
+import (
+	"strconv"
+	"time"
+)
+
+type StockMovementReason int
+
+const (
+	UndefinedReason StockMovementReason = iota
+	StockSale
+	StockRestock
+	StockReturn
+	StockAdjustment
+	StockFreeItem
+)
+
+func (r StockMovementReason) String() string {
+	switch r {
+	case StockSale:
+		return "sale"
+	case StockRestock:
+		return "restock"
+	case StockReturn:
+		return "return"
+	case StockAdjustment:
+		return "adjustment"
+	case StockFreeItem:
+		return "free_item"
+	default:
+		return "undefined"
+	}
+}
+
+// StockMovement is every change of product_quantity
+type StockMovement struct {
+	ID        int64
+	ProductID int64
+	// stock delta, negative for item going out
+	Quantity int
+	Reason   StockMovementReason
+	// eg: order:1 for sale
+	Reference string
+	CreatedAt time.Time
+}
+
+type StockMovementList struct {
+	Product   *Product
+	Movements []*StockMovement
+	Total     int64
+	Page      int
+	Limit     int
+}
+
+// reference of stock movement from an order
+func OrderReference(orderID int64) string {
+	return "order:" + strconv.FormatInt(orderID, 10)
+}
+
+// StockThreshold is reorder threshold of product, 0 means no alert
+type StockThreshold struct {
+	ID        int64
+	ProductID int64
+	Threshold int
+	UpdatedAt time.Time
+}
+
+// StockAlert is sent when checkout takes product stock from above its threshold to the threshold or below
+type StockAlert struct {
+	Product *Product
+	// stock after the checkout
+	Quantity  int
+	Threshold int
+	// order of the checkout that crosses the threshold
+	OrderID   int64
+	CreatedAt time.Time
+}
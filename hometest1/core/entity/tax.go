@@ -0,0 +1,83 @@
+package entity
+
+import "time"
+
+// tax class of product that is not set
+const DefaultTaxClass = "standard"
+
+// basis point of whole price, eg: 2000 is 20%
+const TaxRateScale = 10000
+
+type TaxPriceMode int
+
+const (
+	UndefinedTaxPriceMode TaxPriceMode = iota
+	// tax is added on top of the price, eg: US sales tax
+	TaxExclusive
+	// price already includes the tax, eg: EU VAT
+	TaxInclusive
+)
+
+func (m TaxPriceMode) String() string {
+	switch m {
+	case TaxExclusive:
+		return "exclusive"
+	case TaxInclusive:
+		return "inclusive"
+	default:
+		return "undefined"
+	}
+}
+
+func (m TaxPriceMode) IsValid() bool {
+	return m == TaxExclusive || m == TaxInclusive
+}
+
+// parse tax price mode from its name, return UndefinedTaxPriceMode if it is unknown
+func ParseTaxPriceMode(s string) TaxPriceMode {
+	for _, mode := range []TaxPriceMode{TaxExclusive, TaxInclusive} {
+		if mode.String() == s {
+			return mode
+		}
+	}
+	return UndefinedTaxPriceMode
+}
+
+// TaxRegion is a jurisdiction with its tax rates per tax class
+type TaxRegion struct {
+	ID        int64
+	Code      string
+	Name      string
+	PriceMode TaxPriceMode
+	// region of checkout that does not set the region
+	IsDefault bool
+	UpdatedAt time.Time
+	Rates     []*TaxRate `gorm:"-"`
+}
+
+// return rate of the tax class in basis point, 0 if tax class has no rate
+func (r *TaxRegion) RateOf(taxClass string) int {
+	for _, rate := range r.Rates {
+		if rate.TaxClass == taxClass {
+			return rate.Rate
+		}
+	}
+	return 0
+}
+
+type TaxRate struct {
+	ID          int64
+	TaxRegionID int64
+	TaxClass    string
+	// basis point, eg: 2000 is 20%
+	Rate int
+}
+
+// CheckoutTax is tax line of a tax class
+type CheckoutTax struct {
+	TaxClass string
+	Rate     int
+	// price of the tax class after promotions, without the tax
+	TaxableAmount Money
+	Amount        Money
+}
@@ -0,0 +1,221 @@
+package module
+
+import (
+	"fmt"
+	"sort"
+
+	"hometest1/core/entity"
+)
+
+// bundleUnit is one unit of checkout item that can be used by group price bundle
+type bundleUnit struct {
+	productID int64
+	price     entity.Money
+}
+
+// applyBundles applies bundles by priority, every item unit is used by one bundle at most.
+// Item whose price or quantity is changed by product promotion is not counted in bundle,
+// item of promotion that only gives free item of other product is still counted.
+// Bundle discount is allocated back to checkout items and reduced from their sub total price
+func (uc *checkoutUsecase) applyBundles(checkout *entity.Checkout, bundles []*entity.Bundle) {
+	if len(bundles) == 0 {
+		return
+	}
+
+	// map[int64] = product id
+	mapItem := make(map[int64]*entity.CheckoutItem)
+	available := make(map[int64]int)
+	for _, item := range checkout.Items {
+		if isPromotedItem(item) {
+			continue
+		}
+		mapItem[item.Product.ID] = item
+		available[item.Product.ID] = item.Quantity
+	}
+
+	for _, bundle := range bundles {
+		var allocation map[int64]entity.Money
+		switch bundle.Type {
+		case entity.BundleFixedPrice:
+			allocation = uc.fixedPriceBundle(bundle, mapItem, available)
+		case entity.BundleGroupPrice:
+			allocation = uc.groupPriceBundle(bundle, mapItem, available)
+		}
+		if len(allocation) == 0 {
+			checkout.NotMetPromotions = append(checkout.NotMetPromotions, &entity.AppliedPromotion{
+				Source:      entity.SourceBundle,
+				ID:          bundle.ID,
+				Type:        int(bundle.Type),
+				Description: bundle.Name,
+				Reason:      uc.bundleNotMetReason(checkout, bundle, mapItem, available),
+			})
+			continue
+		}
+
+		for _, item := range checkout.Items {
+			amount, ok := allocation[item.Product.ID]
+			if !ok || amount.IsZero() {
+				continue
+			}
+			if item.BundleID == 0 {
+				item.BundleDiscount = entity.NewMoney(0)
+			}
+			item.BundleID = bundle.ID
+			item.BundleDiscount = item.BundleDiscount.Add(amount)
+			item.SubTotalPrice = item.SubTotalPrice.Sub(amount)
+			checkout.TotalPrice = checkout.TotalPrice.Sub(amount)
+			item.AppliedPromotions = append(item.AppliedPromotions, &entity.AppliedPromotion{
+				Source:      entity.SourceBundle,
+				ID:          bundle.ID,
+				Type:        int(bundle.Type),
+				Description: bundle.Name,
+				Saved:       amount,
+			})
+		}
+	}
+}
+
+// item is discounted or given for free by product promotion.
+// PromotionID is not checked, it is also set on item that only gives free item of other product
+func isPromotedItem(item *entity.CheckoutItem) bool {
+	if item.FreeQuantity > 0 {
+		return true
+	}
+	for _, applied := range item.AppliedPromotions {
+		if applied.Source == entity.SourcePromotion {
+			return true
+		}
+	}
+	return false
+}
+
+// explain why the bundle is not applied, eg: buy 1 more Raspberry Pi B
+func (uc *checkoutUsecase) bundleNotMetReason(checkout *entity.Checkout, bundle *entity.Bundle, mapItem map[int64]*entity.CheckoutItem, available map[int64]int) string {
+	mapCheckoutItem := make(map[int64]*entity.CheckoutItem)
+	for _, item := range checkout.Items {
+		mapCheckoutItem[item.Product.ID] = item
+	}
+
+	switch bundle.Type {
+	case entity.BundleFixedPrice:
+		for _, bundleItem := range bundle.Items {
+			item, ok := mapCheckoutItem[bundleItem.ProductID]
+			if !ok {
+				return "bundle items are not complete"
+			}
+			if _, ok := mapItem[bundleItem.ProductID]; !ok {
+				return fmt.Sprintf("%s already has product promotion", item.Product.Name)
+			}
+			if item.Quantity < bundleItem.Quantity {
+				return fmt.Sprintf("buy %d more %s", bundleItem.Quantity-item.Quantity, item.Product.Name)
+			}
+			if available[bundleItem.ProductID] < bundleItem.Quantity {
+				return fmt.Sprintf("%s is already used by other bundle", item.Product.Name)
+			}
+		}
+	case entity.BundleGroupPrice:
+		units := 0
+		for _, bundleItem := range bundle.Items {
+			if _, ok := mapItem[bundleItem.ProductID]; ok {
+				units += available[bundleItem.ProductID]
+			}
+		}
+		if units < bundle.MatchQuantity {
+			return fmt.Sprintf("buy %d more items of the bundle", bundle.MatchQuantity-units)
+		}
+	}
+	return "bundle is not applicable"
+}
+
+// fixedPriceBundle return discount of each product for every complete set in checkout.
+// Discount is allocated by list price of the products in a set, rounded down,
+// the remainder goes to the product with the biggest id
+func (uc *checkoutUsecase) fixedPriceBundle(bundle *entity.Bundle, mapItem map[int64]*entity.CheckoutItem, available map[int64]int) map[int64]entity.Money {
+	if len(bundle.Items) == 0 || bundle.Price.Amount <= 0 {
+		return nil
+	}
+	items := append([]*entity.BundleItem{}, bundle.Items...)
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].ProductID < items[j].ProductID
+	})
+
+	// number of complete sets and list price of a set
+	sets := -1
+	setPrice := entity.NewMoney(0)
+	for _, bundleItem := range items {
+		item, ok := mapItem[bundleItem.ProductID]
+		if !ok || bundleItem.Quantity <= 0 {
+			return nil
+		}
+		if n := available[bundleItem.ProductID] / bundleItem.Quantity; sets < 0 || n < sets {
+			sets = n
+		}
+		setPrice = setPrice.Add(item.Product.Price.Mul(bundleItem.Quantity))
+	}
+	// bundle price must be cheaper than buying separately
+	if sets <= 0 || setPrice.Amount <= bundle.Price.Amount {
+		return nil
+	}
+
+	discount := setPrice.Sub(bundle.Price).Mul(sets)
+	remaining := discount
+	result := make(map[int64]entity.Money)
+	for i, bundleItem := range items {
+		available[bundleItem.ProductID] -= bundleItem.Quantity * sets
+		if i == len(items)-1 {
+			result[bundleItem.ProductID] = remaining
+			break
+		}
+		share := mapItem[bundleItem.ProductID].Product.Price.Mul(bundleItem.Quantity)
+		amount := entity.NewMoney(discount.Amount * share.Amount / setPrice.Amount)
+		result[bundleItem.ProductID] = amount
+		remaining = remaining.Sub(amount)
+	}
+	return result
+}
+
+// groupPriceBundle return discount of each product for every group of match quantity units.
+// Units are sorted by price descending then product id, and grouped in that order,
+// the cheapest units of each group are free
+func (uc *checkoutUsecase) groupPriceBundle(bundle *entity.Bundle, mapItem map[int64]*entity.CheckoutItem, available map[int64]int) map[int64]entity.Money {
+	if bundle.MatchQuantity < 2 || bundle.PromoValue < 1 || bundle.PromoValue >= bundle.MatchQuantity {
+		return nil
+	}
+
+	var units []bundleUnit
+	for _, bundleItem := range bundle.Items {
+		item, ok := mapItem[bundleItem.ProductID]
+		if !ok {
+			continue
+		}
+		for i := 0; i < available[bundleItem.ProductID]; i++ {
+			units = append(units, bundleUnit{productID: item.Product.ID, price: item.Product.Price})
+		}
+	}
+	sort.SliceStable(units, func(i, j int) bool {
+		if units[i].price.Amount != units[j].price.Amount {
+			return units[i].price.Amount > units[j].price.Amount
+		}
+		return units[i].productID < units[j].productID
+	})
+
+	groups := len(units) / bundle.MatchQuantity
+	if groups == 0 {
+		return nil
+	}
+	result := make(map[int64]entity.Money)
+	for g := 0; g < groups; g++ {
+		group := units[g*bundle.MatchQuantity : (g+1)*bundle.MatchQuantity]
+		for i, unit := range group {
+			available[unit.productID]--
+			if i < bundle.PromoValue {
+				continue
+			}
+			if _, ok := result[unit.productID]; !ok {
+				result[unit.productID] = entity.NewMoney(0)
+			}
+			result[unit.productID] = result[unit.productID].Add(unit.price)
+		}
+	}
+	return result
+}
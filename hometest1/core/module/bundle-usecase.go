@@ -0,0 +1,213 @@
+package module
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"hometest1/core/entity"
+	"hometest1/core/repository"
+)
+
+type BundleUsecase interface {
+	// create bundle, item products are looked up by serial
+	Create(ctx context.Context, payload *entity.Bundle) (*entity.Bundle, error)
+	// update bundle by id and replace its items, item products are looked up by serial
+	Update(ctx context.Context, payload *entity.Bundle) (*entity.Bundle, error)
+	// soft delete bundle
+	Delete(ctx context.Context, bundleID int64) error
+	// get bundles, page start from 1
+	List(ctx context.Context, page, limit int) (*entity.BundleList, error)
+}
+
+type bundleUsecase struct {
+	promoRepo   repository.PromotionRepo
+	productRepo repository.ProductRepo
+}
+
+func NewBundleUsecase(promoRepo repository.PromotionRepo, productRepo repository.ProductRepo) BundleUsecase {
+	return &bundleUsecase{promoRepo, productRepo}
+}
+
+func (uc *bundleUsecase) Create(ctx context.Context, payload *entity.Bundle) (*entity.Bundle, error) {
+	err := uc.resolveProducts(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+	err = uc.validate(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	err = uc.promoRepo.CreateBundle(ctx, payload)
+	if err != nil {
+		return nil, entity.NewInternalError(err)
+	}
+	return payload, nil
+}
+
+func (uc *bundleUsecase) Update(ctx context.Context, payload *entity.Bundle) (*entity.Bundle, error) {
+	existing, err := uc.promoRepo.GetBundle(ctx, payload.ID)
+	if err != nil {
+		return nil, entity.NewInternalError(err)
+	}
+	if existing == nil {
+		return nil, entity.NewError(entity.BundleNotFound, http.StatusNotFound)
+	}
+
+	err = uc.resolveProducts(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+	err = uc.validate(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	err = uc.promoRepo.UpdateBundle(ctx, payload)
+	if err != nil {
+		return nil, entity.NewInternalError(err)
+	}
+	return payload, nil
+}
+
+func (uc *bundleUsecase) Delete(ctx context.Context, bundleID int64) error {
+	existing, err := uc.promoRepo.GetBundle(ctx, bundleID)
+	if err != nil {
+		return entity.NewInternalError(err)
+	}
+	if existing == nil {
+		return entity.NewError(entity.BundleNotFound, http.StatusNotFound)
+	}
+
+	err = uc.promoRepo.DeleteBundle(ctx, bundleID)
+	if err != nil {
+		return entity.NewInternalError(err)
+	}
+	return nil
+}
+
+func (uc *bundleUsecase) List(ctx context.Context, page, limit int) (*entity.BundleList, error) {
+	page, limit, offset := normalizePagination(page, limit)
+	bundles, total, err := uc.promoRepo.GetBundles(ctx, limit, offset)
+	if err != nil {
+		return nil, entity.NewInternalError(err)
+	}
+
+	result := entity.BundleList{
+		Bundles: bundles,
+		Total:   total,
+		Page:    page,
+		Limit:   limit,
+	}
+
+	// get bundle products, product may already be deleted
+	var productIDs []int64
+	for _, bundle := range bundles {
+		for _, item := range bundle.Items {
+			productIDs = append(productIDs, item.ProductID)
+		}
+	}
+	if len(productIDs) == 0 {
+		return &result, nil
+	}
+	products, err := uc.productRepo.GetProductByIDsWithDeleted(ctx, productIDs)
+	if err != nil {
+		return nil, entity.NewInternalError(err)
+	}
+	mapProduct := make(map[int64]*entity.Product)
+	for _, product := range products {
+		mapProduct[product.ID] = product
+	}
+	for _, bundle := range bundles {
+		for _, item := range bundle.Items {
+			item.Product = mapProduct[item.ProductID]
+		}
+	}
+	return &result, nil
+}
+
+// look up item products by serial, then set product id into bundle items
+func (uc *bundleUsecase) resolveProducts(ctx context.Context, payload *entity.Bundle) error {
+	var serials []string
+	seen := make(map[string]bool)
+	for _, item := range payload.Items {
+		if seen[item.Product.Serial] {
+			return entity.NewError(entity.DuplicateBundleItem, http.StatusBadRequest)
+		}
+		seen[item.Product.Serial] = true
+		serials = append(serials, item.Product.Serial)
+	}
+	if len(serials) == 0 {
+		return entity.NewError("bundle must have at least 1 product", http.StatusBadRequest)
+	}
+
+	products, err := uc.productRepo.GetProductBySerials(ctx, serials)
+	if err != nil {
+		return entity.NewInternalError(err)
+	}
+	mapProduct := make(map[string]*entity.Product)
+	for _, product := range products {
+		mapProduct[product.Serial] = product
+	}
+
+	for _, item := range payload.Items {
+		product, ok := mapProduct[item.Product.Serial]
+		if !ok {
+			return entity.NewError(entity.ProductNotFound, http.StatusBadRequest)
+		}
+		item.Product = product
+		item.ProductID = product.ID
+	}
+	return nil
+}
+
+// validate bundle value by its type
+func (uc *bundleUsecase) validate(bundle *entity.Bundle) error {
+	err := uc.validateValue(bundle)
+	if err != nil {
+		return entity.NewError(err.Error(), http.StatusBadRequest)
+	}
+	if bundle.StartsAt != nil && bundle.EndsAt != nil && !bundle.EndsAt.After(*bundle.StartsAt) {
+		return entity.NewError(entity.InvalidPromoPeriod, http.StatusBadRequest)
+	}
+	return nil
+}
+
+func (uc *bundleUsecase) validateValue(bundle *entity.Bundle) error {
+	switch bundle.Type {
+	case entity.BundleFixedPrice:
+		if bundle.MatchQuantity != 0 || bundle.PromoValue != 0 {
+			return errors.New("fixed price bundle only has price and item quantity")
+		}
+		if bundle.Price.Amount <= 0 {
+			return errors.New("bundle price must be greater than 0")
+		}
+		totalQuantity := 0
+		for _, item := range bundle.Items {
+			if item.Quantity < 1 {
+				return errors.New("bundle item quantity must be at least 1")
+			}
+			totalQuantity += item.Quantity
+		}
+		if totalQuantity < 2 {
+			return errors.New("fixed price bundle must have at least 2 items")
+		}
+	case entity.BundleGroupPrice:
+		if !bundle.Price.IsZero() {
+			return errors.New("group price bundle cannot have price")
+		}
+		if bundle.MatchQuantity < 2 {
+			return errors.New("match quantity must be at least 2")
+		}
+		if bundle.PromoValue < 1 || bundle.PromoValue >= bundle.MatchQuantity {
+			return errors.New("paid quantity must be between 1 and match quantity - 1")
+		}
+		for _, item := range bundle.Items {
+			item.Quantity = 0
+		}
+	default:
+		return errors.New(entity.InvalidBundleType)
+	}
+	return nil
+}
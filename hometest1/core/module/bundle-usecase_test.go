@@ -0,0 +1,101 @@
+package module_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"hometest1/core/entity"
+	"hometest1/core/module"
+	repomocks "hometest1/core/repository/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func initBundleUC(ctrl *gomock.Controller) (module.BundleUsecase, *repomocks.MockPromotionRepo, *repomocks.MockProductRepo) {
+	promoRepo := repomocks.NewMockPromotionRepo(ctrl)
+	productRepo := repomocks.NewMockProductRepo(ctrl)
+
+	return module.NewBundleUsecase(promoRepo, productRepo), promoRepo, productRepo
+}
+
+func Test_BundleCreate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+
+	svc, promoRepo, productRepo := initBundleUC(ctrl)
+
+	macbook := &entity.Product{ID: 2, Serial: "43N23P", Name: "MacBook Pro", Price: entity.NewMoney(539999)}
+	alexa := &entity.Product{ID: 3, Serial: "A304SD", Name: "Alexa Speaker", Price: entity.NewMoney(10950)}
+
+	t.Run("positive, fixed price", func(t *testing.T) {
+		productRepo.EXPECT().GetProductBySerials(ctx, []string{"43N23P", "A304SD"}).Return([]*entity.Product{macbook, alexa}, nil).Times(1)
+		expected := &entity.Bundle{Type: entity.BundleFixedPrice, Name: "MacBook Pro + Alexa Speaker", Price: entity.NewMoney(545000), Items: []*entity.BundleItem{
+			{ProductID: 2, Quantity: 1, Product: macbook},
+			{ProductID: 3, Quantity: 1, Product: alexa},
+		}}
+		promoRepo.EXPECT().CreateBundle(ctx, expected).Return(nil).Times(1)
+
+		resp, err := svc.Create(ctx, &entity.Bundle{Type: entity.BundleFixedPrice, Name: "MacBook Pro + Alexa Speaker", Price: entity.NewMoney(545000), Items: []*entity.BundleItem{
+			{Quantity: 1, Product: &entity.Product{Serial: "43N23P"}},
+			{Quantity: 1, Product: &entity.Product{Serial: "A304SD"}},
+		}})
+		assert.Nil(t, err)
+		assert.Equal(t, expected, resp)
+	})
+
+	t.Run("negative, fixed price with single item", func(t *testing.T) {
+		productRepo.EXPECT().GetProductBySerials(ctx, []string{"43N23P"}).Return([]*entity.Product{macbook}, nil).Times(1)
+
+		_, err := svc.Create(ctx, &entity.Bundle{Type: entity.BundleFixedPrice, Price: entity.NewMoney(500000), Items: []*entity.BundleItem{
+			{Quantity: 1, Product: &entity.Product{Serial: "43N23P"}},
+		}})
+		assert.Equal(t, entity.NewError("fixed price bundle must have at least 2 items", http.StatusBadRequest), err)
+	})
+
+	t.Run("negative, invalid paid quantity", func(t *testing.T) {
+		productRepo.EXPECT().GetProductBySerials(ctx, []string{"43N23P", "A304SD"}).Return([]*entity.Product{macbook, alexa}, nil).Times(1)
+
+		_, err := svc.Create(ctx, &entity.Bundle{Type: entity.BundleGroupPrice, MatchQuantity: 3, PromoValue: 3, Items: []*entity.BundleItem{
+			{Product: &entity.Product{Serial: "43N23P"}},
+			{Product: &entity.Product{Serial: "A304SD"}},
+		}})
+		assert.Equal(t, entity.NewError("paid quantity must be between 1 and match quantity - 1", http.StatusBadRequest), err)
+	})
+
+	t.Run("negative, duplicate product", func(t *testing.T) {
+		_, err := svc.Create(ctx, &entity.Bundle{Type: entity.BundleGroupPrice, MatchQuantity: 3, PromoValue: 2, Items: []*entity.BundleItem{
+			{Product: &entity.Product{Serial: "43N23P"}},
+			{Product: &entity.Product{Serial: "43N23P"}},
+		}})
+		assert.Equal(t, entity.NewError(entity.DuplicateBundleItem, http.StatusBadRequest), err)
+	})
+}
+
+func Test_BundleList(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+
+	svc, promoRepo, productRepo := initBundleUC(ctrl)
+
+	googleHome := &entity.Product{ID: 1, Serial: "120P90", Name: "Google Home", Price: entity.NewMoney(4999)}
+	bundles := []*entity.Bundle{
+		{ID: 2, Type: entity.BundleGroupPrice, MatchQuantity: 3, PromoValue: 2, Items: []*entity.BundleItem{{BundleID: 2, ProductID: 1}}},
+	}
+	promoRepo.EXPECT().GetBundles(ctx, 10, 0).Return(bundles, int64(1), nil).Times(1)
+	productRepo.EXPECT().GetProductByIDsWithDeleted(ctx, []int64{1}).Return([]*entity.Product{googleHome}, nil).Times(1)
+
+	resp, err := svc.List(ctx, 1, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, &entity.BundleList{
+		Bundles: []*entity.Bundle{
+			{ID: 2, Type: entity.BundleGroupPrice, MatchQuantity: 3, PromoValue: 2, Items: []*entity.BundleItem{{BundleID: 2, ProductID: 1, Product: googleHome}}},
+		},
+		Total: 1,
+		Page:  1,
+		Limit: 10,
+	}, resp)
+}
@@ -0,0 +1,206 @@
+package module
+
+import (
+	"fmt"
+
+	"hometest1/core/entity"
+)
+
+// cartPromotionResult is calculation of one cart promotion against the checkout
+type cartPromotionResult struct {
+	discount entity.Money
+	// only for free item promotion
+	freeProduct  *entity.Product
+	freeQuantity int
+}
+
+// applyCartPromotions applies the combination of cart promotions that gives the biggest discount.
+// Every cart promotion is calculated from the checkout total price after product promotions,
+// promotions must be sorted by priority
+func (uc *checkoutUsecase) applyCartPromotions(checkout *entity.Checkout, promotions []*entity.CartPromotion, productOf productLookup) error {
+	subTotal := checkout.TotalPrice
+
+	// evaluate every promotion once
+	results := make(map[*entity.CartPromotion]*cartPromotionResult)
+	var metPromotions []*entity.CartPromotion
+	for _, promo := range promotions {
+		result, err := uc.evaluateCartPromotion(subTotal, promo, productOf)
+		if err != nil {
+			return err
+		}
+		if result != nil {
+			results[promo] = result
+			metPromotions = append(metPromotions, promo)
+		}
+	}
+
+	// choose the biggest discount, the first combination on equal discount
+	var best []*entity.CartPromotion
+	bestDiscount := entity.NewMoney(-1)
+	combinations := stackingCombinations(metPromotions, func(promo *entity.CartPromotion) entity.PromotionStacking {
+		return promo.Stacking
+	})
+	for _, combination := range combinations {
+		discount := entity.NewMoney(0)
+		for _, promo := range combination {
+			discount = discount.Add(results[promo].discount)
+		}
+		if discount.Amount > bestDiscount.Amount {
+			best = combination
+			bestDiscount = discount
+		}
+	}
+
+	err := uc.explainCartPromotions(checkout, subTotal, promotions, results, best, productOf)
+	if err != nil {
+		return err
+	}
+
+	// price discount cannot exceed the sub total
+	remaining := subTotal
+	for _, promo := range best {
+		result := results[promo]
+		amount := result.discount
+		if result.freeProduct != nil {
+			// free items are added in full price, then discounted
+			uc.addCartFreeItem(checkout, result.freeProduct, result.freeQuantity)
+		} else {
+			if amount.Amount > remaining.Amount {
+				amount = remaining
+			}
+			remaining = remaining.Sub(amount)
+		}
+		if amount.IsZero() {
+			continue
+		}
+
+		if checkout.Discounts == nil {
+			checkout.DiscountPrice = entity.NewMoney(0)
+		}
+		checkout.Discounts = append(checkout.Discounts, &entity.CheckoutDiscount{
+			CartPromotionID: promo.ID,
+			Type:            promo.Type,
+			Description:     cartPromotionDescription(promo, result.freeProduct),
+			Amount:          amount,
+		})
+		checkout.DiscountPrice = checkout.DiscountPrice.Add(amount)
+		checkout.TotalPrice = checkout.TotalPrice.Sub(amount)
+	}
+	return nil
+}
+
+// list cart promotions that are not applied with the reason
+func (uc *checkoutUsecase) explainCartPromotions(checkout *entity.Checkout, subTotal entity.Money, promotions []*entity.CartPromotion, results map[*entity.CartPromotion]*cartPromotionResult, applied []*entity.CartPromotion, productOf productLookup) error {
+	mapApplied := make(map[*entity.CartPromotion]bool)
+	for _, promo := range applied {
+		mapApplied[promo] = true
+	}
+
+	for _, promo := range promotions {
+		if mapApplied[promo] {
+			continue
+		}
+		var freeProduct *entity.Product
+		reason := notCombinedReason
+		if result, ok := results[promo]; ok {
+			freeProduct = result.freeProduct
+		} else {
+			if promo.Type == entity.CartFreeItem {
+				var err error
+				freeProduct, err = productOf(promo.PromoProductID)
+				if err != nil {
+					return err
+				}
+			}
+			reason = "promotion is not applicable"
+			if subTotal.Amount < promo.MinSpend.Amount {
+				reason = fmt.Sprintf("spend %s more", promo.MinSpend.Sub(subTotal))
+			}
+		}
+		checkout.NotMetPromotions = append(checkout.NotMetPromotions, &entity.AppliedPromotion{
+			Source:      entity.SourceCartPromotion,
+			ID:          promo.ID,
+			Type:        int(promo.Type),
+			Description: cartPromotionDescription(promo, freeProduct),
+			Reason:      reason,
+		})
+	}
+	return nil
+}
+
+// calculate cart promotion discount, return nil if promotion is not met
+func (uc *checkoutUsecase) evaluateCartPromotion(subTotal entity.Money, promo *entity.CartPromotion, productOf productLookup) (*cartPromotionResult, error) {
+	if subTotal.Amount < promo.MinSpend.Amount {
+		return nil, nil
+	}
+
+	switch promo.Type {
+	case entity.CartDiscountInPercent:
+		if promo.PromoValue <= 0 || promo.PromoValue > 100 {
+			return nil, nil
+		}
+		return &cartPromotionResult{discount: subTotal.Percent(promo.PromoValue)}, nil
+	case entity.CartDiscountAmount:
+		if promo.PromoAmount.Amount <= 0 {
+			return nil, nil
+		}
+		return &cartPromotionResult{discount: promo.PromoAmount}, nil
+	case entity.CartFreeItem:
+		if promo.PromoValue <= 0 {
+			return nil, nil
+		}
+		product, err := productOf(promo.PromoProductID)
+		if err != nil || product == nil {
+			return nil, err
+		}
+		return &cartPromotionResult{
+			discount:     product.Price.Mul(promo.PromoValue),
+			freeProduct:  product,
+			freeQuantity: promo.PromoValue,
+		}, nil
+	}
+	return nil, nil
+}
+
+// add free items into checkout in full price
+func (uc *checkoutUsecase) addCartFreeItem(checkout *entity.Checkout, product *entity.Product, quantity int) {
+	price := product.Price.Mul(quantity)
+	checkout.TotalItem += quantity
+	checkout.TotalPrice = checkout.TotalPrice.Add(price)
+
+	for _, item := range checkout.Items {
+		if item.Product.ID == product.ID {
+			item.Quantity += quantity
+			item.FreeQuantity += quantity
+			item.SubTotalPrice = item.SubTotalPrice.Add(price)
+			return
+		}
+	}
+	checkout.Items = append(checkout.Items, &entity.CheckoutItem{
+		Product:       product,
+		Quantity:      quantity,
+		SubTotalPrice: price,
+		FreeQuantity:  quantity,
+	})
+}
+
+// human readable description of cart promotion, eg: 5% off for spending 500.00 or more
+func cartPromotionDescription(promo *entity.CartPromotion, freeProduct *entity.Product) string {
+	var result string
+	switch promo.Type {
+	case entity.CartDiscountInPercent:
+		result = fmt.Sprintf("%d%% off", promo.PromoValue)
+	case entity.CartDiscountAmount:
+		result = fmt.Sprintf("%s off", promo.PromoAmount)
+	case entity.CartFreeItem:
+		name := "item"
+		if freeProduct != nil {
+			name = freeProduct.Name
+		}
+		result = fmt.Sprintf("free %d %s", promo.PromoValue, name)
+	}
+	if promo.MinSpend.Amount > 0 {
+		result += fmt.Sprintf(" for spending %s or more", promo.MinSpend)
+	}
+	return result
+}
@@ -0,0 +1,198 @@
+package module
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"hometest1/core/entity"
+	"hometest1/core/repository"
+)
+
+type CartPromotionUsecase interface {
+	// create cart promotion, promo product is looked up by serial
+	Create(ctx context.Context, payload *entity.CartPromotionDetail) (*entity.CartPromotionDetail, error)
+	// update cart promotion by id, promo product is looked up by serial
+	Update(ctx context.Context, payload *entity.CartPromotionDetail) (*entity.CartPromotionDetail, error)
+	// soft delete cart promotion
+	Delete(ctx context.Context, promotionID int64) error
+	// get cart promotions, page start from 1
+	List(ctx context.Context, page, limit int) (*entity.CartPromotionList, error)
+}
+
+type cartPromotionUsecase struct {
+	promoRepo   repository.PromotionRepo
+	productRepo repository.ProductRepo
+}
+
+func NewCartPromotionUsecase(promoRepo repository.PromotionRepo, productRepo repository.ProductRepo) CartPromotionUsecase {
+	return &cartPromotionUsecase{promoRepo, productRepo}
+}
+
+func (uc *cartPromotionUsecase) Create(ctx context.Context, payload *entity.CartPromotionDetail) (*entity.CartPromotionDetail, error) {
+	err := uc.resolveProduct(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+	err = uc.validate(payload.CartPromotion)
+	if err != nil {
+		return nil, err
+	}
+
+	err = uc.promoRepo.CreateCartPromotion(ctx, payload.CartPromotion)
+	if err != nil {
+		return nil, entity.NewInternalError(err)
+	}
+	return payload, nil
+}
+
+func (uc *cartPromotionUsecase) Update(ctx context.Context, payload *entity.CartPromotionDetail) (*entity.CartPromotionDetail, error) {
+	existing, err := uc.promoRepo.GetCartPromotion(ctx, payload.ID)
+	if err != nil {
+		return nil, entity.NewInternalError(err)
+	}
+	if existing == nil {
+		return nil, entity.NewError(entity.CartPromotionNotFound, http.StatusNotFound)
+	}
+
+	err = uc.resolveProduct(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+	err = uc.validate(payload.CartPromotion)
+	if err != nil {
+		return nil, err
+	}
+
+	err = uc.promoRepo.UpdateCartPromotion(ctx, payload.CartPromotion)
+	if err != nil {
+		return nil, entity.NewInternalError(err)
+	}
+	return payload, nil
+}
+
+func (uc *cartPromotionUsecase) Delete(ctx context.Context, promotionID int64) error {
+	existing, err := uc.promoRepo.GetCartPromotion(ctx, promotionID)
+	if err != nil {
+		return entity.NewInternalError(err)
+	}
+	if existing == nil {
+		return entity.NewError(entity.CartPromotionNotFound, http.StatusNotFound)
+	}
+
+	err = uc.promoRepo.DeleteCartPromotion(ctx, promotionID)
+	if err != nil {
+		return entity.NewInternalError(err)
+	}
+	return nil
+}
+
+func (uc *cartPromotionUsecase) List(ctx context.Context, page, limit int) (*entity.CartPromotionList, error) {
+	page, limit, offset := normalizePagination(page, limit)
+	promotions, total, err := uc.promoRepo.GetCartPromotions(ctx, limit, offset)
+	if err != nil {
+		return nil, entity.NewInternalError(err)
+	}
+
+	result := entity.CartPromotionList{
+		Total: total,
+		Page:  page,
+		Limit: limit,
+	}
+
+	// get free item products, product may already be deleted
+	var productIDs []int64
+	for _, promo := range promotions {
+		if promo.PromoProductID != 0 {
+			productIDs = append(productIDs, promo.PromoProductID)
+		}
+	}
+	mapProduct := make(map[int64]*entity.Product)
+	if len(productIDs) > 0 {
+		products, err := uc.productRepo.GetProductByIDsWithDeleted(ctx, productIDs)
+		if err != nil {
+			return nil, entity.NewInternalError(err)
+		}
+		for _, product := range products {
+			mapProduct[product.ID] = product
+		}
+	}
+
+	for _, promo := range promotions {
+		result.Promotions = append(result.Promotions, &entity.CartPromotionDetail{
+			CartPromotion: promo,
+			PromoProduct:  mapProduct[promo.PromoProductID],
+		})
+	}
+	return &result, nil
+}
+
+// look up promo product by serial, then set product id into promotion
+func (uc *cartPromotionUsecase) resolveProduct(ctx context.Context, payload *entity.CartPromotionDetail) error {
+	payload.PromoProductID = 0
+	if payload.PromoProduct == nil {
+		return nil
+	}
+
+	products, err := uc.productRepo.GetProductBySerials(ctx, []string{payload.PromoProduct.Serial})
+	if err != nil {
+		return entity.NewInternalError(err)
+	}
+	if len(products) == 0 {
+		return entity.NewError(entity.ProductNotFound, http.StatusBadRequest)
+	}
+	payload.PromoProduct = products[0]
+	payload.PromoProductID = products[0].ID
+	return nil
+}
+
+// validate cart promotion value by its type
+func (uc *cartPromotionUsecase) validate(promo *entity.CartPromotion) error {
+	err := uc.validateValue(promo)
+	if err != nil {
+		return entity.NewError(err.Error(), http.StatusBadRequest)
+	}
+	if !promo.Stacking.IsValid() {
+		return entity.NewError(entity.InvalidStacking, http.StatusBadRequest)
+	}
+	if promo.StartsAt != nil && promo.EndsAt != nil && !promo.EndsAt.After(*promo.StartsAt) {
+		return entity.NewError(entity.InvalidPromoPeriod, http.StatusBadRequest)
+	}
+	return nil
+}
+
+func (uc *cartPromotionUsecase) validateValue(promo *entity.CartPromotion) error {
+	if promo.MinSpend.Amount < 0 {
+		return errors.New("minimum spend cannot be negative")
+	}
+
+	switch promo.Type {
+	case entity.CartDiscountInPercent:
+		if promo.PromoProductID != 0 || !promo.PromoAmount.IsZero() {
+			return errors.New("percent discount promotion only has discount percent")
+		}
+		if promo.PromoValue < 1 || promo.PromoValue > 100 {
+			return errors.New("discount percent must be between 1 and 100")
+		}
+	case entity.CartDiscountAmount:
+		if promo.PromoProductID != 0 || promo.PromoValue != 0 {
+			return errors.New("fixed amount discount promotion only has discount amount")
+		}
+		if promo.PromoAmount.Amount <= 0 {
+			return errors.New("discount amount must be greater than 0")
+		}
+	case entity.CartFreeItem:
+		if promo.PromoProductID == 0 {
+			return errors.New("free item product is required")
+		}
+		if !promo.PromoAmount.IsZero() {
+			return errors.New("free item promotion cannot have discount amount")
+		}
+		if promo.PromoValue < 1 {
+			return errors.New("number of free items must be at least 1")
+		}
+	default:
+		return errors.New(entity.InvalidPromotionType)
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package module_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"hometest1/core/entity"
+	"hometest1/core/module"
+	repomocks "hometest1/core/repository/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func initCartPromotionUC(ctrl *gomock.Controller) (module.CartPromotionUsecase, *repomocks.MockPromotionRepo, *repomocks.MockProductRepo) {
+	promoRepo := repomocks.NewMockPromotionRepo(ctrl)
+	productRepo := repomocks.NewMockProductRepo(ctrl)
+
+	return module.NewCartPromotionUsecase(promoRepo, productRepo), promoRepo, productRepo
+}
+
+func Test_CartPromotionCreate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+
+	svc, promoRepo, productRepo := initCartPromotionUC(ctrl)
+
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+	raspberry := &entity.Product{ID: 4, Serial: "234234", Name: "Raspberry Pi B", Price: entity.NewMoney(3000), UpdatedAt: dayCreated}
+
+	t.Run("positive, percent discount", func(t *testing.T) {
+		expected := &entity.CartPromotion{Type: entity.CartDiscountInPercent, MinSpend: entity.NewMoney(50000), PromoValue: 5}
+		promoRepo.EXPECT().CreateCartPromotion(ctx, expected).Return(nil).Times(1)
+
+		resp, err := svc.Create(ctx, &entity.CartPromotionDetail{
+			CartPromotion: &entity.CartPromotion{Type: entity.CartDiscountInPercent, MinSpend: entity.NewMoney(50000), PromoValue: 5},
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, &entity.CartPromotionDetail{CartPromotion: expected}, resp)
+	})
+
+	t.Run("positive, free item", func(t *testing.T) {
+		productRepo.EXPECT().GetProductBySerials(ctx, []string{"234234"}).Return([]*entity.Product{raspberry}, nil).Times(1)
+		expected := &entity.CartPromotion{Type: entity.CartFreeItem, MinSpend: entity.NewMoney(100000), PromoValue: 1, PromoProductID: 4}
+		promoRepo.EXPECT().CreateCartPromotion(ctx, expected).Return(nil).Times(1)
+
+		resp, err := svc.Create(ctx, &entity.CartPromotionDetail{
+			CartPromotion: &entity.CartPromotion{Type: entity.CartFreeItem, MinSpend: entity.NewMoney(100000), PromoValue: 1},
+			PromoProduct:  &entity.Product{Serial: "234234"},
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, &entity.CartPromotionDetail{CartPromotion: expected, PromoProduct: raspberry}, resp)
+	})
+
+	t.Run("negative, free item product not found", func(t *testing.T) {
+		productRepo.EXPECT().GetProductBySerials(ctx, []string{"XXXXXX"}).Return(nil, nil).Times(1)
+
+		_, err := svc.Create(ctx, &entity.CartPromotionDetail{
+			CartPromotion: &entity.CartPromotion{Type: entity.CartFreeItem, PromoValue: 1},
+			PromoProduct:  &entity.Product{Serial: "XXXXXX"},
+		})
+		assert.Equal(t, entity.NewError(entity.ProductNotFound, http.StatusBadRequest), err)
+	})
+
+	t.Run("negative, free item without product", func(t *testing.T) {
+		_, err := svc.Create(ctx, &entity.CartPromotionDetail{
+			CartPromotion: &entity.CartPromotion{Type: entity.CartFreeItem, PromoValue: 1},
+		})
+		assert.Equal(t, entity.NewError("free item product is required", http.StatusBadRequest), err)
+	})
+
+	t.Run("negative, invalid discount amount", func(t *testing.T) {
+		_, err := svc.Create(ctx, &entity.CartPromotionDetail{
+			CartPromotion: &entity.CartPromotion{Type: entity.CartDiscountAmount, MinSpend: entity.NewMoney(10000)},
+		})
+		assert.Equal(t, entity.NewError("discount amount must be greater than 0", http.StatusBadRequest), err)
+	})
+
+	t.Run("negative, negative minimum spend", func(t *testing.T) {
+		_, err := svc.Create(ctx, &entity.CartPromotionDetail{
+			CartPromotion: &entity.CartPromotion{Type: entity.CartDiscountInPercent, MinSpend: entity.NewMoney(-100), PromoValue: 5},
+		})
+		assert.Equal(t, entity.NewError("minimum spend cannot be negative", http.StatusBadRequest), err)
+	})
+
+	t.Run("negative, invalid type", func(t *testing.T) {
+		_, err := svc.Create(ctx, &entity.CartPromotionDetail{
+			CartPromotion: &entity.CartPromotion{Type: entity.UndefinedCartType, PromoValue: 5},
+		})
+		assert.Equal(t, entity.NewError(entity.InvalidPromotionType, http.StatusBadRequest), err)
+	})
+}
+
+func Test_CartPromotionUpdate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+
+	svc, promoRepo, _ := initCartPromotionUC(ctrl)
+
+	t.Run("positive, update amount", func(t *testing.T) {
+		promoRepo.EXPECT().GetCartPromotion(ctx, int64(1)).Return(&entity.CartPromotion{ID: 1, Type: entity.CartDiscountInPercent, PromoValue: 5}, nil).Times(1)
+		expected := &entity.CartPromotion{ID: 1, Type: entity.CartDiscountAmount, PromoAmount: entity.NewMoney(1000)}
+		promoRepo.EXPECT().UpdateCartPromotion(ctx, expected).Return(nil).Times(1)
+
+		resp, err := svc.Update(ctx, &entity.CartPromotionDetail{
+			CartPromotion: &entity.CartPromotion{ID: 1, Type: entity.CartDiscountAmount, PromoAmount: entity.NewMoney(1000)},
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, &entity.CartPromotionDetail{CartPromotion: expected}, resp)
+	})
+
+	t.Run("negative, not found", func(t *testing.T) {
+		promoRepo.EXPECT().GetCartPromotion(ctx, int64(9)).Return(nil, nil).Times(1)
+
+		_, err := svc.Update(ctx, &entity.CartPromotionDetail{
+			CartPromotion: &entity.CartPromotion{ID: 9, Type: entity.CartDiscountAmount, PromoAmount: entity.NewMoney(1000)},
+		})
+		assert.Equal(t, entity.NewError(entity.CartPromotionNotFound, http.StatusNotFound), err)
+	})
+}
+
+func Test_CartPromotionList(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+
+	svc, promoRepo, productRepo := initCartPromotionUC(ctrl)
+
+	raspberry := &entity.Product{ID: 4, Serial: "234234", Name: "Raspberry Pi B", Price: entity.NewMoney(3000)}
+	promotions := []*entity.CartPromotion{
+		{ID: 1, Type: entity.CartDiscountInPercent, MinSpend: entity.NewMoney(50000), PromoValue: 5},
+		{ID: 2, Type: entity.CartFreeItem, MinSpend: entity.NewMoney(100000), PromoValue: 1, PromoProductID: 4},
+	}
+	promoRepo.EXPECT().GetCartPromotions(ctx, 10, 0).Return(promotions, int64(2), nil).Times(1)
+	productRepo.EXPECT().GetProductByIDsWithDeleted(ctx, []int64{4}).Return([]*entity.Product{raspberry}, nil).Times(1)
+
+	resp, err := svc.List(ctx, 0, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, &entity.CartPromotionList{
+		Promotions: []*entity.CartPromotionDetail{
+			{CartPromotion: promotions[0]},
+			{CartPromotion: promotions[1], PromoProduct: raspberry},
+		},
+		Total: 2,
+		Page:  1,
+		Limit: 10,
+	}, resp)
+}
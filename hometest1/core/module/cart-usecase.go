@@ -0,0 +1,187 @@
+package module
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"hometest1/core/entity"
+	"hometest1/core/repository"
+)
+
+type CartUsecase interface {
+	Create(ctx context.Context) (*entity.CartDetail, error)
+	Get(ctx context.Context, cartID int64) (*entity.CartDetail, error)
+	// set product quantity in cart, then reprice the cart
+	SetItem(ctx context.Context, cartID int64, serial string, quantity int) (*entity.CartDetail, error)
+	RemoveItem(ctx context.Context, cartID int64, serial string) (*entity.CartDetail, error)
+	Checkout(ctx context.Context, cartID int64) (*entity.Checkout, error)
+}
+
+type cartUsecase struct {
+	cartRepo    repository.CartRepo
+	productRepo repository.ProductRepo
+	checkoutUC  CheckoutUsecase
+}
+
+func NewCartUsecase(cartRepo repository.CartRepo, productRepo repository.ProductRepo, checkoutUC CheckoutUsecase) CartUsecase {
+	return &cartUsecase{cartRepo, productRepo, checkoutUC}
+}
+
+func (uc *cartUsecase) Create(ctx context.Context) (*entity.CartDetail, error) {
+	cart, err := uc.cartRepo.CreateCart(ctx)
+	if err != nil {
+		return nil, entity.NewInternalError(err)
+	}
+	return &entity.CartDetail{Cart: cart}, nil
+}
+
+func (uc *cartUsecase) Get(ctx context.Context, cartID int64) (*entity.CartDetail, error) {
+	cart, err := uc.getCart(ctx, cartID)
+	if err != nil {
+		return nil, err
+	}
+	return uc.reprice(ctx, cart)
+}
+
+func (uc *cartUsecase) SetItem(ctx context.Context, cartID int64, serial string, quantity int) (*entity.CartDetail, error) {
+	cart, err := uc.getOpenCart(ctx, cartID)
+	if err != nil {
+		return nil, err
+	}
+	product, err := uc.getProduct(ctx, serial)
+	if err != nil {
+		return nil, err
+	}
+
+	// save item
+	err = uc.cartRepo.SaveCartItem(ctx, &entity.CartItem{
+		CartID:    cart.ID,
+		ProductID: product.ID,
+		Quantity:  quantity,
+	})
+	if err != nil {
+		return nil, entity.NewInternalError(err)
+	}
+
+	return uc.Get(ctx, cartID)
+}
+
+func (uc *cartUsecase) RemoveItem(ctx context.Context, cartID int64, serial string) (*entity.CartDetail, error) {
+	cart, err := uc.getOpenCart(ctx, cartID)
+	if err != nil {
+		return nil, err
+	}
+	product, err := uc.getProduct(ctx, serial)
+	if err != nil {
+		return nil, err
+	}
+
+	err = uc.cartRepo.DeleteCartItem(ctx, cart.ID, product.ID)
+	if err != nil {
+		return nil, entity.NewInternalError(err)
+	}
+
+	return uc.Get(ctx, cartID)
+}
+
+func (uc *cartUsecase) Checkout(ctx context.Context, cartID int64) (*entity.Checkout, error) {
+	cart, err := uc.getOpenCart(ctx, cartID)
+	if err != nil {
+		return nil, err
+	}
+	if len(cart.Items) == 0 {
+		return nil, entity.NewError(entity.EmptyCart, http.StatusBadRequest)
+	}
+
+	payload, err := uc.mapCartItems(ctx, cart)
+	if err != nil {
+		return nil, err
+	}
+
+	// submit checkout and close the cart in one transaction, so the cart cannot be checked out twice.
+	// Usecase already return entity.Err
+	return uc.checkoutUC.Submit(ctx, payload, entity.CheckoutOptions{CartID: cart.ID})
+}
+
+func (uc *cartUsecase) getCart(ctx context.Context, cartID int64) (*entity.Cart, error) {
+	cart, err := uc.cartRepo.GetCart(ctx, cartID)
+	if err != nil {
+		return nil, entity.NewInternalError(err)
+	}
+	if cart == nil {
+		return nil, entity.NewError(entity.CartNotFound, http.StatusNotFound)
+	}
+	return cart, nil
+}
+
+// get cart that still can be changed
+func (uc *cartUsecase) getOpenCart(ctx context.Context, cartID int64) (*entity.Cart, error) {
+	cart, err := uc.getCart(ctx, cartID)
+	if err != nil {
+		return nil, err
+	}
+	if cart.Status != entity.CartOpen {
+		return nil, entity.NewError(entity.CartClosed, http.StatusBadRequest)
+	}
+	return cart, nil
+}
+
+func (uc *cartUsecase) getProduct(ctx context.Context, serial string) (*entity.Product, error) {
+	products, err := uc.productRepo.GetProductBySerials(ctx, []string{serial})
+	if err != nil {
+		return nil, entity.NewInternalError(err)
+	}
+	if len(products) == 0 {
+		return nil, entity.NewError(entity.ProductNotFound, http.StatusNotFound)
+	}
+	return products[0], nil
+}
+
+// calculate cart price using checkout quote
+func (uc *cartUsecase) reprice(ctx context.Context, cart *entity.Cart) (*entity.CartDetail, error) {
+	result := entity.CartDetail{Cart: cart}
+	if len(cart.Items) == 0 {
+		return &result, nil
+	}
+
+	payload, err := uc.mapCartItems(ctx, cart)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Quote, err = uc.checkoutUC.Quote(ctx, payload, entity.CheckoutOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// map cart items to checkout payload
+func (uc *cartUsecase) mapCartItems(ctx context.Context, cart *entity.Cart) (entity.MapProductSerialQuantity, error) {
+	var productIDs []int64
+	for _, item := range cart.Items {
+		productIDs = append(productIDs, item.ProductID)
+	}
+	products, err := uc.productRepo.GetProductByIDs(ctx, productIDs)
+	if err != nil {
+		return nil, entity.NewInternalError(err)
+	}
+
+	// map product id to serial
+	serials := make(map[int64]string)
+	for _, product := range products {
+		serials[product.ID] = product.Serial
+	}
+
+	result := make(entity.MapProductSerialQuantity)
+	for _, item := range cart.Items {
+		serial, ok := serials[item.ProductID]
+		if !ok {
+			// product is deleted after it is added to the cart
+			return nil, entity.NewError(fmt.Sprintf("%s: %d", entity.ProductNotAvailable, item.ProductID), http.StatusBadRequest)
+		}
+		result[serial] += item.Quantity
+	}
+	return result, nil
+}
@@ -0,0 +1,146 @@
+package module_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"hometest1/core/entity"
+	"hometest1/core/module"
+	repomocks "hometest1/core/repository/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func initCartUC(ctrl *gomock.Controller) (module.CartUsecase, *repomocks.MockCartRepo, *repomocks.MockProductRepo, *repomocks.MockPromotionRepo) {
+	cartRepo := repomocks.NewMockCartRepo(ctrl)
+	productRepo := repomocks.NewMockProductRepo(ctrl)
+	promoRepo := repomocks.NewMockPromotionRepo(ctrl)
+	checkoutUC := module.NewCheckoutUsecase(productRepo, promoRepo)
+
+	return module.NewCartUsecase(cartRepo, productRepo, checkoutUC), cartRepo, productRepo, promoRepo
+}
+
+func Test_CartSetItem(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+
+	svc, cartRepo, productRepo, promoRepo := initCartUC(ctrl)
+
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+	product := &entity.Product{ID: 1, Serial: "120P90", Name: "Google Home", Price: entity.NewMoney(4999), UpdatedAt: dayCreated}
+
+	t.Run("positive, cart repriced", func(t *testing.T) {
+		openCart := &entity.Cart{ID: 1, Status: entity.CartOpen, UpdatedAt: dayCreated}
+		filledCart := &entity.Cart{ID: 1, Status: entity.CartOpen, UpdatedAt: dayCreated, Items: []*entity.CartItem{
+			{ID: 1, CartID: 1, ProductID: 1, Quantity: 3, UpdatedAt: dayCreated},
+		}}
+		gomock.InOrder(
+			cartRepo.EXPECT().GetCart(ctx, int64(1)).Return(openCart, nil),
+			cartRepo.EXPECT().GetCart(ctx, int64(1)).Return(filledCart, nil),
+		)
+		productRepo.EXPECT().GetProductBySerials(ctx, []string{"120P90"}).Return([]*entity.Product{product}, nil).Times(2)
+		cartRepo.EXPECT().SaveCartItem(ctx, &entity.CartItem{CartID: 1, ProductID: 1, Quantity: 3}).Return(nil).Times(1)
+		productRepo.EXPECT().GetProductByIDs(ctx, []int64{1}).Return([]*entity.Product{product}, nil).Times(1)
+		promoRepo.EXPECT().GetPromotionByProducts(ctx, []*entity.Product{product}).Return(map[int64][]*entity.Promotion{
+			1: {{ID: 2, Type: 2, ProductID: 1, MatchQuantity: 3, PromoValue: 2, UpdatedAt: dayCreated}},
+		}, nil).Times(1)
+		promoRepo.EXPECT().GetActiveBundlesByProducts(ctx, gomock.Any()).Return(nil, nil).Times(1)
+		promoRepo.EXPECT().GetActiveCartPromotions(ctx, nil).Return(nil, nil).Times(1)
+		productRepo.EXPECT().GetAvailableQuantityByIDs(ctx, []int64{1}).Return([]*entity.ProductQuantity{
+			{ID: 1, ProductID: 1, Quantity: 10, UpdatedAt: dayCreated},
+		}, nil).Times(1)
+
+		resp, err := svc.SetItem(ctx, 1, "120P90", 3)
+		assert.Nil(t, err)
+		assert.Equal(t, filledCart, resp.Cart)
+		assert.Equal(t, 3, resp.Quote.TotalItem)
+		assert.Equal(t, entity.NewMoney(4999*2), resp.Quote.TotalPrice)
+		assert.Equal(t, map[int64]int{1: 10}, resp.Quote.AvailableQuantity)
+	})
+
+	t.Run("negative, cart already checked out", func(t *testing.T) {
+		cartRepo.EXPECT().GetCart(ctx, int64(2)).Return(&entity.Cart{ID: 2, Status: entity.CartCheckedOut}, nil).Times(1)
+
+		_, err := svc.SetItem(ctx, 2, "120P90", 1)
+		assert.Equal(t, entity.NewError(entity.CartClosed, http.StatusBadRequest), err)
+	})
+
+	t.Run("negative, cart not found", func(t *testing.T) {
+		cartRepo.EXPECT().GetCart(ctx, int64(3)).Return(nil, nil).Times(1)
+
+		_, err := svc.SetItem(ctx, 3, "120P90", 1)
+		assert.Equal(t, entity.NewError(entity.CartNotFound, http.StatusNotFound), err)
+	})
+}
+
+func Test_CartCheckout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+
+	svc, cartRepo, productRepo, promoRepo := initCartUC(ctrl)
+
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+	product := &entity.Product{ID: 3, Serial: "A304SD", Name: "Alexa Speaker", Price: entity.NewMoney(10950), UpdatedAt: dayCreated}
+
+	t.Run("positive", func(t *testing.T) {
+		cartRepo.EXPECT().GetCart(ctx, int64(1)).Return(&entity.Cart{ID: 1, Status: entity.CartOpen, Items: []*entity.CartItem{
+			{ID: 1, CartID: 1, ProductID: 3, Quantity: 2},
+		}}, nil).Times(1)
+		productRepo.EXPECT().GetProductByIDs(ctx, []int64{3}).Return([]*entity.Product{product}, nil).Times(1)
+		productRepo.EXPECT().GetProductBySerials(ctx, []string{"A304SD"}).Return([]*entity.Product{product}, nil).Times(1)
+		promoRepo.EXPECT().GetPromotionByProducts(ctx, []*entity.Product{product}).Return(nil, nil).Times(1)
+		promoRepo.EXPECT().GetActiveBundlesByProducts(ctx, gomock.Any()).Return(nil, nil).Times(1)
+		promoRepo.EXPECT().GetActiveCartPromotions(ctx, nil).Return(nil, nil).Times(1)
+
+		// cart is closed by the checkout transaction
+		checkout := &entity.Checkout{
+			CartID:     1,
+			Items:      []*entity.CheckoutItem{{Product: product, Quantity: 2, SubTotalPrice: entity.NewMoney(10950 * 2)}},
+			TotalItem:  2,
+			TotalPrice: entity.NewMoney(10950 * 2),
+		}
+		productRepo.EXPECT().SubmitCheckout(ctx, checkout).Return(nil).Times(1)
+
+		resp, err := svc.Checkout(ctx, 1)
+		assert.Nil(t, err)
+		assert.Equal(t, checkout, resp)
+	})
+
+	t.Run("negative, cart is checked out by concurrent checkout", func(t *testing.T) {
+		cartRepo.EXPECT().GetCart(ctx, int64(3)).Return(&entity.Cart{ID: 3, Status: entity.CartOpen, Items: []*entity.CartItem{
+			{ID: 2, CartID: 3, ProductID: 3, Quantity: 1},
+		}}, nil).Times(1)
+		productRepo.EXPECT().GetProductByIDs(ctx, []int64{3}).Return([]*entity.Product{product}, nil).Times(1)
+		productRepo.EXPECT().GetProductBySerials(ctx, []string{"A304SD"}).Return([]*entity.Product{product}, nil).Times(1)
+		promoRepo.EXPECT().GetPromotionByProducts(ctx, []*entity.Product{product}).Return(nil, nil).Times(1)
+		promoRepo.EXPECT().GetActiveBundlesByProducts(ctx, gomock.Any()).Return(nil, nil).Times(1)
+		promoRepo.EXPECT().GetActiveCartPromotions(ctx, nil).Return(nil, nil).Times(1)
+		productRepo.EXPECT().SubmitCheckout(ctx, gomock.Any()).Return(entity.NewError(entity.CartClosed, http.StatusBadRequest)).Times(1)
+
+		_, err := svc.Checkout(ctx, 3)
+		assert.Equal(t, entity.NewError(entity.CartClosed, http.StatusBadRequest), err)
+	})
+
+	t.Run("negative, product is deleted after it is added to the cart", func(t *testing.T) {
+		cartRepo.EXPECT().GetCart(ctx, int64(4)).Return(&entity.Cart{ID: 4, Status: entity.CartOpen, Items: []*entity.CartItem{
+			{ID: 3, CartID: 4, ProductID: 3, Quantity: 1},
+			{ID: 4, CartID: 4, ProductID: 7, Quantity: 1},
+		}}, nil).Times(1)
+		productRepo.EXPECT().GetProductByIDs(ctx, []int64{3, 7}).Return([]*entity.Product{product}, nil).Times(1)
+
+		_, err := svc.Checkout(ctx, 4)
+		assert.Equal(t, entity.NewError("product no longer available: 7", http.StatusBadRequest), err)
+	})
+
+	t.Run("negative, empty cart", func(t *testing.T) {
+		cartRepo.EXPECT().GetCart(ctx, int64(2)).Return(&entity.Cart{ID: 2, Status: entity.CartOpen}, nil).Times(1)
+
+		_, err := svc.Checkout(ctx, 2)
+		assert.Equal(t, entity.NewError(entity.EmptyCart, http.StatusBadRequest), err)
+	})
+}
@@ -0,0 +1,219 @@
+package module
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"hometest1/core/entity"
+)
+
+// columns of catalog csv in export order, header is matched case insensitively in any order
+var catalogColumns = []string{
+	"serial", "name", "price", "taxClass", "quantity",
+	"promoType", "promoMatchQuantity", "promoValue", "promoProductSerial",
+}
+
+var requiredCatalogColumns = []string{"serial", "name", "price", "quantity"}
+
+const maxCatalogRows int = 10000
+
+// read catalog csv, every line that cannot be parsed is reported as one row error.
+// Error is returned if the file is not csv or its header is invalid
+func readCatalogCSV(r io.Reader) ([]*entity.CatalogRow, []*entity.CatalogRowError, error) {
+	reader := csv.NewReader(r)
+	// spreadsheet may trim empty trailing cells
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil, entity.NewError(entity.InvalidCatalog+": file is empty", http.StatusBadRequest)
+	}
+	if err != nil {
+		return nil, nil, newCatalogCSVError(err)
+	}
+	columns, err := parseCatalogHeader(header)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var rows []*entity.CatalogRow
+	var rowErrors []*entity.CatalogRowError
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, newCatalogCSVError(err)
+		}
+		if len(rows)+len(rowErrors) == maxCatalogRows {
+			return nil, nil, entity.NewError(fmt.Sprintf("%s: file has more than %d rows", entity.InvalidCatalog, maxCatalogRows), http.StatusBadRequest)
+		}
+
+		line, _ := reader.FieldPos(0)
+		row, problems := parseCatalogRow(line, columns, record)
+		if len(problems) > 0 {
+			rowErrors = append(rowErrors, newCatalogRowError(row, problems))
+			continue
+		}
+		rows = append(rows, row)
+	}
+	return rows, rowErrors, nil
+}
+
+// write catalog rows as csv with header
+func writeCatalogCSV(w io.Writer, rows []*entity.CatalogRow) error {
+	writer := csv.NewWriter(w)
+	err := writer.Write(catalogColumns)
+	if err != nil {
+		return err
+	}
+	for _, row := range rows {
+		record := []string{row.Serial, row.Name, row.Price.String(), row.TaxClass, strconv.Itoa(row.Quantity), "", "", "", ""}
+		if row.PromoType != entity.UndefinedType {
+			record[5] = strconv.Itoa(int(row.PromoType))
+			record[6] = strconv.Itoa(row.PromoMatchQuantity)
+			record[7] = strconv.Itoa(row.PromoValue)
+			record[8] = row.PromoProductSerial
+		}
+		err = writer.Write(record)
+		if err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// map known column to its index in header
+func parseCatalogHeader(header []string) (map[string]int, error) {
+	mapColumn := make(map[string]string)
+	for _, column := range catalogColumns {
+		mapColumn[strings.ToLower(column)] = column
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		// excel saves utf-8 csv with byte order mark
+		if i == 0 {
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+		column, ok := mapColumn[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return nil, entity.NewError(fmt.Sprintf("%s: unknown column %q", entity.InvalidCatalog, name), http.StatusBadRequest)
+		}
+		if _, ok := columns[column]; ok {
+			return nil, entity.NewError(fmt.Sprintf("%s: column %s is repeated", entity.InvalidCatalog, column), http.StatusBadRequest)
+		}
+		columns[column] = i
+	}
+	for _, column := range requiredCatalogColumns {
+		if _, ok := columns[column]; !ok {
+			return nil, entity.NewError(fmt.Sprintf("%s: column %s is required", entity.InvalidCatalog, column), http.StatusBadRequest)
+		}
+	}
+	return columns, nil
+}
+
+// parse csv record into row, return problems of the row if it is invalid
+func parseCatalogRow(line int, columns map[string]int, record []string) (*entity.CatalogRow, []string) {
+	cell := func(column string) string {
+		i, ok := columns[column]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+	var problems []string
+	if len(record) > len(columns) {
+		problems = append(problems, fmt.Sprintf("row has %d cells but header has %d columns", len(record), len(columns)))
+	}
+
+	row := &entity.CatalogRow{
+		Line:               line,
+		Serial:             cell("serial"),
+		Name:               cell("name"),
+		TaxClass:           cell("taxClass"),
+		PromoProductSerial: cell("promoProductSerial"),
+	}
+	if row.Serial == "" {
+		problems = append(problems, "serial is required")
+	} else if len(row.Serial) > 20 {
+		problems = append(problems, "serial must not exceed 20")
+	}
+	if row.Name == "" {
+		problems = append(problems, "name is required")
+	} else if len(row.Name) > 255 {
+		problems = append(problems, "name must not exceed 255")
+	}
+	if len(row.TaxClass) > 50 {
+		problems = append(problems, "taxClass must not exceed 50")
+	}
+
+	price, err := entity.ParseMoney(cell("price"))
+	if err != nil {
+		problems = append(problems, "price is invalid")
+	} else if price.Amount <= 0 {
+		problems = append(problems, "price is too small")
+	}
+	row.Price = price
+
+	row.Quantity, err = strconv.Atoi(cell("quantity"))
+	if err != nil {
+		problems = append(problems, "quantity is invalid")
+	} else if row.Quantity < 0 {
+		problems = append(problems, "quantity is too small")
+	}
+
+	// promotion columns are set together with promo type
+	if cell("promoType") == "" {
+		if cell("promoMatchQuantity") != "" || cell("promoValue") != "" || row.PromoProductSerial != "" {
+			problems = append(problems, "promoType is required when other promotion columns are set")
+		}
+		return row, problems
+	}
+	promoType, err := strconv.Atoi(cell("promoType"))
+	if err != nil || promoType == int(entity.UndefinedType) {
+		problems = append(problems, "promoType is invalid")
+	}
+	row.PromoType = entity.PromotionType(promoType)
+	// empty promotion value is zero, the promotion rule decides if it is valid
+	for _, column := range []string{"promoMatchQuantity", "promoValue"} {
+		if cell(column) == "" {
+			continue
+		}
+		value, err := strconv.Atoi(cell(column))
+		if err != nil {
+			problems = append(problems, column+" is invalid")
+		}
+		if column == "promoMatchQuantity" {
+			row.PromoMatchQuantity = value
+		} else {
+			row.PromoValue = value
+		}
+	}
+	return row, problems
+}
+
+func newCatalogRowError(row *entity.CatalogRow, problems []string) *entity.CatalogRowError {
+	return &entity.CatalogRowError{
+		Line:    row.Line,
+		Serial:  row.Serial,
+		Message: strings.Join(problems, "; "),
+	}
+}
+
+func newCatalogCSVError(err error) error {
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return entity.NewError(fmt.Sprintf("%s: %s", entity.InvalidCatalog, parseErr.Error()), http.StatusBadRequest)
+	}
+	// reading request body may fail because request is cancelled
+	return entity.NewInternalError(err)
+}
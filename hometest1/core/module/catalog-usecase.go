@@ -0,0 +1,446 @@
+package module
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+
+	"hometest1/core/entity"
+	"hometest1/core/repository"
+)
+
+// reference of stock movement written by catalog import
+const catalogImportReference = "catalog import"
+
+type CatalogUsecase interface {
+	// import products, stock and promotions from csv. Every row is validated before anything is written,
+	// nothing is written if a row is invalid or dry run is true.
+	// Product is created or updated by serial, quantity is the stock level after import,
+	// promotion is created or updated by product and promotion type
+	Import(ctx context.Context, r io.Reader, dryRun bool) (*entity.CatalogImport, error)
+	// write products with stock and upcoming promotions as csv in the import format
+	Export(ctx context.Context, w io.Writer) error
+}
+
+type catalogUsecase struct {
+	productRepo   repository.ProductRepo
+	inventoryRepo repository.InventoryRepo
+	promoRepo     repository.PromotionRepo
+	promoUC       PromotionUsecase
+	promoRules    PromotionRules
+}
+
+func NewCatalogUsecase(productRepo repository.ProductRepo, inventoryRepo repository.InventoryRepo, promoRepo repository.PromotionRepo, promoUC PromotionUsecase, promoRules PromotionRules) CatalogUsecase {
+	return &catalogUsecase{productRepo, inventoryRepo, promoRepo, promoUC, promoRules}
+}
+
+// catalogProduct is planned change of product, from the first row of its serial
+type catalogProduct struct {
+	row *entity.CatalogRow
+	// nil if product is created
+	existing *entity.Product
+	quantity int
+}
+
+func (p *catalogProduct) productChanged() bool {
+	return p.existing.Name != p.row.Name ||
+		p.existing.Price != p.row.Price ||
+		(p.row.TaxClass != "" && p.existing.TaxClass != p.row.TaxClass)
+}
+
+// catalogPromotion is planned change of promotion
+type catalogPromotion struct {
+	row *entity.CatalogRow
+	// nil if promotion is created
+	existing *entity.Promotion
+	// negative if promo product is created by the import
+	promoProductID int64
+}
+
+func (p *catalogPromotion) changed() bool {
+	return p.existing.MatchQuantity != p.row.PromoMatchQuantity ||
+		p.existing.PromoValue != p.row.PromoValue ||
+		p.existing.PromoProductID != p.promoProductID
+}
+
+func (uc *catalogUsecase) Import(ctx context.Context, r io.Reader, dryRun bool) (*entity.CatalogImport, error) {
+	rows, rowErrors, err := readCatalogCSV(r)
+	if err != nil {
+		return nil, err
+	}
+	result := &entity.CatalogImport{
+		DryRun: dryRun,
+		Rows:   len(rows) + len(rowErrors),
+		Errors: rowErrors,
+	}
+
+	products, promotions, planErrors, err := uc.plan(ctx, rows)
+	if err != nil {
+		return nil, err
+	}
+	result.Errors = append(result.Errors, planErrors...)
+	sort.SliceStable(result.Errors, func(i, j int) bool { return result.Errors[i].Line < result.Errors[j].Line })
+
+	if dryRun || len(result.Errors) > 0 {
+		for _, p := range products {
+			countCatalogChange(&result.Products, p.existing == nil, p.existing != nil && (p.productChanged() || p.quantity != p.row.Quantity))
+		}
+		for _, p := range promotions {
+			countCatalogChange(&result.Promotions, p.existing == nil, p.existing != nil && p.changed())
+		}
+		return result, nil
+	}
+
+	result.Applied = true
+	for _, p := range products {
+		created, updated, err := uc.applyProduct(ctx, p)
+		if err != nil {
+			if !isCatalogRowError(err) {
+				return nil, err
+			}
+			result.Errors = append(result.Errors, newCatalogRowError(p.row, []string{err.Error()}))
+			continue
+		}
+		countCatalogChange(&result.Products, created, updated)
+	}
+	for _, p := range promotions {
+		created, updated, err := uc.applyPromotion(ctx, p)
+		if err != nil {
+			if !isCatalogRowError(err) {
+				return nil, err
+			}
+			result.Errors = append(result.Errors, newCatalogRowError(p.row, []string{err.Error()}))
+			continue
+		}
+		countCatalogChange(&result.Promotions, created, updated)
+	}
+	return result, nil
+}
+
+func (uc *catalogUsecase) Export(ctx context.Context, w io.Writer) error {
+	// read every product before writing, so failed export does not write partial file
+	var rows []*entity.CatalogRow
+	for offset := 0; ; offset += MaxPageLimit {
+		products, total, err := uc.productRepo.GetProducts(ctx, MaxPageLimit, offset)
+		if err != nil {
+			return entity.NewInternalError(err)
+		}
+		if len(products) == 0 {
+			break
+		}
+		productRows, err := uc.exportProducts(ctx, products)
+		if err != nil {
+			return err
+		}
+		rows = append(rows, productRows...)
+		if int64(offset+len(products)) >= total {
+			break
+		}
+	}
+
+	err := writeCatalogCSV(w, rows)
+	if err != nil {
+		return entity.NewInternalError(err)
+	}
+	return nil
+}
+
+// validate rows against existing products and promotion rules, then plan the change of every row
+func (uc *catalogUsecase) plan(ctx context.Context, rows []*entity.CatalogRow) ([]*catalogProduct, []*catalogPromotion, []*entity.CatalogRowError, error) {
+	if len(rows) == 0 {
+		return nil, nil, nil, nil
+	}
+
+	// get existing products with stock
+	var serials []string
+	for _, row := range rows {
+		serials = append(serials, row.Serial)
+		if row.PromoProductSerial != "" {
+			serials = append(serials, row.PromoProductSerial)
+		}
+	}
+	existingProducts, err := uc.productRepo.GetProductBySerials(ctx, serials)
+	if err != nil {
+		return nil, nil, nil, entity.NewInternalError(err)
+	}
+	// map[string] = serial, int64 = product id, product created by the import has negative line number
+	mapProductID := make(map[string]int64)
+	mapExisting := make(map[string]*entity.Product)
+	var productIDs []int64
+	for _, product := range existingProducts {
+		mapExisting[product.Serial] = product
+		mapProductID[product.Serial] = product.ID
+		productIDs = append(productIDs, product.ID)
+	}
+	for _, row := range rows {
+		if _, ok := mapProductID[row.Serial]; !ok {
+			mapProductID[row.Serial] = -int64(row.Line)
+		}
+	}
+	mapQuantity := make(map[int64]int)
+	if len(productIDs) > 0 {
+		quantities, err := uc.productRepo.GetProductQuantityByIDs(ctx, productIDs)
+		if err != nil {
+			return nil, nil, nil, entity.NewInternalError(err)
+		}
+		for _, qty := range quantities {
+			mapQuantity[qty.ProductID] = qty.Quantity
+		}
+	}
+
+	// map[string] = serial
+	mapProduct := make(map[string]*catalogProduct)
+	// map[string] = serial and promotion type
+	mapPromotion := make(map[string]*catalogPromotion)
+	var products []*catalogProduct
+	var promotions []*catalogPromotion
+	var rowErrors []*entity.CatalogRowError
+	for _, row := range rows {
+		var problems []string
+		product, ok := mapProduct[row.Serial]
+		if !ok {
+			product = &catalogProduct{row: row, existing: mapExisting[row.Serial]}
+			if product.existing != nil {
+				product.quantity = mapQuantity[product.existing.ID]
+			}
+			mapProduct[row.Serial] = product
+			products = append(products, product)
+		} else if product.row.Name != row.Name || product.row.Price != row.Price ||
+			product.row.TaxClass != row.TaxClass || product.row.Quantity != row.Quantity {
+			problems = append(problems, fmt.Sprintf("product columns differ from line %d", product.row.Line))
+		}
+
+		if row.PromoType != entity.UndefinedType {
+			key := fmt.Sprintf("%s:%d", row.Serial, row.PromoType)
+			promoProductID, promoProblems := uc.validatePromotion(row, mapProductID)
+			if len(promoProblems) > 0 {
+				problems = append(problems, promoProblems...)
+			} else if existing, ok := mapPromotion[key]; ok {
+				problems = append(problems, fmt.Sprintf("promotion type %d is repeated from line %d", row.PromoType, existing.row.Line))
+			} else {
+				promotion := &catalogPromotion{row: row, promoProductID: promoProductID}
+				mapPromotion[key] = promotion
+				promotions = append(promotions, promotion)
+			}
+		}
+
+		if len(problems) > 0 {
+			rowErrors = append(rowErrors, newCatalogRowError(row, problems))
+		}
+	}
+
+	// match promotions of existing product by type
+	mapUpcoming := make(map[int64][]*entity.Promotion)
+	for _, promotion := range promotions {
+		product := mapProduct[promotion.row.Serial].existing
+		if product == nil {
+			continue
+		}
+		upcoming, ok := mapUpcoming[product.ID]
+		if !ok {
+			upcoming, err = uc.promoRepo.GetUpcomingPromotionsByProduct(ctx, product.ID)
+			if err != nil {
+				return nil, nil, nil, entity.NewInternalError(err)
+			}
+			sortPromotionsByID(upcoming)
+			mapUpcoming[product.ID] = upcoming
+		}
+		for _, promo := range upcoming {
+			if promo.Type == promotion.row.PromoType {
+				promotion.existing = promo
+				break
+			}
+		}
+	}
+	return products, promotions, rowErrors, nil
+}
+
+// validate promotion columns by its rule, promo product may be created by another row.
+// Return promo product id and problems of the row
+func (uc *catalogUsecase) validatePromotion(row *entity.CatalogRow, mapProductID map[string]int64) (int64, []string) {
+	promo := &entity.Promotion{
+		Type:          row.PromoType,
+		MatchQuantity: row.PromoMatchQuantity,
+		PromoValue:    row.PromoValue,
+	}
+	if row.PromoProductSerial != "" {
+		promoProductID, ok := mapProductID[row.PromoProductSerial]
+		if !ok {
+			return 0, []string{fmt.Sprintf("promoProductSerial %s is not found", row.PromoProductSerial)}
+		}
+		promo.PromoProductID = promoProductID
+	}
+
+	rule, ok := uc.promoRules[row.PromoType]
+	if !ok {
+		return promo.PromoProductID, []string{entity.InvalidPromotionType}
+	}
+	if validator, ok := rule.(PromotionRuleValidator); ok {
+		if err := validator.Validate(promo); err != nil {
+			return promo.PromoProductID, []string{err.Error()}
+		}
+	}
+	return promo.PromoProductID, nil
+}
+
+// create or update product and set its stock, return if product is created or updated
+func (uc *catalogUsecase) applyProduct(ctx context.Context, p *catalogProduct) (bool, bool, error) {
+	if p.existing == nil {
+		product := &entity.Product{
+			Serial:   p.row.Serial,
+			Name:     p.row.Name,
+			Price:    p.row.Price,
+			TaxClass: p.row.TaxClass,
+		}
+		if product.TaxClass == "" {
+			product.TaxClass = entity.DefaultTaxClass
+		}
+		// repository already return entity.Err
+		err := uc.productRepo.CreateProduct(ctx, product, p.row.Quantity)
+		if err != nil {
+			return false, false, err
+		}
+		return true, false, nil
+	}
+
+	updated := false
+	if p.productChanged() {
+		product := *p.existing
+		product.Name = p.row.Name
+		product.Price = p.row.Price
+		if p.row.TaxClass != "" {
+			product.TaxClass = p.row.TaxClass
+		}
+		err := uc.productRepo.UpdateProduct(ctx, &product)
+		if err != nil {
+			return false, false, entity.NewInternalError(err)
+		}
+		updated = true
+	}
+	// stock is set even if it looks unchanged, checkout may change it after planning
+	productQuantity, err := uc.inventoryRepo.SetStock(ctx, p.existing.ID, p.row.Quantity, catalogImportReference)
+	if err != nil {
+		return false, false, entity.NewInternalError(err)
+	}
+	return false, updated || productQuantity.Quantity != p.quantity, nil
+}
+
+// create or update promotion, it is validated again with free item rules of stored promotions
+func (uc *catalogUsecase) applyPromotion(ctx context.Context, p *catalogPromotion) (bool, bool, error) {
+	payload := &entity.PromotionDetail{
+		Promotion: &entity.Promotion{Type: p.row.PromoType},
+		Product:   &entity.Product{Serial: p.row.Serial},
+	}
+	if p.existing != nil {
+		if !p.changed() {
+			return false, false, nil
+		}
+		// keep priority, stacking and period of existing promotion
+		promo := *p.existing
+		payload.Promotion = &promo
+	}
+	payload.MatchQuantity = p.row.PromoMatchQuantity
+	payload.PromoValue = p.row.PromoValue
+	if p.row.PromoProductSerial != "" {
+		payload.PromoProduct = &entity.Product{Serial: p.row.PromoProductSerial}
+	}
+
+	if p.existing == nil {
+		_, err := uc.promoUC.Create(ctx, payload)
+		return err == nil, false, err
+	}
+	_, err := uc.promoUC.Update(ctx, payload)
+	return false, err == nil, err
+}
+
+// get catalog rows of products, one row for each upcoming promotion
+func (uc *catalogUsecase) exportProducts(ctx context.Context, products []*entity.Product) ([]*entity.CatalogRow, error) {
+	var productIDs []int64
+	for _, product := range products {
+		productIDs = append(productIDs, product.ID)
+	}
+	quantities, err := uc.productRepo.GetProductQuantityByIDs(ctx, productIDs)
+	if err != nil {
+		return nil, entity.NewInternalError(err)
+	}
+	mapQuantity := make(map[int64]int)
+	for _, qty := range quantities {
+		mapQuantity[qty.ProductID] = qty.Quantity
+	}
+
+	// map[int64] = product id
+	mapPromotions := make(map[int64][]*entity.Promotion)
+	var promoProductIDs []int64
+	for _, product := range products {
+		promotions, err := uc.promoRepo.GetUpcomingPromotionsByProduct(ctx, product.ID)
+		if err != nil {
+			return nil, entity.NewInternalError(err)
+		}
+		sortPromotionsByID(promotions)
+		mapPromotions[product.ID] = promotions
+		for _, promo := range promotions {
+			if promo.PromoProductID != 0 {
+				promoProductIDs = append(promoProductIDs, promo.PromoProductID)
+			}
+		}
+	}
+	mapPromoProduct := make(map[int64]*entity.Product)
+	if len(promoProductIDs) > 0 {
+		promoProducts, err := uc.productRepo.GetProductByIDsWithDeleted(ctx, promoProductIDs)
+		if err != nil {
+			return nil, entity.NewInternalError(err)
+		}
+		for _, product := range promoProducts {
+			mapPromoProduct[product.ID] = product
+		}
+	}
+
+	var result []*entity.CatalogRow
+	for _, product := range products {
+		row := entity.CatalogRow{
+			Serial:   product.Serial,
+			Name:     product.Name,
+			Price:    product.Price,
+			TaxClass: product.TaxClass,
+			Quantity: mapQuantity[product.ID],
+		}
+		if len(mapPromotions[product.ID]) == 0 {
+			result = append(result, &row)
+			continue
+		}
+		for _, promo := range mapPromotions[product.ID] {
+			promoRow := row
+			promoRow.PromoType = promo.Type
+			promoRow.PromoMatchQuantity = promo.MatchQuantity
+			promoRow.PromoValue = promo.PromoValue
+			if promoProduct, ok := mapPromoProduct[promo.PromoProductID]; ok {
+				promoRow.PromoProductSerial = promoProduct.Serial
+			}
+			result = append(result, &promoRow)
+		}
+	}
+	return result, nil
+}
+
+func countCatalogChange(count *entity.CatalogImportCount, created, updated bool) {
+	switch {
+	case created:
+		count.Created++
+	case updated:
+		count.Updated++
+	default:
+		count.Unchanged++
+	}
+}
+
+// error caused by the row data is reported with the row, other errors stop the import
+func isCatalogRowError(err error) bool {
+	entityError, ok := err.(entity.Err)
+	return ok && entityError.GetCode() < http.StatusInternalServerError && entityError.GetMessage() != entity.LockTimeout
+}
+
+func sortPromotionsByID(promotions []*entity.Promotion) {
+	sort.Slice(promotions, func(i, j int) bool { return promotions[i].ID < promotions[j].ID })
+}
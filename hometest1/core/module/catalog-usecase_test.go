@@ -0,0 +1,152 @@
+package module_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"hometest1/core/entity"
+	"hometest1/core/module"
+	repomocks "hometest1/core/repository/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func initCatalogUC(ctrl *gomock.Controller) (module.CatalogUsecase, *repomocks.MockProductRepo, *repomocks.MockInventoryRepo, *repomocks.MockPromotionRepo) {
+	productRepo := repomocks.NewMockProductRepo(ctrl)
+	inventoryRepo := repomocks.NewMockInventoryRepo(ctrl)
+	promoRepo := repomocks.NewMockPromotionRepo(ctrl)
+	promoRules := module.DefaultPromotionRules()
+	promoUC := module.NewPromotionUsecase(promoRepo, productRepo, promoRules)
+	return module.NewCatalogUsecase(productRepo, inventoryRepo, promoRepo, promoUC, promoRules), productRepo, inventoryRepo, promoRepo
+}
+
+func Test_CatalogImport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+
+	svc, productRepo, inventoryRepo, promoRepo := initCatalogUC(ctrl)
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+	macbook := &entity.Product{ID: 2, Serial: "43N23P", Name: "MacBook Pro", Price: entity.NewMoney(539999), TaxClass: entity.DefaultTaxClass, UpdatedAt: dayCreated}
+	discount := &entity.Promotion{ID: 3, Type: entity.DiscountInPercent, ProductID: 2, MatchQuantity: 3, PromoValue: 10}
+	// macbook gets lower price and stock, raspberry is created as its free item
+	csv := "serial,name,price,quantity,promoType,promoMatchQuantity,promoValue,promoProductSerial\n" +
+		"43N23P,MacBook Pro,5299.99,8,1,1,1,234234\n" +
+		"43N23P,MacBook Pro,5299.99,8,3,3,10\n" +
+		"234234,Raspberry Pi B,30.00,2\n"
+	expectPlan := func() {
+		productRepo.EXPECT().GetProductBySerials(ctx, []string{"43N23P", "234234", "43N23P", "234234"}).Return([]*entity.Product{macbook}, nil).Times(1)
+		productRepo.EXPECT().GetProductQuantityByIDs(ctx, []int64{2}).Return([]*entity.ProductQuantity{{ProductID: 2, Quantity: 5}}, nil).Times(1)
+		promoRepo.EXPECT().GetUpcomingPromotionsByProduct(ctx, int64(2)).Return([]*entity.Promotion{discount}, nil).Times(1)
+	}
+
+	t.Run("positive, dry run", func(t *testing.T) {
+		expectPlan()
+
+		resp, err := svc.Import(ctx, strings.NewReader(csv), true)
+		assert.Nil(t, err)
+		assert.Equal(t, &entity.CatalogImport{
+			DryRun:     true,
+			Rows:       3,
+			Products:   entity.CatalogImportCount{Created: 1, Updated: 1},
+			Promotions: entity.CatalogImportCount{Created: 1, Unchanged: 1},
+		}, resp)
+	})
+
+	t.Run("positive", func(t *testing.T) {
+		expectPlan()
+		updated := *macbook
+		updated.Price = entity.NewMoney(529999)
+		productRepo.EXPECT().UpdateProduct(ctx, &updated).Return(nil).Times(1)
+		inventoryRepo.EXPECT().SetStock(ctx, int64(2), 8, "catalog import").Return(&entity.ProductQuantity{ProductID: 2, Quantity: 8}, nil).Times(1)
+		productRepo.EXPECT().CreateProduct(ctx, &entity.Product{Serial: "234234", Name: "Raspberry Pi B", Price: entity.NewMoney(3000), TaxClass: entity.DefaultTaxClass}, 2).
+			DoAndReturn(func(_ context.Context, product *entity.Product, _ int) error {
+				product.ID = 4
+				return nil
+			}).Times(1)
+		// free item promotion is validated by promotion usecase
+		raspberry := &entity.Product{ID: 4, Serial: "234234", Name: "Raspberry Pi B", Price: entity.NewMoney(3000), TaxClass: entity.DefaultTaxClass}
+		productRepo.EXPECT().GetProductBySerials(ctx, []string{"43N23P", "234234"}).Return([]*entity.Product{&updated, raspberry}, nil).Times(1)
+		promoRepo.EXPECT().GetFreeItemPromotions(ctx).Return(nil, nil).Times(1)
+		promoRepo.EXPECT().GetUpcomingPromotionsByProduct(ctx, int64(4)).Return(nil, nil).Times(1)
+		promoRepo.EXPECT().CreatePromotion(ctx, &entity.Promotion{Type: entity.BonusItem, ProductID: 2, MatchQuantity: 1, PromoValue: 1, PromoProductID: 4}).Return(nil).Times(1)
+
+		resp, err := svc.Import(ctx, strings.NewReader(csv), false)
+		assert.Nil(t, err)
+		assert.Equal(t, &entity.CatalogImport{
+			Applied:    true,
+			Rows:       3,
+			Products:   entity.CatalogImportCount{Created: 1, Updated: 1},
+			Promotions: entity.CatalogImportCount{Created: 1, Unchanged: 1},
+		}, resp)
+	})
+
+	t.Run("negative, invalid rows are reported and nothing is written", func(t *testing.T) {
+		csv := "Serial,Name,Price,Quantity,PromoType,PromoMatchQuantity,PromoValue,PromoProductSerial\n" +
+			"A-1,Product A,abc,1\n" +
+			"A-2,Product B,10.00,1,3,3,10\n" +
+			"A-2,Product B,10.00,1,3,3,20\n" +
+			"A-3,Product C,10.00,1,1,1,1,XXX\n" +
+			"A-3,Product C,12.00,1\n" +
+			"A-4,Product D,10.00,1,3,1,0\n"
+		productRepo.EXPECT().GetProductBySerials(ctx, []string{"A-2", "A-2", "A-3", "XXX", "A-3", "A-4"}).Return(nil, nil).Times(1)
+
+		resp, err := svc.Import(ctx, strings.NewReader(csv), false)
+		assert.Nil(t, err)
+		assert.Equal(t, &entity.CatalogImport{
+			Rows:       6,
+			Products:   entity.CatalogImportCount{Created: 3},
+			Promotions: entity.CatalogImportCount{Created: 1},
+			Errors: []*entity.CatalogRowError{
+				{Line: 2, Serial: "A-1", Message: "price is invalid"},
+				{Line: 4, Serial: "A-2", Message: "promotion type 3 is repeated from line 3"},
+				{Line: 5, Serial: "A-3", Message: "promoProductSerial XXX is not found"},
+				{Line: 6, Serial: "A-3", Message: "product columns differ from line 5"},
+				{Line: 7, Serial: "A-4", Message: "discount percent must be between 1 and 100"},
+			},
+		}, resp)
+	})
+
+	t.Run("negative, invalid header", func(t *testing.T) {
+		_, err := svc.Import(ctx, strings.NewReader("serial,name,price,stock\n"), false)
+		assert.Equal(t, entity.NewError(`invalid catalog csv: unknown column "stock"`, http.StatusBadRequest), err)
+
+		_, err = svc.Import(ctx, strings.NewReader("serial,name,price\n"), false)
+		assert.Equal(t, entity.NewError("invalid catalog csv: column quantity is required", http.StatusBadRequest), err)
+	})
+}
+
+func Test_CatalogExport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+
+	svc, productRepo, _, promoRepo := initCatalogUC(ctrl)
+	raspberry := &entity.Product{ID: 4, Serial: "234234", Name: "Raspberry Pi B", Price: entity.NewMoney(3000), TaxClass: entity.DefaultTaxClass}
+	macbook := &entity.Product{ID: 2, Serial: "43N23P", Name: "MacBook Pro", Price: entity.NewMoney(539999), TaxClass: entity.DefaultTaxClass}
+
+	t.Run("positive, one row for each promotion", func(t *testing.T) {
+		productRepo.EXPECT().GetProducts(ctx, 100, 0).Return([]*entity.Product{raspberry, macbook}, int64(2), nil).Times(1)
+		productRepo.EXPECT().GetProductQuantityByIDs(ctx, []int64{4, 2}).
+			Return([]*entity.ProductQuantity{{ProductID: 4, Quantity: 2}, {ProductID: 2, Quantity: 5}}, nil).Times(1)
+		promoRepo.EXPECT().GetUpcomingPromotionsByProduct(ctx, int64(4)).Return(nil, nil).Times(1)
+		promoRepo.EXPECT().GetUpcomingPromotionsByProduct(ctx, int64(2)).Return([]*entity.Promotion{
+			{ID: 3, Type: entity.DiscountInPercent, ProductID: 2, MatchQuantity: 3, PromoValue: 10},
+			{ID: 1, Type: entity.BonusItem, ProductID: 2, MatchQuantity: 1, PromoValue: 1, PromoProductID: 4},
+		}, nil).Times(1)
+		productRepo.EXPECT().GetProductByIDsWithDeleted(ctx, []int64{4}).Return([]*entity.Product{raspberry}, nil).Times(1)
+
+		var buf bytes.Buffer
+		err := svc.Export(ctx, &buf)
+		assert.Nil(t, err)
+		assert.Equal(t, "serial,name,price,taxClass,quantity,promoType,promoMatchQuantity,promoValue,promoProductSerial\n"+
+			"234234,Raspberry Pi B,30.00,standard,2,,,,\n"+
+			"43N23P,MacBook Pro,5399.99,standard,5,1,1,1,234234\n"+
+			"43N23P,MacBook Pro,5399.99,standard,5,3,3,10,\n", buf.String())
+	})
+}
@@ -1,177 +1,425 @@
 package module
 
 import (
+	"context"
+	"fmt"
+	"log"
 	"net/http"
+	"time"
 
 	"hometest1/core/entity"
 	"hometest1/core/repository"
 )
 
+// Unknown product serials in payload is rejected with entity.UnknownSerialsErr.
+// On lenient mode, checkout is proceeded with known products and unknown serials are reported in the result.
+// Coupon codes unlock their cart promotions, unknown or expired coupon is rejected
 type CheckoutUsecase interface {
-	Submit(payload entity.MapProductSerialQuantity) (*entity.Checkout, error)
+	Submit(ctx context.Context, payload entity.MapProductSerialQuantity, options entity.CheckoutOptions) (*entity.Checkout, error)
+	// calculate checkout price and stock availability without submit to database
+	Quote(ctx context.Context, payload entity.MapProductSerialQuantity, options entity.CheckoutOptions) (*entity.CheckoutQuote, error)
+	// render checkout of the given products with promotions, bundles and cart promotions applied to the order,
+	// including the ones that are deleted or expired. Product price is used as it is, eg: unit price of the order
+	Reprice(ctx context.Context, products []*entity.Product, payload entity.MapProductSerialQuantity, order *entity.Order) (*entity.Checkout, error)
 }
 
 type checkoutUsecase struct {
 	productRepo repository.ProductRepo
 	promoRepo   repository.PromotionRepo
+	// checkout has no tax if tax repository is not set
+	taxRepo    repository.TaxRepo
+	promoRules PromotionRules
+	clock      entity.Clock
+	// stock alert is not checked if it is not set
+	stockAlertUC StockAlertUsecase
 }
 
 func NewCheckoutUsecase(productRepo repository.ProductRepo, promoRepo repository.PromotionRepo) CheckoutUsecase {
-	return &checkoutUsecase{productRepo, promoRepo}
+	return NewCheckoutUsecaseWithRules(productRepo, promoRepo, DefaultPromotionRules())
 }
 
-func (uc *checkoutUsecase) Submit(payload entity.MapProductSerialQuantity) (*entity.Checkout, error) {
-	// get products
-	products, err := uc.productRepo.GetProductBySerials(payload.PluckSerial())
+// create checkout usecase with custom promotion rules registry
+func NewCheckoutUsecaseWithRules(productRepo repository.ProductRepo, promoRepo repository.PromotionRepo, promoRules PromotionRules) CheckoutUsecase {
+	return &checkoutUsecase{productRepo, promoRepo, nil, promoRules, time.Now, nil}
+}
+
+// create checkout usecase with custom promotion rules registry, tax is calculated by rates of checkout region
+func NewCheckoutUsecaseWithTax(productRepo repository.ProductRepo, promoRepo repository.PromotionRepo, promoRules PromotionRules, taxRepo repository.TaxRepo) CheckoutUsecase {
+	return &checkoutUsecase{productRepo, promoRepo, taxRepo, promoRules, time.Now, nil}
+}
+
+// create checkout usecase with tax, submitted checkout notifies products whose stock reaches reorder threshold
+func NewCheckoutUsecaseWithStockAlert(productRepo repository.ProductRepo, promoRepo repository.PromotionRepo, promoRules PromotionRules, taxRepo repository.TaxRepo, stockAlertUC StockAlertUsecase) CheckoutUsecase {
+	return &checkoutUsecase{productRepo, promoRepo, taxRepo, promoRules, time.Now, stockAlertUC}
+}
+
+func (uc *checkoutUsecase) Submit(ctx context.Context, payload entity.MapProductSerialQuantity, options entity.CheckoutOptions) (*entity.Checkout, error) {
+	// render checkout
+	checkout, err := uc.prepareCheckout(ctx, payload, options)
+	if err != nil {
+		return nil, err
+	}
+
+	// submit checkout to database
+	err = uc.productRepo.SubmitCheckout(ctx, checkout)
+	if err != nil {
+		// repository must handle error with entity.Err
+		return nil, err
+	}
+
+	// order is already placed, failed stock alert does not fail the checkout
+	if uc.stockAlertUC != nil {
+		_, err = uc.stockAlertUC.CheckCheckout(ctx, checkout)
+		if err != nil {
+			log.Printf("Error checking stock alert of order %d: %s", checkout.OrderID, err.Error())
+		}
+	}
+	return checkout, nil
+}
+
+func (uc *checkoutUsecase) Quote(ctx context.Context, payload entity.MapProductSerialQuantity, options entity.CheckoutOptions) (*entity.CheckoutQuote, error) {
+	// render checkout
+	checkout, err := uc.prepareCheckout(ctx, payload, options)
 	if err != nil {
-		return nil, entity.NewError(err.Error(), http.StatusInternalServerError)
+		return nil, err
+	}
+
+	// get current stock minus stock held by reservations, without locking
+	var productIDs []int64
+	for _, item := range checkout.Items {
+		productIDs = append(productIDs, item.Product.ID)
+	}
+	quantities, err := uc.productRepo.GetAvailableQuantityByIDs(ctx, productIDs)
+	if err != nil {
+		return nil, entity.NewInternalError(err)
+	}
+
+	result := entity.CheckoutQuote{
+		Checkout:          checkout,
+		AvailableQuantity: make(map[int64]int),
+	}
+	for _, qty := range quantities {
+		result.AvailableQuantity[qty.ProductID] = qty.Quantity
 	}
+
+	return &result, nil
+}
+
+func (uc *checkoutUsecase) Reprice(ctx context.Context, products []*entity.Product, payload entity.MapProductSerialQuantity, order *entity.Order) (*entity.Checkout, error) {
 	if len(products) == 0 {
 		return nil, entity.NewError(entity.ProductNotFound, http.StatusBadRequest)
 	}
 
-	// get promotions
-	promotionMaps, err := uc.promoRepo.GetPromotionByProducts(products)
+	promotionMaps, bundles, cartPromotions, err := uc.getOrderPromotions(ctx, order)
+	if err != nil {
+		return nil, err
+	}
+	return uc.generateCheckout(ctx, payload, products, promotionMaps, bundles, cartPromotions)
+}
+
+// get products and promotions, then render the checkout
+func (uc *checkoutUsecase) prepareCheckout(ctx context.Context, payload entity.MapProductSerialQuantity, options entity.CheckoutOptions) (*entity.Checkout, error) {
+	// get products
+	products, err := uc.productRepo.GetProductBySerials(ctx, payload.PluckSerial())
 	if err != nil {
-		return nil, entity.NewError(err.Error(), http.StatusInternalServerError)
+		return nil, entity.NewInternalError(err)
 	}
 
-	// render checkout
-	checkout, err := uc.generateCheckout(payload, products, promotionMaps)
+	// validate unknown serials, lenient mode still needs at least one product
+	unknownSerials := payload.UnknownSerials(products)
+	if len(unknownSerials) > 0 && (!options.Lenient || len(products) == 0) {
+		return nil, entity.NewUnknownSerialsError(unknownSerials, http.StatusBadRequest)
+	}
+	if len(products) == 0 {
+		return nil, entity.NewError(entity.ProductNotFound, http.StatusBadRequest)
+	}
+
+	promotionMaps, bundles, err := uc.getPromotions(ctx, products)
 	if err != nil {
 		return nil, err
 	}
 
-	// submit checkout to database
-	err = uc.productRepo.SubmitCheckout(checkout)
+	// get coupons, then cart promotions including the ones unlocked by coupons
+	coupons, err := uc.getCoupons(ctx, options)
 	if err != nil {
-		// repository must handle error with entity.Err
 		return nil, err
 	}
+	var couponPromotionIDs []int64
+	for _, coupon := range coupons {
+		couponPromotionIDs = append(couponPromotionIDs, coupon.CartPromotionID)
+	}
+	cartPromotions, err := uc.promoRepo.GetActiveCartPromotions(ctx, couponPromotionIDs)
+	if err != nil {
+		return nil, entity.NewInternalError(err)
+	}
 
+	checkout, err := uc.generateCheckout(ctx, payload, products, promotionMaps, bundles, cartPromotions)
+	if err != nil {
+		return nil, err
+	}
+	checkout.UnknownSerials = unknownSerials
+	checkout.CustomerID = options.CustomerID
+	checkout.ReservationID = options.ReservationID
+	checkout.CartID = options.CartID
+	uc.setCouponDiscounts(checkout, coupons)
+
+	// tax is calculated from price after promotions
+	err = uc.applyTax(ctx, checkout, options.TaxRegion)
+	if err != nil {
+		return nil, err
+	}
 	return checkout, nil
 }
 
-func (uc *checkoutUsecase) generateCheckout(mapQuantity entity.MapProductSerialQuantity, products []*entity.Product, promotionMaps map[int64][]*entity.Promotion) (*entity.Checkout, error) {
+// get promotions and bundles of the products
+func (uc *checkoutUsecase) getPromotions(ctx context.Context, products []*entity.Product) (map[int64][]*entity.Promotion, []*entity.Bundle, error) {
+	promotionMaps, err := uc.promoRepo.GetPromotionByProducts(ctx, products)
+	if err != nil {
+		return nil, nil, entity.NewInternalError(err)
+	}
+
+	var productIDs []int64
+	for _, product := range products {
+		productIDs = append(productIDs, product.ID)
+	}
+	bundles, err := uc.promoRepo.GetActiveBundlesByProducts(ctx, productIDs)
+	if err != nil {
+		return nil, nil, entity.NewInternalError(err)
+	}
+	return promotionMaps, bundles, nil
+}
+
+// get promotions, bundles and cart promotions applied to the order by id, including deleted and expired ones.
+// Order item has its last promotion and bundle, discounts of the order have the others
+func (uc *checkoutUsecase) getOrderPromotions(ctx context.Context, order *entity.Order) (map[int64][]*entity.Promotion, []*entity.Bundle, []*entity.CartPromotion, error) {
+	ids := make(map[entity.PromotionSource][]int64)
+	seen := make(map[entity.PromotionSource]map[int64]bool)
+	add := func(source entity.PromotionSource, id int64) {
+		if id == 0 || seen[source][id] {
+			return
+		}
+		if seen[source] == nil {
+			seen[source] = make(map[int64]bool)
+		}
+		seen[source][id] = true
+		ids[source] = append(ids[source], id)
+	}
+	for _, item := range order.Items {
+		add(entity.SourcePromotion, item.PromotionID)
+		add(entity.SourceBundle, item.BundleID)
+	}
+	for _, discount := range order.Discounts {
+		add(discount.Source, discount.SourceID)
+	}
+
+	promotionMaps := make(map[int64][]*entity.Promotion)
+	if len(ids[entity.SourcePromotion]) > 0 {
+		promotions, err := uc.promoRepo.GetPromotionByIDsWithDeleted(ctx, ids[entity.SourcePromotion])
+		if err != nil {
+			return nil, nil, nil, entity.NewInternalError(err)
+		}
+		for _, promo := range promotions {
+			promotionMaps[promo.ProductID] = append(promotionMaps[promo.ProductID], promo)
+		}
+	}
+
+	var bundles []*entity.Bundle
+	if len(ids[entity.SourceBundle]) > 0 {
+		var err error
+		bundles, err = uc.promoRepo.GetBundleByIDsWithDeleted(ctx, ids[entity.SourceBundle])
+		if err != nil {
+			return nil, nil, nil, entity.NewInternalError(err)
+		}
+	}
+
+	var cartPromotions []*entity.CartPromotion
+	if len(ids[entity.SourceCartPromotion]) > 0 {
+		var err error
+		cartPromotions, err = uc.promoRepo.GetCartPromotionByIDsWithDeleted(ctx, ids[entity.SourceCartPromotion])
+		if err != nil {
+			return nil, nil, nil, entity.NewInternalError(err)
+		}
+	}
+	return promotionMaps, bundles, cartPromotions, nil
+}
+
+// get and validate coupons of the checkout options
+func (uc *checkoutUsecase) getCoupons(ctx context.Context, options entity.CheckoutOptions) ([]*entity.Coupon, error) {
+	var codes []string
+	seen := make(map[string]bool)
+	for _, code := range options.CouponCodes {
+		code = entity.NormalizeCouponCode(code)
+		if code == "" || seen[code] {
+			continue
+		}
+		seen[code] = true
+		codes = append(codes, code)
+	}
+	if len(codes) == 0 {
+		return nil, nil
+	}
+
+	coupons, err := uc.promoRepo.GetCouponsByCodes(ctx, codes)
+	if err != nil {
+		return nil, entity.NewInternalError(err)
+	}
+	mapCoupon := make(map[string]*entity.Coupon)
+	for _, coupon := range coupons {
+		mapCoupon[coupon.Code] = coupon
+	}
+
+	// keep the order of submitted codes
+	var result []*entity.Coupon
+	now := uc.clock()
+	for _, code := range codes {
+		coupon, ok := mapCoupon[code]
+		if !ok {
+			return nil, entity.NewError(fmt.Sprintf("%s: %s", entity.CouponNotFound, code), http.StatusBadRequest)
+		}
+		if coupon.IsExpired(now) {
+			return nil, entity.NewError(fmt.Sprintf("%s: %s", entity.CouponExpired, code), http.StatusBadRequest)
+		}
+		// redemption limits are validated again when checkout is submitted
+		if coupon.IsExhausted() {
+			return nil, entity.NewError(fmt.Sprintf("%s: %s", entity.CouponExhausted, code), http.StatusBadRequest)
+		}
+		if coupon.PerCustomerLimit > 0 && options.CustomerID == "" {
+			return nil, entity.NewError(fmt.Sprintf("%s: %s", entity.CustomerRequired, code), http.StatusBadRequest)
+		}
+		result = append(result, coupon)
+	}
+	return result, nil
+}
+
+// mark discount lines unlocked by coupons, only coupon of applied discount is redeemed
+func (uc *checkoutUsecase) setCouponDiscounts(checkout *entity.Checkout, coupons []*entity.Coupon) {
+	for _, discount := range checkout.Discounts {
+		for _, coupon := range coupons {
+			if coupon.CartPromotionID != discount.CartPromotionID {
+				continue
+			}
+			discount.CouponCode = coupon.Code
+			checkout.Coupons = append(checkout.Coupons, coupon)
+			break
+		}
+	}
+}
+
+func (uc *checkoutUsecase) generateCheckout(ctx context.Context, mapQuantity entity.MapProductSerialQuantity, products []*entity.Product, promotionMaps map[int64][]*entity.Promotion, bundles []*entity.Bundle, cartPromotions []*entity.CartPromotion) (*entity.Checkout, error) {
 	// if product item is free by promo
-	// map[int64] = product id, int = number available free items
-	var freeProductItem map[int64]int
+	freeProductItem := make(FreeProductItems)
 
-	var result entity.Checkout
+	result := entity.Checkout{TotalPrice: entity.NewMoney(0)}
+	productOf := uc.productLookup(ctx, products)
 
 	// loop products
 	for _, product := range products {
 		var checkoutItem entity.CheckoutItem
 
-		// set quantity
-		qty := mapQuantity[product.Serial]
-
 		// init checkout
+		qty := mapQuantity[product.Serial]
 		checkoutItem.Product = product
 		checkoutItem.Quantity = qty
-		checkoutItem.SubTotalPrice = float64(qty) * product.Price
-
-		for productID, promos := range promotionMaps {
-			if productID == product.ID {
-				for _, promo := range promos {
-					// the repository should sort promotion types in ascending order
-					switch promo.Type {
-					case entity.BonusItem:
-						freeProductItem = uc.handleBonusItemPromotion(qty, promo, freeProductItem)
-					case entity.BuyItemsForReducePrice:
-						checkoutItem.SubTotalPrice = uc.handleReducePricePromotion(qty, product, promo)
-					case entity.DiscountInPercent:
-						checkoutItem.SubTotalPrice = uc.handleDiscountPromotion(qty, checkoutItem.SubTotalPrice, promo)
-					default:
-						continue
-					}
-				}
-			}
+		checkoutItem.SubTotalPrice = product.Price.Mul(qty)
+
+		// the repository should sort promotions by priority
+		err := uc.applyPromotions(&checkoutItem, promotionMaps[product.ID], freeProductItem, productOf)
+		if err != nil {
+			return nil, entity.NewInternalError(err)
 		}
 
 		// set result
 		result.Items = append(result.Items, &checkoutItem)
-		result.TotalItem += qty
-		result.TotalPrice += checkoutItem.SubTotalPrice
+		result.TotalItem += checkoutItem.Quantity
+		result.TotalPrice = result.TotalPrice.Add(checkoutItem.SubTotalPrice)
 	}
 
-	err := uc.handleCheckoutFreeItems(&result, freeProductItem)
+	err := uc.handleCheckoutFreeItems(ctx, &result, freeProductItem)
 	if err != nil {
 		return nil, err
 	}
-	return &result, nil
-}
-
-// This function calculates the free items that will be obtained
-func (uc *checkoutUsecase) handleBonusItemPromotion(quantity int, promo *entity.Promotion, freeProductItem map[int64]int) map[int64]int {
-	// if promo product id empty or no match quantity, no free item for this promo
-	if promo.PromoProductID == 0 || promo.MatchQuantity == 0 || quantity < promo.MatchQuantity {
-		return freeProductItem
-	}
 
-	if freeProductItem == nil {
-		freeProductItem = make(map[int64]int)
+	// describe applied product promotions, and list the ones that are not met
+	err = uc.explainPromotions(&result, mapQuantity, products, promotionMaps, productOf)
+	if err != nil {
+		return nil, entity.NewInternalError(err)
 	}
 
-	// number of free item will user get
-	numOfFreeItems := (quantity / promo.MatchQuantity) * promo.PromoValue
-	freeProductItem[promo.PromoProductID] += numOfFreeItems
-	return freeProductItem
-}
+	// bundles match across items that have no product promotion
+	uc.applyBundles(&result, bundles)
 
-// This function calculates price reductions that apply multiples
-func (uc *checkoutUsecase) handleReducePricePromotion(quantity int, product *entity.Product, promo *entity.Promotion) float64 {
-	// if match quantity empty, return original price
-	if promo.MatchQuantity <= 0 || quantity < promo.MatchQuantity {
-		return product.Price * float64(quantity)
+	// cart promotions are evaluated after product promotions and bundles
+	err = uc.applyCartPromotions(&result, cartPromotions, productOf)
+	if err != nil {
+		return nil, entity.NewInternalError(err)
 	}
-
-	// get item reduction
-	newQuantity := (quantity / promo.MatchQuantity * promo.PromoValue) + (quantity % promo.MatchQuantity)
-	return product.Price * float64(newQuantity)
+	return &result, nil
 }
 
-// This function calculates the discount price
-func (uc *checkoutUsecase) handleDiscountPromotion(quantity int, currentSubTotal float64, promo *entity.Promotion) float64 {
-	// promo value for discount in percent value, only process valid value
-	if promo.PromoValue < 0 || promo.PromoValue > 100 || quantity < promo.MatchQuantity {
-		return currentSubTotal
+// lookup checkout products, other product is read from repository once
+func (uc *checkoutUsecase) productLookup(ctx context.Context, products []*entity.Product) productLookup {
+	mapProduct := make(map[int64]*entity.Product)
+	for _, product := range products {
+		mapProduct[product.ID] = product
 	}
 
-	return currentSubTotal - (currentSubTotal * float64(promo.PromoValue) / float64(100))
+	return func(productID int64) (*entity.Product, error) {
+		if product, ok := mapProduct[productID]; ok {
+			return product, nil
+		}
+		result, err := uc.productRepo.GetProductByIDs(ctx, []int64{productID})
+		if err != nil {
+			return nil, err
+		}
+		// deleted product is cached as nil
+		mapProduct[productID] = nil
+		for _, product := range result {
+			mapProduct[product.ID] = product
+		}
+		return mapProduct[productID], nil
+	}
 }
 
 // This will handle free items obtained through promotions
 // If the item is there, the fee will be deducted, if it is not there it will be added to checkout
-func (uc *checkoutUsecase) handleCheckoutFreeItems(checkout *entity.Checkout, freeProductItem map[int64]int) error {
+func (uc *checkoutUsecase) handleCheckoutFreeItems(ctx context.Context, checkout *entity.Checkout, freeProductItem FreeProductItems) error {
 	// check the item in the existing checkout items list
 	for _, item := range checkout.Items {
-		if freeProductItem[item.Product.ID] > 0 {
+		freeQty := freeProductItem.Get(item.Product.ID)
+		if freeQty > 0 {
 			// If the number of items is less than it should be
-			if item.Quantity < freeProductItem[item.Product.ID] {
+			if item.Quantity < freeQty {
 				// reduce checkout total price for current quantity
-				checkout.TotalPrice -= float64(item.Quantity) * item.Product.Price
+				checkout.TotalPrice = checkout.TotalPrice.Sub(item.Product.Price.Mul(item.Quantity))
 				// add remaining quantity amount
-				checkout.TotalItem += freeProductItem[item.Product.ID] - item.Quantity
-				item.Quantity = freeProductItem[item.Product.ID]
-				item.SubTotalPrice = 0
+				checkout.TotalItem += freeQty - item.Quantity
+				item.Quantity = freeQty
+				item.FreeQuantity = freeQty
+				item.SubTotalPrice = entity.NewMoney(0)
 			} else {
 				// if the free items exceed the total items, only reduce the price of the available free items
-				priceReduction := float64(freeProductItem[item.Product.ID]) * item.Product.Price
-				item.SubTotalPrice = item.SubTotalPrice - priceReduction
+				priceReduction := item.Product.Price.Mul(freeQty)
+				item.SubTotalPrice = item.SubTotalPrice.Sub(priceReduction)
+				item.FreeQuantity += freeQty
 				// reduce the sub total price
-				checkout.TotalPrice -= priceReduction
+				checkout.TotalPrice = checkout.TotalPrice.Sub(priceReduction)
 			}
+			item.PromotionID = freeProductItem[item.Product.ID].PromotionID
+			item.AppliedPromotions = append(item.AppliedPromotions, &entity.AppliedPromotion{
+				Source: entity.SourcePromotion,
+				ID:     item.PromotionID,
+				Saved:  item.Product.Price.Mul(freeQty),
+			})
 
 			// empty free product item
-			freeProductItem[item.Product.ID] = 0
+			delete(freeProductItem, item.Product.ID)
 		}
 	}
 
 	// if freeProductItem still have quantity, add to checkout
 	var productIDs []int64
-	for id, qty := range freeProductItem {
-		if qty == 0 {
+	for id, free := range freeProductItem {
+		if free.Quantity == 0 {
 			continue
 		}
 		productIDs = append(productIDs, id)
@@ -182,22 +430,31 @@ func (uc *checkoutUsecase) handleCheckoutFreeItems(checkout *entity.Checkout, fr
 	}
 
 	// get free product
-	products, err := uc.productRepo.GetProductByIDs(productIDs)
+	products, err := uc.productRepo.GetProductByIDs(ctx, productIDs)
 	if err != nil {
-		return entity.NewError(err.Error(), http.StatusInternalServerError)
+		return entity.NewInternalError(err)
 	}
 
 	// append to checkout
 	for _, product := range products {
+		free := freeProductItem[product.ID]
 		// append new items
 		checkout.Items = append(checkout.Items, &entity.CheckoutItem{
-			Product:  product,
-			Quantity: freeProductItem[product.ID],
+			Product:       product,
+			Quantity:      free.Quantity,
+			SubTotalPrice: entity.NewMoney(0),
+			FreeQuantity:  free.Quantity,
+			PromotionID:   free.PromotionID,
+			AppliedPromotions: []*entity.AppliedPromotion{{
+				Source: entity.SourcePromotion,
+				ID:     free.PromotionID,
+				Saved:  product.Price.Mul(free.Quantity),
+			}},
 		})
 		// append checkout total item
-		checkout.TotalItem += freeProductItem[product.ID]
+		checkout.TotalItem += free.Quantity
 		// empty free product item
-		freeProductItem[product.ID] = 0
+		delete(freeProductItem, product.ID)
 	}
 
 	return nil
@@ -1,6 +1,8 @@
 package module_test
 
 import (
+	"context"
+	"net/http"
 	"testing"
 	"time"
 
@@ -20,18 +22,26 @@ func initCheckoutUC(ctrl *gomock.Controller) (module.CheckoutUsecase, *repomocks
 	return module.NewCheckoutUsecase(productRepo, promoRepo), productRepo, promoRepo
 }
 
+// explain single promotion applied to checkout item
+func appliedPromotion(source entity.PromotionSource, id int64, promoType int, description string, saved int64) []*entity.AppliedPromotion {
+	return []*entity.AppliedPromotion{
+		{Source: source, ID: id, Type: promoType, Description: description, Saved: entity.NewMoney(saved)},
+	}
+}
+
 func Test_Submit(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
+	ctx := context.Background()
 
 	svc, productRepo, promoRepo := initCheckoutUC(ctrl)
 
 	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
 	products := []*entity.Product{
-		{ID: 1, Serial: "120P90", Name: "Google Home", Price: 49.99, UpdatedAt: dayCreated},
-		{ID: 2, Serial: "43N23P", Name: "MacBook Pro", Price: 5399.99, UpdatedAt: dayCreated},
-		{ID: 3, Serial: "A304SD", Name: "Alexa Speaker", Price: 109.50, UpdatedAt: dayCreated},
-		{ID: 4, Serial: "234234", Name: "Raspberry Pi B", Price: 30.00, UpdatedAt: dayCreated},
+		{ID: 1, Serial: "120P90", Name: "Google Home", Price: entity.NewMoney(4999), UpdatedAt: dayCreated},
+		{ID: 2, Serial: "43N23P", Name: "MacBook Pro", Price: entity.NewMoney(539999), UpdatedAt: dayCreated},
+		{ID: 3, Serial: "A304SD", Name: "Alexa Speaker", Price: entity.NewMoney(10950), UpdatedAt: dayCreated},
+		{ID: 4, Serial: "234234", Name: "Raspberry Pi B", Price: entity.NewMoney(3000), UpdatedAt: dayCreated},
 	}
 	promotions := []*entity.Promotion{
 		{ID: 1, Type: 1, ProductID: 2, MatchQuantity: 1, PromoValue: 1, PromoProductID: 4, UpdatedAt: dayCreated},
@@ -41,83 +51,97 @@ func Test_Submit(t *testing.T) {
 
 	t.Run("Scanned Items: MacBook Pro, Raspberry Pi B", func(t *testing.T) {
 		payload := entity.MapProductSerialQuantity{"43N23P": 1, "234234": 1}
-		productRepo.EXPECT().GetProductBySerials(gomock.Any()).Return([]*entity.Product{
+		productRepo.EXPECT().GetProductBySerials(ctx, gomock.Any()).Return([]*entity.Product{
 			products[1], products[3],
 		}, nil).Times(1)
-		promoRepo.EXPECT().GetPromotionByProducts([]*entity.Product{
+		promoRepo.EXPECT().GetPromotionByProducts(ctx, []*entity.Product{
 			products[1], products[3],
 		}).Return(map[int64][]*entity.Promotion{
 			2: {promotions[0]},
 		}, nil).Times(1)
+		promoRepo.EXPECT().GetActiveBundlesByProducts(ctx, gomock.Any()).Return(nil, nil).Times(1)
+		promoRepo.EXPECT().GetActiveCartPromotions(ctx, nil).Return(nil, nil).Times(1)
 
 		checkout := &entity.Checkout{
 			Items: []*entity.CheckoutItem{
 				{
 					Product:       products[1],
 					Quantity:      1,
-					SubTotalPrice: 5399.99,
+					SubTotalPrice: entity.NewMoney(539999),
+					PromotionID:   1,
 				},
 				{
-					Product:       products[3],
-					Quantity:      1,
-					SubTotalPrice: 0,
+					Product:           products[3],
+					Quantity:          1,
+					SubTotalPrice:     entity.NewMoney(0),
+					PromotionID:       1,
+					FreeQuantity:      1,
+					AppliedPromotions: appliedPromotion(entity.SourcePromotion, 1, 1, "buy 1 get 1 Raspberry Pi B free", 3000),
 				},
 			},
 			TotalItem:  2,
-			TotalPrice: 5399.99,
+			TotalPrice: entity.NewMoney(539999),
 		}
-		productRepo.EXPECT().SubmitCheckout(checkout).Return(nil).Times(1)
+		productRepo.EXPECT().SubmitCheckout(ctx, checkout).Return(nil).Times(1)
 
-		resp, err := svc.Submit(payload)
+		resp, err := svc.Submit(ctx, payload, entity.CheckoutOptions{})
 		assert.Nil(t, err)
 		assert.Equal(t, checkout, resp)
 	})
 
 	t.Run("Scanned Items: MacBook Pro, 2 Raspberry Pi B", func(t *testing.T) {
 		payload := entity.MapProductSerialQuantity{"43N23P": 1, "234234": 2}
-		productRepo.EXPECT().GetProductBySerials(gomock.Any()).Return([]*entity.Product{
+		productRepo.EXPECT().GetProductBySerials(ctx, gomock.Any()).Return([]*entity.Product{
 			products[1], products[3],
 		}, nil).Times(1)
-		promoRepo.EXPECT().GetPromotionByProducts([]*entity.Product{
+		promoRepo.EXPECT().GetPromotionByProducts(ctx, []*entity.Product{
 			products[1], products[3],
 		}).Return(map[int64][]*entity.Promotion{
 			2: {promotions[0]},
 		}, nil).Times(1)
+		promoRepo.EXPECT().GetActiveBundlesByProducts(ctx, gomock.Any()).Return(nil, nil).Times(1)
+		promoRepo.EXPECT().GetActiveCartPromotions(ctx, nil).Return(nil, nil).Times(1)
 
 		checkout := &entity.Checkout{
 			Items: []*entity.CheckoutItem{
 				{
 					Product:       products[1],
 					Quantity:      1,
-					SubTotalPrice: 5399.99,
+					SubTotalPrice: entity.NewMoney(539999),
+					PromotionID:   1,
 				},
 				{
-					Product:       products[3],
-					Quantity:      2,
-					SubTotalPrice: 30,
+					Product:           products[3],
+					Quantity:          2,
+					SubTotalPrice:     entity.NewMoney(3000),
+					PromotionID:       1,
+					FreeQuantity:      1,
+					AppliedPromotions: appliedPromotion(entity.SourcePromotion, 1, 1, "buy 1 get 1 Raspberry Pi B free", 3000),
 				},
 			},
 			TotalItem:  3,
-			TotalPrice: 5399.99 + 30,
+			TotalPrice: entity.NewMoney(539999 + 3000),
 		}
-		productRepo.EXPECT().SubmitCheckout(checkout).Return(nil).Times(1)
+		productRepo.EXPECT().SubmitCheckout(ctx, checkout).Return(nil).Times(1)
 
-		resp, err := svc.Submit(payload)
+		resp, err := svc.Submit(ctx, payload, entity.CheckoutOptions{})
 		assert.Nil(t, err)
 		assert.Equal(t, checkout, resp)
 	})
 
 	t.Run("Scanned Items: MacBook Pro, without Raspberry Pi B", func(t *testing.T) {
 		payload := entity.MapProductSerialQuantity{"43N23P": 1}
-		productRepo.EXPECT().GetProductBySerials(gomock.Any()).Return([]*entity.Product{
+		productRepo.EXPECT().GetProductBySerials(ctx, gomock.Any()).Return([]*entity.Product{
 			products[1],
 		}, nil).Times(1)
-		promoRepo.EXPECT().GetPromotionByProducts([]*entity.Product{
+		promoRepo.EXPECT().GetPromotionByProducts(ctx, []*entity.Product{
 			products[1],
 		}).Return(map[int64][]*entity.Promotion{
 			2: {promotions[0]},
 		}, nil).Times(1)
-		productRepo.EXPECT().GetProductByIDs([]int64{4}).Return([]*entity.Product{
+		promoRepo.EXPECT().GetActiveBundlesByProducts(ctx, gomock.Any()).Return(nil, nil).Times(1)
+		promoRepo.EXPECT().GetActiveCartPromotions(ctx, nil).Return(nil, nil).Times(1)
+		productRepo.EXPECT().GetProductByIDs(ctx, []int64{4}).Return([]*entity.Product{
 			products[3],
 		}, nil).Times(1)
 
@@ -126,229 +150,962 @@ func Test_Submit(t *testing.T) {
 				{
 					Product:       products[1],
 					Quantity:      1,
-					SubTotalPrice: 5399.99,
+					SubTotalPrice: entity.NewMoney(539999),
+					PromotionID:   1,
 				},
 				{
-					Product:       products[3],
-					Quantity:      1,
-					SubTotalPrice: 0,
+					Product:           products[3],
+					Quantity:          1,
+					SubTotalPrice:     entity.NewMoney(0),
+					PromotionID:       1,
+					FreeQuantity:      1,
+					AppliedPromotions: appliedPromotion(entity.SourcePromotion, 1, 1, "buy 1 get 1 Raspberry Pi B free", 3000),
 				},
 			},
 			TotalItem:  2,
-			TotalPrice: 5399.99,
+			TotalPrice: entity.NewMoney(539999),
 		}
-		productRepo.EXPECT().SubmitCheckout(checkout).Return(nil).Times(1)
+		productRepo.EXPECT().SubmitCheckout(ctx, checkout).Return(nil).Times(1)
 
-		resp, err := svc.Submit(payload)
+		resp, err := svc.Submit(ctx, payload, entity.CheckoutOptions{})
 		assert.Nil(t, err)
 		assert.Equal(t, checkout, resp)
 	})
 
 	t.Run("Scanned Items: 2 MacBook Pro, 1 Raspberry Pi B", func(t *testing.T) {
 		payload := entity.MapProductSerialQuantity{"43N23P": 2, "234234": 1}
-		productRepo.EXPECT().GetProductBySerials(gomock.Any()).Return([]*entity.Product{
+		productRepo.EXPECT().GetProductBySerials(ctx, gomock.Any()).Return([]*entity.Product{
 			products[1], products[3],
 		}, nil).Times(1)
-		promoRepo.EXPECT().GetPromotionByProducts([]*entity.Product{
+		promoRepo.EXPECT().GetPromotionByProducts(ctx, []*entity.Product{
 			products[1], products[3],
 		}).Return(map[int64][]*entity.Promotion{
 			2: {promotions[0]},
 		}, nil).Times(1)
+		promoRepo.EXPECT().GetActiveBundlesByProducts(ctx, gomock.Any()).Return(nil, nil).Times(1)
+		promoRepo.EXPECT().GetActiveCartPromotions(ctx, nil).Return(nil, nil).Times(1)
 
 		checkout := &entity.Checkout{
 			Items: []*entity.CheckoutItem{
 				{
 					Product:       products[1],
 					Quantity:      2,
-					SubTotalPrice: 5399.99 * 2,
+					SubTotalPrice: entity.NewMoney(539999 * 2),
+					PromotionID:   1,
 				},
 				{
-					Product:       products[3],
-					Quantity:      2,
-					SubTotalPrice: 0,
+					Product:           products[3],
+					Quantity:          2,
+					SubTotalPrice:     entity.NewMoney(0),
+					PromotionID:       1,
+					FreeQuantity:      2,
+					AppliedPromotions: appliedPromotion(entity.SourcePromotion, 1, 1, "buy 1 get 1 Raspberry Pi B free", 6000),
 				},
 			},
 			TotalItem:  4,
-			TotalPrice: 5399.99 * 2,
+			TotalPrice: entity.NewMoney(539999 * 2),
 		}
-		productRepo.EXPECT().SubmitCheckout(checkout).Return(nil).Times(1)
+		productRepo.EXPECT().SubmitCheckout(ctx, checkout).Return(nil).Times(1)
 
-		resp, err := svc.Submit(payload)
+		resp, err := svc.Submit(ctx, payload, entity.CheckoutOptions{})
 		assert.Nil(t, err)
 		assert.Equal(t, checkout, resp)
 	})
 
 	t.Run("Scanned Items: Google Home, Google Home, Google Home", func(t *testing.T) {
 		payload := entity.MapProductSerialQuantity{"120P90": 3}
-		productRepo.EXPECT().GetProductBySerials(gomock.Any()).Return([]*entity.Product{
+		productRepo.EXPECT().GetProductBySerials(ctx, gomock.Any()).Return([]*entity.Product{
 			products[0],
 		}, nil).Times(1)
-		promoRepo.EXPECT().GetPromotionByProducts([]*entity.Product{
+		promoRepo.EXPECT().GetPromotionByProducts(ctx, []*entity.Product{
 			products[0],
 		}).Return(map[int64][]*entity.Promotion{
 			1: {promotions[1]},
 		}, nil).Times(1)
+		promoRepo.EXPECT().GetActiveBundlesByProducts(ctx, gomock.Any()).Return(nil, nil).Times(1)
+		promoRepo.EXPECT().GetActiveCartPromotions(ctx, nil).Return(nil, nil).Times(1)
 
 		checkout := &entity.Checkout{
 			Items: []*entity.CheckoutItem{
 				{
-					Product:       products[0],
-					Quantity:      3,
-					SubTotalPrice: 49.99 * 2,
+					Product:           products[0],
+					Quantity:          3,
+					SubTotalPrice:     entity.NewMoney(4999 * 2),
+					PromotionID:       1,
+					AppliedPromotions: appliedPromotion(entity.SourcePromotion, 1, 2, "buy 3 for the price of 2", 4999),
 				},
 			},
 			TotalItem:  3,
-			TotalPrice: 49.99 * 2,
+			TotalPrice: entity.NewMoney(4999 * 2),
 		}
-		productRepo.EXPECT().SubmitCheckout(checkout).Return(nil).Times(1)
+		productRepo.EXPECT().SubmitCheckout(ctx, checkout).Return(nil).Times(1)
 
-		resp, err := svc.Submit(payload)
+		resp, err := svc.Submit(ctx, payload, entity.CheckoutOptions{})
 		assert.Nil(t, err)
 		assert.Equal(t, checkout, resp)
 	})
 
 	t.Run("Scanned Items: 6 Google Home", func(t *testing.T) {
 		payload := entity.MapProductSerialQuantity{"120P90": 6}
-		productRepo.EXPECT().GetProductBySerials(gomock.Any()).Return([]*entity.Product{
+		productRepo.EXPECT().GetProductBySerials(ctx, gomock.Any()).Return([]*entity.Product{
 			products[0],
 		}, nil).Times(1)
-		promoRepo.EXPECT().GetPromotionByProducts([]*entity.Product{
+		promoRepo.EXPECT().GetPromotionByProducts(ctx, []*entity.Product{
 			products[0],
 		}).Return(map[int64][]*entity.Promotion{
 			1: {promotions[1]},
 		}, nil).Times(1)
+		promoRepo.EXPECT().GetActiveBundlesByProducts(ctx, gomock.Any()).Return(nil, nil).Times(1)
+		promoRepo.EXPECT().GetActiveCartPromotions(ctx, nil).Return(nil, nil).Times(1)
 
 		checkout := &entity.Checkout{
 			Items: []*entity.CheckoutItem{
 				{
-					Product:       products[0],
-					Quantity:      6,
-					SubTotalPrice: 49.99 * 4,
+					Product:           products[0],
+					Quantity:          6,
+					SubTotalPrice:     entity.NewMoney(4999 * 4),
+					PromotionID:       1,
+					AppliedPromotions: appliedPromotion(entity.SourcePromotion, 1, 2, "buy 3 for the price of 2", 4999*2),
 				},
 			},
 			TotalItem:  6,
-			TotalPrice: 49.99 * 4,
+			TotalPrice: entity.NewMoney(4999 * 4),
 		}
-		productRepo.EXPECT().SubmitCheckout(checkout).Return(nil).Times(1)
+		productRepo.EXPECT().SubmitCheckout(ctx, checkout).Return(nil).Times(1)
 
-		resp, err := svc.Submit(payload)
+		resp, err := svc.Submit(ctx, payload, entity.CheckoutOptions{})
 		assert.Nil(t, err)
 		assert.Equal(t, checkout, resp)
 	})
 
 	t.Run("Scanned Items: 4 Google Home", func(t *testing.T) {
 		payload := entity.MapProductSerialQuantity{"120P90": 4}
-		productRepo.EXPECT().GetProductBySerials(gomock.Any()).Return([]*entity.Product{
+		productRepo.EXPECT().GetProductBySerials(ctx, gomock.Any()).Return([]*entity.Product{
 			products[0],
 		}, nil).Times(1)
-		promoRepo.EXPECT().GetPromotionByProducts([]*entity.Product{
+		promoRepo.EXPECT().GetPromotionByProducts(ctx, []*entity.Product{
 			products[0],
 		}).Return(map[int64][]*entity.Promotion{
 			1: {promotions[1]},
 		}, nil).Times(1)
+		promoRepo.EXPECT().GetActiveBundlesByProducts(ctx, gomock.Any()).Return(nil, nil).Times(1)
+		promoRepo.EXPECT().GetActiveCartPromotions(ctx, nil).Return(nil, nil).Times(1)
 
 		checkout := &entity.Checkout{
 			Items: []*entity.CheckoutItem{
 				{
-					Product:       products[0],
-					Quantity:      4,
-					SubTotalPrice: 49.99 * 3,
+					Product:           products[0],
+					Quantity:          4,
+					SubTotalPrice:     entity.NewMoney(4999 * 3),
+					PromotionID:       1,
+					AppliedPromotions: appliedPromotion(entity.SourcePromotion, 1, 2, "buy 3 for the price of 2", 4999),
 				},
 			},
 			TotalItem:  4,
-			TotalPrice: 49.99 * 3,
+			TotalPrice: entity.NewMoney(4999 * 3),
 		}
-		productRepo.EXPECT().SubmitCheckout(checkout).Return(nil).Times(1)
+		productRepo.EXPECT().SubmitCheckout(ctx, checkout).Return(nil).Times(1)
 
-		resp, err := svc.Submit(payload)
+		resp, err := svc.Submit(ctx, payload, entity.CheckoutOptions{})
 		assert.Nil(t, err)
 		assert.Equal(t, checkout, resp)
 	})
 
 	t.Run("Scanned Items: Alexa Speaker, Alexa Speaker, Alexa Speaker", func(t *testing.T) {
 		payload := entity.MapProductSerialQuantity{"A304SD": 3}
-		productRepo.EXPECT().GetProductBySerials(gomock.Any()).Return([]*entity.Product{
+		productRepo.EXPECT().GetProductBySerials(ctx, gomock.Any()).Return([]*entity.Product{
 			products[2],
 		}, nil).Times(1)
-		promoRepo.EXPECT().GetPromotionByProducts([]*entity.Product{
+		promoRepo.EXPECT().GetPromotionByProducts(ctx, []*entity.Product{
 			products[2],
 		}).Return(map[int64][]*entity.Promotion{
 			3: {promotions[2]},
 		}, nil).Times(1)
+		promoRepo.EXPECT().GetActiveBundlesByProducts(ctx, gomock.Any()).Return(nil, nil).Times(1)
+		promoRepo.EXPECT().GetActiveCartPromotions(ctx, nil).Return(nil, nil).Times(1)
 
 		checkout := &entity.Checkout{
 			Items: []*entity.CheckoutItem{
 				{
-					Product:       products[2],
-					Quantity:      3,
-					SubTotalPrice: (109.50 * 3) - (109.50 * 3 * 10 / 100),
+					Product:           products[2],
+					Quantity:          3,
+					SubTotalPrice:     entity.NewMoney((10950 * 3) - (10950 * 3 * 10 / 100)),
+					PromotionID:       3,
+					AppliedPromotions: appliedPromotion(entity.SourcePromotion, 3, 3, "10% off for buying 3 or more", 10950*3*10/100),
 				},
 			},
 			TotalItem:  3,
-			TotalPrice: (109.50 * 3) - (109.50 * 3 * 10 / 100),
+			TotalPrice: entity.NewMoney((10950 * 3) - (10950 * 3 * 10 / 100)),
 		}
-		productRepo.EXPECT().SubmitCheckout(checkout).Return(nil).Times(1)
+		productRepo.EXPECT().SubmitCheckout(ctx, checkout).Return(nil).Times(1)
 
-		resp, err := svc.Submit(payload)
+		resp, err := svc.Submit(ctx, payload, entity.CheckoutOptions{})
 		assert.Nil(t, err)
 		assert.Equal(t, checkout, resp)
 	})
 
 	t.Run("Scanned Items: 4 Alexa Speaker", func(t *testing.T) {
 		payload := entity.MapProductSerialQuantity{"A304SD": 4}
-		productRepo.EXPECT().GetProductBySerials(gomock.Any()).Return([]*entity.Product{
+		productRepo.EXPECT().GetProductBySerials(ctx, gomock.Any()).Return([]*entity.Product{
 			products[2],
 		}, nil).Times(1)
-		promoRepo.EXPECT().GetPromotionByProducts([]*entity.Product{
+		promoRepo.EXPECT().GetPromotionByProducts(ctx, []*entity.Product{
 			products[2],
 		}).Return(map[int64][]*entity.Promotion{
 			3: {promotions[2]},
 		}, nil).Times(1)
+		promoRepo.EXPECT().GetActiveBundlesByProducts(ctx, gomock.Any()).Return(nil, nil).Times(1)
+		promoRepo.EXPECT().GetActiveCartPromotions(ctx, nil).Return(nil, nil).Times(1)
 
 		checkout := &entity.Checkout{
 			Items: []*entity.CheckoutItem{
 				{
-					Product:       products[2],
-					Quantity:      4,
-					SubTotalPrice: (109.50 * 4) - (109.50 * 4 * 10 / 100),
+					Product:           products[2],
+					Quantity:          4,
+					SubTotalPrice:     entity.NewMoney((10950 * 4) - (10950 * 4 * 10 / 100)),
+					PromotionID:       3,
+					AppliedPromotions: appliedPromotion(entity.SourcePromotion, 3, 3, "10% off for buying 3 or more", 10950*4*10/100),
 				},
 			},
 			TotalItem:  4,
-			TotalPrice: (109.50 * 4) - (109.50 * 4 * 10 / 100),
+			TotalPrice: entity.NewMoney((10950 * 4) - (10950 * 4 * 10 / 100)),
 		}
-		productRepo.EXPECT().SubmitCheckout(checkout).Return(nil).Times(1)
+		productRepo.EXPECT().SubmitCheckout(ctx, checkout).Return(nil).Times(1)
 
-		resp, err := svc.Submit(payload)
+		resp, err := svc.Submit(ctx, payload, entity.CheckoutOptions{})
 		assert.Nil(t, err)
 		assert.Equal(t, checkout, resp)
 	})
 
 	t.Run("Scanned Items: 2 Alexa Speaker (don't get discount)", func(t *testing.T) {
 		payload := entity.MapProductSerialQuantity{"A304SD": 2}
-		productRepo.EXPECT().GetProductBySerials(gomock.Any()).Return([]*entity.Product{
+		productRepo.EXPECT().GetProductBySerials(ctx, gomock.Any()).Return([]*entity.Product{
 			products[2],
 		}, nil).Times(1)
-		promoRepo.EXPECT().GetPromotionByProducts([]*entity.Product{
+		promoRepo.EXPECT().GetPromotionByProducts(ctx, []*entity.Product{
 			products[2],
 		}).Return(map[int64][]*entity.Promotion{
 			3: {promotions[2]},
 		}, nil).Times(1)
+		promoRepo.EXPECT().GetActiveBundlesByProducts(ctx, gomock.Any()).Return(nil, nil).Times(1)
+		promoRepo.EXPECT().GetActiveCartPromotions(ctx, nil).Return(nil, nil).Times(1)
 
 		checkout := &entity.Checkout{
 			Items: []*entity.CheckoutItem{
 				{
 					Product:       products[2],
 					Quantity:      2,
-					SubTotalPrice: 109.50 * 2,
+					SubTotalPrice: entity.NewMoney(10950 * 2),
 				},
 			},
 			TotalItem:  2,
-			TotalPrice: 109.50 * 2,
+			TotalPrice: entity.NewMoney(10950 * 2),
+			NotMetPromotions: []*entity.AppliedPromotion{
+				{Source: entity.SourcePromotion, ID: 3, Type: 3, Description: "10% off for buying 3 or more", Reason: "buy 1 more"},
+			},
+		}
+		productRepo.EXPECT().SubmitCheckout(ctx, checkout).Return(nil).Times(1)
+
+		resp, err := svc.Submit(ctx, payload, entity.CheckoutOptions{})
+		assert.Nil(t, err)
+		assert.Equal(t, checkout, resp)
+	})
+}
+
+// halfPriceRule is example of custom promotion rule
+type halfPriceRule struct{}
+
+func (r *halfPriceRule) Apply(item *entity.CheckoutItem, promo *entity.Promotion, freeProductItem module.FreeProductItems) bool {
+	item.SubTotalPrice = item.SubTotalPrice.Sub(item.SubTotalPrice.Percent(50))
+	return true
+}
+
+func Test_SubmitWithPromotionRules(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+	product := &entity.Product{ID: 4, Serial: "234234", Name: "Raspberry Pi B", Price: entity.NewMoney(3000), UpdatedAt: dayCreated}
+
+	t.Run("Free item: buy 2 Raspberry Pi B get 1 free", func(t *testing.T) {
+		svc, productRepo, promoRepo := initCheckoutUC(ctrl)
+
+		payload := entity.MapProductSerialQuantity{"234234": 2}
+		productRepo.EXPECT().GetProductBySerials(ctx, gomock.Any()).Return([]*entity.Product{product}, nil).Times(1)
+		promoRepo.EXPECT().GetPromotionByProducts(ctx, []*entity.Product{product}).Return(map[int64][]*entity.Promotion{
+			4: {{ID: 4, Type: entity.FreeItem, ProductID: 4, MatchQuantity: 2, PromoValue: 1, UpdatedAt: dayCreated}},
+		}, nil).Times(1)
+		promoRepo.EXPECT().GetActiveBundlesByProducts(ctx, gomock.Any()).Return(nil, nil).Times(1)
+		promoRepo.EXPECT().GetActiveCartPromotions(ctx, nil).Return(nil, nil).Times(1)
+
+		checkout := &entity.Checkout{
+			Items: []*entity.CheckoutItem{
+				{
+					Product:           product,
+					Quantity:          3,
+					SubTotalPrice:     entity.NewMoney(3000 * 2),
+					PromotionID:       4,
+					FreeQuantity:      1,
+					AppliedPromotions: appliedPromotion(entity.SourcePromotion, 4, int(entity.FreeItem), "buy 2 get 1 free", 3000),
+				},
+			},
+			TotalItem:  3,
+			TotalPrice: entity.NewMoney(3000 * 2),
 		}
-		productRepo.EXPECT().SubmitCheckout(checkout).Return(nil).Times(1)
+		productRepo.EXPECT().SubmitCheckout(ctx, checkout).Return(nil).Times(1)
 
-		resp, err := svc.Submit(payload)
+		resp, err := svc.Submit(ctx, payload, entity.CheckoutOptions{})
 		assert.Nil(t, err)
 		assert.Equal(t, checkout, resp)
 	})
+
+	t.Run("Custom registered rule", func(t *testing.T) {
+		productRepo := repomocks.NewMockProductRepo(ctrl)
+		promoRepo := repomocks.NewMockPromotionRepo(ctrl)
+		rules := module.DefaultPromotionRules()
+		rules.Register(entity.PromotionType(99), &halfPriceRule{})
+		svc := module.NewCheckoutUsecaseWithRules(productRepo, promoRepo, rules)
+
+		payload := entity.MapProductSerialQuantity{"234234": 1}
+		productRepo.EXPECT().GetProductBySerials(ctx, gomock.Any()).Return([]*entity.Product{product}, nil).Times(1)
+		promoRepo.EXPECT().GetPromotionByProducts(ctx, []*entity.Product{product}).Return(map[int64][]*entity.Promotion{
+			4: {{ID: 5, Type: 99, ProductID: 4, UpdatedAt: dayCreated}},
+		}, nil).Times(1)
+		promoRepo.EXPECT().GetActiveBundlesByProducts(ctx, gomock.Any()).Return(nil, nil).Times(1)
+		promoRepo.EXPECT().GetActiveCartPromotions(ctx, nil).Return(nil, nil).Times(1)
+
+		checkout := &entity.Checkout{
+			Items: []*entity.CheckoutItem{
+				{
+					Product:           product,
+					Quantity:          1,
+					SubTotalPrice:     entity.NewMoney(1500),
+					PromotionID:       5,
+					AppliedPromotions: appliedPromotion(entity.SourcePromotion, 5, 99, "promotion 5", 1500),
+				},
+			},
+			TotalItem:  1,
+			TotalPrice: entity.NewMoney(1500),
+		}
+		productRepo.EXPECT().SubmitCheckout(ctx, checkout).Return(nil).Times(1)
+
+		resp, err := svc.Submit(ctx, payload, entity.CheckoutOptions{})
+		assert.Nil(t, err)
+		assert.Equal(t, checkout, resp)
+	})
+}
+
+func Test_SubmitUnknownSerials(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+
+	svc, productRepo, promoRepo := initCheckoutUC(ctrl)
+
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+	product := &entity.Product{ID: 1, Serial: "120P90", Name: "Google Home", Price: entity.NewMoney(4999), UpdatedAt: dayCreated}
+
+	t.Run("negative, some serials are unknown", func(t *testing.T) {
+		payload := entity.MapProductSerialQuantity{"120P90": 1, "XXX": 1, "AAA": 2}
+		productRepo.EXPECT().GetProductBySerials(ctx, gomock.Any()).Return([]*entity.Product{product}, nil).Times(1)
+		productRepo.EXPECT().SubmitCheckout(ctx, gomock.Any()).Times(0)
+
+		_, err := svc.Submit(ctx, payload, entity.CheckoutOptions{})
+		assert.Equal(t, entity.NewUnknownSerialsError([]string{"AAA", "XXX"}, http.StatusBadRequest), err)
+	})
+
+	t.Run("negative, lenient but all serials are unknown", func(t *testing.T) {
+		payload := entity.MapProductSerialQuantity{"XXX": 1}
+		productRepo.EXPECT().GetProductBySerials(ctx, gomock.Any()).Return(nil, nil).Times(1)
+
+		_, err := svc.Submit(ctx, payload, entity.CheckoutOptions{Lenient: true})
+		assert.Equal(t, entity.NewUnknownSerialsError([]string{"XXX"}, http.StatusBadRequest), err)
+	})
+
+	t.Run("positive, lenient reports unknown serials", func(t *testing.T) {
+		payload := entity.MapProductSerialQuantity{"120P90": 1, "XXX": 1}
+		productRepo.EXPECT().GetProductBySerials(ctx, gomock.Any()).Return([]*entity.Product{product}, nil).Times(1)
+		promoRepo.EXPECT().GetPromotionByProducts(ctx, []*entity.Product{product}).Return(nil, nil).Times(1)
+		promoRepo.EXPECT().GetActiveBundlesByProducts(ctx, gomock.Any()).Return(nil, nil).Times(1)
+		promoRepo.EXPECT().GetActiveCartPromotions(ctx, nil).Return(nil, nil).Times(1)
+
+		checkout := &entity.Checkout{
+			Items: []*entity.CheckoutItem{
+				{
+					Product:       product,
+					Quantity:      1,
+					SubTotalPrice: entity.NewMoney(4999),
+				},
+			},
+			TotalItem:      1,
+			TotalPrice:     entity.NewMoney(4999),
+			UnknownSerials: []string{"XXX"},
+		}
+		productRepo.EXPECT().SubmitCheckout(ctx, checkout).Return(nil).Times(1)
+
+		resp, err := svc.Submit(ctx, payload, entity.CheckoutOptions{Lenient: true})
+		assert.Nil(t, err)
+		assert.Equal(t, checkout, resp)
+	})
+}
+
+func Test_SubmitPromotionStacking(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+
+	svc, productRepo, promoRepo := initCheckoutUC(ctrl)
+
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+	products := []*entity.Product{
+		{ID: 1, Serial: "120P90", Name: "Google Home", Price: entity.NewMoney(4999), UpdatedAt: dayCreated},
+		{ID: 2, Serial: "43N23P", Name: "MacBook Pro", Price: entity.NewMoney(539999), UpdatedAt: dayCreated},
+		{ID: 3, Serial: "A304SD", Name: "Alexa Speaker", Price: entity.NewMoney(10950), UpdatedAt: dayCreated},
+		{ID: 4, Serial: "234234", Name: "Raspberry Pi B", Price: entity.NewMoney(3000), UpdatedAt: dayCreated},
+	}
+
+	// submit single product and return its checkout item
+	submit := func(product *entity.Product, qty int, promotions []*entity.Promotion) *entity.CheckoutItem {
+		productRepo.EXPECT().GetProductBySerials(ctx, gomock.Any()).Return([]*entity.Product{product}, nil).Times(1)
+		promoRepo.EXPECT().GetPromotionByProducts(ctx, []*entity.Product{product}).Return(map[int64][]*entity.Promotion{
+			product.ID: promotions,
+		}, nil).Times(1)
+		promoRepo.EXPECT().GetActiveBundlesByProducts(ctx, gomock.Any()).Return(nil, nil).Times(1)
+		promoRepo.EXPECT().GetActiveCartPromotions(ctx, nil).Return(nil, nil).Times(1)
+		productRepo.EXPECT().SubmitCheckout(ctx, gomock.Any()).Return(nil).Times(1)
+
+		resp, err := svc.Submit(ctx, entity.MapProductSerialQuantity{product.Serial: qty}, entity.CheckoutOptions{})
+		assert.Nil(t, err)
+		return resp.Items[0]
+	}
+
+	t.Run("Stackable: buy 3 Google Home for the price of 2, then 10% discount", func(t *testing.T) {
+		item := submit(products[0], 3, []*entity.Promotion{
+			{ID: 1, Type: entity.BuyItemsForReducePrice, ProductID: 1, MatchQuantity: 3, PromoValue: 2, Priority: 2},
+			{ID: 2, Type: entity.DiscountInPercent, ProductID: 1, MatchQuantity: 1, PromoValue: 10, Priority: 1},
+		})
+		assert.Equal(t, &entity.CheckoutItem{
+			Product:       products[0],
+			Quantity:      3,
+			SubTotalPrice: entity.NewMoney(4999*2 - 1000),
+			PromotionID:   2,
+			AppliedPromotions: []*entity.AppliedPromotion{
+				{Source: entity.SourcePromotion, ID: 1, Type: int(entity.BuyItemsForReducePrice), Description: "buy 3 for the price of 2", Saved: entity.NewMoney(4999)},
+				{Source: entity.SourcePromotion, ID: 2, Type: int(entity.DiscountInPercent), Description: "10% off", Saved: entity.NewMoney(1000)},
+			},
+		}, item)
+	})
+
+	t.Run("Exclusive: buy 3 Alexa Speaker for the price of 2 is better than 10% discount", func(t *testing.T) {
+		item := submit(products[2], 3, []*entity.Promotion{
+			{ID: 3, Type: entity.BuyItemsForReducePrice, ProductID: 3, MatchQuantity: 3, PromoValue: 2, Stacking: entity.Exclusive},
+			{ID: 4, Type: entity.DiscountInPercent, ProductID: 3, MatchQuantity: 1, PromoValue: 10},
+		})
+		assert.Equal(t, &entity.CheckoutItem{
+			Product:           products[2],
+			Quantity:          3,
+			SubTotalPrice:     entity.NewMoney(10950 * 2),
+			PromotionID:       3,
+			AppliedPromotions: appliedPromotion(entity.SourcePromotion, 3, int(entity.BuyItemsForReducePrice), "buy 3 for the price of 2", 10950),
+		}, item)
+	})
+
+	t.Run("Exclusive: not met, use stackable promotion", func(t *testing.T) {
+		item := submit(products[2], 2, []*entity.Promotion{
+			{ID: 3, Type: entity.BuyItemsForReducePrice, ProductID: 3, MatchQuantity: 3, PromoValue: 2, Stacking: entity.Exclusive},
+			{ID: 4, Type: entity.DiscountInPercent, ProductID: 3, MatchQuantity: 1, PromoValue: 10},
+		})
+		assert.Equal(t, &entity.CheckoutItem{
+			Product:           products[2],
+			Quantity:          2,
+			SubTotalPrice:     entity.NewMoney(10950*2 - 2190),
+			PromotionID:       4,
+			AppliedPromotions: appliedPromotion(entity.SourcePromotion, 4, int(entity.DiscountInPercent), "10% off", 2190),
+		}, item)
+	})
+
+	t.Run("Best of: 20% discount is chosen over 10% discount", func(t *testing.T) {
+		item := submit(products[2], 1, []*entity.Promotion{
+			{ID: 5, Type: entity.DiscountInPercent, ProductID: 3, MatchQuantity: 1, PromoValue: 10, Stacking: entity.BestOf},
+			{ID: 6, Type: entity.DiscountInPercent, ProductID: 3, MatchQuantity: 1, PromoValue: 20, Stacking: entity.BestOf},
+		})
+		assert.Equal(t, &entity.CheckoutItem{
+			Product:           products[2],
+			Quantity:          1,
+			SubTotalPrice:     entity.NewMoney(10950 - 2190),
+			PromotionID:       6,
+			AppliedPromotions: appliedPromotion(entity.SourcePromotion, 6, int(entity.DiscountInPercent), "20% off", 2190),
+		}, item)
+	})
+
+	t.Run("Best of: free Raspberry Pi B is valued by its price", func(t *testing.T) {
+		// free Raspberry Pi B is 30.00, 1% discount of MacBook Pro is 54.00
+		productRepo.EXPECT().GetProductByIDs(ctx, []int64{4}).Return([]*entity.Product{products[3]}, nil).Times(1)
+		item := submit(products[1], 1, []*entity.Promotion{
+			{ID: 7, Type: entity.BonusItem, ProductID: 2, MatchQuantity: 1, PromoValue: 1, PromoProductID: 4, Stacking: entity.BestOf},
+			{ID: 8, Type: entity.DiscountInPercent, ProductID: 2, MatchQuantity: 1, PromoValue: 1, Stacking: entity.BestOf},
+		})
+		assert.Equal(t, &entity.CheckoutItem{
+			Product:           products[1],
+			Quantity:          1,
+			SubTotalPrice:     entity.NewMoney(539999 - 5400),
+			PromotionID:       8,
+			AppliedPromotions: appliedPromotion(entity.SourcePromotion, 8, int(entity.DiscountInPercent), "1% off", 5400),
+		}, item)
+	})
+}
+
+func Test_SubmitCartPromotions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+
+	svc, productRepo, promoRepo := initCheckoutUC(ctrl)
+
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+	products := []*entity.Product{
+		{ID: 1, Serial: "120P90", Name: "Google Home", Price: entity.NewMoney(4999), UpdatedAt: dayCreated},
+		{ID: 2, Serial: "43N23P", Name: "MacBook Pro", Price: entity.NewMoney(539999), UpdatedAt: dayCreated},
+		{ID: 3, Serial: "A304SD", Name: "Alexa Speaker", Price: entity.NewMoney(10950), UpdatedAt: dayCreated},
+		{ID: 4, Serial: "234234", Name: "Raspberry Pi B", Price: entity.NewMoney(3000), UpdatedAt: dayCreated},
+	}
+	spend500 := &entity.CartPromotion{ID: 1, Type: entity.CartDiscountInPercent, MinSpend: entity.NewMoney(50000), PromoValue: 5}
+	freePi := &entity.CartPromotion{ID: 2, Type: entity.CartFreeItem, MinSpend: entity.NewMoney(100000), PromoValue: 1, PromoProductID: 4}
+
+	// submit single product without product promotion
+	submit := func(product *entity.Product, cartPromotions []*entity.CartPromotion) *entity.Checkout {
+		productRepo.EXPECT().GetProductBySerials(ctx, gomock.Any()).Return([]*entity.Product{product}, nil).Times(1)
+		promoRepo.EXPECT().GetPromotionByProducts(ctx, []*entity.Product{product}).Return(nil, nil).Times(1)
+		promoRepo.EXPECT().GetActiveBundlesByProducts(ctx, gomock.Any()).Return(nil, nil).Times(1)
+		promoRepo.EXPECT().GetActiveCartPromotions(ctx, nil).Return(cartPromotions, nil).Times(1)
+		productRepo.EXPECT().SubmitCheckout(ctx, gomock.Any()).Return(nil).Times(1)
+
+		resp, err := svc.Submit(ctx, entity.MapProductSerialQuantity{product.Serial: 1}, entity.CheckoutOptions{})
+		assert.Nil(t, err)
+		return resp
+	}
+
+	t.Run("Spend 500.00 get 5% off", func(t *testing.T) {
+		resp := submit(products[1], []*entity.CartPromotion{spend500})
+		assert.Equal(t, &entity.Checkout{
+			Items: []*entity.CheckoutItem{
+				{Product: products[1], Quantity: 1, SubTotalPrice: entity.NewMoney(539999)},
+			},
+			TotalItem:  1,
+			TotalPrice: entity.NewMoney(539999 - 27000),
+			Discounts: []*entity.CheckoutDiscount{
+				{CartPromotionID: 1, Type: entity.CartDiscountInPercent, Description: "5% off for spending 500.00 or more", Amount: entity.NewMoney(27000)},
+			},
+			DiscountPrice: entity.NewMoney(27000),
+		}, resp)
+	})
+
+	t.Run("Spend 500.00 get 5% off, not met", func(t *testing.T) {
+		resp := submit(products[0], []*entity.CartPromotion{spend500})
+		assert.Equal(t, &entity.Checkout{
+			Items: []*entity.CheckoutItem{
+				{Product: products[0], Quantity: 1, SubTotalPrice: entity.NewMoney(4999)},
+			},
+			TotalItem:  1,
+			TotalPrice: entity.NewMoney(4999),
+			NotMetPromotions: []*entity.AppliedPromotion{
+				{Source: entity.SourceCartPromotion, ID: 1, Type: int(entity.CartDiscountInPercent), Description: "5% off for spending 500.00 or more", Reason: "spend 450.01 more"},
+			},
+		}, resp)
+	})
+
+	t.Run("Free Raspberry Pi B on orders over 1000.00, stacked with 5% off", func(t *testing.T) {
+		productRepo.EXPECT().GetProductByIDs(ctx, []int64{4}).Return([]*entity.Product{products[3]}, nil).Times(1)
+		resp := submit(products[1], []*entity.CartPromotion{spend500, freePi})
+		assert.Equal(t, &entity.Checkout{
+			Items: []*entity.CheckoutItem{
+				{Product: products[1], Quantity: 1, SubTotalPrice: entity.NewMoney(539999)},
+				{Product: products[3], Quantity: 1, SubTotalPrice: entity.NewMoney(3000), FreeQuantity: 1},
+			},
+			TotalItem:  2,
+			TotalPrice: entity.NewMoney(539999 - 27000),
+			Discounts: []*entity.CheckoutDiscount{
+				{CartPromotionID: 1, Type: entity.CartDiscountInPercent, Description: "5% off for spending 500.00 or more", Amount: entity.NewMoney(27000)},
+				{CartPromotionID: 2, Type: entity.CartFreeItem, Description: "free 1 Raspberry Pi B for spending 1000.00 or more", Amount: entity.NewMoney(3000)},
+			},
+			DiscountPrice: entity.NewMoney(27000 + 3000),
+		}, resp)
+	})
+
+	t.Run("Exclusive fixed amount is better than 5% off", func(t *testing.T) {
+		resp := submit(products[2], []*entity.CartPromotion{
+			{ID: 3, Type: entity.CartDiscountInPercent, PromoValue: 5},
+			{ID: 4, Type: entity.CartDiscountAmount, MinSpend: entity.NewMoney(10000), PromoAmount: entity.NewMoney(1000), Stacking: entity.Exclusive},
+		})
+		assert.Equal(t, &entity.Checkout{
+			Items: []*entity.CheckoutItem{
+				{Product: products[2], Quantity: 1, SubTotalPrice: entity.NewMoney(10950)},
+			},
+			TotalItem:  1,
+			TotalPrice: entity.NewMoney(10950 - 1000),
+			Discounts: []*entity.CheckoutDiscount{
+				{CartPromotionID: 4, Type: entity.CartDiscountAmount, Description: "10.00 off for spending 100.00 or more", Amount: entity.NewMoney(1000)},
+			},
+			DiscountPrice: entity.NewMoney(1000),
+			NotMetPromotions: []*entity.AppliedPromotion{
+				{Source: entity.SourceCartPromotion, ID: 3, Type: int(entity.CartDiscountInPercent), Description: "5% off", Reason: "not combined with the applied promotions"},
+			},
+		}, resp)
+	})
+
+	t.Run("Fixed amount cannot exceed total price", func(t *testing.T) {
+		resp := submit(products[0], []*entity.CartPromotion{
+			{ID: 5, Type: entity.CartDiscountAmount, PromoAmount: entity.NewMoney(20000)},
+		})
+		assert.Equal(t, &entity.Checkout{
+			Items: []*entity.CheckoutItem{
+				{Product: products[0], Quantity: 1, SubTotalPrice: entity.NewMoney(4999)},
+			},
+			TotalItem:  1,
+			TotalPrice: entity.NewMoney(0),
+			Discounts: []*entity.CheckoutDiscount{
+				{CartPromotionID: 5, Type: entity.CartDiscountAmount, Description: "200.00 off", Amount: entity.NewMoney(4999)},
+			},
+			DiscountPrice: entity.NewMoney(4999),
+		}, resp)
+	})
+}
+
+func Test_Quote(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+
+	svc, productRepo, promoRepo := initCheckoutUC(ctrl)
+
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+	products := []*entity.Product{
+		{ID: 2, Serial: "43N23P", Name: "MacBook Pro", Price: entity.NewMoney(539999), UpdatedAt: dayCreated},
+		{ID: 4, Serial: "234234", Name: "Raspberry Pi B", Price: entity.NewMoney(3000), UpdatedAt: dayCreated},
+	}
+
+	t.Run("Scanned Items: MacBook Pro, without Raspberry Pi B", func(t *testing.T) {
+		payload := entity.MapProductSerialQuantity{"43N23P": 1}
+		productRepo.EXPECT().GetProductBySerials(ctx, gomock.Any()).Return([]*entity.Product{
+			products[0],
+		}, nil).Times(1)
+		promoRepo.EXPECT().GetPromotionByProducts(ctx, []*entity.Product{
+			products[0],
+		}).Return(map[int64][]*entity.Promotion{
+			2: {{ID: 1, Type: 1, ProductID: 2, MatchQuantity: 1, PromoValue: 1, PromoProductID: 4, UpdatedAt: dayCreated}},
+		}, nil).Times(1)
+		promoRepo.EXPECT().GetActiveBundlesByProducts(ctx, gomock.Any()).Return(nil, nil).Times(1)
+		promoRepo.EXPECT().GetActiveCartPromotions(ctx, nil).Return(nil, nil).Times(1)
+		productRepo.EXPECT().GetProductByIDs(ctx, []int64{4}).Return([]*entity.Product{
+			products[1],
+		}, nil).Times(1)
+		productRepo.EXPECT().GetAvailableQuantityByIDs(ctx, []int64{2, 4}).Return([]*entity.ProductQuantity{
+			{ID: 2, ProductID: 2, Quantity: 5, UpdatedAt: dayCreated},
+			{ID: 4, ProductID: 4, Quantity: 0, UpdatedAt: dayCreated},
+		}, nil).Times(1)
+		// quote never submit checkout
+		productRepo.EXPECT().SubmitCheckout(ctx, gomock.Any()).Times(0)
+
+		resp, err := svc.Quote(ctx, payload, entity.CheckoutOptions{})
+		assert.Nil(t, err)
+		assert.Equal(t, &entity.CheckoutQuote{
+			Checkout: &entity.Checkout{
+				Items: []*entity.CheckoutItem{
+					{
+						Product:       products[0],
+						Quantity:      1,
+						SubTotalPrice: entity.NewMoney(539999),
+						PromotionID:   1,
+					},
+					{
+						Product:           products[1],
+						Quantity:          1,
+						SubTotalPrice:     entity.NewMoney(0),
+						PromotionID:       1,
+						FreeQuantity:      1,
+						AppliedPromotions: appliedPromotion(entity.SourcePromotion, 1, 1, "buy 1 get 1 Raspberry Pi B free", 3000),
+					},
+				},
+				TotalItem:  2,
+				TotalPrice: entity.NewMoney(539999),
+			},
+			AvailableQuantity: map[int64]int{2: 5, 4: 0},
+		}, resp)
+	})
+}
+
+func Test_SubmitCoupons(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+
+	svc, productRepo, promoRepo := initCheckoutUC(ctrl)
+
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+	product := &entity.Product{ID: 3, Serial: "A304SD", Name: "Alexa Speaker", Price: entity.NewMoney(10950), UpdatedAt: dayCreated}
+	influencer := &entity.CartPromotion{ID: 5, Type: entity.CartDiscountInPercent, PromoValue: 10}
+	coupon := &entity.Coupon{ID: 1, Code: "ALEXA10", CartPromotionID: 5, MaxRedemptions: 100, PerCustomerLimit: 1, RedeemedCount: 12}
+	payload := entity.MapProductSerialQuantity{product.Serial: 1}
+
+	t.Run("positive, coupon unlocks cart promotion", func(t *testing.T) {
+		productRepo.EXPECT().GetProductBySerials(ctx, gomock.Any()).Return([]*entity.Product{product}, nil).Times(1)
+		promoRepo.EXPECT().GetPromotionByProducts(ctx, []*entity.Product{product}).Return(nil, nil).Times(1)
+		promoRepo.EXPECT().GetActiveBundlesByProducts(ctx, gomock.Any()).Return(nil, nil).Times(1)
+		promoRepo.EXPECT().GetCouponsByCodes(ctx, []string{"ALEXA10"}).Return([]*entity.Coupon{coupon}, nil).Times(1)
+		promoRepo.EXPECT().GetActiveCartPromotions(ctx, []int64{5}).Return([]*entity.CartPromotion{influencer}, nil).Times(1)
+		productRepo.EXPECT().SubmitCheckout(ctx, gomock.Any()).Return(nil).Times(1)
+
+		resp, err := svc.Submit(ctx, payload, entity.CheckoutOptions{CouponCodes: []string{" alexa10", "ALEXA10"}, CustomerID: "cust-1"})
+		assert.Nil(t, err)
+		assert.Equal(t, &entity.Checkout{
+			Items: []*entity.CheckoutItem{
+				{Product: product, Quantity: 1, SubTotalPrice: entity.NewMoney(10950)},
+			},
+			TotalItem:  1,
+			TotalPrice: entity.NewMoney(10950 - 1095),
+			Discounts: []*entity.CheckoutDiscount{
+				{CartPromotionID: 5, Type: entity.CartDiscountInPercent, Description: "10% off", Amount: entity.NewMoney(1095), CouponCode: "ALEXA10"},
+			},
+			DiscountPrice: entity.NewMoney(1095),
+			CustomerID:    "cust-1",
+			Coupons:       []*entity.Coupon{coupon},
+		}, resp)
+	})
+
+	t.Run("negative, unknown coupon", func(t *testing.T) {
+		productRepo.EXPECT().GetProductBySerials(ctx, gomock.Any()).Return([]*entity.Product{product}, nil).Times(1)
+		promoRepo.EXPECT().GetPromotionByProducts(ctx, []*entity.Product{product}).Return(nil, nil).Times(1)
+		promoRepo.EXPECT().GetActiveBundlesByProducts(ctx, gomock.Any()).Return(nil, nil).Times(1)
+		promoRepo.EXPECT().GetCouponsByCodes(ctx, []string{"NOPE"}).Return(nil, nil).Times(1)
+
+		_, err := svc.Submit(ctx, payload, entity.CheckoutOptions{CouponCodes: []string{"nope"}})
+		assert.Equal(t, entity.NewError(entity.CouponNotFound+": NOPE", http.StatusBadRequest), err)
+	})
+
+	t.Run("negative, expired coupon", func(t *testing.T) {
+		expired := &entity.Coupon{ID: 2, Code: "SPRING", CartPromotionID: 5, ExpiresAt: &dayCreated}
+		productRepo.EXPECT().GetProductBySerials(ctx, gomock.Any()).Return([]*entity.Product{product}, nil).Times(1)
+		promoRepo.EXPECT().GetPromotionByProducts(ctx, []*entity.Product{product}).Return(nil, nil).Times(1)
+		promoRepo.EXPECT().GetActiveBundlesByProducts(ctx, gomock.Any()).Return(nil, nil).Times(1)
+		promoRepo.EXPECT().GetCouponsByCodes(ctx, []string{"SPRING"}).Return([]*entity.Coupon{expired}, nil).Times(1)
+
+		_, err := svc.Submit(ctx, payload, entity.CheckoutOptions{CouponCodes: []string{"SPRING"}})
+		assert.Equal(t, entity.NewError(entity.CouponExpired+": SPRING", http.StatusBadRequest), err)
+	})
+
+	t.Run("negative, per customer limit without customer", func(t *testing.T) {
+		productRepo.EXPECT().GetProductBySerials(ctx, gomock.Any()).Return([]*entity.Product{product}, nil).Times(1)
+		promoRepo.EXPECT().GetPromotionByProducts(ctx, []*entity.Product{product}).Return(nil, nil).Times(1)
+		promoRepo.EXPECT().GetActiveBundlesByProducts(ctx, gomock.Any()).Return(nil, nil).Times(1)
+		promoRepo.EXPECT().GetCouponsByCodes(ctx, []string{"ALEXA10"}).Return([]*entity.Coupon{coupon}, nil).Times(1)
+
+		_, err := svc.Submit(ctx, payload, entity.CheckoutOptions{CouponCodes: []string{"ALEXA10"}})
+		assert.Equal(t, entity.NewError(entity.CustomerRequired+": ALEXA10", http.StatusBadRequest), err)
+	})
+}
+
+func Test_SubmitBundles(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+
+	svc, productRepo, promoRepo := initCheckoutUC(ctrl)
+
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+	products := []*entity.Product{
+		{ID: 1, Serial: "120P90", Name: "Google Home", Price: entity.NewMoney(4999), UpdatedAt: dayCreated},
+		{ID: 2, Serial: "43N23P", Name: "MacBook Pro", Price: entity.NewMoney(539999), UpdatedAt: dayCreated},
+		{ID: 3, Serial: "A304SD", Name: "Alexa Speaker", Price: entity.NewMoney(10950), UpdatedAt: dayCreated},
+		{ID: 4, Serial: "234234", Name: "Raspberry Pi B", Price: entity.NewMoney(3000), UpdatedAt: dayCreated},
+	}
+	macbookAlexa := &entity.Bundle{ID: 1, Type: entity.BundleFixedPrice, Name: "MacBook Pro + Alexa Speaker", Price: entity.NewMoney(545000), Items: []*entity.BundleItem{
+		{BundleID: 1, ProductID: 2, Quantity: 1},
+		{BundleID: 1, ProductID: 3, Quantity: 1},
+	}}
+	smartHome := &entity.Bundle{ID: 2, Type: entity.BundleGroupPrice, Name: "Any 3 smart home for the price of 2", MatchQuantity: 3, PromoValue: 2, Items: []*entity.BundleItem{
+		{BundleID: 2, ProductID: 1},
+		{BundleID: 2, ProductID: 3},
+		{BundleID: 2, ProductID: 4},
+	}}
+
+	submit := func(payload entity.MapProductSerialQuantity, found []*entity.Product, promotions map[int64][]*entity.Promotion, bundles []*entity.Bundle) *entity.Checkout {
+		productRepo.EXPECT().GetProductBySerials(ctx, gomock.Any()).Return(found, nil).Times(1)
+		promoRepo.EXPECT().GetPromotionByProducts(ctx, found).Return(promotions, nil).Times(1)
+		promoRepo.EXPECT().GetActiveBundlesByProducts(ctx, gomock.Any()).Return(bundles, nil).Times(1)
+		promoRepo.EXPECT().GetActiveCartPromotions(ctx, nil).Return(nil, nil).Times(1)
+		productRepo.EXPECT().SubmitCheckout(ctx, gomock.Any()).Return(nil).Times(1)
+
+		resp, err := svc.Submit(ctx, payload, entity.CheckoutOptions{})
+		assert.Nil(t, err)
+		return resp
+	}
+
+	t.Run("MacBook Pro + Alexa Speaker for 5450.00, allocated by list price", func(t *testing.T) {
+		resp := submit(entity.MapProductSerialQuantity{"43N23P": 1, "A304SD": 2}, []*entity.Product{products[1], products[2]}, nil, []*entity.Bundle{macbookAlexa})
+		assert.Equal(t, &entity.Checkout{
+			Items: []*entity.CheckoutItem{
+				{Product: products[1], Quantity: 1, SubTotalPrice: entity.NewMoney(539999 - 5830), BundleID: 1, BundleDiscount: entity.NewMoney(5830),
+					AppliedPromotions: appliedPromotion(entity.SourceBundle, 1, int(entity.BundleFixedPrice), "MacBook Pro + Alexa Speaker", 5830)},
+				{Product: products[2], Quantity: 2, SubTotalPrice: entity.NewMoney(21900 - 119), BundleID: 1, BundleDiscount: entity.NewMoney(119),
+					AppliedPromotions: appliedPromotion(entity.SourceBundle, 1, int(entity.BundleFixedPrice), "MacBook Pro + Alexa Speaker", 119)},
+			},
+			TotalItem:  3,
+			TotalPrice: entity.NewMoney(545000 + 10950),
+		}, resp)
+	})
+
+	t.Run("Any 3 for the price of 2, the cheapest is free", func(t *testing.T) {
+		resp := submit(entity.MapProductSerialQuantity{"120P90": 2, "234234": 2}, []*entity.Product{products[0], products[3]}, nil, []*entity.Bundle{smartHome})
+		assert.Equal(t, &entity.Checkout{
+			Items: []*entity.CheckoutItem{
+				{Product: products[0], Quantity: 2, SubTotalPrice: entity.NewMoney(9998)},
+				{Product: products[3], Quantity: 2, SubTotalPrice: entity.NewMoney(3000), BundleID: 2, BundleDiscount: entity.NewMoney(3000),
+					AppliedPromotions: appliedPromotion(entity.SourceBundle, 2, int(entity.BundleGroupPrice), "Any 3 smart home for the price of 2", 3000)},
+			},
+			TotalItem:  4,
+			TotalPrice: entity.NewMoney(9998 + 3000),
+		}, resp)
+	})
+
+	t.Run("Item with product promotion is not counted in bundle", func(t *testing.T) {
+		promotions := map[int64][]*entity.Promotion{
+			3: {{ID: 3, Type: entity.DiscountInPercent, ProductID: 3, MatchQuantity: 1, PromoValue: 10}},
+		}
+		resp := submit(entity.MapProductSerialQuantity{"43N23P": 1, "A304SD": 1}, []*entity.Product{products[1], products[2]}, promotions, []*entity.Bundle{macbookAlexa})
+		assert.Equal(t, &entity.Checkout{
+			Items: []*entity.CheckoutItem{
+				{Product: products[1], Quantity: 1, SubTotalPrice: entity.NewMoney(539999)},
+				{Product: products[2], Quantity: 1, SubTotalPrice: entity.NewMoney(10950 - 1095), PromotionID: 3,
+					AppliedPromotions: appliedPromotion(entity.SourcePromotion, 3, int(entity.DiscountInPercent), "10% off", 1095)},
+			},
+			TotalItem:  2,
+			TotalPrice: entity.NewMoney(539999 + 10950 - 1095),
+			NotMetPromotions: []*entity.AppliedPromotion{
+				{Source: entity.SourceBundle, ID: 1, Type: int(entity.BundleFixedPrice), Description: "MacBook Pro + Alexa Speaker", Reason: "Alexa Speaker already has product promotion"},
+			},
+		}, resp)
+	})
+
+	t.Run("Item that only gives free item is counted in bundle", func(t *testing.T) {
+		// seeded promotion, buy 1 MacBook Pro get 1 Raspberry Pi B free
+		promotions := map[int64][]*entity.Promotion{
+			2: {{ID: 1, Type: entity.BonusItem, ProductID: 2, MatchQuantity: 1, PromoValue: 1, PromoProductID: 4, UpdatedAt: dayCreated}},
+		}
+		found := []*entity.Product{products[1], products[2], products[3]}
+		resp := submit(entity.MapProductSerialQuantity{"43N23P": 1, "A304SD": 1, "234234": 1}, found, promotions, []*entity.Bundle{macbookAlexa})
+		assert.Equal(t, &entity.Checkout{
+			Items: []*entity.CheckoutItem{
+				{Product: products[1], Quantity: 1, SubTotalPrice: entity.NewMoney(539999 - 5830), PromotionID: 1, BundleID: 1, BundleDiscount: entity.NewMoney(5830),
+					AppliedPromotions: appliedPromotion(entity.SourceBundle, 1, int(entity.BundleFixedPrice), "MacBook Pro + Alexa Speaker", 5830)},
+				{Product: products[2], Quantity: 1, SubTotalPrice: entity.NewMoney(10950 - 119), BundleID: 1, BundleDiscount: entity.NewMoney(119),
+					AppliedPromotions: appliedPromotion(entity.SourceBundle, 1, int(entity.BundleFixedPrice), "MacBook Pro + Alexa Speaker", 119)},
+				// free item is not counted in bundle
+				{Product: products[3], Quantity: 1, SubTotalPrice: entity.NewMoney(0), PromotionID: 1, FreeQuantity: 1,
+					AppliedPromotions: appliedPromotion(entity.SourcePromotion, 1, int(entity.BonusItem), "buy 1 get 1 Raspberry Pi B free", 3000)},
+			},
+			TotalItem:  3,
+			TotalPrice: entity.NewMoney(545000),
+		}, resp)
+	})
+
+	t.Run("Bundles that are not met are explained", func(t *testing.T) {
+		resp := submit(entity.MapProductSerialQuantity{"120P90": 1, "A304SD": 1}, []*entity.Product{products[0], products[2]}, nil, []*entity.Bundle{macbookAlexa, smartHome})
+		assert.Equal(t, &entity.Checkout{
+			Items: []*entity.CheckoutItem{
+				{Product: products[0], Quantity: 1, SubTotalPrice: entity.NewMoney(4999)},
+				{Product: products[2], Quantity: 1, SubTotalPrice: entity.NewMoney(10950)},
+			},
+			TotalItem:  2,
+			TotalPrice: entity.NewMoney(4999 + 10950),
+			NotMetPromotions: []*entity.AppliedPromotion{
+				{Source: entity.SourceBundle, ID: 1, Type: int(entity.BundleFixedPrice), Description: "MacBook Pro + Alexa Speaker", Reason: "bundle items are not complete"},
+				{Source: entity.SourceBundle, ID: 2, Type: int(entity.BundleGroupPrice), Description: "Any 3 smart home for the price of 2", Reason: "buy 1 more items of the bundle"},
+			},
+		}, resp)
+	})
+}
+
+func Test_SubmitTax(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+
+	productRepo := repomocks.NewMockProductRepo(ctrl)
+	promoRepo := repomocks.NewMockPromotionRepo(ctrl)
+	taxRepo := repomocks.NewMockTaxRepo(ctrl)
+	svc := module.NewCheckoutUsecaseWithTax(productRepo, promoRepo, module.DefaultPromotionRules(), taxRepo)
+
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+	googleHome := &entity.Product{ID: 1, Serial: "120P90", Name: "Google Home", Price: entity.NewMoney(4999), TaxClass: "standard", UpdatedAt: dayCreated}
+	macbook := &entity.Product{ID: 2, Serial: "43N23P", Name: "MacBook Pro", Price: entity.NewMoney(539999), UpdatedAt: dayCreated}
+	alexa := &entity.Product{ID: 3, Serial: "A304SD", Name: "Alexa Speaker", Price: entity.NewMoney(10950), TaxClass: "reduced", UpdatedAt: dayCreated}
+	california := &entity.TaxRegion{ID: 1, Code: "US-CA", PriceMode: entity.TaxExclusive, Rates: []*entity.TaxRate{
+		{ID: 1, TaxRegionID: 1, TaxClass: "standard", Rate: 725},
+	}}
+	germany := &entity.TaxRegion{ID: 2, Code: "DE", PriceMode: entity.TaxInclusive, Rates: []*entity.TaxRate{
+		{ID: 2, TaxRegionID: 2, TaxClass: "reduced", Rate: 700},
+		{ID: 3, TaxRegionID: 2, TaxClass: "standard", Rate: 1900},
+	}}
+	tenPercent := &entity.CartPromotion{ID: 1, Type: entity.CartDiscountInPercent, MinSpend: entity.NewMoney(100), PromoValue: 10}
+
+	// expect products without product promotion
+	expectProducts := func(products []*entity.Product, cartPromotions []*entity.CartPromotion) {
+		productRepo.EXPECT().GetProductBySerials(ctx, gomock.Any()).Return(products, nil).Times(1)
+		promoRepo.EXPECT().GetPromotionByProducts(ctx, products).Return(nil, nil).Times(1)
+		promoRepo.EXPECT().GetActiveBundlesByProducts(ctx, gomock.Any()).Return(nil, nil).Times(1)
+		promoRepo.EXPECT().GetActiveCartPromotions(ctx, nil).Return(cartPromotions, nil).Times(1)
+	}
+
+	t.Run("positive, exclusive tax is added to total price", func(t *testing.T) {
+		expectProducts([]*entity.Product{macbook}, nil)
+		taxRepo.EXPECT().GetTaxRegion(ctx, "US-CA").Return(california, nil).Times(1)
+		productRepo.EXPECT().SubmitCheckout(ctx, gomock.Any()).Return(nil).Times(1)
+
+		resp, err := svc.Submit(ctx, entity.MapProductSerialQuantity{"43N23P": 1}, entity.CheckoutOptions{TaxRegion: "US-CA"})
+		assert.Nil(t, err)
+		assert.Equal(t, "US-CA", resp.TaxRegion)
+		assert.Equal(t, entity.TaxExclusive, resp.TaxPriceMode)
+		// product without tax class is standard, 7.25% of 5399.99 is 391.499
+		assert.Equal(t, []*entity.CheckoutTax{
+			{TaxClass: "standard", Rate: 725, TaxableAmount: entity.NewMoney(539999), Amount: entity.NewMoney(39150)},
+		}, resp.Taxes)
+		assert.Equal(t, entity.NewMoney(39150), resp.TaxPrice)
+		assert.Equal(t, entity.NewMoney(539999+39150), resp.TotalPrice)
+	})
+
+	t.Run("positive, inclusive tax per class after cart discount", func(t *testing.T) {
+		expectProducts([]*entity.Product{googleHome, alexa}, []*entity.CartPromotion{tenPercent})
+		taxRepo.EXPECT().GetTaxRegion(ctx, "").Return(germany, nil).Times(1)
+		productRepo.EXPECT().SubmitCheckout(ctx, gomock.Any()).Return(nil).Times(1)
+
+		resp, err := svc.Submit(ctx, entity.MapProductSerialQuantity{"120P90": 1, "A304SD": 1}, entity.CheckoutOptions{})
+		assert.Nil(t, err)
+		// 159.49 - 10% discount is 143.54, allocated by sub total: reduced 98.55, standard 44.99
+		assert.Equal(t, []*entity.CheckoutTax{
+			{TaxClass: "reduced", Rate: 700, TaxableAmount: entity.NewMoney(9855 - 645), Amount: entity.NewMoney(645)},
+			{TaxClass: "standard", Rate: 1900, TaxableAmount: entity.NewMoney(4499 - 718), Amount: entity.NewMoney(718)},
+		}, resp.Taxes)
+		assert.Equal(t, entity.NewMoney(645+718), resp.TaxPrice)
+		assert.Equal(t, entity.NewMoney(14354), resp.TotalPrice)
+	})
+
+	t.Run("positive, no default region", func(t *testing.T) {
+		expectProducts([]*entity.Product{macbook}, nil)
+		taxRepo.EXPECT().GetTaxRegion(ctx, "").Return(nil, nil).Times(1)
+		productRepo.EXPECT().SubmitCheckout(ctx, gomock.Any()).Return(nil).Times(1)
+
+		resp, err := svc.Submit(ctx, entity.MapProductSerialQuantity{"43N23P": 1}, entity.CheckoutOptions{})
+		assert.Nil(t, err)
+		assert.Empty(t, resp.Taxes)
+		assert.Equal(t, entity.NewMoney(539999), resp.TotalPrice)
+	})
+
+	t.Run("negative, unknown region", func(t *testing.T) {
+		expectProducts([]*entity.Product{macbook}, nil)
+		taxRepo.EXPECT().GetTaxRegion(ctx, "XX").Return(nil, nil).Times(1)
+
+		_, err := svc.Submit(ctx, entity.MapProductSerialQuantity{"43N23P": 1}, entity.CheckoutOptions{TaxRegion: "XX"})
+		assert.Equal(t, entity.NewError(entity.TaxRegionNotFound, http.StatusBadRequest), err)
+	})
 }
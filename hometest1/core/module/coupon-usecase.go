@@ -0,0 +1,116 @@
+package module
+
+import (
+	"context"
+	"net/http"
+
+	"hometest1/core/entity"
+	"hometest1/core/repository"
+)
+
+type CouponUsecase interface {
+	// create coupon of a cart promotion, code is case insensitive
+	Create(ctx context.Context, payload *entity.Coupon) (*entity.Coupon, error)
+	// update coupon by id, redeemed count is kept
+	Update(ctx context.Context, payload *entity.Coupon) (*entity.Coupon, error)
+	// soft delete coupon
+	Delete(ctx context.Context, couponID int64) error
+	// get coupons, page start from 1
+	List(ctx context.Context, page, limit int) (*entity.CouponList, error)
+}
+
+type couponUsecase struct {
+	promoRepo repository.PromotionRepo
+}
+
+func NewCouponUsecase(promoRepo repository.PromotionRepo) CouponUsecase {
+	return &couponUsecase{promoRepo}
+}
+
+func (uc *couponUsecase) Create(ctx context.Context, payload *entity.Coupon) (*entity.Coupon, error) {
+	err := uc.validate(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	// repository must handle error with entity.Err
+	err = uc.promoRepo.CreateCoupon(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+func (uc *couponUsecase) Update(ctx context.Context, payload *entity.Coupon) (*entity.Coupon, error) {
+	existing, err := uc.promoRepo.GetCoupon(ctx, payload.ID)
+	if err != nil {
+		return nil, entity.NewInternalError(err)
+	}
+	if existing == nil {
+		return nil, entity.NewError(entity.CouponNotFound, http.StatusNotFound)
+	}
+
+	err = uc.validate(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	// repository must handle error with entity.Err
+	err = uc.promoRepo.UpdateCoupon(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+	payload.RedeemedCount = existing.RedeemedCount
+	return payload, nil
+}
+
+func (uc *couponUsecase) Delete(ctx context.Context, couponID int64) error {
+	existing, err := uc.promoRepo.GetCoupon(ctx, couponID)
+	if err != nil {
+		return entity.NewInternalError(err)
+	}
+	if existing == nil {
+		return entity.NewError(entity.CouponNotFound, http.StatusNotFound)
+	}
+
+	err = uc.promoRepo.DeleteCoupon(ctx, couponID)
+	if err != nil {
+		return entity.NewInternalError(err)
+	}
+	return nil
+}
+
+func (uc *couponUsecase) List(ctx context.Context, page, limit int) (*entity.CouponList, error) {
+	page, limit, offset := normalizePagination(page, limit)
+	coupons, total, err := uc.promoRepo.GetCoupons(ctx, limit, offset)
+	if err != nil {
+		return nil, entity.NewInternalError(err)
+	}
+
+	return &entity.CouponList{
+		Coupons: coupons,
+		Total:   total,
+		Page:    page,
+		Limit:   limit,
+	}, nil
+}
+
+// normalize coupon code and validate the linked cart promotion
+func (uc *couponUsecase) validate(ctx context.Context, coupon *entity.Coupon) error {
+	coupon.Code = entity.NormalizeCouponCode(coupon.Code)
+	if coupon.Code == "" {
+		return entity.NewError("coupon code is required", http.StatusBadRequest)
+	}
+	if coupon.MaxRedemptions < 0 || coupon.PerCustomerLimit < 0 {
+		return entity.NewError("redemption limit cannot be negative", http.StatusBadRequest)
+	}
+
+	promo, err := uc.promoRepo.GetCartPromotion(ctx, coupon.CartPromotionID)
+	if err != nil {
+		return entity.NewInternalError(err)
+	}
+	if promo == nil {
+		return entity.NewError(entity.CartPromotionNotFound, http.StatusBadRequest)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package module_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"hometest1/core/entity"
+	"hometest1/core/module"
+	repomocks "hometest1/core/repository/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func initCouponUC(ctrl *gomock.Controller) (module.CouponUsecase, *repomocks.MockPromotionRepo) {
+	promoRepo := repomocks.NewMockPromotionRepo(ctrl)
+
+	return module.NewCouponUsecase(promoRepo), promoRepo
+}
+
+func Test_CouponCreate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+
+	svc, promoRepo := initCouponUC(ctrl)
+
+	t.Run("positive, code is normalized", func(t *testing.T) {
+		promoRepo.EXPECT().GetCartPromotion(ctx, int64(5)).Return(&entity.CartPromotion{ID: 5, Type: entity.CartDiscountInPercent, PromoValue: 10}, nil).Times(1)
+		expected := &entity.Coupon{Code: "ALEXA10", CartPromotionID: 5, MaxRedemptions: 100, PerCustomerLimit: 1}
+		promoRepo.EXPECT().CreateCoupon(ctx, expected).Return(nil).Times(1)
+
+		resp, err := svc.Create(ctx, &entity.Coupon{Code: " alexa10 ", CartPromotionID: 5, MaxRedemptions: 100, PerCustomerLimit: 1})
+		assert.Nil(t, err)
+		assert.Equal(t, expected, resp)
+	})
+
+	t.Run("negative, cart promotion not found", func(t *testing.T) {
+		promoRepo.EXPECT().GetCartPromotion(ctx, int64(9)).Return(nil, nil).Times(1)
+
+		_, err := svc.Create(ctx, &entity.Coupon{Code: "ALEXA10", CartPromotionID: 9})
+		assert.Equal(t, entity.NewError(entity.CartPromotionNotFound, http.StatusBadRequest), err)
+	})
+
+	t.Run("negative, code exists", func(t *testing.T) {
+		promoRepo.EXPECT().GetCartPromotion(ctx, int64(5)).Return(&entity.CartPromotion{ID: 5}, nil).Times(1)
+		promoRepo.EXPECT().CreateCoupon(ctx, gomock.Any()).Return(entity.NewError(entity.CouponCodeExists, http.StatusConflict)).Times(1)
+
+		_, err := svc.Create(ctx, &entity.Coupon{Code: "ALEXA10", CartPromotionID: 5})
+		assert.Equal(t, entity.NewError(entity.CouponCodeExists, http.StatusConflict), err)
+	})
+
+	t.Run("negative, negative limit", func(t *testing.T) {
+		_, err := svc.Create(ctx, &entity.Coupon{Code: "ALEXA10", CartPromotionID: 5, MaxRedemptions: -1})
+		assert.Equal(t, entity.NewError("redemption limit cannot be negative", http.StatusBadRequest), err)
+	})
+}
+
+func Test_CouponUpdate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+
+	svc, promoRepo := initCouponUC(ctrl)
+
+	t.Run("positive, redeemed count is kept", func(t *testing.T) {
+		promoRepo.EXPECT().GetCoupon(ctx, int64(1)).Return(&entity.Coupon{ID: 1, Code: "ALEXA10", CartPromotionID: 5, RedeemedCount: 12}, nil).Times(1)
+		promoRepo.EXPECT().GetCartPromotion(ctx, int64(5)).Return(&entity.CartPromotion{ID: 5}, nil).Times(1)
+		promoRepo.EXPECT().UpdateCoupon(ctx, &entity.Coupon{ID: 1, Code: "ALEXA15", CartPromotionID: 5, MaxRedemptions: 50}).Return(nil).Times(1)
+
+		resp, err := svc.Update(ctx, &entity.Coupon{ID: 1, Code: "alexa15", CartPromotionID: 5, MaxRedemptions: 50})
+		assert.Nil(t, err)
+		assert.Equal(t, &entity.Coupon{ID: 1, Code: "ALEXA15", CartPromotionID: 5, MaxRedemptions: 50, RedeemedCount: 12}, resp)
+	})
+
+	t.Run("negative, not found", func(t *testing.T) {
+		promoRepo.EXPECT().GetCoupon(ctx, int64(9)).Return(nil, nil).Times(1)
+
+		_, err := svc.Update(ctx, &entity.Coupon{ID: 9, Code: "ALEXA10", CartPromotionID: 5})
+		assert.Equal(t, entity.NewError(entity.CouponNotFound, http.StatusNotFound), err)
+	})
+}
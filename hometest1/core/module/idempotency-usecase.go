@@ -0,0 +1,104 @@
+package module
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"hometest1/core/entity"
+	"hometest1/core/repository"
+)
+
+// in progress request older than this is treated as abandoned, eg: server is restarted
+const idempotencyLockTimeout = time.Minute
+
+type IdempotencyUsecase interface {
+	// start request of the key, return the in progress key owned by this request,
+	// or the completed key to replay if the same request is completed.
+	// Key used with different request is rejected with 422, request still in progress with 409
+	Begin(ctx context.Context, key, requestHash string) (*entity.IdempotencyKey, error)
+	// store response of the key returned by Begin, server error releases the key so client can retry.
+	// Nothing is changed if the key is taken over by other request
+	Complete(ctx context.Context, key *entity.IdempotencyKey, responseCode int, responseBody []byte) error
+}
+
+type idempotencyUsecase struct {
+	idempotencyRepo repository.IdempotencyRepo
+	clock           entity.Clock
+}
+
+func NewIdempotencyUsecase(idempotencyRepo repository.IdempotencyRepo) IdempotencyUsecase {
+	return NewIdempotencyUsecaseWithClock(idempotencyRepo, time.Now)
+}
+
+// create idempotency usecase with custom clock to expire abandoned request
+func NewIdempotencyUsecaseWithClock(idempotencyRepo repository.IdempotencyRepo, clock entity.Clock) IdempotencyUsecase {
+	return &idempotencyUsecase{idempotencyRepo, clock}
+}
+
+func (uc *idempotencyUsecase) Begin(ctx context.Context, key, requestHash string) (*entity.IdempotencyKey, error) {
+	if key == "" || len(key) > 255 {
+		return nil, entity.NewError(entity.InvalidIdempotencyKey, http.StatusBadRequest)
+	}
+
+	owned := &entity.IdempotencyKey{Key: key, RequestHash: requestHash}
+	created, err := uc.idempotencyRepo.CreateKey(ctx, owned)
+	if err != nil {
+		return nil, entity.NewInternalError(err)
+	}
+	if created {
+		return owned, nil
+	}
+
+	existing, err := uc.idempotencyRepo.GetKey(ctx, key)
+	if err != nil {
+		return nil, entity.NewInternalError(err)
+	}
+	// key is released by the other request in between
+	if existing == nil {
+		return nil, entity.NewError(entity.IdempotencyKeyInProgress, http.StatusConflict)
+	}
+	if existing.RequestHash != requestHash {
+		return nil, entity.NewError(entity.IdempotencyKeyMismatch, http.StatusUnprocessableEntity)
+	}
+	if existing.IsCompleted() {
+		return existing, nil
+	}
+	if uc.clock().Sub(existing.UpdatedAt) < idempotencyLockTimeout {
+		return nil, entity.NewError(entity.IdempotencyKeyInProgress, http.StatusConflict)
+	}
+
+	// take over abandoned request, the stale key is deleted by its id,
+	// so only one of concurrent retries deletes it and creates the key again
+	deleted, err := uc.idempotencyRepo.DeleteKey(ctx, existing)
+	if err != nil {
+		return nil, entity.NewInternalError(err)
+	}
+	if !deleted {
+		return nil, entity.NewError(entity.IdempotencyKeyInProgress, http.StatusConflict)
+	}
+	created, err = uc.idempotencyRepo.CreateKey(ctx, owned)
+	if err != nil {
+		return nil, entity.NewInternalError(err)
+	}
+	if !created {
+		return nil, entity.NewError(entity.IdempotencyKeyInProgress, http.StatusConflict)
+	}
+	return owned, nil
+}
+
+func (uc *idempotencyUsecase) Complete(ctx context.Context, key *entity.IdempotencyKey, responseCode int, responseBody []byte) error {
+	var err error
+	if responseCode >= http.StatusInternalServerError {
+		// key of other request that took over is not released
+		_, err = uc.idempotencyRepo.DeleteKey(ctx, key)
+	} else {
+		key.ResponseCode = responseCode
+		key.ResponseBody = string(responseBody)
+		err = uc.idempotencyRepo.SaveResponse(ctx, key)
+	}
+	if err != nil {
+		return entity.NewInternalError(err)
+	}
+	return nil
+}
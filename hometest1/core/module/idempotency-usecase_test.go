@@ -0,0 +1,181 @@
+package module_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"hometest1/core/entity"
+	"hometest1/core/module"
+	"hometest1/core/repository"
+	repomocks "hometest1/core/repository/mocks"
+	memoryrepository "hometest1/repository/memory-repository"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func initIdempotencyUC(ctrl *gomock.Controller, now time.Time) (module.IdempotencyUsecase, *repomocks.MockIdempotencyRepo) {
+	idempotencyRepo := repomocks.NewMockIdempotencyRepo(ctrl)
+	return module.NewIdempotencyUsecaseWithClock(idempotencyRepo, func() time.Time { return now }), idempotencyRepo
+}
+
+func Test_IdempotencyBegin(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+
+	now, _ := time.Parse("2006-01-02 15:04:05", "2023-05-16 10:00:00")
+	svc, idempotencyRepo := initIdempotencyUC(ctrl, now)
+	newKey := &entity.IdempotencyKey{Key: "pos-1", RequestHash: "abc"}
+
+	t.Run("positive, new key proceeds the request", func(t *testing.T) {
+		idempotencyRepo.EXPECT().CreateKey(ctx, newKey).Return(true, nil).Times(1)
+
+		resp, err := svc.Begin(ctx, "pos-1", "abc")
+		assert.Nil(t, err)
+		assert.Equal(t, newKey, resp)
+		assert.False(t, resp.IsCompleted())
+	})
+
+	t.Run("positive, completed request is replayed", func(t *testing.T) {
+		completed := &entity.IdempotencyKey{ID: 1, Key: "pos-1", RequestHash: "abc", ResponseCode: 200, ResponseBody: `{"orderId":1}`, UpdatedAt: now}
+		idempotencyRepo.EXPECT().CreateKey(ctx, newKey).Return(false, nil).Times(1)
+		idempotencyRepo.EXPECT().GetKey(ctx, "pos-1").Return(completed, nil).Times(1)
+
+		resp, err := svc.Begin(ctx, "pos-1", "abc")
+		assert.Nil(t, err)
+		assert.Equal(t, completed, resp)
+	})
+
+	t.Run("positive, abandoned request is taken over", func(t *testing.T) {
+		abandoned := &entity.IdempotencyKey{ID: 1, Key: "pos-1", RequestHash: "abc", UpdatedAt: now.Add(-2 * time.Minute)}
+		idempotencyRepo.EXPECT().CreateKey(ctx, newKey).Return(false, nil).Times(1)
+		idempotencyRepo.EXPECT().GetKey(ctx, "pos-1").Return(abandoned, nil).Times(1)
+		idempotencyRepo.EXPECT().DeleteKey(ctx, abandoned).Return(true, nil).Times(1)
+		idempotencyRepo.EXPECT().CreateKey(ctx, newKey).Return(true, nil).Times(1)
+
+		resp, err := svc.Begin(ctx, "pos-1", "abc")
+		assert.Nil(t, err)
+		assert.Equal(t, newKey, resp)
+	})
+
+	t.Run("negative, abandoned request is taken over by other retry", func(t *testing.T) {
+		abandoned := &entity.IdempotencyKey{ID: 1, Key: "pos-1", RequestHash: "abc", UpdatedAt: now.Add(-2 * time.Minute)}
+		idempotencyRepo.EXPECT().CreateKey(ctx, newKey).Return(false, nil).Times(1)
+		idempotencyRepo.EXPECT().GetKey(ctx, "pos-1").Return(abandoned, nil).Times(1)
+		idempotencyRepo.EXPECT().DeleteKey(ctx, abandoned).Return(false, nil).Times(1)
+
+		_, err := svc.Begin(ctx, "pos-1", "abc")
+		assert.Equal(t, entity.NewError(entity.IdempotencyKeyInProgress, http.StatusConflict), err)
+	})
+
+	t.Run("negative, key is used with different request", func(t *testing.T) {
+		idempotencyRepo.EXPECT().CreateKey(ctx, &entity.IdempotencyKey{Key: "pos-1", RequestHash: "def"}).Return(false, nil).Times(1)
+		idempotencyRepo.EXPECT().GetKey(ctx, "pos-1").Return(&entity.IdempotencyKey{ID: 1, Key: "pos-1", RequestHash: "abc", ResponseCode: 200}, nil).Times(1)
+
+		_, err := svc.Begin(ctx, "pos-1", "def")
+		assert.Equal(t, entity.NewError(entity.IdempotencyKeyMismatch, http.StatusUnprocessableEntity), err)
+	})
+
+	t.Run("negative, request is still in progress", func(t *testing.T) {
+		idempotencyRepo.EXPECT().CreateKey(ctx, newKey).Return(false, nil).Times(1)
+		idempotencyRepo.EXPECT().GetKey(ctx, "pos-1").Return(&entity.IdempotencyKey{ID: 1, Key: "pos-1", RequestHash: "abc", UpdatedAt: now.Add(-time.Second)}, nil).Times(1)
+
+		_, err := svc.Begin(ctx, "pos-1", "abc")
+		assert.Equal(t, entity.NewError(entity.IdempotencyKeyInProgress, http.StatusConflict), err)
+	})
+
+	t.Run("negative, key too long", func(t *testing.T) {
+		_, err := svc.Begin(ctx, string(make([]byte, 256)), "abc")
+		assert.Equal(t, entity.NewError(entity.InvalidIdempotencyKey, http.StatusBadRequest), err)
+	})
+
+	t.Run("negative, repository error", func(t *testing.T) {
+		idempotencyRepo.EXPECT().CreateKey(ctx, newKey).Return(false, errors.New("connection lost")).Times(1)
+
+		_, err := svc.Begin(ctx, "pos-1", "abc")
+		assert.Equal(t, entity.NewError("connection lost", http.StatusInternalServerError), err)
+	})
+}
+
+func Test_IdempotencyComplete(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+
+	now, _ := time.Parse("2006-01-02 15:04:05", "2023-05-16 10:00:00")
+	svc, idempotencyRepo := initIdempotencyUC(ctrl, now)
+
+	t.Run("positive, response is stored", func(t *testing.T) {
+		idempotencyRepo.EXPECT().SaveResponse(ctx, &entity.IdempotencyKey{ID: 1, Key: "pos-1", RequestHash: "abc", ResponseCode: 400, ResponseBody: `{"message":"cart is empty"}`}).Return(nil).Times(1)
+
+		err := svc.Complete(ctx, &entity.IdempotencyKey{ID: 1, Key: "pos-1", RequestHash: "abc"}, 400, []byte(`{"message":"cart is empty"}`))
+		assert.Nil(t, err)
+	})
+
+	t.Run("positive, server error releases the key by its id", func(t *testing.T) {
+		key := &entity.IdempotencyKey{ID: 1, Key: "pos-1", RequestHash: "abc"}
+		idempotencyRepo.EXPECT().DeleteKey(ctx, key).Return(true, nil).Times(1)
+
+		err := svc.Complete(ctx, key, 500, []byte(`{"message":"connection lost"}`))
+		assert.Nil(t, err)
+	})
+}
+
+// idempotency repository that waits until every request has read the key
+type barrierIdempotencyRepo struct {
+	repository.IdempotencyRepo
+	read *sync.WaitGroup
+}
+
+func (r *barrierIdempotencyRepo) GetKey(ctx context.Context, key string) (*entity.IdempotencyKey, error) {
+	result, err := r.IdempotencyRepo.GetKey(ctx, key)
+	r.read.Done()
+	r.read.Wait()
+	return result, err
+}
+
+func Test_IdempotencyTakeOver(t *testing.T) {
+	ctx := context.Background()
+	now, _ := time.Parse("2006-01-02 15:04:05", "2023-05-16 10:00:00")
+
+	t.Run("positive, only one of concurrent retries takes over abandoned request", func(t *testing.T) {
+		// key is created 2 minutes ago and abandoned
+		store := memoryrepository.NewStoreWithClock(func() time.Time { return now.Add(-2 * time.Minute) })
+		created, err := memoryrepository.NewIdempotencyRepo(store).CreateKey(ctx, &entity.IdempotencyKey{Key: "pos-1", RequestHash: "abc"})
+		assert.Nil(t, err)
+		assert.True(t, created)
+
+		// both retries read the same stale key before any of them takes it over
+		retries := 2
+		var read sync.WaitGroup
+		read.Add(retries)
+		repo := &barrierIdempotencyRepo{memoryrepository.NewIdempotencyRepo(store), &read}
+		svc := module.NewIdempotencyUsecaseWithClock(repo, func() time.Time { return now })
+
+		var wg sync.WaitGroup
+		errs := make([]error, retries)
+		for i := 0; i < retries; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				_, errs[i] = svc.Begin(ctx, "pos-1", "abc")
+			}(i)
+		}
+		wg.Wait()
+
+		var succeeded int
+		for _, err := range errs {
+			if err == nil {
+				succeeded++
+				continue
+			}
+			assert.Equal(t, entity.NewError(entity.IdempotencyKeyInProgress, http.StatusConflict), err)
+		}
+		assert.Equal(t, 1, succeeded)
+	})
+}
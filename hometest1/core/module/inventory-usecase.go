@@ -0,0 +1,72 @@
+package module
+
+import (
+	"context"
+	"net/http"
+
+	"hometest1/core/entity"
+	"hometest1/core/repository"
+)
+
+type InventoryUsecase interface {
+	// add product stock by serial, reference is free text eg: purchase order number
+	Restock(ctx context.Context, serial string, quantity int, reference string) (*entity.ProductStock, error)
+	// get stock movements of the product sorted by newest, page start from 1
+	History(ctx context.Context, serial string, page, limit int) (*entity.StockMovementList, error)
+}
+
+type inventoryUsecase struct {
+	inventoryRepo repository.InventoryRepo
+	productRepo   repository.ProductRepo
+}
+
+func NewInventoryUsecase(inventoryRepo repository.InventoryRepo, productRepo repository.ProductRepo) InventoryUsecase {
+	return &inventoryUsecase{inventoryRepo, productRepo}
+}
+
+func (uc *inventoryUsecase) Restock(ctx context.Context, serial string, quantity int, reference string) (*entity.ProductStock, error) {
+	if quantity < 1 {
+		return nil, entity.NewError(entity.EmptyQuantity, http.StatusBadRequest)
+	}
+	product, err := uc.getProduct(ctx, serial)
+	if err != nil {
+		return nil, err
+	}
+
+	productQuantity, err := uc.inventoryRepo.Restock(ctx, product.ID, quantity, reference)
+	if err != nil {
+		return nil, entity.NewInternalError(err)
+	}
+	return &entity.ProductStock{Product: product, Quantity: productQuantity.Quantity}, nil
+}
+
+func (uc *inventoryUsecase) History(ctx context.Context, serial string, page, limit int) (*entity.StockMovementList, error) {
+	product, err := uc.getProduct(ctx, serial)
+	if err != nil {
+		return nil, err
+	}
+
+	page, limit, offset := normalizePagination(page, limit)
+	movements, total, err := uc.inventoryRepo.GetStockMovements(ctx, product.ID, limit, offset)
+	if err != nil {
+		return nil, entity.NewInternalError(err)
+	}
+	return &entity.StockMovementList{
+		Product:   product,
+		Movements: movements,
+		Total:     total,
+		Page:      page,
+		Limit:     limit,
+	}, nil
+}
+
+func (uc *inventoryUsecase) getProduct(ctx context.Context, serial string) (*entity.Product, error) {
+	products, err := uc.productRepo.GetProductBySerials(ctx, []string{serial})
+	if err != nil {
+		return nil, entity.NewInternalError(err)
+	}
+	if len(products) == 0 {
+		return nil, entity.NewError(entity.ProductNotFound, http.StatusNotFound)
+	}
+	return products[0], nil
+}
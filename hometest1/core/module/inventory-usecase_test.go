@@ -0,0 +1,81 @@
+package module_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"hometest1/core/entity"
+	"hometest1/core/module"
+	repomocks "hometest1/core/repository/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func initInventoryUC(ctrl *gomock.Controller) (module.InventoryUsecase, *repomocks.MockInventoryRepo, *repomocks.MockProductRepo) {
+	inventoryRepo := repomocks.NewMockInventoryRepo(ctrl)
+	productRepo := repomocks.NewMockProductRepo(ctrl)
+	return module.NewInventoryUsecase(inventoryRepo, productRepo), inventoryRepo, productRepo
+}
+
+func Test_InventoryRestock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+
+	svc, inventoryRepo, productRepo := initInventoryUC(ctrl)
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+	product := &entity.Product{ID: 4, Serial: "234234", Name: "Raspberry Pi B", Price: entity.NewMoney(3000), UpdatedAt: dayCreated}
+
+	t.Run("positive", func(t *testing.T) {
+		productRepo.EXPECT().GetProductBySerials(ctx, []string{"234234"}).Return([]*entity.Product{product}, nil).Times(1)
+		inventoryRepo.EXPECT().Restock(ctx, int64(4), 10, "PO-001").Return(&entity.ProductQuantity{ID: 4, ProductID: 4, Quantity: 12}, nil).Times(1)
+
+		resp, err := svc.Restock(ctx, "234234", 10, "PO-001")
+		assert.Nil(t, err)
+		assert.Equal(t, &entity.ProductStock{Product: product, Quantity: 12}, resp)
+	})
+
+	t.Run("negative, empty quantity", func(t *testing.T) {
+		_, err := svc.Restock(ctx, "234234", 0, "PO-001")
+		assert.Equal(t, entity.NewError(entity.EmptyQuantity, http.StatusBadRequest), err)
+	})
+
+	t.Run("negative, product not found", func(t *testing.T) {
+		productRepo.EXPECT().GetProductBySerials(ctx, []string{"XXX"}).Return(nil, nil).Times(1)
+
+		_, err := svc.Restock(ctx, "XXX", 10, "PO-001")
+		assert.Equal(t, entity.NewError(entity.ProductNotFound, http.StatusNotFound), err)
+	})
+}
+
+func Test_InventoryHistory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+
+	svc, inventoryRepo, productRepo := initInventoryUC(ctrl)
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+	product := &entity.Product{ID: 4, Serial: "234234", Name: "Raspberry Pi B", Price: entity.NewMoney(3000), UpdatedAt: dayCreated}
+
+	t.Run("positive", func(t *testing.T) {
+		movements := []*entity.StockMovement{
+			{ID: 3, ProductID: 4, Quantity: -1, Reason: entity.StockFreeItem, Reference: "order:1", CreatedAt: dayCreated},
+			{ID: 1, ProductID: 4, Quantity: 2, Reason: entity.StockRestock, Reference: "initial stock", CreatedAt: dayCreated},
+		}
+		productRepo.EXPECT().GetProductBySerials(ctx, []string{"234234"}).Return([]*entity.Product{product}, nil).Times(1)
+		inventoryRepo.EXPECT().GetStockMovements(ctx, int64(4), 2, 2).Return(movements, int64(4), nil).Times(1)
+
+		resp, err := svc.History(ctx, "234234", 2, 2)
+		assert.Nil(t, err)
+		assert.Equal(t, &entity.StockMovementList{
+			Product:   product,
+			Movements: movements,
+			Total:     4,
+			Page:      2,
+			Limit:     2,
+		}, resp)
+	})
+}
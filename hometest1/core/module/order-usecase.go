@@ -0,0 +1,284 @@
+package module
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"hometest1/core/entity"
+	"hometest1/core/repository"
+)
+
+type OrderUsecase interface {
+	Get(ctx context.Context, orderID int64) (*entity.Order, error)
+	// get orders, page start from 1
+	List(ctx context.Context, page, limit int) (*entity.OrderList, error)
+	// return items of the order and restore their stock.
+	// Kept items are priced again with promotions applied to the order, so free item given by returned item is clawed back from the refund
+	Return(ctx context.Context, orderID int64, payload entity.MapProductSerialQuantity) (*entity.OrderReturn, error)
+	// return all kept items of the order and refund the rest of paid price
+	Cancel(ctx context.Context, orderID int64) (*entity.OrderReturn, error)
+}
+
+type orderUsecase struct {
+	orderRepo   repository.OrderRepo
+	productRepo repository.ProductRepo
+	checkoutUC  CheckoutUsecase
+}
+
+func NewOrderUsecase(orderRepo repository.OrderRepo, productRepo repository.ProductRepo, checkoutUC CheckoutUsecase) OrderUsecase {
+	return &orderUsecase{orderRepo, productRepo, checkoutUC}
+}
+
+func (uc *orderUsecase) Get(ctx context.Context, orderID int64) (*entity.Order, error) {
+	order, err := uc.orderRepo.GetOrder(ctx, orderID)
+	if err != nil {
+		return nil, entity.NewInternalError(err)
+	}
+	if order == nil {
+		return nil, entity.NewError(entity.OrderNotFound, http.StatusNotFound)
+	}
+
+	// fill order item products
+	var productIDs []int64
+	for _, item := range order.Items {
+		productIDs = append(productIDs, item.ProductID)
+	}
+	// product may be deleted after the order
+	products, err := uc.productRepo.GetProductByIDsWithDeleted(ctx, productIDs)
+	if err != nil {
+		return nil, entity.NewInternalError(err)
+	}
+	mapProduct := make(map[int64]*entity.Product)
+	for _, product := range products {
+		mapProduct[product.ID] = product
+	}
+	for _, item := range order.Items {
+		item.Product = mapProduct[item.ProductID]
+	}
+
+	return order, nil
+}
+
+func (uc *orderUsecase) List(ctx context.Context, page, limit int) (*entity.OrderList, error) {
+	page, limit, offset := normalizePagination(page, limit)
+	orders, total, err := uc.orderRepo.GetOrders(ctx, limit, offset)
+	if err != nil {
+		return nil, entity.NewInternalError(err)
+	}
+
+	return &entity.OrderList{
+		Orders: orders,
+		Total:  total,
+		Page:   page,
+		Limit:  limit,
+	}, nil
+}
+
+func (uc *orderUsecase) Return(ctx context.Context, orderID int64, payload entity.MapProductSerialQuantity) (*entity.OrderReturn, error) {
+	if len(payload) == 0 {
+		return nil, entity.NewError(entity.EmptyReturn, http.StatusBadRequest)
+	}
+	order, err := uc.getOpenOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	// map returned quantity to order items, following order item sequence
+	ret := entity.OrderReturn{OrderID: order.ID, Order: order}
+	remaining := make(entity.MapProductSerialQuantity)
+	for serial, quantity := range payload {
+		remaining[serial] = quantity
+	}
+	for _, item := range order.Items {
+		if item.Product == nil || remaining[item.Product.Serial] == 0 {
+			continue
+		}
+		quantity := min(remaining[item.Product.Serial], item.KeptQuantity())
+		if quantity == 0 {
+			continue
+		}
+		remaining[item.Product.Serial] -= quantity
+		ret.Items = append(ret.Items, &entity.OrderReturnItem{
+			OrderItemID: item.ID,
+			ProductID:   item.ProductID,
+			Quantity:    quantity,
+		})
+	}
+	if err := uc.validateReturnQuantity(order, payload, remaining); err != nil {
+		return nil, err
+	}
+
+	ret.RefundPrice, err = uc.computeRefund(ctx, order, ret.Items)
+	if err != nil {
+		return nil, err
+	}
+	status := entity.OrderPartiallyReturned
+	if uc.isAllReturned(order, ret.Items) {
+		status = entity.OrderReturned
+	}
+	return uc.saveReturn(ctx, &ret, status)
+}
+
+func (uc *orderUsecase) Cancel(ctx context.Context, orderID int64) (*entity.OrderReturn, error) {
+	order, err := uc.getOpenOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := entity.OrderReturn{OrderID: order.ID, Order: order, RefundPrice: order.NetPrice()}
+	for _, item := range order.Items {
+		if item.KeptQuantity() == 0 {
+			continue
+		}
+		ret.Items = append(ret.Items, &entity.OrderReturnItem{
+			OrderItemID: item.ID,
+			ProductID:   item.ProductID,
+			Quantity:    item.KeptQuantity(),
+		})
+	}
+	return uc.saveReturn(ctx, &ret, entity.OrderCancelled)
+}
+
+// get order with products that still has kept items
+func (uc *orderUsecase) getOpenOrder(ctx context.Context, orderID int64) (*entity.Order, error) {
+	order, err := uc.Get(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.IsClosed() {
+		return nil, entity.NewError(entity.OrderClosed, http.StatusConflict)
+	}
+	return order, nil
+}
+
+// reject serial that is not in the order, or quantity more than kept quantity
+func (uc *orderUsecase) validateReturnQuantity(order *entity.Order, payload, remaining entity.MapProductSerialQuantity) error {
+	kept := make(map[string]int)
+	mapProduct := make(map[string]*entity.Product)
+	for _, item := range order.Items {
+		if item.Product != nil {
+			kept[item.Product.Serial] += item.KeptQuantity()
+			mapProduct[item.Product.Serial] = item.Product
+		}
+	}
+
+	serials := payload.PluckSerial()
+	sort.Strings(serials)
+	for _, serial := range serials {
+		if remaining[serial] == 0 {
+			continue
+		}
+		product, ok := mapProduct[serial]
+		if !ok {
+			return entity.NewError(fmt.Sprintf("%s: %s", entity.ProductNotOrdered, serial), http.StatusBadRequest)
+		}
+		return entity.NewError(
+			fmt.Sprintf("return item %s(%s) exceeds kept quantity, only %d items kept", product.Name, product.Serial, kept[serial]),
+			http.StatusBadRequest)
+	}
+	return nil
+}
+
+// Refund is price of kept items before the return minus price of kept items after the return,
+// both are priced at order unit price with promotions applied to the order, even if they are deleted or expired.
+// The difference is prorated to paid price, so order level discount like coupon is not refunded twice.
+// Last return refunds the rest of paid price.
+func (uc *orderUsecase) computeRefund(ctx context.Context, order *entity.Order, returnItems []*entity.OrderReturnItem) (entity.Money, error) {
+	netPrice := order.NetPrice()
+	if uc.isAllReturned(order, returnItems) {
+		return netPrice, nil
+	}
+
+	returned := make(map[int64]int)
+	for _, item := range returnItems {
+		returned[item.OrderItemID] += item.Quantity
+	}
+	beforePrice, err := uc.priceKeptItems(ctx, order, nil)
+	if err != nil {
+		return entity.Money{}, err
+	}
+	afterPrice, err := uc.priceKeptItems(ctx, order, returned)
+	if err != nil {
+		return entity.Money{}, err
+	}
+
+	diff := beforePrice.Sub(afterPrice)
+	if diff.Amount <= 0 || beforePrice.Amount <= 0 {
+		// eg: returned item is free item
+		return entity.NewMoney(0), nil
+	}
+	return netPrice.Prorate(diff, beforePrice), nil
+}
+
+// price kept items of the order minus the returned quantity, return zero if nothing is kept
+// map[int64] = order item id, int = returned quantity
+func (uc *orderUsecase) priceKeptItems(ctx context.Context, order *entity.Order, returned map[int64]int) (entity.Money, error) {
+	payload := make(entity.MapProductSerialQuantity)
+	mapProduct := make(map[int64]*entity.Product)
+	var products []*entity.Product
+	for _, item := range order.Items {
+		quantity := item.KeptQuantity() - returned[item.ID]
+		if quantity <= 0 {
+			continue
+		}
+		if item.Product == nil {
+			return entity.Money{}, entity.NewError(fmt.Sprintf("product %d of the order is not found", item.ProductID), http.StatusInternalServerError)
+		}
+		// price kept item at the price it was sold
+		if _, ok := mapProduct[item.ProductID]; !ok {
+			product := *item.Product
+			product.Price = item.UnitPrice
+			mapProduct[item.ProductID] = &product
+			products = append(products, &product)
+		}
+		payload[item.Product.Serial] += quantity
+	}
+	if len(products) == 0 {
+		return entity.NewMoney(0), nil
+	}
+
+	// usecase already return entity.Err
+	checkout, err := uc.checkoutUC.Reprice(ctx, products, payload, order)
+	if err != nil {
+		return entity.Money{}, err
+	}
+	return checkout.TotalPrice, nil
+}
+
+// check if the return items leave nothing kept in the order
+func (uc *orderUsecase) isAllReturned(order *entity.Order, returnItems []*entity.OrderReturnItem) bool {
+	returned := make(map[int64]int)
+	for _, item := range returnItems {
+		returned[item.OrderItemID] += item.Quantity
+	}
+	for _, item := range order.Items {
+		if item.KeptQuantity() > returned[item.ID] {
+			return false
+		}
+	}
+	return true
+}
+
+// save the return, then apply it to the order
+func (uc *orderUsecase) saveReturn(ctx context.Context, ret *entity.OrderReturn, status entity.OrderStatus) (*entity.OrderReturn, error) {
+	err := uc.orderRepo.ReturnOrder(ctx, ret, status)
+	if err != nil {
+		if _, ok := err.(entity.Err); !ok {
+			err = entity.NewInternalError(err)
+		}
+		return nil, err
+	}
+
+	returned := make(map[int64]int)
+	for _, item := range ret.Items {
+		returned[item.OrderItemID] += item.Quantity
+	}
+	for _, item := range ret.Order.Items {
+		item.ReturnedQuantity += returned[item.ID]
+	}
+	ret.Order.Status = status
+	ret.Order.RefundedPrice = ret.Order.RefundedPrice.Add(ret.RefundPrice)
+	return ret, nil
+}
@@ -0,0 +1,259 @@
+package module_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"hometest1/core/entity"
+	"hometest1/core/module"
+	repomocks "hometest1/core/repository/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func initOrderUC(ctrl *gomock.Controller) (module.OrderUsecase, *repomocks.MockOrderRepo, *repomocks.MockProductRepo, *repomocks.MockPromotionRepo) {
+	orderRepo := repomocks.NewMockOrderRepo(ctrl)
+	productRepo := repomocks.NewMockProductRepo(ctrl)
+	promoRepo := repomocks.NewMockPromotionRepo(ctrl)
+	checkoutUC := module.NewCheckoutUsecase(productRepo, promoRepo)
+
+	return module.NewOrderUsecase(orderRepo, productRepo, checkoutUC), orderRepo, productRepo, promoRepo
+}
+
+func Test_OrderGet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+
+	svc, orderRepo, productRepo, _ := initOrderUC(ctrl)
+
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+	product := &entity.Product{ID: 2, Serial: "43N23P", Name: "MacBook Pro", Price: entity.NewMoney(539999), UpdatedAt: dayCreated}
+
+	t.Run("positive", func(t *testing.T) {
+		orderRepo.EXPECT().GetOrder(ctx, int64(1)).Return(&entity.Order{
+			ID: 1, TotalItem: 1, TotalPrice: entity.NewMoney(539999), CreatedAt: dayCreated,
+			Items: []*entity.OrderItem{
+				{ID: 1, OrderID: 1, ProductID: 2, UnitPrice: entity.NewMoney(539999), Quantity: 1, SubTotalPrice: entity.NewMoney(539999)},
+			},
+		}, nil).Times(1)
+		productRepo.EXPECT().GetProductByIDsWithDeleted(ctx, []int64{2}).Return([]*entity.Product{product}, nil).Times(1)
+
+		resp, err := svc.Get(ctx, 1)
+		assert.Nil(t, err)
+		assert.Equal(t, product, resp.Items[0].Product)
+	})
+
+	t.Run("negative, order not found", func(t *testing.T) {
+		orderRepo.EXPECT().GetOrder(ctx, int64(2)).Return(nil, nil).Times(1)
+
+		_, err := svc.Get(ctx, 2)
+		assert.Equal(t, entity.NewError(entity.OrderNotFound, http.StatusNotFound), err)
+	})
+}
+
+func Test_OrderList(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+
+	svc, orderRepo, _, _ := initOrderUC(ctrl)
+
+	t.Run("normalize pagination", func(t *testing.T) {
+		orderRepo.EXPECT().GetOrders(ctx, module.MaxPageLimit, 0).Return(nil, int64(0), nil).Times(1)
+
+		resp, err := svc.List(ctx, 0, 1000)
+		assert.Nil(t, err)
+		assert.Equal(t, &entity.OrderList{Page: 1, Limit: module.MaxPageLimit}, resp)
+	})
+
+	t.Run("offset from page", func(t *testing.T) {
+		orderRepo.EXPECT().GetOrders(ctx, module.DefaultPageLimit, 20).Return(nil, int64(0), nil).Times(1)
+
+		resp, err := svc.List(ctx, 3, 0)
+		assert.Nil(t, err)
+		assert.Equal(t, &entity.OrderList{Page: 3, Limit: module.DefaultPageLimit}, resp)
+	})
+}
+
+func Test_OrderReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+
+	svc, orderRepo, productRepo, promoRepo := initOrderUC(ctrl)
+
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+	macbook := &entity.Product{ID: 2, Serial: "43N23P", Name: "MacBook Pro", Price: entity.NewMoney(539999), UpdatedAt: dayCreated}
+	raspberry := &entity.Product{ID: 4, Serial: "234234", Name: "Raspberry Pi B", Price: entity.NewMoney(3000), UpdatedAt: dayCreated}
+	alexa := &entity.Product{ID: 3, Serial: "A304SD", Name: "Alexa Speaker", Price: entity.NewMoney(10950), UpdatedAt: dayCreated}
+	// promotion is deleted after the order
+	bonus := &entity.Promotion{ID: 1, Type: 1, ProductID: 2, MatchQuantity: 1, PromoProductID: 4, PromoValue: 1, UpdatedAt: dayCreated, DeletedAt: gorm.DeletedAt{Time: dayCreated, Valid: true}}
+	// macbook with free raspberry pi
+	bonusOrder := func() *entity.Order {
+		return &entity.Order{
+			ID: 1, Status: entity.OrderPlaced, TotalItem: 2, TotalPrice: entity.NewMoney(539999), RefundedPrice: entity.NewMoney(0), CreatedAt: dayCreated,
+			Items: []*entity.OrderItem{
+				{ID: 1, OrderID: 1, ProductID: 2, UnitPrice: entity.NewMoney(539999), Quantity: 1, SubTotalPrice: entity.NewMoney(539999), PromotionID: 1},
+				{ID: 2, OrderID: 1, ProductID: 4, UnitPrice: entity.NewMoney(3000), Quantity: 1, SubTotalPrice: entity.NewMoney(0), PromotionID: 1},
+			},
+			Discounts: []*entity.OrderDiscount{
+				{ID: 1, OrderID: 1, ProductID: 4, Source: entity.SourcePromotion, SourceID: 1, Amount: entity.NewMoney(3000)},
+			},
+		}
+	}
+
+	t.Run("positive, free item is clawed back", func(t *testing.T) {
+		orderRepo.EXPECT().GetOrder(ctx, int64(1)).Return(bonusOrder(), nil).Times(1)
+		productRepo.EXPECT().GetProductByIDsWithDeleted(ctx, []int64{2, 4}).Return([]*entity.Product{macbook, raspberry}, nil).Times(1)
+		// promotion of the order prices kept items before and after the return, raspberry pi is no longer free after
+		promoRepo.EXPECT().GetPromotionByIDsWithDeleted(ctx, []int64{1}).Return([]*entity.Promotion{bonus}, nil).Times(2)
+		orderRepo.EXPECT().ReturnOrder(ctx, gomock.Any(), entity.OrderPartiallyReturned).DoAndReturn(func(_ context.Context, ret *entity.OrderReturn, status entity.OrderStatus) error {
+			assert.Equal(t, []*entity.OrderReturnItem{{OrderItemID: 1, ProductID: 2, Quantity: 1}}, ret.Items)
+			ret.ID = 3
+			return nil
+		}).Times(1)
+
+		resp, err := svc.Return(ctx, 1, entity.MapProductSerialQuantity{"43N23P": 1})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(3), resp.ID)
+		// 5399.99 paid - 30.00 of raspberry pi that is no longer free
+		assert.Equal(t, entity.NewMoney(536999), resp.RefundPrice)
+		assert.Equal(t, entity.OrderPartiallyReturned, resp.Order.Status)
+		assert.Equal(t, entity.NewMoney(536999), resp.Order.RefundedPrice)
+		assert.Equal(t, 1, resp.Order.Items[0].ReturnedQuantity)
+	})
+
+	t.Run("positive, order discount is prorated", func(t *testing.T) {
+		// 2 alexa speakers, 10% off the order
+		orderRepo.EXPECT().GetOrder(ctx, int64(2)).Return(&entity.Order{
+			ID: 2, Status: entity.OrderPlaced, TotalItem: 2, TotalPrice: entity.NewMoney(19710), DiscountPrice: entity.NewMoney(2190), RefundedPrice: entity.NewMoney(0),
+			Items: []*entity.OrderItem{
+				{ID: 3, OrderID: 2, ProductID: 3, UnitPrice: entity.NewMoney(10950), Quantity: 2, SubTotalPrice: entity.NewMoney(21900)},
+			},
+			Discounts: []*entity.OrderDiscount{
+				{ID: 2, OrderID: 2, Source: entity.SourceCartPromotion, SourceID: 7, Amount: entity.NewMoney(2190)},
+			},
+		}, nil).Times(1)
+		productRepo.EXPECT().GetProductByIDsWithDeleted(ctx, []int64{3}).Return([]*entity.Product{alexa}, nil).Times(1)
+		promoRepo.EXPECT().GetCartPromotionByIDsWithDeleted(ctx, []int64{7}).Return([]*entity.CartPromotion{
+			{ID: 7, Type: entity.CartDiscountInPercent, PromoValue: 10},
+		}, nil).Times(2)
+		orderRepo.EXPECT().ReturnOrder(ctx, gomock.Any(), entity.OrderPartiallyReturned).Return(nil).Times(1)
+
+		resp, err := svc.Return(ctx, 2, entity.MapProductSerialQuantity{"A304SD": 1})
+		assert.Nil(t, err)
+		assert.Equal(t, entity.NewMoney(9855), resp.RefundPrice)
+	})
+
+	t.Run("positive, expired coupon discount that is no longer met is clawed back", func(t *testing.T) {
+		// 2 alexa speakers, 10% off for spending 200.00 or more, unlocked by coupon that is expired now
+		orderRepo.EXPECT().GetOrder(ctx, int64(3)).Return(&entity.Order{
+			ID: 3, Status: entity.OrderPlaced, TotalItem: 2, TotalPrice: entity.NewMoney(19710), DiscountPrice: entity.NewMoney(2190), RefundedPrice: entity.NewMoney(0),
+			Items: []*entity.OrderItem{
+				{ID: 4, OrderID: 3, ProductID: 3, UnitPrice: entity.NewMoney(10950), Quantity: 2, SubTotalPrice: entity.NewMoney(21900)},
+			},
+			Discounts: []*entity.OrderDiscount{
+				{ID: 3, OrderID: 3, Source: entity.SourceCartPromotion, SourceID: 8, Amount: entity.NewMoney(2190)},
+			},
+		}, nil).Times(1)
+		productRepo.EXPECT().GetProductByIDsWithDeleted(ctx, []int64{3}).Return([]*entity.Product{alexa}, nil).Times(1)
+		promoRepo.EXPECT().GetCartPromotionByIDsWithDeleted(ctx, []int64{8}).Return([]*entity.CartPromotion{
+			{ID: 8, Type: entity.CartDiscountInPercent, MinSpend: entity.NewMoney(20000), PromoValue: 10, EndsAt: &dayCreated},
+		}, nil).Times(2)
+		orderRepo.EXPECT().ReturnOrder(ctx, gomock.Any(), entity.OrderPartiallyReturned).Return(nil).Times(1)
+
+		resp, err := svc.Return(ctx, 3, entity.MapProductSerialQuantity{"A304SD": 1})
+		assert.Nil(t, err)
+		// 197.10 paid - 109.50 of kept speaker without discount
+		assert.Equal(t, entity.NewMoney(8760), resp.RefundPrice)
+	})
+
+	t.Run("positive, last return refunds the rest", func(t *testing.T) {
+		order := bonusOrder()
+		order.Status = entity.OrderPartiallyReturned
+		order.RefundedPrice = entity.NewMoney(536999)
+		order.Items[0].ReturnedQuantity = 1
+		orderRepo.EXPECT().GetOrder(ctx, int64(1)).Return(order, nil).Times(1)
+		productRepo.EXPECT().GetProductByIDsWithDeleted(ctx, []int64{2, 4}).Return([]*entity.Product{macbook, raspberry}, nil).Times(1)
+		orderRepo.EXPECT().ReturnOrder(ctx, gomock.Any(), entity.OrderReturned).Return(nil).Times(1)
+
+		resp, err := svc.Return(ctx, 1, entity.MapProductSerialQuantity{"234234": 1})
+		assert.Nil(t, err)
+		assert.Equal(t, entity.NewMoney(3000), resp.RefundPrice)
+		assert.Equal(t, entity.NewMoney(539999), resp.Order.RefundedPrice)
+	})
+
+	t.Run("negative, exceeds kept quantity", func(t *testing.T) {
+		orderRepo.EXPECT().GetOrder(ctx, int64(1)).Return(bonusOrder(), nil).Times(1)
+		productRepo.EXPECT().GetProductByIDsWithDeleted(ctx, []int64{2, 4}).Return([]*entity.Product{macbook, raspberry}, nil).Times(1)
+
+		_, err := svc.Return(ctx, 1, entity.MapProductSerialQuantity{"43N23P": 2})
+		assert.Equal(t, entity.NewError("return item MacBook Pro(43N23P) exceeds kept quantity, only 1 items kept", http.StatusBadRequest), err)
+	})
+
+	t.Run("negative, product is not in the order", func(t *testing.T) {
+		orderRepo.EXPECT().GetOrder(ctx, int64(1)).Return(bonusOrder(), nil).Times(1)
+		productRepo.EXPECT().GetProductByIDsWithDeleted(ctx, []int64{2, 4}).Return([]*entity.Product{macbook, raspberry}, nil).Times(1)
+
+		_, err := svc.Return(ctx, 1, entity.MapProductSerialQuantity{"A304SD": 1})
+		assert.Equal(t, entity.NewError(entity.ProductNotOrdered+": A304SD", http.StatusBadRequest), err)
+	})
+
+	t.Run("negative, order is changed by other return", func(t *testing.T) {
+		orderRepo.EXPECT().GetOrder(ctx, int64(1)).Return(bonusOrder(), nil).Times(1)
+		productRepo.EXPECT().GetProductByIDsWithDeleted(ctx, []int64{2, 4}).Return([]*entity.Product{macbook, raspberry}, nil).Times(1)
+		orderRepo.EXPECT().ReturnOrder(ctx, gomock.Any(), entity.OrderReturned).Return(entity.NewError(entity.OrderChanged, http.StatusConflict)).Times(1)
+
+		_, err := svc.Return(ctx, 1, entity.MapProductSerialQuantity{"43N23P": 1, "234234": 1})
+		assert.Equal(t, entity.NewError(entity.OrderChanged, http.StatusConflict), err)
+	})
+
+	t.Run("negative, empty return", func(t *testing.T) {
+		_, err := svc.Return(ctx, 1, entity.MapProductSerialQuantity{})
+		assert.Equal(t, entity.NewError(entity.EmptyReturn, http.StatusBadRequest), err)
+	})
+}
+
+func Test_OrderCancel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+
+	svc, orderRepo, productRepo, _ := initOrderUC(ctrl)
+
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+	alexa := &entity.Product{ID: 3, Serial: "A304SD", Name: "Alexa Speaker", Price: entity.NewMoney(10950), UpdatedAt: dayCreated}
+
+	t.Run("positive, kept items are returned", func(t *testing.T) {
+		orderRepo.EXPECT().GetOrder(ctx, int64(2)).Return(&entity.Order{
+			ID: 2, Status: entity.OrderPartiallyReturned, TotalItem: 3, TotalPrice: entity.NewMoney(32850), RefundedPrice: entity.NewMoney(10950),
+			Items: []*entity.OrderItem{
+				{ID: 3, OrderID: 2, ProductID: 3, UnitPrice: entity.NewMoney(10950), Quantity: 3, SubTotalPrice: entity.NewMoney(32850), ReturnedQuantity: 1},
+			},
+		}, nil).Times(1)
+		productRepo.EXPECT().GetProductByIDsWithDeleted(ctx, []int64{3}).Return([]*entity.Product{alexa}, nil).Times(1)
+		orderRepo.EXPECT().ReturnOrder(ctx, gomock.Any(), entity.OrderCancelled).DoAndReturn(func(_ context.Context, ret *entity.OrderReturn, status entity.OrderStatus) error {
+			assert.Equal(t, []*entity.OrderReturnItem{{OrderItemID: 3, ProductID: 3, Quantity: 2}}, ret.Items)
+			return nil
+		}).Times(1)
+
+		resp, err := svc.Cancel(ctx, 2)
+		assert.Nil(t, err)
+		assert.Equal(t, entity.NewMoney(21900), resp.RefundPrice)
+		assert.Equal(t, entity.OrderCancelled, resp.Order.Status)
+		assert.Equal(t, 3, resp.Order.Items[0].ReturnedQuantity)
+	})
+
+	t.Run("negative, order is already cancelled", func(t *testing.T) {
+		orderRepo.EXPECT().GetOrder(ctx, int64(3)).Return(&entity.Order{ID: 3, Status: entity.OrderCancelled}, nil).Times(1)
+		productRepo.EXPECT().GetProductByIDsWithDeleted(ctx, nil).Return(nil, nil).Times(1)
+
+		_, err := svc.Cancel(ctx, 3)
+		assert.Equal(t, entity.NewError(entity.OrderClosed, http.StatusConflict), err)
+	})
+}
@@ -0,0 +1,115 @@
+package module
+
+import (
+	"context"
+	"time"
+	"unicode/utf8"
+
+	"hometest1/core/entity"
+	"hometest1/core/repository"
+)
+
+// max length of outbox last error column
+const maxOutboxErrorLength = 255
+
+// OutboxSink deliver outbox event, eg: webhook, file or stdout.
+// Event may be delivered more than once, consumer deduplicates it by event id
+type OutboxSink interface {
+	Publish(ctx context.Context, event *entity.Outbox) error
+}
+
+// OutboxOptions is batch size, lease and retry backoff of outbox dispatcher
+type OutboxOptions struct {
+	// max events delivered by one dispatch
+	BatchSize int
+	// event taken by a dispatcher is not taken by others until the lease is over,
+	// so event of crashed dispatcher is delivered again
+	Lease time.Duration
+	// delay after the first failed delivery, doubled after every failure up to max backoff
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+}
+
+type OutboxUsecase interface {
+	// deliver due events to the sink once, failed event is retried later with backoff.
+	// Return number of delivered and failed events
+	Dispatch(ctx context.Context) (int, int, error)
+}
+
+type outboxUsecase struct {
+	outboxRepo repository.OutboxRepo
+	sink       OutboxSink
+	options    OutboxOptions
+	clock      entity.Clock
+}
+
+func NewOutboxUsecase(outboxRepo repository.OutboxRepo, sink OutboxSink, options OutboxOptions) OutboxUsecase {
+	return NewOutboxUsecaseWithClock(outboxRepo, sink, options, time.Now)
+}
+
+// create outbox usecase with custom clock of due, lease and delivered time
+func NewOutboxUsecaseWithClock(outboxRepo repository.OutboxRepo, sink OutboxSink, options OutboxOptions, clock entity.Clock) OutboxUsecase {
+	return &outboxUsecase{outboxRepo, sink, options, clock}
+}
+
+func (uc *outboxUsecase) Dispatch(ctx context.Context) (int, int, error) {
+	now := uc.clock()
+	events, err := uc.outboxRepo.GetDueEvents(ctx, now, uc.options.BatchSize)
+	if err != nil {
+		return 0, 0, entity.NewInternalError(err)
+	}
+
+	delivered, failed := 0, 0
+	for _, event := range events {
+		// lease starts when the event is taken, previous events of the batch may be slow
+		claimed, err := uc.outboxRepo.ClaimEvent(ctx, event.ID, now, uc.clock().Add(uc.options.Lease))
+		if err != nil {
+			return delivered, failed, entity.NewInternalError(err)
+		}
+		if !claimed {
+			continue
+		}
+
+		// event that is not marked is delivered again after the lease
+		err = uc.sink.Publish(ctx, event)
+		if err != nil {
+			event.Attempts++
+			event.LastError = truncateError(err.Error())
+			event.NextAttemptAt = uc.clock().Add(uc.backoff(event.Attempts))
+			err = uc.outboxRepo.MarkFailed(ctx, event)
+			if err != nil {
+				return delivered, failed, entity.NewInternalError(err)
+			}
+			failed++
+			continue
+		}
+
+		err = uc.outboxRepo.MarkDelivered(ctx, event.ID, uc.clock())
+		if err != nil {
+			return delivered, failed, entity.NewInternalError(err)
+		}
+		delivered++
+	}
+	return delivered, failed, nil
+}
+
+// delay before next attempt of event that has failed the attempts
+func (uc *outboxUsecase) backoff(attempts int) time.Duration {
+	delay := uc.options.Backoff
+	for i := 1; i < attempts && delay < uc.options.MaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, uc.options.MaxBackoff)
+}
+
+// cut error message to fit last error column, without breaking utf-8 character
+func truncateError(message string) string {
+	if len(message) <= maxOutboxErrorLength {
+		return message
+	}
+	cut := maxOutboxErrorLength
+	for cut > 0 && !utf8.RuneStart(message[cut]) {
+		cut--
+	}
+	return message[:cut]
+}
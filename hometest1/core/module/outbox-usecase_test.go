@@ -0,0 +1,112 @@
+package module_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"hometest1/core/entity"
+	"hometest1/core/module"
+	repomocks "hometest1/core/repository/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+// sink that records events, publishing event with failing id returns error
+type fakeOutboxSink struct {
+	failing   map[int64]error
+	published []int64
+}
+
+func (s *fakeOutboxSink) Publish(ctx context.Context, event *entity.Outbox) error {
+	if err, ok := s.failing[event.ID]; ok {
+		return err
+	}
+	s.published = append(s.published, event.ID)
+	return nil
+}
+
+var outboxTime = time.Date(2023, 5, 16, 12, 0, 0, 0, time.UTC)
+
+func initOutboxUC(ctrl *gomock.Controller, sink module.OutboxSink) (module.OutboxUsecase, *repomocks.MockOutboxRepo) {
+	outboxRepo := repomocks.NewMockOutboxRepo(ctrl)
+	options := module.OutboxOptions{BatchSize: 10, Lease: time.Minute, Backoff: time.Second, MaxBackoff: time.Hour}
+	return module.NewOutboxUsecaseWithClock(outboxRepo, sink, options, func() time.Time { return outboxTime }), outboxRepo
+}
+
+func Test_DispatchOutbox(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+	leaseUntil := outboxTime.Add(time.Minute)
+
+	t.Run("positive, failed event is retried with backoff", func(t *testing.T) {
+		sink := &fakeOutboxSink{failing: map[int64]error{4: errors.New("webhook responds 502 Bad Gateway")}}
+		svc, outboxRepo := initOutboxUC(ctrl, sink)
+		outboxRepo.EXPECT().GetDueEvents(ctx, outboxTime, 10).Return([]*entity.Outbox{
+			{ID: 3, EventType: entity.OrderPlacedEvent, AggregateID: 7},
+			{ID: 4, EventType: entity.OrderPlacedEvent, AggregateID: 8, Attempts: 1},
+		}, nil).Times(1)
+		outboxRepo.EXPECT().ClaimEvent(ctx, int64(3), outboxTime, leaseUntil).Return(true, nil).Times(1)
+		outboxRepo.EXPECT().MarkDelivered(ctx, int64(3), outboxTime).Return(nil).Times(1)
+		outboxRepo.EXPECT().ClaimEvent(ctx, int64(4), outboxTime, leaseUntil).Return(true, nil).Times(1)
+		// second failure waits twice the backoff
+		outboxRepo.EXPECT().MarkFailed(ctx, &entity.Outbox{
+			ID:            4,
+			EventType:     entity.OrderPlacedEvent,
+			AggregateID:   8,
+			Attempts:      2,
+			NextAttemptAt: outboxTime.Add(2 * time.Second),
+			LastError:     "webhook responds 502 Bad Gateway",
+		}).Return(nil).Times(1)
+
+		delivered, failed, err := svc.Dispatch(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, delivered)
+		assert.Equal(t, 1, failed)
+		assert.Equal(t, []int64{3}, sink.published)
+	})
+
+	t.Run("positive, event taken by another dispatcher is skipped", func(t *testing.T) {
+		sink := &fakeOutboxSink{}
+		svc, outboxRepo := initOutboxUC(ctrl, sink)
+		outboxRepo.EXPECT().GetDueEvents(ctx, outboxTime, 10).Return([]*entity.Outbox{{ID: 3}}, nil).Times(1)
+		outboxRepo.EXPECT().ClaimEvent(ctx, int64(3), outboxTime, leaseUntil).Return(false, nil).Times(1)
+
+		delivered, failed, err := svc.Dispatch(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, 0, delivered)
+		assert.Equal(t, 0, failed)
+		assert.Empty(t, sink.published)
+	})
+
+	t.Run("positive, backoff and error are capped", func(t *testing.T) {
+		sink := &fakeOutboxSink{failing: map[int64]error{3: errors.New(strings.Repeat("é", 200))}}
+		svc, outboxRepo := initOutboxUC(ctrl, sink)
+		outboxRepo.EXPECT().GetDueEvents(ctx, outboxTime, 10).Return([]*entity.Outbox{{ID: 3, Attempts: 20}}, nil).Times(1)
+		outboxRepo.EXPECT().ClaimEvent(ctx, int64(3), outboxTime, leaseUntil).Return(true, nil).Times(1)
+		outboxRepo.EXPECT().MarkFailed(ctx, &entity.Outbox{
+			ID:            3,
+			Attempts:      21,
+			NextAttemptAt: outboxTime.Add(time.Hour),
+			LastError:     strings.Repeat("é", 127),
+		}).Return(nil).Times(1)
+
+		delivered, failed, err := svc.Dispatch(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, 0, delivered)
+		assert.Equal(t, 1, failed)
+	})
+
+	t.Run("negative, database error", func(t *testing.T) {
+		svc, outboxRepo := initOutboxUC(ctrl, &fakeOutboxSink{})
+		outboxRepo.EXPECT().GetDueEvents(ctx, outboxTime, 10).Return(nil, errors.New("connection refused")).Times(1)
+
+		_, _, err := svc.Dispatch(ctx)
+		assert.Equal(t, entity.NewError("connection refused", http.StatusInternalServerError), err)
+	})
+}
@@ -0,0 +1,20 @@
+package module
+
+const (
+	DefaultPageLimit int = 10
+	MaxPageLimit     int = 100
+)
+
+// normalize page (start from 1) and limit, then return the offset
+func normalizePagination(page, limit int) (int, int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = DefaultPageLimit
+	}
+	if limit > MaxPageLimit {
+		limit = MaxPageLimit
+	}
+	return page, limit, (page - 1) * limit
+}
@@ -0,0 +1,121 @@
+package module
+
+import (
+	"context"
+	"net/http"
+
+	"hometest1/core/entity"
+	"hometest1/core/repository"
+)
+
+type ProductUsecase interface {
+	// create product with initial stock
+	Create(ctx context.Context, product *entity.Product, quantity int) (*entity.ProductStock, error)
+	// update product name, price and tax class by serial, empty tax class keeps the current one
+	Update(ctx context.Context, serial string, name string, price entity.Money, taxClass string) (*entity.Product, error)
+	// soft delete product by serial
+	Delete(ctx context.Context, serial string) error
+	// get products with its stock, page start from 1
+	List(ctx context.Context, page, limit int) (*entity.ProductList, error)
+}
+
+type productUsecase struct {
+	productRepo repository.ProductRepo
+}
+
+func NewProductUsecase(productRepo repository.ProductRepo) ProductUsecase {
+	return &productUsecase{productRepo}
+}
+
+func (uc *productUsecase) Create(ctx context.Context, product *entity.Product, quantity int) (*entity.ProductStock, error) {
+	if product.TaxClass == "" {
+		product.TaxClass = entity.DefaultTaxClass
+	}
+	// repository already return entity.Err
+	err := uc.productRepo.CreateProduct(ctx, product, quantity)
+	if err != nil {
+		return nil, err
+	}
+	return &entity.ProductStock{Product: product, Quantity: quantity}, nil
+}
+
+func (uc *productUsecase) Update(ctx context.Context, serial string, name string, price entity.Money, taxClass string) (*entity.Product, error) {
+	product, err := uc.getProduct(ctx, serial)
+	if err != nil {
+		return nil, err
+	}
+
+	product.Name = name
+	product.Price = price
+	if taxClass != "" {
+		product.TaxClass = taxClass
+	}
+	err = uc.productRepo.UpdateProduct(ctx, product)
+	if err != nil {
+		return nil, entity.NewInternalError(err)
+	}
+	return product, nil
+}
+
+func (uc *productUsecase) Delete(ctx context.Context, serial string) error {
+	product, err := uc.getProduct(ctx, serial)
+	if err != nil {
+		return err
+	}
+
+	err = uc.productRepo.DeleteProduct(ctx, product.ID)
+	if err != nil {
+		return entity.NewInternalError(err)
+	}
+	return nil
+}
+
+func (uc *productUsecase) List(ctx context.Context, page, limit int) (*entity.ProductList, error) {
+	page, limit, offset := normalizePagination(page, limit)
+	products, total, err := uc.productRepo.GetProducts(ctx, limit, offset)
+	if err != nil {
+		return nil, entity.NewInternalError(err)
+	}
+
+	result := entity.ProductList{
+		Total: total,
+		Page:  page,
+		Limit: limit,
+	}
+	if len(products) == 0 {
+		return &result, nil
+	}
+
+	// get products stock
+	var productIDs []int64
+	for _, product := range products {
+		productIDs = append(productIDs, product.ID)
+	}
+	quantities, err := uc.productRepo.GetProductQuantityByIDs(ctx, productIDs)
+	if err != nil {
+		return nil, entity.NewInternalError(err)
+	}
+	mapQuantity := make(map[int64]int)
+	for _, qty := range quantities {
+		mapQuantity[qty.ProductID] = qty.Quantity
+	}
+
+	for _, product := range products {
+		result.Products = append(result.Products, &entity.ProductStock{
+			Product:  product,
+			Quantity: mapQuantity[product.ID],
+		})
+	}
+	return &result, nil
+}
+
+func (uc *productUsecase) getProduct(ctx context.Context, serial string) (*entity.Product, error) {
+	products, err := uc.productRepo.GetProductBySerials(ctx, []string{serial})
+	if err != nil {
+		return nil, entity.NewInternalError(err)
+	}
+	if len(products) == 0 {
+		return nil, entity.NewError(entity.ProductNotFound, http.StatusNotFound)
+	}
+	return products[0], nil
+}
@@ -0,0 +1,80 @@
+package module_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"hometest1/core/entity"
+	"hometest1/core/module"
+	repomocks "hometest1/core/repository/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func initProductUC(ctrl *gomock.Controller) (module.ProductUsecase, *repomocks.MockProductRepo) {
+	productRepo := repomocks.NewMockProductRepo(ctrl)
+	return module.NewProductUsecase(productRepo), productRepo
+}
+
+func Test_ProductUpdate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+
+	svc, productRepo := initProductUC(ctrl)
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+
+	t.Run("positive", func(t *testing.T) {
+		productRepo.EXPECT().GetProductBySerials(ctx, []string{"120P90"}).Return([]*entity.Product{
+			{ID: 1, Serial: "120P90", Name: "Google Home", Price: entity.NewMoney(4999), UpdatedAt: dayCreated},
+		}, nil).Times(1)
+		expected := &entity.Product{ID: 1, Serial: "120P90", Name: "Google Nest", Price: entity.NewMoney(4500), TaxClass: "reduced", UpdatedAt: dayCreated}
+		productRepo.EXPECT().UpdateProduct(ctx, expected).Return(nil).Times(1)
+
+		resp, err := svc.Update(ctx, "120P90", "Google Nest", entity.NewMoney(4500), "reduced")
+		assert.Nil(t, err)
+		assert.Equal(t, expected, resp)
+	})
+
+	t.Run("negative, product not found", func(t *testing.T) {
+		productRepo.EXPECT().GetProductBySerials(ctx, []string{"XXX"}).Return(nil, nil).Times(1)
+
+		_, err := svc.Update(ctx, "XXX", "Google Nest", entity.NewMoney(4500), "")
+		assert.Equal(t, entity.NewError(entity.ProductNotFound, http.StatusNotFound), err)
+	})
+}
+
+func Test_ProductList(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+
+	svc, productRepo := initProductUC(ctrl)
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+
+	t.Run("positive, with stock", func(t *testing.T) {
+		products := []*entity.Product{
+			{ID: 1, Serial: "120P90", Name: "Google Home", Price: entity.NewMoney(4999), UpdatedAt: dayCreated},
+			{ID: 4, Serial: "234234", Name: "Raspberry Pi B", Price: entity.NewMoney(3000), UpdatedAt: dayCreated},
+		}
+		productRepo.EXPECT().GetProducts(ctx, module.DefaultPageLimit, 0).Return(products, int64(2), nil).Times(1)
+		productRepo.EXPECT().GetProductQuantityByIDs(ctx, []int64{1, 4}).Return([]*entity.ProductQuantity{
+			{ID: 1, ProductID: 1, Quantity: 10},
+		}, nil).Times(1)
+
+		resp, err := svc.List(ctx, 1, 0)
+		assert.Nil(t, err)
+		assert.Equal(t, &entity.ProductList{
+			Products: []*entity.ProductStock{
+				{Product: products[0], Quantity: 10},
+				{Product: products[1], Quantity: 0},
+			},
+			Total: 2,
+			Page:  1,
+			Limit: module.DefaultPageLimit,
+		}, resp)
+	})
+}
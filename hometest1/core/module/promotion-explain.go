@@ -0,0 +1,94 @@
+package module
+
+import (
+	"fmt"
+
+	"hometest1/core/entity"
+)
+
+// reason of promotion that is met, but another combination gives better price
+const notCombinedReason = "not combined with the applied promotions"
+
+// explainPromotions describes product promotions applied on checkout items,
+// and lists product promotions that are not applied with the reason
+func (uc *checkoutUsecase) explainPromotions(checkout *entity.Checkout, mapQuantity entity.MapProductSerialQuantity, products []*entity.Product, promotionMaps map[int64][]*entity.Promotion, productOf productLookup) error {
+	mapPromotion := make(map[int64]*entity.Promotion)
+	for _, promotions := range promotionMaps {
+		for _, promo := range promotions {
+			mapPromotion[promo.ID] = promo
+		}
+	}
+
+	// free products are already in checkout items
+	mapItemProduct := make(map[int64]*entity.Product)
+	for _, item := range checkout.Items {
+		mapItemProduct[item.Product.ID] = item.Product
+	}
+	describe := func(promo *entity.Promotion) (string, error) {
+		return uc.describePromotion(promo, func(productID int64) (*entity.Product, error) {
+			if product, ok := mapItemProduct[productID]; ok {
+				return product, nil
+			}
+			return productOf(productID)
+		})
+	}
+
+	applied := make(map[int64]bool)
+	for _, item := range checkout.Items {
+		for _, explained := range item.AppliedPromotions {
+			promo, ok := mapPromotion[explained.ID]
+			if explained.Source != entity.SourcePromotion || !ok {
+				continue
+			}
+			description, err := describe(promo)
+			if err != nil {
+				return err
+			}
+			explained.Type = int(promo.Type)
+			explained.Description = description
+			applied[promo.ID] = true
+		}
+	}
+
+	for _, product := range products {
+		for _, promo := range promotionMaps[product.ID] {
+			if _, ok := uc.promoRules[promo.Type]; !ok || applied[promo.ID] {
+				continue
+			}
+			description, err := describe(promo)
+			if err != nil {
+				return err
+			}
+			reason := notCombinedReason
+			if qty := mapQuantity[product.Serial]; qty < promo.MatchQuantity {
+				reason = fmt.Sprintf("buy %d more", promo.MatchQuantity-qty)
+			}
+			checkout.NotMetPromotions = append(checkout.NotMetPromotions, &entity.AppliedPromotion{
+				Source:      entity.SourcePromotion,
+				ID:          promo.ID,
+				Type:        int(promo.Type),
+				Description: description,
+				Reason:      reason,
+			})
+		}
+	}
+	return nil
+}
+
+// human readable description of product promotion, by its rule
+func (uc *checkoutUsecase) describePromotion(promo *entity.Promotion, productOf productLookup) (string, error) {
+	describer, ok := uc.promoRules[promo.Type].(PromotionRuleDescriber)
+	if !ok {
+		return fmt.Sprintf("promotion %d", promo.ID), nil
+	}
+
+	var promoProduct *entity.Product
+	if promo.PromoProductID != 0 {
+		var err error
+		promoProduct, err = productOf(promo.PromoProductID)
+		if err != nil {
+			return "", err
+		}
+	}
+	return describer.Describe(promo, promoProduct), nil
+}
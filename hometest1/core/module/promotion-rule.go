@@ -0,0 +1,217 @@
+package module
+
+import (
+	"errors"
+	"fmt"
+
+	"hometest1/core/entity"
+)
+
+// PromotionRule is calculation of one promotion type against a checkout item.
+// Rule may change the item sub total price / quantity, or add free items into freeProductItem.
+// Return true if the promotion is applied
+type PromotionRule interface {
+	Apply(item *entity.CheckoutItem, promo *entity.Promotion, freeProductItem FreeProductItems) bool
+}
+
+// PromotionRuleValidator is optional interface of PromotionRule,
+// to validate promotion value when admin inputs promotion data
+type PromotionRuleValidator interface {
+	Validate(promo *entity.Promotion) error
+}
+
+// PromotionRuleDescriber is optional interface of PromotionRule,
+// to explain the promotion on checkout. promoProduct is nil if promotion has no free item product
+type PromotionRuleDescriber interface {
+	Describe(promo *entity.Promotion, promoProduct *entity.Product) string
+}
+
+// FreeProductItems is free items obtained from promotions
+// map[int64] = product id
+type FreeProductItems map[int64]*FreeProductItem
+
+type FreeProductItem struct {
+	Quantity int
+	// promotion that give the free item
+	PromotionID int64
+}
+
+// Add free item quantity of the product
+func (f FreeProductItems) Add(productID int64, quantity int, promotionID int64) {
+	if f[productID] == nil {
+		f[productID] = &FreeProductItem{}
+	}
+	f[productID].Quantity += quantity
+	f[productID].PromotionID = promotionID
+}
+
+// Get free item quantity of the product
+func (f FreeProductItems) Get(productID int64) int {
+	if f[productID] == nil {
+		return 0
+	}
+	return f[productID].Quantity
+}
+
+// PromotionRules is registry of promotion rule, keyed by promotion type.
+// Promotion with unregistered type will be ignored on checkout
+type PromotionRules map[entity.PromotionType]PromotionRule
+
+// Register add or replace rule for the promotion type
+func (r PromotionRules) Register(promoType entity.PromotionType, rule PromotionRule) {
+	r[promoType] = rule
+}
+
+// DefaultPromotionRules return registry with all built in promotion rules
+func DefaultPromotionRules() PromotionRules {
+	return PromotionRules{
+		entity.BonusItem:              &bonusItemRule{},
+		entity.BuyItemsForReducePrice: &reducePriceRule{},
+		entity.DiscountInPercent:      &discountRule{},
+		entity.FreeItem:               &freeItemRule{},
+	}
+}
+
+// This rule calculates the free items that will be obtained
+type bonusItemRule struct{}
+
+func (r *bonusItemRule) Apply(item *entity.CheckoutItem, promo *entity.Promotion, freeProductItem FreeProductItems) bool {
+	// if promo product id empty or no match quantity, no free item for this promo
+	if promo.PromoProductID == 0 || promo.MatchQuantity == 0 || item.Quantity < promo.MatchQuantity {
+		return false
+	}
+
+	// number of free item will user get
+	numOfFreeItems := (item.Quantity / promo.MatchQuantity) * promo.PromoValue
+	freeProductItem.Add(promo.PromoProductID, numOfFreeItems, promo.ID)
+	return true
+}
+
+func (r *bonusItemRule) Validate(promo *entity.Promotion) error {
+	if promo.PromoProductID == 0 {
+		return errors.New("free item product is required")
+	}
+	if promo.MatchQuantity < 1 {
+		return errors.New("match quantity must be at least 1")
+	}
+	if promo.PromoValue < 1 {
+		return errors.New("number of free items must be at least 1")
+	}
+	return nil
+}
+
+// eg: buy 1 get 1 Raspberry Pi B free
+func (r *bonusItemRule) Describe(promo *entity.Promotion, promoProduct *entity.Product) string {
+	name := "item"
+	if promoProduct != nil {
+		name = promoProduct.Name
+	}
+	return fmt.Sprintf("buy %d get %d %s free", promo.MatchQuantity, promo.PromoValue, name)
+}
+
+// This rule calculates price reductions that apply multiples
+type reducePriceRule struct{}
+
+func (r *reducePriceRule) Apply(item *entity.CheckoutItem, promo *entity.Promotion, freeProductItem FreeProductItems) bool {
+	// if match quantity empty, keep current price
+	if promo.MatchQuantity <= 0 || item.Quantity < promo.MatchQuantity {
+		return false
+	}
+
+	// reduce price of unpaid items, so it is combined with previous promotions
+	// eg: buy 3 for the price of 2, 1 item is unpaid every 3 items
+	unpaidQuantity := item.Quantity / promo.MatchQuantity * (promo.MatchQuantity - promo.PromoValue)
+	item.SubTotalPrice = item.SubTotalPrice.Sub(item.Product.Price.Mul(unpaidQuantity))
+	if item.SubTotalPrice.Amount < 0 {
+		item.SubTotalPrice = entity.NewMoney(0)
+	}
+	return true
+}
+
+func (r *reducePriceRule) Validate(promo *entity.Promotion) error {
+	if promo.PromoProductID != 0 {
+		return errors.New("reduce price promotion cannot have free item product")
+	}
+	if promo.MatchQuantity < 2 {
+		return errors.New("match quantity must be at least 2")
+	}
+	// eg: buy 3 for the price of 2
+	if promo.PromoValue < 1 || promo.PromoValue >= promo.MatchQuantity {
+		return errors.New("paid quantity must be between 1 and match quantity - 1")
+	}
+	return nil
+}
+
+// eg: buy 3 for the price of 2
+func (r *reducePriceRule) Describe(promo *entity.Promotion, promoProduct *entity.Product) string {
+	return fmt.Sprintf("buy %d for the price of %d", promo.MatchQuantity, promo.PromoValue)
+}
+
+// This rule calculates the discount price
+type discountRule struct{}
+
+func (r *discountRule) Apply(item *entity.CheckoutItem, promo *entity.Promotion, freeProductItem FreeProductItems) bool {
+	// promo value for discount in percent value, only process valid value
+	if promo.PromoValue < 0 || promo.PromoValue > 100 || item.Quantity < promo.MatchQuantity {
+		return false
+	}
+
+	// discount is rounded half up to the minor unit
+	item.SubTotalPrice = item.SubTotalPrice.Sub(item.SubTotalPrice.Percent(promo.PromoValue))
+	return true
+}
+
+func (r *discountRule) Validate(promo *entity.Promotion) error {
+	if promo.PromoProductID != 0 {
+		return errors.New("discount promotion cannot have free item product")
+	}
+	if promo.MatchQuantity < 1 {
+		return errors.New("match quantity must be at least 1")
+	}
+	if promo.PromoValue < 1 || promo.PromoValue > 100 {
+		return errors.New("discount percent must be between 1 and 100")
+	}
+	return nil
+}
+
+// eg: 10% off for buying 3 or more
+func (r *discountRule) Describe(promo *entity.Promotion, promoProduct *entity.Product) string {
+	if promo.MatchQuantity > 1 {
+		return fmt.Sprintf("%d%% off for buying %d or more", promo.PromoValue, promo.MatchQuantity)
+	}
+	return fmt.Sprintf("%d%% off", promo.PromoValue)
+}
+
+// This rule gives extra units of the same product for free.
+// Every match quantity bought, user will get promo value items on top of it
+type freeItemRule struct{}
+
+func (r *freeItemRule) Apply(item *entity.CheckoutItem, promo *entity.Promotion, freeProductItem FreeProductItems) bool {
+	if promo.MatchQuantity <= 0 || promo.PromoValue <= 0 || item.Quantity < promo.MatchQuantity {
+		return false
+	}
+
+	// sub total price is not changed, only the quantity
+	freeQuantity := (item.Quantity / promo.MatchQuantity) * promo.PromoValue
+	item.Quantity += freeQuantity
+	item.FreeQuantity += freeQuantity
+	return true
+}
+
+func (r *freeItemRule) Validate(promo *entity.Promotion) error {
+	if promo.PromoProductID != 0 {
+		return errors.New("free same item promotion cannot have free item product")
+	}
+	if promo.MatchQuantity < 1 {
+		return errors.New("match quantity must be at least 1")
+	}
+	if promo.PromoValue < 1 {
+		return errors.New("number of free items must be at least 1")
+	}
+	return nil
+}
+
+// eg: buy 2 get 1 free
+func (r *freeItemRule) Describe(promo *entity.Promotion, promoProduct *entity.Product) string {
+	return fmt.Sprintf("buy %d get %d free", promo.MatchQuantity, promo.PromoValue)
+}
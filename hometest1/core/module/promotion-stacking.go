@@ -0,0 +1,149 @@
+package module
+
+import "hometest1/core/entity"
+
+// productLookup return product by id, used to value free items of promotion.
+// Return nil if product is not found
+type productLookup func(productID int64) (*entity.Product, error)
+
+// promotionCandidate is result of applying a combination of promotions to a checkout item
+type promotionCandidate struct {
+	item      entity.CheckoutItem
+	freeItems FreeProductItems
+	// last applied promotion, 0 if no promotion applied
+	promotionID int64
+}
+
+// applyPromotions applies the combination of promotions that gives the lowest price for the customer.
+// Promotions must be sorted by priority, the combinations are:
+//   - all stackable promotions, plus at most one best-of promotion
+//   - each exclusive promotion alone
+//
+// On equal price, the combination listed first is chosen.
+func (uc *checkoutUsecase) applyPromotions(item *entity.CheckoutItem, promotions []*entity.Promotion, freeProductItem FreeProductItems, productOf productLookup) error {
+	combinations := uc.promotionCombinations(promotions)
+
+	// single combination has nothing to compare
+	if len(combinations) == 1 {
+		candidate := uc.applyCombination(item, combinations[0])
+		uc.useCandidate(item, candidate, freeProductItem)
+		return nil
+	}
+
+	var best *promotionCandidate
+	var bestSaving entity.Money
+	for _, combination := range combinations {
+		candidate := uc.applyCombination(item, combination)
+		saving, err := uc.candidateSaving(item, candidate, productOf)
+		if err != nil {
+			return err
+		}
+		if best == nil || saving.Amount > bestSaving.Amount {
+			best = candidate
+			bestSaving = saving
+		}
+	}
+	uc.useCandidate(item, best, freeProductItem)
+	return nil
+}
+
+// group promotions with registered rule into combinations, keeping the priority order
+func (uc *checkoutUsecase) promotionCombinations(promotions []*entity.Promotion) [][]*entity.Promotion {
+	var registered []*entity.Promotion
+	for _, promo := range promotions {
+		if _, ok := uc.promoRules[promo.Type]; ok {
+			registered = append(registered, promo)
+		}
+	}
+	return stackingCombinations(registered, func(promo *entity.Promotion) entity.PromotionStacking {
+		return promo.Stacking
+	})
+}
+
+// stackingCombinations group promotions into combinations by its stacking mode, keeping the order:
+//   - all stackable promotions, plus at most one best-of promotion
+//   - each exclusive promotion alone
+func stackingCombinations[T comparable](promotions []T, stacking func(T) entity.PromotionStacking) [][]T {
+	var stackable, bestOf, exclusive []T
+	for _, promo := range promotions {
+		switch stacking(promo) {
+		case entity.Exclusive:
+			exclusive = append(exclusive, promo)
+		case entity.BestOf:
+			bestOf = append(bestOf, promo)
+		default:
+			stackable = append(stackable, promo)
+		}
+	}
+
+	result := [][]T{stackable}
+	for _, chosen := range bestOf {
+		var combination []T
+		for _, promo := range promotions {
+			if promo == chosen || stacking(promo) == entity.Stackable {
+				combination = append(combination, promo)
+			}
+		}
+		result = append(result, combination)
+	}
+	for _, promo := range exclusive {
+		result = append(result, []T{promo})
+	}
+	return result
+}
+
+// apply promotions to copy of the item
+func (uc *checkoutUsecase) applyCombination(item *entity.CheckoutItem, combination []*entity.Promotion) *promotionCandidate {
+	result := promotionCandidate{
+		item:      *item,
+		freeItems: make(FreeProductItems),
+	}
+	for _, promo := range combination {
+		before := result.item
+		if !uc.promoRules[promo.Type].Apply(&result.item, promo, result.freeItems) {
+			continue
+		}
+		result.promotionID = promo.ID
+
+		// saving on this item, free items of other product are explained on their own item
+		saved := before.SubTotalPrice.Sub(result.item.SubTotalPrice).
+			Add(item.Product.Price.Mul(result.item.Quantity - before.Quantity))
+		if saved.Amount > 0 {
+			result.item.AppliedPromotions = append(result.item.AppliedPromotions, &entity.AppliedPromotion{
+				Source: entity.SourcePromotion,
+				ID:     promo.ID,
+				Saved:  saved,
+			})
+		}
+	}
+	return &result
+}
+
+// saving is reduced price plus price of the free items
+func (uc *checkoutUsecase) candidateSaving(item *entity.CheckoutItem, candidate *promotionCandidate, productOf productLookup) (entity.Money, error) {
+	result := item.SubTotalPrice.Sub(candidate.item.SubTotalPrice)
+	// extra items of the same product
+	result = result.Add(item.Product.Price.Mul(candidate.item.Quantity - item.Quantity))
+	// free items of other product
+	for productID, free := range candidate.freeItems {
+		product, err := productOf(productID)
+		if err != nil {
+			return entity.Money{}, err
+		}
+		if product != nil {
+			result = result.Add(product.Price.Mul(free.Quantity))
+		}
+	}
+	return result, nil
+}
+
+// set candidate result into the item and collect its free items
+func (uc *checkoutUsecase) useCandidate(item *entity.CheckoutItem, candidate *promotionCandidate, freeProductItem FreeProductItems) {
+	*item = candidate.item
+	if candidate.promotionID != 0 {
+		item.PromotionID = candidate.promotionID
+	}
+	for productID, free := range candidate.freeItems {
+		freeProductItem.Add(productID, free.Quantity, free.PromotionID)
+	}
+}
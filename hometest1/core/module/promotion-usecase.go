@@ -0,0 +1,244 @@
+package module
+
+import (
+	"context"
+	"net/http"
+
+	"hometest1/core/entity"
+	"hometest1/core/repository"
+)
+
+type PromotionUsecase interface {
+	// create promotion, product and promo product are looked up by serial
+	Create(ctx context.Context, payload *entity.PromotionDetail) (*entity.PromotionDetail, error)
+	// update promotion by id, product and promo product are looked up by serial
+	Update(ctx context.Context, payload *entity.PromotionDetail) (*entity.PromotionDetail, error)
+	// soft delete promotion
+	Delete(ctx context.Context, promotionID int64) error
+	// get promotions, page start from 1
+	List(ctx context.Context, page, limit int) (*entity.PromotionList, error)
+}
+
+type promotionUsecase struct {
+	promoRepo   repository.PromotionRepo
+	productRepo repository.ProductRepo
+	promoRules  PromotionRules
+}
+
+func NewPromotionUsecase(promoRepo repository.PromotionRepo, productRepo repository.ProductRepo, promoRules PromotionRules) PromotionUsecase {
+	return &promotionUsecase{promoRepo, productRepo, promoRules}
+}
+
+func (uc *promotionUsecase) Create(ctx context.Context, payload *entity.PromotionDetail) (*entity.PromotionDetail, error) {
+	err := uc.resolveProducts(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+	err = uc.validate(ctx, payload.Promotion)
+	if err != nil {
+		return nil, err
+	}
+
+	err = uc.promoRepo.CreatePromotion(ctx, payload.Promotion)
+	if err != nil {
+		return nil, entity.NewInternalError(err)
+	}
+	return payload, nil
+}
+
+func (uc *promotionUsecase) Update(ctx context.Context, payload *entity.PromotionDetail) (*entity.PromotionDetail, error) {
+	existing, err := uc.promoRepo.GetPromotion(ctx, payload.ID)
+	if err != nil {
+		return nil, entity.NewInternalError(err)
+	}
+	if existing == nil {
+		return nil, entity.NewError(entity.PromotionNotFound, http.StatusNotFound)
+	}
+
+	err = uc.resolveProducts(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+	err = uc.validate(ctx, payload.Promotion)
+	if err != nil {
+		return nil, err
+	}
+
+	err = uc.promoRepo.UpdatePromotion(ctx, payload.Promotion)
+	if err != nil {
+		return nil, entity.NewInternalError(err)
+	}
+	return payload, nil
+}
+
+func (uc *promotionUsecase) Delete(ctx context.Context, promotionID int64) error {
+	existing, err := uc.promoRepo.GetPromotion(ctx, promotionID)
+	if err != nil {
+		return entity.NewInternalError(err)
+	}
+	if existing == nil {
+		return entity.NewError(entity.PromotionNotFound, http.StatusNotFound)
+	}
+
+	err = uc.promoRepo.DeletePromotion(ctx, promotionID)
+	if err != nil {
+		return entity.NewInternalError(err)
+	}
+	return nil
+}
+
+func (uc *promotionUsecase) List(ctx context.Context, page, limit int) (*entity.PromotionList, error) {
+	page, limit, offset := normalizePagination(page, limit)
+	promotions, total, err := uc.promoRepo.GetPromotions(ctx, limit, offset)
+	if err != nil {
+		return nil, entity.NewInternalError(err)
+	}
+
+	result := entity.PromotionList{
+		Total: total,
+		Page:  page,
+		Limit: limit,
+	}
+	if len(promotions) == 0 {
+		return &result, nil
+	}
+
+	// get promotion products, product may already be deleted
+	var productIDs []int64
+	for _, promo := range promotions {
+		productIDs = append(productIDs, promo.ProductID)
+		if promo.PromoProductID != 0 {
+			productIDs = append(productIDs, promo.PromoProductID)
+		}
+	}
+	products, err := uc.productRepo.GetProductByIDsWithDeleted(ctx, productIDs)
+	if err != nil {
+		return nil, entity.NewInternalError(err)
+	}
+	mapProduct := make(map[int64]*entity.Product)
+	for _, product := range products {
+		mapProduct[product.ID] = product
+	}
+
+	for _, promo := range promotions {
+		result.Promotions = append(result.Promotions, &entity.PromotionDetail{
+			Promotion:    promo,
+			Product:      mapProduct[promo.ProductID],
+			PromoProduct: mapProduct[promo.PromoProductID],
+		})
+	}
+	return &result, nil
+}
+
+// look up payload products by serial, then set product id into promotion
+func (uc *promotionUsecase) resolveProducts(ctx context.Context, payload *entity.PromotionDetail) error {
+	serials := []string{payload.Product.Serial}
+	if payload.PromoProduct != nil {
+		serials = append(serials, payload.PromoProduct.Serial)
+	}
+	products, err := uc.productRepo.GetProductBySerials(ctx, serials)
+	if err != nil {
+		return entity.NewInternalError(err)
+	}
+	mapProduct := make(map[string]*entity.Product)
+	for _, product := range products {
+		mapProduct[product.Serial] = product
+	}
+
+	// set product
+	product, ok := mapProduct[payload.Product.Serial]
+	if !ok {
+		return entity.NewError(entity.ProductNotFound, http.StatusBadRequest)
+	}
+	payload.Product = product
+	payload.ProductID = product.ID
+
+	// set promo product
+	payload.PromoProductID = 0
+	if payload.PromoProduct != nil {
+		promoProduct, ok := mapProduct[payload.PromoProduct.Serial]
+		if !ok {
+			return entity.NewError(entity.ProductNotFound, http.StatusBadRequest)
+		}
+		payload.PromoProduct = promoProduct
+		payload.PromoProductID = promoProduct.ID
+	}
+	return nil
+}
+
+// validate promotion value and free item rules
+func (uc *promotionUsecase) validate(ctx context.Context, promo *entity.Promotion) error {
+	rule, ok := uc.promoRules[promo.Type]
+	if !ok {
+		return entity.NewError(entity.InvalidPromotionType, http.StatusBadRequest)
+	}
+	if !promo.Stacking.IsValid() {
+		return entity.NewError(entity.InvalidStacking, http.StatusBadRequest)
+	}
+	if promo.StartsAt != nil && promo.EndsAt != nil && !promo.EndsAt.After(*promo.StartsAt) {
+		return entity.NewError(entity.InvalidPromoPeriod, http.StatusBadRequest)
+	}
+	if validator, ok := rule.(PromotionRuleValidator); ok {
+		if err := validator.Validate(promo); err != nil {
+			return entity.NewError(err.Error(), http.StatusBadRequest)
+		}
+	}
+
+	// get existing free item promotions, except promotion that being updated
+	freeItemPromos, err := uc.promoRepo.GetFreeItemPromotions(ctx)
+	if err != nil {
+		return entity.NewInternalError(err)
+	}
+	// map[int64] = product id, []int64 = free item product ids
+	freeItemGraph := make(map[int64][]int64)
+	for _, p := range freeItemPromos {
+		if p.ID == promo.ID {
+			continue
+		}
+		// products set as free items cannot be promoted
+		if p.PromoProductID == promo.ProductID {
+			return entity.NewError(entity.FreeItemPromoted, http.StatusBadRequest)
+		}
+		freeItemGraph[p.ProductID] = append(freeItemGraph[p.ProductID], p.PromoProductID)
+	}
+
+	if promo.PromoProductID == 0 {
+		return nil
+	}
+
+	// product that has promotion, including scheduled one, cannot be set as free item
+	promoProductPromos, err := uc.promoRepo.GetUpcomingPromotionsByProduct(ctx, promo.PromoProductID)
+	if err != nil {
+		return entity.NewInternalError(err)
+	}
+	for _, p := range promoProductPromos {
+		if p.ID != promo.ID {
+			return entity.NewError(entity.PromotedFreeItem, http.StatusBadRequest)
+		}
+	}
+
+	// reject cycle, eg: A gives free B, B gives free A
+	freeItemGraph[promo.ProductID] = append(freeItemGraph[promo.ProductID], promo.PromoProductID)
+	if uc.hasFreeItemCycle(freeItemGraph, promo.PromoProductID, promo.ProductID, map[int64]bool{}) {
+		return entity.NewError(entity.FreeItemCycle, http.StatusBadRequest)
+	}
+	return nil
+}
+
+// check if target product is reachable from product through free item promotions
+func (uc *promotionUsecase) hasFreeItemCycle(graph map[int64][]int64, productID, targetID int64, visited map[int64]bool) bool {
+	if productID == targetID {
+		return true
+	}
+	if visited[productID] {
+		return false
+	}
+	visited[productID] = true
+
+	for _, next := range graph[productID] {
+		if uc.hasFreeItemCycle(graph, next, targetID, visited) {
+			return true
+		}
+	}
+	return false
+}
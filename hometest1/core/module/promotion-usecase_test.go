@@ -0,0 +1,174 @@
+package module_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"hometest1/core/entity"
+	"hometest1/core/module"
+	repomocks "hometest1/core/repository/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func initPromotionUC(ctrl *gomock.Controller) (module.PromotionUsecase, *repomocks.MockPromotionRepo, *repomocks.MockProductRepo) {
+	promoRepo := repomocks.NewMockPromotionRepo(ctrl)
+	productRepo := repomocks.NewMockProductRepo(ctrl)
+
+	return module.NewPromotionUsecase(promoRepo, productRepo, module.DefaultPromotionRules()), promoRepo, productRepo
+}
+
+func Test_PromotionCreate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+
+	svc, promoRepo, productRepo := initPromotionUC(ctrl)
+
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+	products := []*entity.Product{
+		{ID: 1, Serial: "120P90", Name: "Google Home", Price: entity.NewMoney(4999), UpdatedAt: dayCreated},
+		{ID: 2, Serial: "43N23P", Name: "MacBook Pro", Price: entity.NewMoney(539999), UpdatedAt: dayCreated},
+		{ID: 4, Serial: "234234", Name: "Raspberry Pi B", Price: entity.NewMoney(3000), UpdatedAt: dayCreated},
+	}
+	// MacBook Pro gives free Raspberry Pi B
+	macbookPromo := &entity.Promotion{ID: 1, Type: entity.BonusItem, ProductID: 2, MatchQuantity: 1, PromoValue: 1, PromoProductID: 4}
+
+	t.Run("positive, bonus item", func(t *testing.T) {
+		productRepo.EXPECT().GetProductBySerials(ctx, []string{"120P90", "234234"}).Return([]*entity.Product{products[0], products[2]}, nil).Times(1)
+		promoRepo.EXPECT().GetFreeItemPromotions(ctx).Return([]*entity.Promotion{macbookPromo}, nil).Times(1)
+		promoRepo.EXPECT().GetUpcomingPromotionsByProduct(ctx, int64(4)).Return(nil, nil).Times(1)
+		expected := &entity.Promotion{Type: entity.BonusItem, ProductID: 1, MatchQuantity: 2, PromoValue: 1, PromoProductID: 4}
+		promoRepo.EXPECT().CreatePromotion(ctx, expected).Return(nil).Times(1)
+
+		resp, err := svc.Create(ctx, &entity.PromotionDetail{
+			Promotion:    &entity.Promotion{Type: entity.BonusItem, MatchQuantity: 2, PromoValue: 1},
+			Product:      &entity.Product{Serial: "120P90"},
+			PromoProduct: &entity.Product{Serial: "234234"},
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, &entity.PromotionDetail{Promotion: expected, Product: products[0], PromoProduct: products[2]}, resp)
+	})
+
+	t.Run("negative, invalid discount value", func(t *testing.T) {
+		productRepo.EXPECT().GetProductBySerials(ctx, []string{"120P90"}).Return([]*entity.Product{products[0]}, nil).Times(1)
+
+		_, err := svc.Create(ctx, &entity.PromotionDetail{
+			Promotion: &entity.Promotion{Type: entity.DiscountInPercent, MatchQuantity: 3, PromoValue: 120},
+			Product:   &entity.Product{Serial: "120P90"},
+		})
+		assert.Equal(t, entity.NewError("discount percent must be between 1 and 100", http.StatusBadRequest), err)
+	})
+
+	t.Run("negative, invalid stacking", func(t *testing.T) {
+		productRepo.EXPECT().GetProductBySerials(ctx, []string{"120P90"}).Return([]*entity.Product{products[0]}, nil).Times(1)
+
+		_, err := svc.Create(ctx, &entity.PromotionDetail{
+			Promotion: &entity.Promotion{Type: entity.DiscountInPercent, MatchQuantity: 1, PromoValue: 10, Stacking: 9},
+			Product:   &entity.Product{Serial: "120P90"},
+		})
+		assert.Equal(t, entity.NewError(entity.InvalidStacking, http.StatusBadRequest), err)
+	})
+
+	t.Run("negative, ends before starts", func(t *testing.T) {
+		productRepo.EXPECT().GetProductBySerials(ctx, []string{"120P90"}).Return([]*entity.Product{products[0]}, nil).Times(1)
+		startsAt := dayCreated.Add(24 * time.Hour)
+
+		_, err := svc.Create(ctx, &entity.PromotionDetail{
+			Promotion: &entity.Promotion{Type: entity.DiscountInPercent, MatchQuantity: 1, PromoValue: 10, StartsAt: &startsAt, EndsAt: &dayCreated},
+			Product:   &entity.Product{Serial: "120P90"},
+		})
+		assert.Equal(t, entity.NewError(entity.InvalidPromoPeriod, http.StatusBadRequest), err)
+	})
+
+	t.Run("negative, invalid type", func(t *testing.T) {
+		productRepo.EXPECT().GetProductBySerials(ctx, []string{"120P90"}).Return([]*entity.Product{products[0]}, nil).Times(1)
+
+		_, err := svc.Create(ctx, &entity.PromotionDetail{
+			Promotion: &entity.Promotion{Type: 99, MatchQuantity: 3, PromoValue: 10},
+			Product:   &entity.Product{Serial: "120P90"},
+		})
+		assert.Equal(t, entity.NewError(entity.InvalidPromotionType, http.StatusBadRequest), err)
+	})
+
+	t.Run("negative, free item product cannot be promoted", func(t *testing.T) {
+		productRepo.EXPECT().GetProductBySerials(ctx, []string{"234234"}).Return([]*entity.Product{products[2]}, nil).Times(1)
+		promoRepo.EXPECT().GetFreeItemPromotions(ctx).Return([]*entity.Promotion{macbookPromo}, nil).Times(1)
+
+		_, err := svc.Create(ctx, &entity.PromotionDetail{
+			Promotion: &entity.Promotion{Type: entity.DiscountInPercent, MatchQuantity: 3, PromoValue: 10},
+			Product:   &entity.Product{Serial: "234234"},
+		})
+		assert.Equal(t, entity.NewError(entity.FreeItemPromoted, http.StatusBadRequest), err)
+	})
+
+	t.Run("negative, promoted product cannot be free item", func(t *testing.T) {
+		productRepo.EXPECT().GetProductBySerials(ctx, []string{"120P90", "43N23P"}).Return([]*entity.Product{products[0], products[1]}, nil).Times(1)
+		promoRepo.EXPECT().GetFreeItemPromotions(ctx).Return([]*entity.Promotion{macbookPromo}, nil).Times(1)
+		promoRepo.EXPECT().GetUpcomingPromotionsByProduct(ctx, int64(2)).Return([]*entity.Promotion{macbookPromo}, nil).Times(1)
+
+		_, err := svc.Create(ctx, &entity.PromotionDetail{
+			Promotion:    &entity.Promotion{Type: entity.BonusItem, MatchQuantity: 1, PromoValue: 1},
+			Product:      &entity.Product{Serial: "120P90"},
+			PromoProduct: &entity.Product{Serial: "43N23P"},
+		})
+		assert.Equal(t, entity.NewError(entity.PromotedFreeItem, http.StatusBadRequest), err)
+	})
+
+	t.Run("negative, product gives itself as free item", func(t *testing.T) {
+		productRepo.EXPECT().GetProductBySerials(ctx, []string{"120P90", "120P90"}).Return([]*entity.Product{products[0]}, nil).Times(1)
+		promoRepo.EXPECT().GetFreeItemPromotions(ctx).Return(nil, nil).Times(1)
+		promoRepo.EXPECT().GetUpcomingPromotionsByProduct(ctx, int64(1)).Return(nil, nil).Times(1)
+
+		_, err := svc.Create(ctx, &entity.PromotionDetail{
+			Promotion:    &entity.Promotion{Type: entity.BonusItem, MatchQuantity: 1, PromoValue: 1},
+			Product:      &entity.Product{Serial: "120P90"},
+			PromoProduct: &entity.Product{Serial: "120P90"},
+		})
+		assert.Equal(t, entity.NewError(entity.FreeItemCycle, http.StatusBadRequest), err)
+	})
+}
+
+func Test_PromotionUpdate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+
+	svc, promoRepo, productRepo := initPromotionUC(ctrl)
+
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+	products := []*entity.Product{
+		{ID: 2, Serial: "43N23P", Name: "MacBook Pro", Price: entity.NewMoney(539999), UpdatedAt: dayCreated},
+		{ID: 4, Serial: "234234", Name: "Raspberry Pi B", Price: entity.NewMoney(3000), UpdatedAt: dayCreated},
+	}
+	macbookPromo := &entity.Promotion{ID: 1, Type: entity.BonusItem, ProductID: 2, MatchQuantity: 1, PromoValue: 1, PromoProductID: 4}
+
+	t.Run("positive, updated promotion is not validated against itself", func(t *testing.T) {
+		promoRepo.EXPECT().GetPromotion(ctx, int64(1)).Return(macbookPromo, nil).Times(1)
+		productRepo.EXPECT().GetProductBySerials(ctx, []string{"43N23P", "234234"}).Return(products, nil).Times(1)
+		promoRepo.EXPECT().GetFreeItemPromotions(ctx).Return([]*entity.Promotion{macbookPromo}, nil).Times(1)
+		promoRepo.EXPECT().GetUpcomingPromotionsByProduct(ctx, int64(4)).Return(nil, nil).Times(1)
+		expected := &entity.Promotion{ID: 1, Type: entity.BonusItem, ProductID: 2, MatchQuantity: 1, PromoValue: 2, PromoProductID: 4}
+		promoRepo.EXPECT().UpdatePromotion(ctx, expected).Return(nil).Times(1)
+
+		_, err := svc.Update(ctx, &entity.PromotionDetail{
+			Promotion:    &entity.Promotion{ID: 1, Type: entity.BonusItem, MatchQuantity: 1, PromoValue: 2},
+			Product:      &entity.Product{Serial: "43N23P"},
+			PromoProduct: &entity.Product{Serial: "234234"},
+		})
+		assert.Nil(t, err)
+	})
+
+	t.Run("negative, promotion not found", func(t *testing.T) {
+		promoRepo.EXPECT().GetPromotion(ctx, int64(9)).Return(nil, nil).Times(1)
+
+		_, err := svc.Update(ctx, &entity.PromotionDetail{
+			Promotion: &entity.Promotion{ID: 9},
+			Product:   &entity.Product{Serial: "43N23P"},
+		})
+		assert.Equal(t, entity.NewError(entity.PromotionNotFound, http.StatusNotFound), err)
+	})
+}
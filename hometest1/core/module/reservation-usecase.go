@@ -0,0 +1,156 @@
+package module
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"hometest1/core/entity"
+	"hometest1/core/repository"
+)
+
+// default reservation time, if it is not set by the client
+const defaultReservationTTL = 15
+
+type ReservationUsecase interface {
+	// render checkout and hold stock of its items, including free items, for ttl minutes
+	Reserve(ctx context.Context, payload entity.MapProductSerialQuantity, options entity.CheckoutOptions, ttlMinutes int) (*entity.ReservationDetail, error)
+	// submit checkout of the reservation using its held stock, checkout is rendered again with current promotions
+	Confirm(ctx context.Context, reservationID int64, options entity.CheckoutOptions) (*entity.Checkout, error)
+	// release stock held by the reservation
+	Release(ctx context.Context, reservationID int64) error
+	// expire reservations past their expiry time, return number of expired reservations
+	ExpireReservations(ctx context.Context) (int64, error)
+}
+
+type reservationUsecase struct {
+	reservationRepo repository.ReservationRepo
+	productRepo     repository.ProductRepo
+	checkoutUC      CheckoutUsecase
+	clock           entity.Clock
+}
+
+func NewReservationUsecase(reservationRepo repository.ReservationRepo, productRepo repository.ProductRepo, checkoutUC CheckoutUsecase) ReservationUsecase {
+	return NewReservationUsecaseWithClock(reservationRepo, productRepo, checkoutUC, time.Now)
+}
+
+// create reservation usecase with custom clock to set and check expiry time
+func NewReservationUsecaseWithClock(reservationRepo repository.ReservationRepo, productRepo repository.ProductRepo, checkoutUC CheckoutUsecase, clock entity.Clock) ReservationUsecase {
+	return &reservationUsecase{reservationRepo, productRepo, checkoutUC, clock}
+}
+
+func (uc *reservationUsecase) Reserve(ctx context.Context, payload entity.MapProductSerialQuantity, options entity.CheckoutOptions, ttlMinutes int) (*entity.ReservationDetail, error) {
+	if ttlMinutes == 0 {
+		ttlMinutes = defaultReservationTTL
+	}
+	if ttlMinutes < 1 || ttlMinutes > 60 {
+		return nil, entity.NewError(entity.InvalidReservationTTL, http.StatusBadRequest)
+	}
+
+	// render checkout, usecase already return entity.Err
+	quote, err := uc.checkoutUC.Quote(ctx, payload, options)
+	if err != nil {
+		return nil, err
+	}
+
+	reservation := entity.Reservation{
+		Status:     entity.ReservationActive,
+		CustomerID: options.CustomerID,
+		ExpiresAt:  uc.clock().Add(time.Duration(ttlMinutes) * time.Minute),
+	}
+	for _, item := range quote.Items {
+		reservation.Items = append(reservation.Items, &entity.ReservationItem{
+			ProductID:       item.Product.ID,
+			Quantity:        item.Quantity,
+			OrderedQuantity: payload[item.Product.Serial],
+			Product:         item.Product,
+		})
+	}
+
+	// repository must handle error with entity.Err
+	err = uc.productRepo.ReserveStock(ctx, &reservation)
+	if err != nil {
+		return nil, err
+	}
+	return &entity.ReservationDetail{Reservation: &reservation, Checkout: quote.Checkout}, nil
+}
+
+func (uc *reservationUsecase) Confirm(ctx context.Context, reservationID int64, options entity.CheckoutOptions) (*entity.Checkout, error) {
+	reservation, err := uc.getActiveReservation(ctx, reservationID)
+	if err != nil {
+		return nil, err
+	}
+
+	// map ordered items back to checkout payload, free items are rendered by promotions
+	var productIDs []int64
+	for _, item := range reservation.Items {
+		productIDs = append(productIDs, item.ProductID)
+	}
+	products, err := uc.productRepo.GetProductByIDs(ctx, productIDs)
+	if err != nil {
+		return nil, entity.NewInternalError(err)
+	}
+	serials := make(map[int64]string)
+	for _, product := range products {
+		serials[product.ID] = product.Serial
+	}
+	payload := make(entity.MapProductSerialQuantity)
+	for _, item := range reservation.Items {
+		if serial, ok := serials[item.ProductID]; ok && item.OrderedQuantity > 0 {
+			payload[serial] += item.OrderedQuantity
+		}
+	}
+	if len(payload) == 0 {
+		return nil, entity.NewError(entity.ProductNotFound, http.StatusBadRequest)
+	}
+
+	options.ReservationID = reservation.ID
+	if options.CustomerID == "" {
+		options.CustomerID = reservation.CustomerID
+	}
+	// reservation is validated again when checkout is submitted
+	return uc.checkoutUC.Submit(ctx, payload, options)
+}
+
+func (uc *reservationUsecase) Release(ctx context.Context, reservationID int64) error {
+	reservation, err := uc.getActiveReservation(ctx, reservationID)
+	if err != nil {
+		return err
+	}
+
+	released, err := uc.reservationRepo.ReleaseReservation(ctx, reservation.ID)
+	if err != nil {
+		return entity.NewInternalError(err)
+	}
+	// confirmed or expired in between
+	if !released {
+		return entity.NewError(entity.ReservationNotActive, http.StatusConflict)
+	}
+	return nil
+}
+
+func (uc *reservationUsecase) ExpireReservations(ctx context.Context) (int64, error) {
+	expired, err := uc.reservationRepo.ExpireReservations(ctx, uc.clock())
+	if err != nil {
+		return 0, entity.NewInternalError(err)
+	}
+	return expired, nil
+}
+
+// get reservation that still holds the stock
+func (uc *reservationUsecase) getActiveReservation(ctx context.Context, reservationID int64) (*entity.Reservation, error) {
+	reservation, err := uc.reservationRepo.GetReservation(ctx, reservationID)
+	if err != nil {
+		return nil, entity.NewInternalError(err)
+	}
+	if reservation == nil {
+		return nil, entity.NewError(entity.ReservationNotFound, http.StatusNotFound)
+	}
+	if reservation.Status != entity.ReservationActive {
+		return nil, entity.NewError(entity.ReservationNotActive, http.StatusConflict)
+	}
+	if !reservation.IsActive(uc.clock()) {
+		return nil, entity.NewError(entity.ReservationHasExpired, http.StatusConflict)
+	}
+	return reservation, nil
+}
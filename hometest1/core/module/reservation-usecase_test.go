@@ -0,0 +1,174 @@
+package module_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"hometest1/core/entity"
+	"hometest1/core/module"
+	repomocks "hometest1/core/repository/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func initReservationUC(ctrl *gomock.Controller, now time.Time) (module.ReservationUsecase, *repomocks.MockReservationRepo, *repomocks.MockProductRepo, *repomocks.MockPromotionRepo) {
+	reservationRepo := repomocks.NewMockReservationRepo(ctrl)
+	productRepo := repomocks.NewMockProductRepo(ctrl)
+	promoRepo := repomocks.NewMockPromotionRepo(ctrl)
+	checkoutUC := module.NewCheckoutUsecase(productRepo, promoRepo)
+
+	return module.NewReservationUsecaseWithClock(reservationRepo, productRepo, checkoutUC, func() time.Time { return now }), reservationRepo, productRepo, promoRepo
+}
+
+func Test_Reserve(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+
+	now, _ := time.Parse("2006-01-02 15:04:05", "2023-05-16 10:00:00")
+	svc, _, productRepo, promoRepo := initReservationUC(ctrl, now)
+	macbook := &entity.Product{ID: 2, Serial: "43N23P", Name: "MacBook Pro", Price: entity.NewMoney(539999), UpdatedAt: now}
+	raspberry := &entity.Product{ID: 4, Serial: "234234", Name: "Raspberry Pi B", Price: entity.NewMoney(3000), UpdatedAt: now}
+
+	t.Run("positive, free item is held too", func(t *testing.T) {
+		productRepo.EXPECT().GetProductBySerials(ctx, []string{"43N23P"}).Return([]*entity.Product{macbook}, nil).Times(1)
+		promoRepo.EXPECT().GetPromotionByProducts(ctx, []*entity.Product{macbook}).Return(map[int64][]*entity.Promotion{
+			2: {{ID: 1, Type: 1, ProductID: 2, MatchQuantity: 1, PromoProductID: 4, PromoValue: 1, UpdatedAt: now}},
+		}, nil).Times(1)
+		promoRepo.EXPECT().GetActiveBundlesByProducts(ctx, []int64{2}).Return(nil, nil).Times(1)
+		promoRepo.EXPECT().GetActiveCartPromotions(ctx, nil).Return(nil, nil).Times(1)
+		productRepo.EXPECT().GetProductByIDs(ctx, []int64{4}).Return([]*entity.Product{raspberry}, nil).Times(1)
+		productRepo.EXPECT().GetAvailableQuantityByIDs(ctx, []int64{2, 4}).Return([]*entity.ProductQuantity{
+			{ID: 2, ProductID: 2, Quantity: 5},
+			{ID: 4, ProductID: 4, Quantity: 5},
+		}, nil).Times(1)
+		productRepo.EXPECT().ReserveStock(ctx, &entity.Reservation{
+			Status:     entity.ReservationActive,
+			CustomerID: "cust-1",
+			ExpiresAt:  now.Add(10 * time.Minute),
+			Items: []*entity.ReservationItem{
+				{ProductID: 2, Quantity: 1, OrderedQuantity: 1, Product: macbook},
+				{ProductID: 4, Quantity: 1, OrderedQuantity: 0, Product: raspberry},
+			},
+		}).DoAndReturn(func(_ context.Context, reservation *entity.Reservation) error {
+			reservation.ID = 5
+			return nil
+		}).Times(1)
+
+		resp, err := svc.Reserve(ctx, entity.MapProductSerialQuantity{"43N23P": 1}, entity.CheckoutOptions{CustomerID: "cust-1"}, 10)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(5), resp.ID)
+		assert.Equal(t, now.Add(10*time.Minute), resp.ExpiresAt)
+		assert.Equal(t, 2, resp.Checkout.TotalItem)
+		assert.Equal(t, entity.NewMoney(539999), resp.Checkout.TotalPrice)
+	})
+
+	t.Run("negative, ttl too long", func(t *testing.T) {
+		_, err := svc.Reserve(ctx, entity.MapProductSerialQuantity{"43N23P": 1}, entity.CheckoutOptions{}, 61)
+		assert.Equal(t, entity.NewError(entity.InvalidReservationTTL, http.StatusBadRequest), err)
+	})
+}
+
+func Test_ConfirmReservation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+
+	now, _ := time.Parse("2006-01-02 15:04:05", "2023-05-16 10:00:00")
+	svc, reservationRepo, productRepo, promoRepo := initReservationUC(ctrl, now)
+	product := &entity.Product{ID: 3, Serial: "A304SD", Name: "Alexa Speaker", Price: entity.NewMoney(10950), UpdatedAt: now}
+
+	t.Run("positive, checkout uses reservation stock", func(t *testing.T) {
+		reservationRepo.EXPECT().GetReservation(ctx, int64(5)).Return(&entity.Reservation{
+			ID: 5, Status: entity.ReservationActive, CustomerID: "cust-1", ExpiresAt: now.Add(time.Minute),
+			Items: []*entity.ReservationItem{{ID: 1, ReservationID: 5, ProductID: 3, Quantity: 2, OrderedQuantity: 2}},
+		}, nil).Times(1)
+		productRepo.EXPECT().GetProductByIDs(ctx, []int64{3}).Return([]*entity.Product{product}, nil).Times(1)
+		productRepo.EXPECT().GetProductBySerials(ctx, []string{"A304SD"}).Return([]*entity.Product{product}, nil).Times(1)
+		promoRepo.EXPECT().GetPromotionByProducts(ctx, []*entity.Product{product}).Return(nil, nil).Times(1)
+		promoRepo.EXPECT().GetActiveBundlesByProducts(ctx, []int64{3}).Return(nil, nil).Times(1)
+		promoRepo.EXPECT().GetActiveCartPromotions(ctx, nil).Return(nil, nil).Times(1)
+
+		checkout := &entity.Checkout{
+			Items:         []*entity.CheckoutItem{{Product: product, Quantity: 2, SubTotalPrice: entity.NewMoney(10950 * 2)}},
+			TotalItem:     2,
+			TotalPrice:    entity.NewMoney(10950 * 2),
+			CustomerID:    "cust-1",
+			ReservationID: 5,
+		}
+		productRepo.EXPECT().SubmitCheckout(ctx, checkout).Return(nil).Times(1)
+
+		resp, err := svc.Confirm(ctx, 5, entity.CheckoutOptions{})
+		assert.Nil(t, err)
+		assert.Equal(t, checkout, resp)
+	})
+
+	t.Run("negative, reservation is expired", func(t *testing.T) {
+		reservationRepo.EXPECT().GetReservation(ctx, int64(6)).Return(&entity.Reservation{
+			ID: 6, Status: entity.ReservationActive, ExpiresAt: now,
+		}, nil).Times(1)
+
+		_, err := svc.Confirm(ctx, 6, entity.CheckoutOptions{})
+		assert.Equal(t, entity.NewError(entity.ReservationHasExpired, http.StatusConflict), err)
+	})
+
+	t.Run("negative, reservation not found", func(t *testing.T) {
+		reservationRepo.EXPECT().GetReservation(ctx, int64(7)).Return(nil, nil).Times(1)
+
+		_, err := svc.Confirm(ctx, 7, entity.CheckoutOptions{})
+		assert.Equal(t, entity.NewError(entity.ReservationNotFound, http.StatusNotFound), err)
+	})
+}
+
+func Test_ReleaseReservation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+
+	now, _ := time.Parse("2006-01-02 15:04:05", "2023-05-16 10:00:00")
+	svc, reservationRepo, _, _ := initReservationUC(ctrl, now)
+	active := &entity.Reservation{ID: 5, Status: entity.ReservationActive, ExpiresAt: now.Add(time.Minute)}
+
+	t.Run("positive", func(t *testing.T) {
+		reservationRepo.EXPECT().GetReservation(ctx, int64(5)).Return(active, nil).Times(1)
+		reservationRepo.EXPECT().ReleaseReservation(ctx, int64(5)).Return(true, nil).Times(1)
+
+		err := svc.Release(ctx, 5)
+		assert.Nil(t, err)
+	})
+
+	t.Run("negative, confirmed in between", func(t *testing.T) {
+		reservationRepo.EXPECT().GetReservation(ctx, int64(5)).Return(active, nil).Times(1)
+		reservationRepo.EXPECT().ReleaseReservation(ctx, int64(5)).Return(false, nil).Times(1)
+
+		err := svc.Release(ctx, 5)
+		assert.Equal(t, entity.NewError(entity.ReservationNotActive, http.StatusConflict), err)
+	})
+
+	t.Run("negative, already confirmed", func(t *testing.T) {
+		reservationRepo.EXPECT().GetReservation(ctx, int64(6)).Return(&entity.Reservation{ID: 6, Status: entity.ReservationConfirmed, OrderID: 7}, nil).Times(1)
+
+		err := svc.Release(ctx, 6)
+		assert.Equal(t, entity.NewError(entity.ReservationNotActive, http.StatusConflict), err)
+	})
+}
+
+func Test_ExpireReservations(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+
+	now, _ := time.Parse("2006-01-02 15:04:05", "2023-05-16 10:00:00")
+	svc, reservationRepo, _, _ := initReservationUC(ctrl, now)
+
+	t.Run("positive", func(t *testing.T) {
+		reservationRepo.EXPECT().ExpireReservations(ctx, now).Return(int64(2), nil).Times(1)
+
+		expired, err := svc.ExpireReservations(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(2), expired)
+	})
+}
@@ -0,0 +1,116 @@
+package module
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"hometest1/core/entity"
+	"hometest1/core/repository"
+)
+
+// StockAlertNotifier deliver stock alert, eg: webhook or log
+type StockAlertNotifier interface {
+	Notify(ctx context.Context, alert *entity.StockAlert) error
+}
+
+type StockAlertUsecase interface {
+	// set reorder threshold of product by serial, 0 disables the alert
+	SetThreshold(ctx context.Context, serial string, threshold int) (*entity.StockThreshold, error)
+	// notify every product of submitted checkout whose stock goes from above its threshold
+	// to the threshold or below, return the alerts
+	CheckCheckout(ctx context.Context, checkout *entity.Checkout) ([]*entity.StockAlert, error)
+}
+
+type stockAlertUsecase struct {
+	inventoryRepo repository.InventoryRepo
+	productRepo   repository.ProductRepo
+	notifier      StockAlertNotifier
+	clock         entity.Clock
+}
+
+func NewStockAlertUsecase(inventoryRepo repository.InventoryRepo, productRepo repository.ProductRepo, notifier StockAlertNotifier) StockAlertUsecase {
+	return NewStockAlertUsecaseWithClock(inventoryRepo, productRepo, notifier, time.Now)
+}
+
+// create stock alert usecase with custom clock of alert time
+func NewStockAlertUsecaseWithClock(inventoryRepo repository.InventoryRepo, productRepo repository.ProductRepo, notifier StockAlertNotifier, clock entity.Clock) StockAlertUsecase {
+	return &stockAlertUsecase{inventoryRepo, productRepo, notifier, clock}
+}
+
+func (uc *stockAlertUsecase) SetThreshold(ctx context.Context, serial string, threshold int) (*entity.StockThreshold, error) {
+	if threshold < 0 {
+		return nil, entity.NewError(entity.InvalidStockThreshold, http.StatusBadRequest)
+	}
+	products, err := uc.productRepo.GetProductBySerials(ctx, []string{serial})
+	if err != nil {
+		return nil, entity.NewInternalError(err)
+	}
+	if len(products) == 0 {
+		return nil, entity.NewError(entity.ProductNotFound, http.StatusNotFound)
+	}
+
+	result := &entity.StockThreshold{ProductID: products[0].ID, Threshold: threshold}
+	err = uc.inventoryRepo.SaveStockThreshold(ctx, result)
+	if err != nil {
+		return nil, entity.NewInternalError(err)
+	}
+	return result, nil
+}
+
+func (uc *stockAlertUsecase) CheckCheckout(ctx context.Context, checkout *entity.Checkout) ([]*entity.StockAlert, error) {
+	// stock taken by the checkout, including free items
+	// map[int64] = product id
+	mapTaken := make(map[int64]int)
+	mapProduct := make(map[int64]*entity.Product)
+	var productIDs []int64
+	for _, item := range checkout.Items {
+		if _, ok := mapProduct[item.Product.ID]; !ok {
+			productIDs = append(productIDs, item.Product.ID)
+		}
+		mapTaken[item.Product.ID] += item.Quantity
+		mapProduct[item.Product.ID] = item.Product
+	}
+	if len(productIDs) == 0 {
+		return nil, nil
+	}
+
+	thresholds, err := uc.inventoryRepo.GetStockThresholds(ctx, productIDs)
+	if err != nil {
+		return nil, entity.NewInternalError(err)
+	}
+
+	// failed alert does not stop alerts of other products
+	var result []*entity.StockAlert
+	var errs []error
+	for _, threshold := range thresholds {
+		remaining, ok := checkout.RemainingStock[threshold.ProductID]
+		if !ok || threshold.Threshold == 0 {
+			continue
+		}
+		// remaining stock is read in the checkout transaction, so only one checkout crosses the threshold
+		if remaining > threshold.Threshold || remaining+mapTaken[threshold.ProductID] <= threshold.Threshold {
+			continue
+		}
+
+		alert := &entity.StockAlert{
+			Product:   mapProduct[threshold.ProductID],
+			Quantity:  remaining,
+			Threshold: threshold.Threshold,
+			OrderID:   checkout.OrderID,
+			CreatedAt: uc.clock(),
+		}
+		err = uc.notifier.Notify(ctx, alert)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s(%s): %w", alert.Product.Name, alert.Product.Serial, err))
+			continue
+		}
+		result = append(result, alert)
+	}
+	if len(errs) > 0 {
+		return result, entity.NewInternalError(errors.Join(errs...))
+	}
+	return result, nil
+}
@@ -0,0 +1,194 @@
+package module_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"hometest1/core/entity"
+	"hometest1/core/module"
+	repomocks "hometest1/core/repository/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+// notifier that records alerts, alert of failing serial returns error
+type fakeStockAlertNotifier struct {
+	alerts  []*entity.StockAlert
+	failing string
+}
+
+func (n *fakeStockAlertNotifier) Notify(ctx context.Context, alert *entity.StockAlert) error {
+	if alert.Product.Serial == n.failing {
+		return errors.New("queue is full")
+	}
+	n.alerts = append(n.alerts, alert)
+	return nil
+}
+
+var alertTime = time.Date(2023, 5, 16, 12, 0, 0, 0, time.UTC)
+
+func initStockAlertUC(ctrl *gomock.Controller) (module.StockAlertUsecase, *repomocks.MockInventoryRepo, *repomocks.MockProductRepo, *fakeStockAlertNotifier) {
+	inventoryRepo := repomocks.NewMockInventoryRepo(ctrl)
+	productRepo := repomocks.NewMockProductRepo(ctrl)
+	notifier := &fakeStockAlertNotifier{}
+	svc := module.NewStockAlertUsecaseWithClock(inventoryRepo, productRepo, notifier, func() time.Time { return alertTime })
+	return svc, inventoryRepo, productRepo, notifier
+}
+
+func Test_SetStockThreshold(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+
+	svc, inventoryRepo, productRepo, _ := initStockAlertUC(ctrl)
+	product := &entity.Product{ID: 4, Serial: "234234", Name: "Raspberry Pi B", Price: entity.NewMoney(3000)}
+
+	t.Run("positive", func(t *testing.T) {
+		productRepo.EXPECT().GetProductBySerials(ctx, []string{"234234"}).Return([]*entity.Product{product}, nil).Times(1)
+		inventoryRepo.EXPECT().SaveStockThreshold(ctx, &entity.StockThreshold{ProductID: 4, Threshold: 3}).Return(nil).Times(1)
+
+		resp, err := svc.SetThreshold(ctx, "234234", 3)
+		assert.Nil(t, err)
+		assert.Equal(t, &entity.StockThreshold{ProductID: 4, Threshold: 3}, resp)
+	})
+
+	t.Run("negative, invalid threshold", func(t *testing.T) {
+		_, err := svc.SetThreshold(ctx, "234234", -1)
+		assert.Equal(t, entity.NewError(entity.InvalidStockThreshold, http.StatusBadRequest), err)
+	})
+
+	t.Run("negative, product not found", func(t *testing.T) {
+		productRepo.EXPECT().GetProductBySerials(ctx, []string{"XXX"}).Return(nil, nil).Times(1)
+
+		_, err := svc.SetThreshold(ctx, "XXX", 3)
+		assert.Equal(t, entity.NewError(entity.ProductNotFound, http.StatusNotFound), err)
+	})
+}
+
+func Test_CheckCheckoutStockAlert(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+
+	macbook := &entity.Product{ID: 2, Serial: "43N23P", Name: "MacBook Pro", Price: entity.NewMoney(539999)}
+	raspberry := &entity.Product{ID: 4, Serial: "234234", Name: "Raspberry Pi B", Price: entity.NewMoney(3000)}
+	checkout := func(remaining map[int64]int) *entity.Checkout {
+		return &entity.Checkout{
+			OrderID: 7,
+			Items: []*entity.CheckoutItem{
+				{Product: macbook, Quantity: 1},
+				{Product: raspberry, Quantity: 2, FreeQuantity: 1},
+			},
+			RemainingStock: remaining,
+		}
+	}
+	thresholds := []*entity.StockThreshold{{ProductID: 2, Threshold: 0}, {ProductID: 4, Threshold: 3}}
+
+	t.Run("positive, stock crosses threshold", func(t *testing.T) {
+		svc, inventoryRepo, _, notifier := initStockAlertUC(ctrl)
+		inventoryRepo.EXPECT().GetStockThresholds(ctx, []int64{2, 4}).Return(thresholds, nil).Times(1)
+
+		// raspberry goes from 4 to 2, macbook has no threshold
+		resp, err := svc.CheckCheckout(ctx, checkout(map[int64]int{2: 0, 4: 2}))
+		assert.Nil(t, err)
+		expected := []*entity.StockAlert{{Product: raspberry, Quantity: 2, Threshold: 3, OrderID: 7, CreatedAt: alertTime}}
+		assert.Equal(t, expected, resp)
+		assert.Equal(t, expected, notifier.alerts)
+	})
+
+	t.Run("negative, failed alert does not stop other alerts", func(t *testing.T) {
+		svc, inventoryRepo, _, notifier := initStockAlertUC(ctrl)
+		notifier.failing = "43N23P"
+		inventoryRepo.EXPECT().GetStockThresholds(ctx, []int64{2, 4}).
+			Return([]*entity.StockThreshold{{ProductID: 2, Threshold: 1}, {ProductID: 4, Threshold: 3}}, nil).Times(1)
+
+		// macbook goes from 2 to 1
+		resp, err := svc.CheckCheckout(ctx, checkout(map[int64]int{2: 1, 4: 2}))
+		assert.Equal(t, entity.NewError("MacBook Pro(43N23P): queue is full", http.StatusInternalServerError), err)
+		expected := []*entity.StockAlert{{Product: raspberry, Quantity: 2, Threshold: 3, OrderID: 7, CreatedAt: alertTime}}
+		assert.Equal(t, expected, resp)
+		assert.Equal(t, expected, notifier.alerts)
+	})
+
+	t.Run("positive, stock is already at threshold", func(t *testing.T) {
+		svc, inventoryRepo, _, notifier := initStockAlertUC(ctrl)
+		inventoryRepo.EXPECT().GetStockThresholds(ctx, []int64{2, 4}).Return(thresholds, nil).Times(1)
+
+		// raspberry goes from 3 to 1
+		resp, err := svc.CheckCheckout(ctx, checkout(map[int64]int{2: 0, 4: 1}))
+		assert.Nil(t, err)
+		assert.Empty(t, resp)
+		assert.Empty(t, notifier.alerts)
+	})
+
+	t.Run("positive, stock is above threshold", func(t *testing.T) {
+		svc, inventoryRepo, _, notifier := initStockAlertUC(ctrl)
+		inventoryRepo.EXPECT().GetStockThresholds(ctx, []int64{2, 4}).Return(thresholds, nil).Times(1)
+
+		resp, err := svc.CheckCheckout(ctx, checkout(map[int64]int{2: 0, 4: 4}))
+		assert.Nil(t, err)
+		assert.Empty(t, resp)
+		assert.Empty(t, notifier.alerts)
+	})
+}
+
+func Test_SubmitStockAlert(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+
+	productRepo := repomocks.NewMockProductRepo(ctrl)
+	promoRepo := repomocks.NewMockPromotionRepo(ctrl)
+	inventoryRepo := repomocks.NewMockInventoryRepo(ctrl)
+	notifier := &fakeStockAlertNotifier{}
+	stockAlertUC := module.NewStockAlertUsecaseWithClock(inventoryRepo, productRepo, notifier, func() time.Time { return alertTime })
+	svc := module.NewCheckoutUsecaseWithStockAlert(productRepo, promoRepo, module.DefaultPromotionRules(), nil, stockAlertUC)
+	raspberry := &entity.Product{ID: 4, Serial: "234234", Name: "Raspberry Pi B", Price: entity.NewMoney(3000)}
+
+	t.Run("positive, alert is sent after checkout is submitted", func(t *testing.T) {
+		productRepo.EXPECT().GetProductBySerials(ctx, []string{"234234"}).Return([]*entity.Product{raspberry}, nil).Times(1)
+		promoRepo.EXPECT().GetPromotionByProducts(ctx, []*entity.Product{raspberry}).Return(nil, nil).Times(1)
+		promoRepo.EXPECT().GetActiveBundlesByProducts(ctx, gomock.Any()).Return(nil, nil).Times(1)
+		promoRepo.EXPECT().GetActiveCartPromotions(ctx, nil).Return(nil, nil).Times(1)
+		productRepo.EXPECT().SubmitCheckout(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, payload *entity.Checkout) error {
+			payload.OrderID = 9
+			payload.RemainingStock = map[int64]int{4: 0}
+			return nil
+		}).Times(1)
+		inventoryRepo.EXPECT().GetStockThresholds(ctx, []int64{4}).Return([]*entity.StockThreshold{{ProductID: 4, Threshold: 1}}, nil).Times(1)
+
+		resp, err := svc.Submit(ctx, entity.MapProductSerialQuantity{"234234": 2}, entity.CheckoutOptions{})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(9), resp.OrderID)
+		assert.Equal(t, []*entity.StockAlert{{Product: raspberry, Quantity: 0, Threshold: 1, OrderID: 9, CreatedAt: alertTime}}, notifier.alerts)
+	})
+
+	t.Run("positive, failed stock alert is logged and checkout succeeds", func(t *testing.T) {
+		var logs bytes.Buffer
+		log.SetOutput(&logs)
+		defer log.SetOutput(os.Stderr)
+
+		productRepo.EXPECT().GetProductBySerials(ctx, []string{"234234"}).Return([]*entity.Product{raspberry}, nil).Times(1)
+		promoRepo.EXPECT().GetPromotionByProducts(ctx, []*entity.Product{raspberry}).Return(nil, nil).Times(1)
+		promoRepo.EXPECT().GetActiveBundlesByProducts(ctx, gomock.Any()).Return(nil, nil).Times(1)
+		promoRepo.EXPECT().GetActiveCartPromotions(ctx, nil).Return(nil, nil).Times(1)
+		productRepo.EXPECT().SubmitCheckout(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, payload *entity.Checkout) error {
+			payload.OrderID = 10
+			payload.RemainingStock = map[int64]int{4: 0}
+			return nil
+		}).Times(1)
+		inventoryRepo.EXPECT().GetStockThresholds(ctx, []int64{4}).Return(nil, errors.New("connection refused")).Times(1)
+
+		resp, err := svc.Submit(ctx, entity.MapProductSerialQuantity{"234234": 2}, entity.CheckoutOptions{})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(10), resp.OrderID)
+		assert.Contains(t, logs.String(), "Error checking stock alert of order 10: connection refused")
+	})
+}
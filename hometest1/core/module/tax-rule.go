@@ -0,0 +1,83 @@
+package module
+
+import (
+	"context"
+	"net/http"
+	"sort"
+
+	"hometest1/core/entity"
+)
+
+// applyTax adds tax lines of the checkout region, one line per tax class of the items.
+// Cart discount is allocated to tax classes by their sub total, so tax is calculated from the price customer pays.
+// On exclusive mode tax is added to total price, on inclusive mode it is already in the price
+func (uc *checkoutUsecase) applyTax(ctx context.Context, checkout *entity.Checkout, regionCode string) error {
+	if uc.taxRepo == nil {
+		return nil
+	}
+	region, err := uc.taxRepo.GetTaxRegion(ctx, regionCode)
+	if err != nil {
+		return entity.NewInternalError(err)
+	}
+	if region == nil {
+		// no default region is set
+		if regionCode == "" {
+			return nil
+		}
+		return entity.NewError(entity.TaxRegionNotFound, http.StatusBadRequest)
+	}
+
+	// sum sub total per tax class
+	// map[string] = tax class, entity.Money = sub total after product promotions and bundles
+	classSubTotal := make(map[string]entity.Money)
+	var classes []string
+	itemsSubTotal := entity.NewMoney(0)
+	for _, item := range checkout.Items {
+		taxClass := item.Product.TaxClass
+		if taxClass == "" {
+			taxClass = entity.DefaultTaxClass
+		}
+		if _, ok := classSubTotal[taxClass]; !ok {
+			classes = append(classes, taxClass)
+			classSubTotal[taxClass] = entity.NewMoney(0)
+		}
+		classSubTotal[taxClass] = classSubTotal[taxClass].Add(item.SubTotalPrice)
+		itemsSubTotal = itemsSubTotal.Add(item.SubTotalPrice)
+	}
+	sort.Strings(classes)
+
+	checkout.TaxRegion = region.Code
+	checkout.TaxPriceMode = region.PriceMode
+	checkout.TaxPrice = entity.NewMoney(0)
+	if itemsSubTotal.Amount <= 0 {
+		return nil
+	}
+
+	// total price is the price after cart discount, the last class takes the rounding remainder
+	price := checkout.TotalPrice
+	allocated := entity.NewMoney(0)
+	for i, taxClass := range classes {
+		base := price.Prorate(classSubTotal[taxClass], itemsSubTotal)
+		if i == len(classes)-1 {
+			base = price.Sub(allocated)
+		}
+		allocated = allocated.Add(base)
+
+		rate := region.RateOf(taxClass)
+		tax := entity.CheckoutTax{TaxClass: taxClass, Rate: rate}
+		if region.PriceMode == entity.TaxInclusive {
+			tax.Amount = base.Fraction(int64(rate), int64(entity.TaxRateScale+rate))
+			tax.TaxableAmount = base.Sub(tax.Amount)
+		} else {
+			tax.Amount = base.Fraction(int64(rate), entity.TaxRateScale)
+			tax.TaxableAmount = base
+		}
+		checkout.Taxes = append(checkout.Taxes, &tax)
+		checkout.TaxPrice = checkout.TaxPrice.Add(tax.Amount)
+	}
+
+	if region.PriceMode == entity.TaxExclusive {
+		checkout.TotalPrice = checkout.TotalPrice.Add(checkout.TaxPrice)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package module
+
+import (
+	"context"
+	"net/http"
+
+	"hometest1/core/entity"
+	"hometest1/core/repository"
+)
+
+type TaxUsecase interface {
+	// create or replace tax region by code with its rates
+	Save(ctx context.Context, region *entity.TaxRegion) (*entity.TaxRegion, error)
+	// get tax regions with rates
+	List(ctx context.Context) ([]*entity.TaxRegion, error)
+}
+
+type taxUsecase struct {
+	taxRepo repository.TaxRepo
+}
+
+func NewTaxUsecase(taxRepo repository.TaxRepo) TaxUsecase {
+	return &taxUsecase{taxRepo}
+}
+
+func (uc *taxUsecase) Save(ctx context.Context, region *entity.TaxRegion) (*entity.TaxRegion, error) {
+	err := uc.validate(region)
+	if err != nil {
+		return nil, err
+	}
+
+	err = uc.taxRepo.SaveTaxRegion(ctx, region)
+	if err != nil {
+		return nil, entity.NewInternalError(err)
+	}
+	return region, nil
+}
+
+func (uc *taxUsecase) List(ctx context.Context) ([]*entity.TaxRegion, error) {
+	regions, err := uc.taxRepo.GetTaxRegions(ctx)
+	if err != nil {
+		return nil, entity.NewInternalError(err)
+	}
+	return regions, nil
+}
+
+// validate price mode and rates, a tax class can only have one rate
+func (uc *taxUsecase) validate(region *entity.TaxRegion) error {
+	if !region.PriceMode.IsValid() {
+		return entity.NewError(entity.InvalidTaxPriceMode, http.StatusBadRequest)
+	}
+	taxClasses := make(map[string]bool)
+	for _, rate := range region.Rates {
+		if rate.Rate < 0 || rate.Rate > entity.TaxRateScale {
+			return entity.NewError("tax rate must be between 0 and 100 percent", http.StatusBadRequest)
+		}
+		if taxClasses[rate.TaxClass] {
+			return entity.NewError(entity.DuplicateTaxClass, http.StatusBadRequest)
+		}
+		taxClasses[rate.TaxClass] = true
+	}
+	return nil
+}
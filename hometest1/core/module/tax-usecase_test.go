@@ -0,0 +1,68 @@
+package module_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"hometest1/core/entity"
+	"hometest1/core/module"
+	repomocks "hometest1/core/repository/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func initTaxUC(ctrl *gomock.Controller) (module.TaxUsecase, *repomocks.MockTaxRepo) {
+	taxRepo := repomocks.NewMockTaxRepo(ctrl)
+
+	return module.NewTaxUsecase(taxRepo), taxRepo
+}
+
+func Test_TaxSave(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+
+	svc, taxRepo := initTaxUC(ctrl)
+
+	t.Run("positive", func(t *testing.T) {
+		region := &entity.TaxRegion{Code: "DE", Name: "Germany", PriceMode: entity.TaxInclusive, IsDefault: true, Rates: []*entity.TaxRate{
+			{TaxClass: "standard", Rate: 1900},
+			{TaxClass: "reduced", Rate: 700},
+		}}
+		taxRepo.EXPECT().SaveTaxRegion(ctx, region).Return(nil).Times(1)
+
+		resp, err := svc.Save(ctx, region)
+		assert.Nil(t, err)
+		assert.Equal(t, region, resp)
+	})
+
+	t.Run("negative, invalid price mode", func(t *testing.T) {
+		_, err := svc.Save(ctx, &entity.TaxRegion{Code: "DE"})
+		assert.Equal(t, entity.NewError(entity.InvalidTaxPriceMode, http.StatusBadRequest), err)
+	})
+
+	t.Run("negative, rate is over 100 percent", func(t *testing.T) {
+		_, err := svc.Save(ctx, &entity.TaxRegion{Code: "DE", PriceMode: entity.TaxInclusive, Rates: []*entity.TaxRate{
+			{TaxClass: "standard", Rate: 10001},
+		}})
+		assert.Equal(t, entity.NewError("tax rate must be between 0 and 100 percent", http.StatusBadRequest), err)
+	})
+
+	t.Run("negative, duplicate tax class", func(t *testing.T) {
+		_, err := svc.Save(ctx, &entity.TaxRegion{Code: "DE", PriceMode: entity.TaxInclusive, Rates: []*entity.TaxRate{
+			{TaxClass: "standard", Rate: 1900},
+			{TaxClass: "standard", Rate: 700},
+		}})
+		assert.Equal(t, entity.NewError(entity.DuplicateTaxClass, http.StatusBadRequest), err)
+	})
+
+	t.Run("negative, repository error", func(t *testing.T) {
+		taxRepo.EXPECT().SaveTaxRegion(ctx, gomock.Any()).Return(errors.New("connection refused")).Times(1)
+
+		_, err := svc.Save(ctx, &entity.TaxRegion{Code: "CA", PriceMode: entity.TaxExclusive})
+		assert.Equal(t, entity.NewError("connection refused", http.StatusInternalServerError), err)
+	})
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+
+	"hometest1/core/entity"
+)
+
+type CartRepo interface {
+	CreateCart(ctx context.Context) (*entity.Cart, error)
+	// get cart with its items, return nil if cart not found
+	GetCart(ctx context.Context, id int64) (*entity.Cart, error)
+	// insert item, or update quantity if product already in cart
+	SaveCartItem(ctx context.Context, item *entity.CartItem) error
+	DeleteCartItem(ctx context.Context, cartID int64, productID int64) error
+}
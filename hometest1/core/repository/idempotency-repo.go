@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+
+	"hometest1/core/entity"
+)
+
+type IdempotencyRepo interface {
+	// insert the key as in progress request, return false if the key already exists
+	CreateKey(ctx context.Context, key *entity.IdempotencyKey) (bool, error)
+	// return nil if key is not found
+	GetKey(ctx context.Context, key string) (*entity.IdempotencyKey, error)
+	// store response code and body of the key
+	SaveResponse(ctx context.Context, key *entity.IdempotencyKey) error
+	// delete in progress key by id, return false if it is completed or already deleted,
+	// so only one request can release or take over the key
+	DeleteKey(ctx context.Context, key *entity.IdempotencyKey) (bool, error)
+}
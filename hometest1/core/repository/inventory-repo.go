@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+
+	"hometest1/core/entity"
+)
+
+type InventoryRepo interface {
+	// add product quantity and record it as restock movement, return updated quantity
+	Restock(ctx context.Context, productID int64, quantity int, reference string) (*entity.ProductQuantity, error)
+	// set product quantity and record the difference as restock or adjustment movement,
+	// nothing is recorded if quantity is unchanged. Return updated quantity
+	SetStock(ctx context.Context, productID int64, quantity int, reference string) (*entity.ProductQuantity, error)
+	// get stock movements of the product sorted by newest
+	GetStockMovements(ctx context.Context, productID int64, limit, offset int) ([]*entity.StockMovement, int64, error)
+	// get reorder thresholds of the products, product without threshold is skipped
+	GetStockThresholds(ctx context.Context, productIDs []int64) ([]*entity.StockThreshold, error)
+	// create or replace reorder threshold of the product
+	SaveStockThreshold(ctx context.Context, threshold *entity.StockThreshold) error
+}
@@ -0,0 +1,94 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: cart-repo.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "hometest1/core/entity"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockCartRepo is a mock of CartRepo interface.
+type MockCartRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockCartRepoMockRecorder
+}
+
+// MockCartRepoMockRecorder is the mock recorder for MockCartRepo.
+type MockCartRepoMockRecorder struct {
+	mock *MockCartRepo
+}
+
+// NewMockCartRepo creates a new mock instance.
+func NewMockCartRepo(ctrl *gomock.Controller) *MockCartRepo {
+	mock := &MockCartRepo{ctrl: ctrl}
+	mock.recorder = &MockCartRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCartRepo) EXPECT() *MockCartRepoMockRecorder {
+	return m.recorder
+}
+
+// CreateCart mocks base method.
+func (m *MockCartRepo) CreateCart(ctx context.Context) (*entity.Cart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCart", ctx)
+	ret0, _ := ret[0].(*entity.Cart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCart indicates an expected call of CreateCart.
+func (mr *MockCartRepoMockRecorder) CreateCart(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCart", reflect.TypeOf((*MockCartRepo)(nil).CreateCart), ctx)
+}
+
+// DeleteCartItem mocks base method.
+func (m *MockCartRepo) DeleteCartItem(ctx context.Context, cartID, productID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCartItem", ctx, cartID, productID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCartItem indicates an expected call of DeleteCartItem.
+func (mr *MockCartRepoMockRecorder) DeleteCartItem(ctx, cartID, productID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCartItem", reflect.TypeOf((*MockCartRepo)(nil).DeleteCartItem), ctx, cartID, productID)
+}
+
+// GetCart mocks base method.
+func (m *MockCartRepo) GetCart(ctx context.Context, id int64) (*entity.Cart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCart", ctx, id)
+	ret0, _ := ret[0].(*entity.Cart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCart indicates an expected call of GetCart.
+func (mr *MockCartRepoMockRecorder) GetCart(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCart", reflect.TypeOf((*MockCartRepo)(nil).GetCart), ctx, id)
+}
+
+// SaveCartItem mocks base method.
+func (m *MockCartRepo) SaveCartItem(ctx context.Context, item *entity.CartItem) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveCartItem", ctx, item)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveCartItem indicates an expected call of SaveCartItem.
+func (mr *MockCartRepoMockRecorder) SaveCartItem(ctx, item interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveCartItem", reflect.TypeOf((*MockCartRepo)(nil).SaveCartItem), ctx, item)
+}
@@ -0,0 +1,111 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: inventory-repo.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "hometest1/core/entity"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockInventoryRepo is a mock of InventoryRepo interface.
+type MockInventoryRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockInventoryRepoMockRecorder
+}
+
+// MockInventoryRepoMockRecorder is the mock recorder for MockInventoryRepo.
+type MockInventoryRepoMockRecorder struct {
+	mock *MockInventoryRepo
+}
+
+// NewMockInventoryRepo creates a new mock instance.
+func NewMockInventoryRepo(ctrl *gomock.Controller) *MockInventoryRepo {
+	mock := &MockInventoryRepo{ctrl: ctrl}
+	mock.recorder = &MockInventoryRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInventoryRepo) EXPECT() *MockInventoryRepoMockRecorder {
+	return m.recorder
+}
+
+// GetStockMovements mocks base method.
+func (m *MockInventoryRepo) GetStockMovements(ctx context.Context, productID int64, limit, offset int) ([]*entity.StockMovement, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStockMovements", ctx, productID, limit, offset)
+	ret0, _ := ret[0].([]*entity.StockMovement)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetStockMovements indicates an expected call of GetStockMovements.
+func (mr *MockInventoryRepoMockRecorder) GetStockMovements(ctx, productID, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStockMovements", reflect.TypeOf((*MockInventoryRepo)(nil).GetStockMovements), ctx, productID, limit, offset)
+}
+
+// GetStockThresholds mocks base method.
+func (m *MockInventoryRepo) GetStockThresholds(ctx context.Context, productIDs []int64) ([]*entity.StockThreshold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStockThresholds", ctx, productIDs)
+	ret0, _ := ret[0].([]*entity.StockThreshold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStockThresholds indicates an expected call of GetStockThresholds.
+func (mr *MockInventoryRepoMockRecorder) GetStockThresholds(ctx, productIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStockThresholds", reflect.TypeOf((*MockInventoryRepo)(nil).GetStockThresholds), ctx, productIDs)
+}
+
+// Restock mocks base method.
+func (m *MockInventoryRepo) Restock(ctx context.Context, productID int64, quantity int, reference string) (*entity.ProductQuantity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restock", ctx, productID, quantity, reference)
+	ret0, _ := ret[0].(*entity.ProductQuantity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Restock indicates an expected call of Restock.
+func (mr *MockInventoryRepoMockRecorder) Restock(ctx, productID, quantity, reference interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restock", reflect.TypeOf((*MockInventoryRepo)(nil).Restock), ctx, productID, quantity, reference)
+}
+
+// SaveStockThreshold mocks base method.
+func (m *MockInventoryRepo) SaveStockThreshold(ctx context.Context, threshold *entity.StockThreshold) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveStockThreshold", ctx, threshold)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveStockThreshold indicates an expected call of SaveStockThreshold.
+func (mr *MockInventoryRepoMockRecorder) SaveStockThreshold(ctx, threshold interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveStockThreshold", reflect.TypeOf((*MockInventoryRepo)(nil).SaveStockThreshold), ctx, threshold)
+}
+
+// SetStock mocks base method.
+func (m *MockInventoryRepo) SetStock(ctx context.Context, productID int64, quantity int, reference string) (*entity.ProductQuantity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetStock", ctx, productID, quantity, reference)
+	ret0, _ := ret[0].(*entity.ProductQuantity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetStock indicates an expected call of SetStock.
+func (mr *MockInventoryRepoMockRecorder) SetStock(ctx, productID, quantity, reference interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetStock", reflect.TypeOf((*MockInventoryRepo)(nil).SetStock), ctx, productID, quantity, reference)
+}
@@ -0,0 +1,81 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: order-repo.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "hometest1/core/entity"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockOrderRepo is a mock of OrderRepo interface.
+type MockOrderRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockOrderRepoMockRecorder
+}
+
+// MockOrderRepoMockRecorder is the mock recorder for MockOrderRepo.
+type MockOrderRepoMockRecorder struct {
+	mock *MockOrderRepo
+}
+
+// NewMockOrderRepo creates a new mock instance.
+func NewMockOrderRepo(ctrl *gomock.Controller) *MockOrderRepo {
+	mock := &MockOrderRepo{ctrl: ctrl}
+	mock.recorder = &MockOrderRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOrderRepo) EXPECT() *MockOrderRepoMockRecorder {
+	return m.recorder
+}
+
+// GetOrder mocks base method.
+func (m *MockOrderRepo) GetOrder(ctx context.Context, id int64) (*entity.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrder", ctx, id)
+	ret0, _ := ret[0].(*entity.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrder indicates an expected call of GetOrder.
+func (mr *MockOrderRepoMockRecorder) GetOrder(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrder", reflect.TypeOf((*MockOrderRepo)(nil).GetOrder), ctx, id)
+}
+
+// GetOrders mocks base method.
+func (m *MockOrderRepo) GetOrders(ctx context.Context, limit, offset int) ([]*entity.Order, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrders", ctx, limit, offset)
+	ret0, _ := ret[0].([]*entity.Order)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetOrders indicates an expected call of GetOrders.
+func (mr *MockOrderRepoMockRecorder) GetOrders(ctx, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrders", reflect.TypeOf((*MockOrderRepo)(nil).GetOrders), ctx, limit, offset)
+}
+
+// ReturnOrder mocks base method.
+func (m *MockOrderRepo) ReturnOrder(ctx context.Context, ret *entity.OrderReturn, status entity.OrderStatus) error {
+	m.ctrl.T.Helper()
+	ret_2 := m.ctrl.Call(m, "ReturnOrder", ctx, ret, status)
+	ret0, _ := ret_2[0].(error)
+	return ret0
+}
+
+// ReturnOrder indicates an expected call of ReturnOrder.
+func (mr *MockOrderRepoMockRecorder) ReturnOrder(ctx, ret, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReturnOrder", reflect.TypeOf((*MockOrderRepo)(nil).ReturnOrder), ctx, ret, status)
+}
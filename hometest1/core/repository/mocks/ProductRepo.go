@@ -5,6 +5,7 @@
 package mock_repository
 
 import (
+	context "context"
 	reflect "reflect"
 
 	entity "hometest1/core/entity"
@@ -34,46 +35,163 @@ func (m *MockProductRepo) EXPECT() *MockProductRepoMockRecorder {
 	return m.recorder
 }
 
+// CreateProduct mocks base method.
+func (m *MockProductRepo) CreateProduct(ctx context.Context, product *entity.Product, quantity int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateProduct", ctx, product, quantity)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateProduct indicates an expected call of CreateProduct.
+func (mr *MockProductRepoMockRecorder) CreateProduct(ctx, product, quantity interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateProduct", reflect.TypeOf((*MockProductRepo)(nil).CreateProduct), ctx, product, quantity)
+}
+
+// DeleteProduct mocks base method.
+func (m *MockProductRepo) DeleteProduct(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteProduct", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteProduct indicates an expected call of DeleteProduct.
+func (mr *MockProductRepoMockRecorder) DeleteProduct(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteProduct", reflect.TypeOf((*MockProductRepo)(nil).DeleteProduct), ctx, id)
+}
+
+// GetAvailableQuantityByIDs mocks base method.
+func (m *MockProductRepo) GetAvailableQuantityByIDs(ctx context.Context, productIDs []int64) ([]*entity.ProductQuantity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAvailableQuantityByIDs", ctx, productIDs)
+	ret0, _ := ret[0].([]*entity.ProductQuantity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAvailableQuantityByIDs indicates an expected call of GetAvailableQuantityByIDs.
+func (mr *MockProductRepoMockRecorder) GetAvailableQuantityByIDs(ctx, productIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAvailableQuantityByIDs", reflect.TypeOf((*MockProductRepo)(nil).GetAvailableQuantityByIDs), ctx, productIDs)
+}
+
 // GetProductByIDs mocks base method.
-func (m *MockProductRepo) GetProductByIDs(ids []int64) ([]*entity.Product, error) {
+func (m *MockProductRepo) GetProductByIDs(ctx context.Context, ids []int64) ([]*entity.Product, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetProductByIDs", ids)
+	ret := m.ctrl.Call(m, "GetProductByIDs", ctx, ids)
 	ret0, _ := ret[0].([]*entity.Product)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetProductByIDs indicates an expected call of GetProductByIDs.
-func (mr *MockProductRepoMockRecorder) GetProductByIDs(ids interface{}) *gomock.Call {
+func (mr *MockProductRepoMockRecorder) GetProductByIDs(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProductByIDs", reflect.TypeOf((*MockProductRepo)(nil).GetProductByIDs), ctx, ids)
+}
+
+// GetProductByIDsWithDeleted mocks base method.
+func (m *MockProductRepo) GetProductByIDsWithDeleted(ctx context.Context, ids []int64) ([]*entity.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProductByIDsWithDeleted", ctx, ids)
+	ret0, _ := ret[0].([]*entity.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProductByIDsWithDeleted indicates an expected call of GetProductByIDsWithDeleted.
+func (mr *MockProductRepoMockRecorder) GetProductByIDsWithDeleted(ctx, ids interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProductByIDs", reflect.TypeOf((*MockProductRepo)(nil).GetProductByIDs), ids)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProductByIDsWithDeleted", reflect.TypeOf((*MockProductRepo)(nil).GetProductByIDsWithDeleted), ctx, ids)
 }
 
 // GetProductBySerials mocks base method.
-func (m *MockProductRepo) GetProductBySerials(serials []string) ([]*entity.Product, error) {
+func (m *MockProductRepo) GetProductBySerials(ctx context.Context, serials []string) ([]*entity.Product, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetProductBySerials", serials)
+	ret := m.ctrl.Call(m, "GetProductBySerials", ctx, serials)
 	ret0, _ := ret[0].([]*entity.Product)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetProductBySerials indicates an expected call of GetProductBySerials.
-func (mr *MockProductRepoMockRecorder) GetProductBySerials(serials interface{}) *gomock.Call {
+func (mr *MockProductRepoMockRecorder) GetProductBySerials(ctx, serials interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProductBySerials", reflect.TypeOf((*MockProductRepo)(nil).GetProductBySerials), ctx, serials)
+}
+
+// GetProductQuantityByIDs mocks base method.
+func (m *MockProductRepo) GetProductQuantityByIDs(ctx context.Context, productIDs []int64) ([]*entity.ProductQuantity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProductQuantityByIDs", ctx, productIDs)
+	ret0, _ := ret[0].([]*entity.ProductQuantity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProductQuantityByIDs indicates an expected call of GetProductQuantityByIDs.
+func (mr *MockProductRepoMockRecorder) GetProductQuantityByIDs(ctx, productIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProductQuantityByIDs", reflect.TypeOf((*MockProductRepo)(nil).GetProductQuantityByIDs), ctx, productIDs)
+}
+
+// GetProducts mocks base method.
+func (m *MockProductRepo) GetProducts(ctx context.Context, limit, offset int) ([]*entity.Product, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProducts", ctx, limit, offset)
+	ret0, _ := ret[0].([]*entity.Product)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetProducts indicates an expected call of GetProducts.
+func (mr *MockProductRepoMockRecorder) GetProducts(ctx, limit, offset interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProductBySerials", reflect.TypeOf((*MockProductRepo)(nil).GetProductBySerials), serials)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProducts", reflect.TypeOf((*MockProductRepo)(nil).GetProducts), ctx, limit, offset)
+}
+
+// ReserveStock mocks base method.
+func (m *MockProductRepo) ReserveStock(ctx context.Context, reservation *entity.Reservation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReserveStock", ctx, reservation)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReserveStock indicates an expected call of ReserveStock.
+func (mr *MockProductRepoMockRecorder) ReserveStock(ctx, reservation interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReserveStock", reflect.TypeOf((*MockProductRepo)(nil).ReserveStock), ctx, reservation)
 }
 
 // SubmitCheckout mocks base method.
-func (m *MockProductRepo) SubmitCheckout(payload *entity.Checkout) error {
+func (m *MockProductRepo) SubmitCheckout(ctx context.Context, payload *entity.Checkout) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SubmitCheckout", payload)
+	ret := m.ctrl.Call(m, "SubmitCheckout", ctx, payload)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // SubmitCheckout indicates an expected call of SubmitCheckout.
-func (mr *MockProductRepoMockRecorder) SubmitCheckout(payload interface{}) *gomock.Call {
+func (mr *MockProductRepoMockRecorder) SubmitCheckout(ctx, payload interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubmitCheckout", reflect.TypeOf((*MockProductRepo)(nil).SubmitCheckout), ctx, payload)
+}
+
+// UpdateProduct mocks base method.
+func (m *MockProductRepo) UpdateProduct(ctx context.Context, product *entity.Product) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProduct", ctx, product)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateProduct indicates an expected call of UpdateProduct.
+func (mr *MockProductRepoMockRecorder) UpdateProduct(ctx, product interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubmitCheckout", reflect.TypeOf((*MockProductRepo)(nil).SubmitCheckout), payload)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProduct", reflect.TypeOf((*MockProductRepo)(nil).UpdateProduct), ctx, product)
 }
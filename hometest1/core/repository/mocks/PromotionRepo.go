@@ -5,6 +5,7 @@
 package mock_repository
 
 import (
+	context "context"
 	reflect "reflect"
 
 	entity "hometest1/core/entity"
@@ -34,17 +35,429 @@ func (m *MockPromotionRepo) EXPECT() *MockPromotionRepoMockRecorder {
 	return m.recorder
 }
 
+// CreateBundle mocks base method.
+func (m *MockPromotionRepo) CreateBundle(ctx context.Context, bundle *entity.Bundle) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBundle", ctx, bundle)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateBundle indicates an expected call of CreateBundle.
+func (mr *MockPromotionRepoMockRecorder) CreateBundle(ctx, bundle interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBundle", reflect.TypeOf((*MockPromotionRepo)(nil).CreateBundle), ctx, bundle)
+}
+
+// CreateCartPromotion mocks base method.
+func (m *MockPromotionRepo) CreateCartPromotion(ctx context.Context, promo *entity.CartPromotion) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCartPromotion", ctx, promo)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateCartPromotion indicates an expected call of CreateCartPromotion.
+func (mr *MockPromotionRepoMockRecorder) CreateCartPromotion(ctx, promo interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCartPromotion", reflect.TypeOf((*MockPromotionRepo)(nil).CreateCartPromotion), ctx, promo)
+}
+
+// CreateCoupon mocks base method.
+func (m *MockPromotionRepo) CreateCoupon(ctx context.Context, coupon *entity.Coupon) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCoupon", ctx, coupon)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateCoupon indicates an expected call of CreateCoupon.
+func (mr *MockPromotionRepoMockRecorder) CreateCoupon(ctx, coupon interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCoupon", reflect.TypeOf((*MockPromotionRepo)(nil).CreateCoupon), ctx, coupon)
+}
+
+// CreatePromotion mocks base method.
+func (m *MockPromotionRepo) CreatePromotion(ctx context.Context, promo *entity.Promotion) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePromotion", ctx, promo)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreatePromotion indicates an expected call of CreatePromotion.
+func (mr *MockPromotionRepoMockRecorder) CreatePromotion(ctx, promo interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePromotion", reflect.TypeOf((*MockPromotionRepo)(nil).CreatePromotion), ctx, promo)
+}
+
+// DeleteBundle mocks base method.
+func (m *MockPromotionRepo) DeleteBundle(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBundle", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteBundle indicates an expected call of DeleteBundle.
+func (mr *MockPromotionRepoMockRecorder) DeleteBundle(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBundle", reflect.TypeOf((*MockPromotionRepo)(nil).DeleteBundle), ctx, id)
+}
+
+// DeleteCartPromotion mocks base method.
+func (m *MockPromotionRepo) DeleteCartPromotion(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCartPromotion", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCartPromotion indicates an expected call of DeleteCartPromotion.
+func (mr *MockPromotionRepoMockRecorder) DeleteCartPromotion(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCartPromotion", reflect.TypeOf((*MockPromotionRepo)(nil).DeleteCartPromotion), ctx, id)
+}
+
+// DeleteCoupon mocks base method.
+func (m *MockPromotionRepo) DeleteCoupon(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCoupon", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCoupon indicates an expected call of DeleteCoupon.
+func (mr *MockPromotionRepoMockRecorder) DeleteCoupon(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCoupon", reflect.TypeOf((*MockPromotionRepo)(nil).DeleteCoupon), ctx, id)
+}
+
+// DeletePromotion mocks base method.
+func (m *MockPromotionRepo) DeletePromotion(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePromotion", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePromotion indicates an expected call of DeletePromotion.
+func (mr *MockPromotionRepoMockRecorder) DeletePromotion(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePromotion", reflect.TypeOf((*MockPromotionRepo)(nil).DeletePromotion), ctx, id)
+}
+
+// GetActiveBundlesByProducts mocks base method.
+func (m *MockPromotionRepo) GetActiveBundlesByProducts(ctx context.Context, productIDs []int64) ([]*entity.Bundle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveBundlesByProducts", ctx, productIDs)
+	ret0, _ := ret[0].([]*entity.Bundle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveBundlesByProducts indicates an expected call of GetActiveBundlesByProducts.
+func (mr *MockPromotionRepoMockRecorder) GetActiveBundlesByProducts(ctx, productIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveBundlesByProducts", reflect.TypeOf((*MockPromotionRepo)(nil).GetActiveBundlesByProducts), ctx, productIDs)
+}
+
+// GetActiveCartPromotions mocks base method.
+func (m *MockPromotionRepo) GetActiveCartPromotions(ctx context.Context, couponPromotionIDs []int64) ([]*entity.CartPromotion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveCartPromotions", ctx, couponPromotionIDs)
+	ret0, _ := ret[0].([]*entity.CartPromotion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveCartPromotions indicates an expected call of GetActiveCartPromotions.
+func (mr *MockPromotionRepoMockRecorder) GetActiveCartPromotions(ctx, couponPromotionIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveCartPromotions", reflect.TypeOf((*MockPromotionRepo)(nil).GetActiveCartPromotions), ctx, couponPromotionIDs)
+}
+
+// GetBundle mocks base method.
+func (m *MockPromotionRepo) GetBundle(ctx context.Context, id int64) (*entity.Bundle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBundle", ctx, id)
+	ret0, _ := ret[0].(*entity.Bundle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBundle indicates an expected call of GetBundle.
+func (mr *MockPromotionRepoMockRecorder) GetBundle(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBundle", reflect.TypeOf((*MockPromotionRepo)(nil).GetBundle), ctx, id)
+}
+
+// GetBundleByIDsWithDeleted mocks base method.
+func (m *MockPromotionRepo) GetBundleByIDsWithDeleted(ctx context.Context, ids []int64) ([]*entity.Bundle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBundleByIDsWithDeleted", ctx, ids)
+	ret0, _ := ret[0].([]*entity.Bundle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBundleByIDsWithDeleted indicates an expected call of GetBundleByIDsWithDeleted.
+func (mr *MockPromotionRepoMockRecorder) GetBundleByIDsWithDeleted(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBundleByIDsWithDeleted", reflect.TypeOf((*MockPromotionRepo)(nil).GetBundleByIDsWithDeleted), ctx, ids)
+}
+
+// GetBundles mocks base method.
+func (m *MockPromotionRepo) GetBundles(ctx context.Context, limit, offset int) ([]*entity.Bundle, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBundles", ctx, limit, offset)
+	ret0, _ := ret[0].([]*entity.Bundle)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetBundles indicates an expected call of GetBundles.
+func (mr *MockPromotionRepoMockRecorder) GetBundles(ctx, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBundles", reflect.TypeOf((*MockPromotionRepo)(nil).GetBundles), ctx, limit, offset)
+}
+
+// GetCartPromotion mocks base method.
+func (m *MockPromotionRepo) GetCartPromotion(ctx context.Context, id int64) (*entity.CartPromotion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCartPromotion", ctx, id)
+	ret0, _ := ret[0].(*entity.CartPromotion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCartPromotion indicates an expected call of GetCartPromotion.
+func (mr *MockPromotionRepoMockRecorder) GetCartPromotion(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCartPromotion", reflect.TypeOf((*MockPromotionRepo)(nil).GetCartPromotion), ctx, id)
+}
+
+// GetCartPromotionByIDsWithDeleted mocks base method.
+func (m *MockPromotionRepo) GetCartPromotionByIDsWithDeleted(ctx context.Context, ids []int64) ([]*entity.CartPromotion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCartPromotionByIDsWithDeleted", ctx, ids)
+	ret0, _ := ret[0].([]*entity.CartPromotion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCartPromotionByIDsWithDeleted indicates an expected call of GetCartPromotionByIDsWithDeleted.
+func (mr *MockPromotionRepoMockRecorder) GetCartPromotionByIDsWithDeleted(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCartPromotionByIDsWithDeleted", reflect.TypeOf((*MockPromotionRepo)(nil).GetCartPromotionByIDsWithDeleted), ctx, ids)
+}
+
+// GetCartPromotions mocks base method.
+func (m *MockPromotionRepo) GetCartPromotions(ctx context.Context, limit, offset int) ([]*entity.CartPromotion, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCartPromotions", ctx, limit, offset)
+	ret0, _ := ret[0].([]*entity.CartPromotion)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetCartPromotions indicates an expected call of GetCartPromotions.
+func (mr *MockPromotionRepoMockRecorder) GetCartPromotions(ctx, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCartPromotions", reflect.TypeOf((*MockPromotionRepo)(nil).GetCartPromotions), ctx, limit, offset)
+}
+
+// GetCoupon mocks base method.
+func (m *MockPromotionRepo) GetCoupon(ctx context.Context, id int64) (*entity.Coupon, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCoupon", ctx, id)
+	ret0, _ := ret[0].(*entity.Coupon)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCoupon indicates an expected call of GetCoupon.
+func (mr *MockPromotionRepoMockRecorder) GetCoupon(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCoupon", reflect.TypeOf((*MockPromotionRepo)(nil).GetCoupon), ctx, id)
+}
+
+// GetCoupons mocks base method.
+func (m *MockPromotionRepo) GetCoupons(ctx context.Context, limit, offset int) ([]*entity.Coupon, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCoupons", ctx, limit, offset)
+	ret0, _ := ret[0].([]*entity.Coupon)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetCoupons indicates an expected call of GetCoupons.
+func (mr *MockPromotionRepoMockRecorder) GetCoupons(ctx, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCoupons", reflect.TypeOf((*MockPromotionRepo)(nil).GetCoupons), ctx, limit, offset)
+}
+
+// GetCouponsByCodes mocks base method.
+func (m *MockPromotionRepo) GetCouponsByCodes(ctx context.Context, codes []string) ([]*entity.Coupon, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCouponsByCodes", ctx, codes)
+	ret0, _ := ret[0].([]*entity.Coupon)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCouponsByCodes indicates an expected call of GetCouponsByCodes.
+func (mr *MockPromotionRepoMockRecorder) GetCouponsByCodes(ctx, codes interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCouponsByCodes", reflect.TypeOf((*MockPromotionRepo)(nil).GetCouponsByCodes), ctx, codes)
+}
+
+// GetFreeItemPromotions mocks base method.
+func (m *MockPromotionRepo) GetFreeItemPromotions(ctx context.Context) ([]*entity.Promotion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFreeItemPromotions", ctx)
+	ret0, _ := ret[0].([]*entity.Promotion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFreeItemPromotions indicates an expected call of GetFreeItemPromotions.
+func (mr *MockPromotionRepoMockRecorder) GetFreeItemPromotions(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFreeItemPromotions", reflect.TypeOf((*MockPromotionRepo)(nil).GetFreeItemPromotions), ctx)
+}
+
+// GetPromotion mocks base method.
+func (m *MockPromotionRepo) GetPromotion(ctx context.Context, id int64) (*entity.Promotion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPromotion", ctx, id)
+	ret0, _ := ret[0].(*entity.Promotion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPromotion indicates an expected call of GetPromotion.
+func (mr *MockPromotionRepoMockRecorder) GetPromotion(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPromotion", reflect.TypeOf((*MockPromotionRepo)(nil).GetPromotion), ctx, id)
+}
+
+// GetPromotionByIDsWithDeleted mocks base method.
+func (m *MockPromotionRepo) GetPromotionByIDsWithDeleted(ctx context.Context, ids []int64) ([]*entity.Promotion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPromotionByIDsWithDeleted", ctx, ids)
+	ret0, _ := ret[0].([]*entity.Promotion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPromotionByIDsWithDeleted indicates an expected call of GetPromotionByIDsWithDeleted.
+func (mr *MockPromotionRepoMockRecorder) GetPromotionByIDsWithDeleted(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPromotionByIDsWithDeleted", reflect.TypeOf((*MockPromotionRepo)(nil).GetPromotionByIDsWithDeleted), ctx, ids)
+}
+
 // GetPromotionByProducts mocks base method.
-func (m *MockPromotionRepo) GetPromotionByProducts(products []*entity.Product) (map[int64][]*entity.Promotion, error) {
+func (m *MockPromotionRepo) GetPromotionByProducts(ctx context.Context, products []*entity.Product) (map[int64][]*entity.Promotion, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetPromotionByProducts", products)
+	ret := m.ctrl.Call(m, "GetPromotionByProducts", ctx, products)
 	ret0, _ := ret[0].(map[int64][]*entity.Promotion)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetPromotionByProducts indicates an expected call of GetPromotionByProducts.
-func (mr *MockPromotionRepoMockRecorder) GetPromotionByProducts(products interface{}) *gomock.Call {
+func (mr *MockPromotionRepoMockRecorder) GetPromotionByProducts(ctx, products interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPromotionByProducts", reflect.TypeOf((*MockPromotionRepo)(nil).GetPromotionByProducts), ctx, products)
+}
+
+// GetPromotions mocks base method.
+func (m *MockPromotionRepo) GetPromotions(ctx context.Context, limit, offset int) ([]*entity.Promotion, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPromotions", ctx, limit, offset)
+	ret0, _ := ret[0].([]*entity.Promotion)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetPromotions indicates an expected call of GetPromotions.
+func (mr *MockPromotionRepoMockRecorder) GetPromotions(ctx, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPromotions", reflect.TypeOf((*MockPromotionRepo)(nil).GetPromotions), ctx, limit, offset)
+}
+
+// GetUpcomingPromotionsByProduct mocks base method.
+func (m *MockPromotionRepo) GetUpcomingPromotionsByProduct(ctx context.Context, productID int64) ([]*entity.Promotion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUpcomingPromotionsByProduct", ctx, productID)
+	ret0, _ := ret[0].([]*entity.Promotion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUpcomingPromotionsByProduct indicates an expected call of GetUpcomingPromotionsByProduct.
+func (mr *MockPromotionRepoMockRecorder) GetUpcomingPromotionsByProduct(ctx, productID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUpcomingPromotionsByProduct", reflect.TypeOf((*MockPromotionRepo)(nil).GetUpcomingPromotionsByProduct), ctx, productID)
+}
+
+// UpdateBundle mocks base method.
+func (m *MockPromotionRepo) UpdateBundle(ctx context.Context, bundle *entity.Bundle) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateBundle", ctx, bundle)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateBundle indicates an expected call of UpdateBundle.
+func (mr *MockPromotionRepoMockRecorder) UpdateBundle(ctx, bundle interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBundle", reflect.TypeOf((*MockPromotionRepo)(nil).UpdateBundle), ctx, bundle)
+}
+
+// UpdateCartPromotion mocks base method.
+func (m *MockPromotionRepo) UpdateCartPromotion(ctx context.Context, promo *entity.CartPromotion) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCartPromotion", ctx, promo)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateCartPromotion indicates an expected call of UpdateCartPromotion.
+func (mr *MockPromotionRepoMockRecorder) UpdateCartPromotion(ctx, promo interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCartPromotion", reflect.TypeOf((*MockPromotionRepo)(nil).UpdateCartPromotion), ctx, promo)
+}
+
+// UpdateCoupon mocks base method.
+func (m *MockPromotionRepo) UpdateCoupon(ctx context.Context, coupon *entity.Coupon) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCoupon", ctx, coupon)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateCoupon indicates an expected call of UpdateCoupon.
+func (mr *MockPromotionRepoMockRecorder) UpdateCoupon(ctx, coupon interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCoupon", reflect.TypeOf((*MockPromotionRepo)(nil).UpdateCoupon), ctx, coupon)
+}
+
+// UpdatePromotion mocks base method.
+func (m *MockPromotionRepo) UpdatePromotion(ctx context.Context, promo *entity.Promotion) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePromotion", ctx, promo)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePromotion indicates an expected call of UpdatePromotion.
+func (mr *MockPromotionRepoMockRecorder) UpdatePromotion(ctx, promo interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPromotionByProducts", reflect.TypeOf((*MockPromotionRepo)(nil).GetPromotionByProducts), products)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePromotion", reflect.TypeOf((*MockPromotionRepo)(nil).UpdatePromotion), ctx, promo)
 }
@@ -0,0 +1,82 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: reservation-repo.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	entity "hometest1/core/entity"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockReservationRepo is a mock of ReservationRepo interface.
+type MockReservationRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockReservationRepoMockRecorder
+}
+
+// MockReservationRepoMockRecorder is the mock recorder for MockReservationRepo.
+type MockReservationRepoMockRecorder struct {
+	mock *MockReservationRepo
+}
+
+// NewMockReservationRepo creates a new mock instance.
+func NewMockReservationRepo(ctrl *gomock.Controller) *MockReservationRepo {
+	mock := &MockReservationRepo{ctrl: ctrl}
+	mock.recorder = &MockReservationRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReservationRepo) EXPECT() *MockReservationRepoMockRecorder {
+	return m.recorder
+}
+
+// ExpireReservations mocks base method.
+func (m *MockReservationRepo) ExpireReservations(ctx context.Context, now time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireReservations", ctx, now)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireReservations indicates an expected call of ExpireReservations.
+func (mr *MockReservationRepoMockRecorder) ExpireReservations(ctx, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireReservations", reflect.TypeOf((*MockReservationRepo)(nil).ExpireReservations), ctx, now)
+}
+
+// GetReservation mocks base method.
+func (m *MockReservationRepo) GetReservation(ctx context.Context, id int64) (*entity.Reservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReservation", ctx, id)
+	ret0, _ := ret[0].(*entity.Reservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReservation indicates an expected call of GetReservation.
+func (mr *MockReservationRepoMockRecorder) GetReservation(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReservation", reflect.TypeOf((*MockReservationRepo)(nil).GetReservation), ctx, id)
+}
+
+// ReleaseReservation mocks base method.
+func (m *MockReservationRepo) ReleaseReservation(ctx context.Context, id int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseReservation", ctx, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReleaseReservation indicates an expected call of ReleaseReservation.
+func (mr *MockReservationRepoMockRecorder) ReleaseReservation(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseReservation", reflect.TypeOf((*MockReservationRepo)(nil).ReleaseReservation), ctx, id)
+}
@@ -0,0 +1,80 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: tax-repo.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "hometest1/core/entity"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockTaxRepo is a mock of TaxRepo interface.
+type MockTaxRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockTaxRepoMockRecorder
+}
+
+// MockTaxRepoMockRecorder is the mock recorder for MockTaxRepo.
+type MockTaxRepoMockRecorder struct {
+	mock *MockTaxRepo
+}
+
+// NewMockTaxRepo creates a new mock instance.
+func NewMockTaxRepo(ctrl *gomock.Controller) *MockTaxRepo {
+	mock := &MockTaxRepo{ctrl: ctrl}
+	mock.recorder = &MockTaxRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTaxRepo) EXPECT() *MockTaxRepoMockRecorder {
+	return m.recorder
+}
+
+// GetTaxRegion mocks base method.
+func (m *MockTaxRepo) GetTaxRegion(ctx context.Context, code string) (*entity.TaxRegion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTaxRegion", ctx, code)
+	ret0, _ := ret[0].(*entity.TaxRegion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTaxRegion indicates an expected call of GetTaxRegion.
+func (mr *MockTaxRepoMockRecorder) GetTaxRegion(ctx, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaxRegion", reflect.TypeOf((*MockTaxRepo)(nil).GetTaxRegion), ctx, code)
+}
+
+// GetTaxRegions mocks base method.
+func (m *MockTaxRepo) GetTaxRegions(ctx context.Context) ([]*entity.TaxRegion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTaxRegions", ctx)
+	ret0, _ := ret[0].([]*entity.TaxRegion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTaxRegions indicates an expected call of GetTaxRegions.
+func (mr *MockTaxRepoMockRecorder) GetTaxRegions(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaxRegions", reflect.TypeOf((*MockTaxRepo)(nil).GetTaxRegions), ctx)
+}
+
+// SaveTaxRegion mocks base method.
+func (m *MockTaxRepo) SaveTaxRegion(ctx context.Context, region *entity.TaxRegion) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveTaxRegion", ctx, region)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveTaxRegion indicates an expected call of SaveTaxRegion.
+func (mr *MockTaxRepoMockRecorder) SaveTaxRegion(ctx, region interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveTaxRegion", reflect.TypeOf((*MockTaxRepo)(nil).SaveTaxRegion), ctx, region)
+}
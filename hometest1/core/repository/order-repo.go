@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+
+	"hometest1/core/entity"
+)
+
+type OrderRepo interface {
+	// get order with its items, return nil if order not found
+	GetOrder(ctx context.Context, id int64) (*entity.Order, error)
+	// get orders sorted by newest, without items
+	GetOrders(ctx context.Context, limit, offset int) ([]*entity.Order, int64, error)
+	// save the return with its items, add returned quantity and refund to the order, set the order status,
+	// then restore product quantity of returned items.
+	// return entity.Err 409 if the order is changed since return order is read
+	ReturnOrder(ctx context.Context, ret *entity.OrderReturn, status entity.OrderStatus) error
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"hometest1/core/entity"
+)
+
+type OutboxRepo interface {
+	// get undelivered events that are due at now, sorted by id
+	GetDueEvents(ctx context.Context, now time.Time, limit int) ([]*entity.Outbox, error)
+	// take the due event until the lease time, return false if another dispatcher already took it
+	ClaimEvent(ctx context.Context, id int64, now, leaseUntil time.Time) (bool, error)
+	MarkDelivered(ctx context.Context, id int64, deliveredAt time.Time) error
+	// save attempts, last error and next attempt time of failed delivery
+	MarkFailed(ctx context.Context, event *entity.Outbox) error
+}
@@ -1,9 +1,30 @@
 package repository
 
-import "hometest1/core/entity"
+import (
+	"context"
+
+	"hometest1/core/entity"
+)
 
 type ProductRepo interface {
-	GetProductBySerials(serials []string) ([]*entity.Product, error)
-	GetProductByIDs(ids []int64) ([]*entity.Product, error)
-	SubmitCheckout(payload *entity.Checkout) error
+	GetProductBySerials(ctx context.Context, serials []string) ([]*entity.Product, error)
+	GetProductByIDs(ctx context.Context, ids []int64) ([]*entity.Product, error)
+	// same as GetProductByIDs, including soft deleted products
+	GetProductByIDsWithDeleted(ctx context.Context, ids []int64) ([]*entity.Product, error)
+	// get products sorted by serial
+	GetProducts(ctx context.Context, limit, offset int) ([]*entity.Product, int64, error)
+	// insert product with its initial stock
+	CreateProduct(ctx context.Context, product *entity.Product, quantity int) error
+	// update product name and price
+	UpdateProduct(ctx context.Context, product *entity.Product) error
+	// soft delete product
+	DeleteProduct(ctx context.Context, id int64) error
+	GetProductQuantityByIDs(ctx context.Context, productIDs []int64) ([]*entity.ProductQuantity, error)
+	// same as GetProductQuantityByIDs, minus quantity held by active reservations
+	GetAvailableQuantityByIDs(ctx context.Context, productIDs []int64) ([]*entity.ProductQuantity, error)
+	// submit checkout, stock held by the checkout reservation is used and the reservation is confirmed.
+	// Cart of the checkout is closed in the same transaction, return entity.Err 400 if it is already closed
+	SubmitCheckout(ctx context.Context, payload *entity.Checkout) error
+	// lock product quantity, then insert the reservation if available stock is sufficient
+	ReserveStock(ctx context.Context, reservation *entity.Reservation) error
 }
@@ -1,9 +1,70 @@
 package repository
 
-import "hometest1/core/entity"
+import (
+	"context"
+
+	"hometest1/core/entity"
+)
 
 type PromotionRepo interface {
-	// get promotion by products
+	// get active promotions by products at current time
 	// will return map[int64] where int64 is product id
-	GetPromotionByProducts(products []*entity.Product) (map[int64][]*entity.Promotion, error)
+	GetPromotionByProducts(ctx context.Context, products []*entity.Product) (map[int64][]*entity.Promotion, error)
+	// get promotions by ids including deleted and expired promotions, eg: promotions applied to an order.
+	// sorted like GetPromotionByProducts
+	GetPromotionByIDsWithDeleted(ctx context.Context, ids []int64) ([]*entity.Promotion, error)
+	// get promotions of the product that are active or scheduled
+	GetUpcomingPromotionsByProduct(ctx context.Context, productID int64) ([]*entity.Promotion, error)
+	// get promotion by id, return nil if not found
+	GetPromotion(ctx context.Context, id int64) (*entity.Promotion, error)
+	// get promotions sorted by id
+	GetPromotions(ctx context.Context, limit, offset int) ([]*entity.Promotion, int64, error)
+	// get all promotions that give free item of other product, except expired promotions
+	GetFreeItemPromotions(ctx context.Context) ([]*entity.Promotion, error)
+	CreatePromotion(ctx context.Context, promo *entity.Promotion) error
+	UpdatePromotion(ctx context.Context, promo *entity.Promotion) error
+	// soft delete promotion
+	DeletePromotion(ctx context.Context, id int64) error
+
+	// get active cart promotions at current time, sorted by priority.
+	// cart promotion linked to a coupon is only returned if its id is in couponPromotionIDs
+	GetActiveCartPromotions(ctx context.Context, couponPromotionIDs []int64) ([]*entity.CartPromotion, error)
+	// get cart promotions by ids including deleted and expired promotions, sorted by priority
+	GetCartPromotionByIDsWithDeleted(ctx context.Context, ids []int64) ([]*entity.CartPromotion, error)
+	// get cart promotion by id, return nil if not found
+	GetCartPromotion(ctx context.Context, id int64) (*entity.CartPromotion, error)
+	// get cart promotions sorted by id
+	GetCartPromotions(ctx context.Context, limit, offset int) ([]*entity.CartPromotion, int64, error)
+	CreateCartPromotion(ctx context.Context, promo *entity.CartPromotion) error
+	UpdateCartPromotion(ctx context.Context, promo *entity.CartPromotion) error
+	// soft delete cart promotion
+	DeleteCartPromotion(ctx context.Context, id int64) error
+
+	// get coupons by normalized codes, including expired coupons
+	GetCouponsByCodes(ctx context.Context, codes []string) ([]*entity.Coupon, error)
+	// get coupon by id, return nil if not found
+	GetCoupon(ctx context.Context, id int64) (*entity.Coupon, error)
+	// get coupons sorted by id
+	GetCoupons(ctx context.Context, limit, offset int) ([]*entity.Coupon, int64, error)
+	// return entity.Err 409 if code already exists
+	CreateCoupon(ctx context.Context, coupon *entity.Coupon) error
+	// update coupon, redeemed count is not changed
+	UpdateCoupon(ctx context.Context, coupon *entity.Coupon) error
+	// soft delete coupon
+	DeleteCoupon(ctx context.Context, id int64) error
+
+	// get active bundles that contain any of the products at current time, with items, sorted by priority
+	GetActiveBundlesByProducts(ctx context.Context, productIDs []int64) ([]*entity.Bundle, error)
+	// get bundles with items by ids including deleted and expired bundles, sorted by priority
+	GetBundleByIDsWithDeleted(ctx context.Context, ids []int64) ([]*entity.Bundle, error)
+	// get bundle with items by id, return nil if not found
+	GetBundle(ctx context.Context, id int64) (*entity.Bundle, error)
+	// get bundles with items sorted by id
+	GetBundles(ctx context.Context, limit, offset int) ([]*entity.Bundle, int64, error)
+	// create bundle and its items
+	CreateBundle(ctx context.Context, bundle *entity.Bundle) error
+	// update bundle and replace its items
+	UpdateBundle(ctx context.Context, bundle *entity.Bundle) error
+	// soft delete bundle
+	DeleteBundle(ctx context.Context, id int64) error
 }
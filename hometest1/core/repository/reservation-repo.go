@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"hometest1/core/entity"
+)
+
+type ReservationRepo interface {
+	// get reservation with its items, return nil if not found
+	GetReservation(ctx context.Context, id int64) (*entity.Reservation, error)
+	// release active reservation, return false if reservation is not active anymore
+	ReleaseReservation(ctx context.Context, id int64) (bool, error)
+	// expire active reservations that expire before now, return number of expired reservations
+	ExpireReservations(ctx context.Context, now time.Time) (int64, error)
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+
+	"hometest1/core/entity"
+)
+
+type TaxRepo interface {
+	// get tax region with its rates by code, the default region if code is empty.
+	// return nil if not found
+	GetTaxRegion(ctx context.Context, code string) (*entity.TaxRegion, error)
+	// get tax regions with rates sorted by code
+	GetTaxRegions(ctx context.Context) ([]*entity.TaxRegion, error)
+	// create or update tax region by code and replace its rates.
+	// Other regions are no longer default if the region is default
+	SaveTaxRegion(ctx context.Context, region *entity.TaxRegion) error
+}
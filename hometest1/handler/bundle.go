@@ -0,0 +1,186 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"hometest1/core/entity"
+	"hometest1/core/module"
+
+	"github.com/labstack/echo/v4"
+)
+
+type BundleHandler struct {
+	bundleUC module.BundleUsecase
+}
+
+func NewBundleHandler(bundleUC module.BundleUsecase) *BundleHandler {
+	return &BundleHandler{bundleUC}
+}
+
+type bundleItemPayload struct {
+	ProductSerial string `json:"productSerial" validate:"required"`
+	// quantity in a set, only for fixed price bundle
+	Quantity int `json:"quantity" validate:"min=0"`
+}
+
+type bundlePayload struct {
+	// 1: fixed price, 2: group price
+	Type          int                  `json:"type" validate:"required"`
+	Name          string               `json:"name" validate:"required,max=100"`
+	Price         entity.Money         `json:"price" validate:"gte=0"`
+	MatchQuantity int                  `json:"matchQuantity" validate:"min=0"`
+	PromoValue    int                  `json:"promoValue" validate:"min=0"`
+	Priority      int                  `json:"priority"`
+	Items         []*bundleItemPayload `json:"items" validate:"required,dive"`
+	// optional promotion period, RFC 3339 format
+	StartsAt *time.Time `json:"startsAt"`
+	EndsAt   *time.Time `json:"endsAt"`
+}
+
+type bundleItemResponse struct {
+	ProductSerial string `json:"productSerial"`
+	Quantity      int    `json:"quantity,omitempty"`
+}
+
+type bundleResponse struct {
+	ID            int64                  `json:"id"`
+	Type          int                    `json:"type"`
+	Name          string                 `json:"name"`
+	Price         entity.Money           `json:"price"`
+	MatchQuantity int                    `json:"matchQuantity"`
+	PromoValue    int                    `json:"promoValue"`
+	Priority      int                    `json:"priority"`
+	Items         []*bundleItemResponse  `json:"items"`
+	StartsAt      *time.Time             `json:"startsAt,omitempty"`
+	EndsAt        *time.Time             `json:"endsAt,omitempty"`
+	Status        entity.PromotionStatus `json:"status"`
+}
+
+type bundleListResponse struct {
+	Data  []*bundleResponse `json:"data"`
+	Page  int               `json:"page"`
+	Limit int               `json:"limit"`
+	Total int64             `json:"total"`
+}
+
+func (h *BundleHandler) Create(c echo.Context) error {
+	p, err := h.bindPayload(c)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.bundleUC.Create(c.Request().Context(), p)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, parseToBundleResponse(resp))
+}
+
+func (h *BundleHandler) Update(c echo.Context) error {
+	bundleID, err := parseIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+	p, err := h.bindPayload(c)
+	if err != nil {
+		return err
+	}
+	p.ID = bundleID
+
+	resp, err := h.bundleUC.Update(c.Request().Context(), p)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, parseToBundleResponse(resp))
+}
+
+func (h *BundleHandler) Delete(c echo.Context) error {
+	bundleID, err := parseIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	err = h.bundleUC.Delete(c.Request().Context(), bundleID)
+	if err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *BundleHandler) List(c echo.Context) error {
+	// invalid value will be normalized by usecase
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+
+	resp, err := h.bundleUC.List(c.Request().Context(), page, limit)
+	if err != nil {
+		return err
+	}
+
+	result := bundleListResponse{
+		Data:  []*bundleResponse{},
+		Page:  resp.Page,
+		Limit: resp.Limit,
+		Total: resp.Total,
+	}
+	for _, bundle := range resp.Bundles {
+		result.Data = append(result.Data, parseToBundleResponse(bundle))
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+func (h *BundleHandler) bindPayload(c echo.Context) (*entity.Bundle, error) {
+	p := new(bundlePayload)
+	// bind json payload
+	if err := c.Bind(p); err != nil {
+		return nil, err
+	}
+	// validate payload
+	if err := c.Validate(p); err != nil {
+		return nil, err
+	}
+
+	result := entity.Bundle{
+		Type:          entity.BundleType(p.Type),
+		Name:          p.Name,
+		Price:         p.Price,
+		MatchQuantity: p.MatchQuantity,
+		PromoValue:    p.PromoValue,
+		Priority:      p.Priority,
+		StartsAt:      p.StartsAt,
+		EndsAt:        p.EndsAt,
+	}
+	for _, item := range p.Items {
+		result.Items = append(result.Items, &entity.BundleItem{
+			Quantity: item.Quantity,
+			Product:  &entity.Product{Serial: item.ProductSerial},
+		})
+	}
+	return &result, nil
+}
+
+func parseToBundleResponse(b *entity.Bundle) *bundleResponse {
+	result := bundleResponse{
+		ID:            b.ID,
+		Type:          int(b.Type),
+		Name:          b.Name,
+		Price:         b.Price,
+		MatchQuantity: b.MatchQuantity,
+		PromoValue:    b.PromoValue,
+		Priority:      b.Priority,
+		Items:         []*bundleItemResponse{},
+		StartsAt:      b.StartsAt,
+		EndsAt:        b.EndsAt,
+		Status:        b.Status(time.Now()),
+	}
+	for _, item := range b.Items {
+		respItem := bundleItemResponse{Quantity: item.Quantity}
+		if item.Product != nil {
+			respItem.ProductSerial = item.Product.Serial
+		}
+		result.Items = append(result.Items, &respItem)
+	}
+	return &result
+}
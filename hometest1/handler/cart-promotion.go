@@ -0,0 +1,170 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"hometest1/core/entity"
+	"hometest1/core/module"
+
+	"github.com/labstack/echo/v4"
+)
+
+type CartPromotionHandler struct {
+	cartPromoUC module.CartPromotionUsecase
+}
+
+func NewCartPromotionHandler(cartPromoUC module.CartPromotionUsecase) *CartPromotionHandler {
+	return &CartPromotionHandler{cartPromoUC}
+}
+
+type cartPromotionPayload struct {
+	// 1: percent discount, 2: fixed amount discount, 3: free item
+	Type               int          `json:"type" validate:"required"`
+	MinSpend           entity.Money `json:"minSpend" validate:"gte=0"`
+	PromoValue         int          `json:"promoValue" validate:"min=0"`
+	PromoAmount        entity.Money `json:"promoAmount" validate:"gte=0"`
+	PromoProductSerial string       `json:"promoProductSerial"`
+	Priority           int          `json:"priority"`
+	// 0: stackable, 1: exclusive, 2: best-of
+	Stacking int `json:"stacking"`
+	// optional promotion period, RFC 3339 format
+	StartsAt *time.Time `json:"startsAt"`
+	EndsAt   *time.Time `json:"endsAt"`
+}
+
+type cartPromotionResponse struct {
+	ID                 int64                  `json:"id"`
+	Type               int                    `json:"type"`
+	MinSpend           entity.Money           `json:"minSpend"`
+	PromoValue         int                    `json:"promoValue"`
+	PromoAmount        entity.Money           `json:"promoAmount"`
+	PromoProductSerial string                 `json:"promoProductSerial,omitempty"`
+	Priority           int                    `json:"priority"`
+	Stacking           int                    `json:"stacking"`
+	StartsAt           *time.Time             `json:"startsAt,omitempty"`
+	EndsAt             *time.Time             `json:"endsAt,omitempty"`
+	Status             entity.PromotionStatus `json:"status"`
+}
+
+type cartPromotionListResponse struct {
+	Data  []*cartPromotionResponse `json:"data"`
+	Page  int                      `json:"page"`
+	Limit int                      `json:"limit"`
+	Total int64                    `json:"total"`
+}
+
+func (h *CartPromotionHandler) Create(c echo.Context) error {
+	p, err := h.bindPayload(c)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.cartPromoUC.Create(c.Request().Context(), p)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, parseToCartPromotionResponse(resp))
+}
+
+func (h *CartPromotionHandler) Update(c echo.Context) error {
+	promotionID, err := parseIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+	p, err := h.bindPayload(c)
+	if err != nil {
+		return err
+	}
+	p.ID = promotionID
+
+	resp, err := h.cartPromoUC.Update(c.Request().Context(), p)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, parseToCartPromotionResponse(resp))
+}
+
+func (h *CartPromotionHandler) Delete(c echo.Context) error {
+	promotionID, err := parseIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	err = h.cartPromoUC.Delete(c.Request().Context(), promotionID)
+	if err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *CartPromotionHandler) List(c echo.Context) error {
+	// invalid value will be normalized by usecase
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+
+	resp, err := h.cartPromoUC.List(c.Request().Context(), page, limit)
+	if err != nil {
+		return err
+	}
+
+	result := cartPromotionListResponse{
+		Data:  []*cartPromotionResponse{},
+		Page:  resp.Page,
+		Limit: resp.Limit,
+		Total: resp.Total,
+	}
+	for _, promo := range resp.Promotions {
+		result.Data = append(result.Data, parseToCartPromotionResponse(promo))
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+func (h *CartPromotionHandler) bindPayload(c echo.Context) (*entity.CartPromotionDetail, error) {
+	p := new(cartPromotionPayload)
+	// bind json payload
+	if err := c.Bind(p); err != nil {
+		return nil, err
+	}
+	// validate payload
+	if err := c.Validate(p); err != nil {
+		return nil, err
+	}
+
+	result := entity.CartPromotionDetail{
+		CartPromotion: &entity.CartPromotion{
+			Type:        entity.CartPromotionType(p.Type),
+			MinSpend:    p.MinSpend,
+			PromoValue:  p.PromoValue,
+			PromoAmount: p.PromoAmount,
+			Priority:    p.Priority,
+			Stacking:    entity.PromotionStacking(p.Stacking),
+			StartsAt:    p.StartsAt,
+			EndsAt:      p.EndsAt,
+		},
+	}
+	if p.PromoProductSerial != "" {
+		result.PromoProduct = &entity.Product{Serial: p.PromoProductSerial}
+	}
+	return &result, nil
+}
+
+func parseToCartPromotionResponse(p *entity.CartPromotionDetail) *cartPromotionResponse {
+	result := cartPromotionResponse{
+		ID:          p.ID,
+		Type:        int(p.Type),
+		MinSpend:    p.MinSpend,
+		PromoValue:  p.PromoValue,
+		PromoAmount: p.PromoAmount,
+		Priority:    p.Priority,
+		Stacking:    int(p.Stacking),
+		StartsAt:    p.StartsAt,
+		EndsAt:      p.EndsAt,
+		Status:      p.Status(time.Now()),
+	}
+	if p.PromoProduct != nil {
+		result.PromoProductSerial = p.PromoProduct.Serial
+	}
+	return &result
+}
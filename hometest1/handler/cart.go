@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"hometest1/core/entity"
+	"hometest1/core/module"
+
+	"github.com/labstack/echo/v4"
+)
+
+type CartHandler struct {
+	cartUC module.CartUsecase
+}
+
+func NewCartHandler(cartUC module.CartUsecase) *CartHandler {
+	return &CartHandler{cartUC}
+}
+
+type cartItemPayload struct {
+	Quantity int `json:"quantity" validate:"required,min=1"`
+}
+
+type cartResponse struct {
+	ID     int64  `json:"id"`
+	Status string `json:"status"`
+	*quoteResponse
+}
+
+func (h *CartHandler) Create(c echo.Context) error {
+	resp, err := h.cartUC.Create(c.Request().Context())
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, parseToCartResponse(resp))
+}
+
+func (h *CartHandler) Get(c echo.Context) error {
+	cartID, err := parseIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.cartUC.Get(c.Request().Context(), cartID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, parseToCartResponse(resp))
+}
+
+func (h *CartHandler) SetItem(c echo.Context) error {
+	cartID, err := parseIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	p := new(cartItemPayload)
+	// bind json payload
+	if err := c.Bind(p); err != nil {
+		return err
+	}
+	// validate payload
+	if err := c.Validate(p); err != nil {
+		return err
+	}
+
+	resp, err := h.cartUC.SetItem(c.Request().Context(), cartID, c.Param("serial"), p.Quantity)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, parseToCartResponse(resp))
+}
+
+func (h *CartHandler) RemoveItem(c echo.Context) error {
+	cartID, err := parseIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.cartUC.RemoveItem(c.Request().Context(), cartID, c.Param("serial"))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, parseToCartResponse(resp))
+}
+
+func (h *CartHandler) Checkout(c echo.Context) error {
+	cartID, err := parseIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.cartUC.Checkout(c.Request().Context(), cartID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, parseToResponse(resp))
+}
+
+func parseIDParam(c echo.Context, name string) (int64, error) {
+	id, err := strconv.ParseInt(c.Param(name), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, echo.NewHTTPError(http.StatusBadRequest, "invalid "+name)
+	}
+	return id, nil
+}
+
+func parseToCartResponse(p *entity.CartDetail) *cartResponse {
+	result := cartResponse{
+		ID:     p.ID,
+		Status: "open",
+		quoteResponse: &quoteResponse{
+			Items:      []*quoteResponseItem{},
+			TotalPrice: entity.NewMoney(0),
+			Currency:   entity.DefaultCurrency,
+		},
+	}
+	if p.Status == entity.CartCheckedOut {
+		result.Status = "checked_out"
+	}
+	if p.Quote != nil {
+		result.quoteResponse = parseToQuoteResponse(p.Quote)
+	}
+	return &result
+}
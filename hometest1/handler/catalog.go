@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"hometest1/core/entity"
+	"hometest1/core/module"
+
+	"github.com/labstack/echo/v4"
+)
+
+type CatalogHandler struct {
+	catalogUC module.CatalogUsecase
+}
+
+func NewCatalogHandler(catalogUC module.CatalogUsecase) *CatalogHandler {
+	return &CatalogHandler{catalogUC}
+}
+
+type catalogImportCountResponse struct {
+	Created   int `json:"created"`
+	Updated   int `json:"updated"`
+	Unchanged int `json:"unchanged"`
+}
+
+type catalogRowErrorResponse struct {
+	Line    int    `json:"line"`
+	Serial  string `json:"serial"`
+	Message string `json:"message"`
+}
+
+type catalogImportResponse struct {
+	DryRun     bool                       `json:"dryRun"`
+	Applied    bool                       `json:"applied"`
+	Rows       int                        `json:"rows"`
+	Products   catalogImportCountResponse `json:"products"`
+	Promotions catalogImportCountResponse `json:"promotions"`
+	Errors     []*catalogRowErrorResponse `json:"errors"`
+}
+
+// import csv from request body, or from multipart form field "file"
+func (h *CatalogHandler) Import(c echo.Context) error {
+	// invalid value is not a dry run
+	dryRun, _ := strconv.ParseBool(c.QueryParam("dryRun"))
+
+	var body io.Reader = c.Request().Body
+	if strings.HasPrefix(c.Request().Header.Get(echo.HeaderContentType), echo.MIMEMultipartForm) {
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "file is required")
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		body = file
+	}
+
+	resp, err := h.catalogUC.Import(c.Request().Context(), body, dryRun)
+	if err != nil {
+		return err
+	}
+
+	// nothing is written because of invalid rows
+	code := http.StatusOK
+	if !resp.Applied && len(resp.Errors) > 0 {
+		code = http.StatusUnprocessableEntity
+	}
+	return c.JSON(code, parseToCatalogImportResponse(resp))
+}
+
+func (h *CatalogHandler) Export(c echo.Context) error {
+	var buf bytes.Buffer
+	err := h.catalogUC.Export(c.Request().Context(), &buf)
+	if err != nil {
+		return err
+	}
+	c.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="catalog.csv"`)
+	return c.Blob(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
+}
+
+func parseToCatalogImportResponse(result *entity.CatalogImport) *catalogImportResponse {
+	resp := &catalogImportResponse{
+		DryRun:     result.DryRun,
+		Applied:    result.Applied,
+		Rows:       result.Rows,
+		Products:   catalogImportCountResponse(result.Products),
+		Promotions: catalogImportCountResponse(result.Promotions),
+		Errors:     []*catalogRowErrorResponse{},
+	}
+	for _, rowError := range result.Errors {
+		resp.Errors = append(resp.Errors, &catalogRowErrorResponse{
+			Line:    rowError.Line,
+			Serial:  rowError.Serial,
+			Message: rowError.Message,
+		})
+	}
+	return resp
+}
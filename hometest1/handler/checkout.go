@@ -19,63 +19,257 @@ func NewCheckoutHandler(checkoutUC module.CheckoutUsecase) *CheckoutHandler {
 
 type payload struct {
 	ProductSerials []string `json:"productSerials" validate:"required"`
+	// proceed with known products and report unknown serials in response
+	Lenient     bool     `json:"lenient"`
+	CouponCodes []string `json:"couponCodes"`
+	// customer reference, required by coupon with per customer limit
+	CustomerID string `json:"customerId" validate:"max=100"`
+	// code of tax region, default region is used if it is empty
+	TaxRegion string `json:"taxRegion" validate:"max=10"`
 }
 
 type responseItem struct {
-	Serial   string  `json:"serial"`
-	Name     string  `json:"name"`
-	Quantity int     `json:"quantity"`
-	Price    float64 `json:"price"`
-	SubTotal float64 `json:"subTotal"`
+	Serial   string       `json:"serial"`
+	Name     string       `json:"name"`
+	Quantity int          `json:"quantity"`
+	Price    entity.Money `json:"price"`
+	SubTotal entity.Money `json:"subTotal"`
+	// only if bundle discount is allocated to the item
+	BundleID       int64         `json:"bundleId,omitempty"`
+	BundleDiscount *entity.Money `json:"bundleDiscount,omitempty"`
+	// promotions and bundles applied to the item
+	Promotions []*appliedPromotionResponse `json:"promotions,omitempty"`
+}
+
+type appliedPromotionResponse struct {
+	Source      entity.PromotionSource `json:"source"`
+	ID          int64                  `json:"id"`
+	Type        int                    `json:"type"`
+	Description string                 `json:"description"`
+	// only for applied promotion
+	Saved *entity.Money `json:"saved,omitempty"`
+	// only for promotion that is not met
+	Reason string `json:"reason,omitempty"`
+}
+
+type discountResponse struct {
+	CartPromotionID int64        `json:"cartPromotionId"`
+	Type            int          `json:"type"`
+	Description     string       `json:"description"`
+	Amount          entity.Money `json:"amount"`
+	CouponCode      string       `json:"couponCode,omitempty"`
+}
+
+type taxResponse struct {
+	TaxClass string `json:"taxClass"`
+	// percent, eg: 19.5 is 19.5%
+	Rate    float64      `json:"rate"`
+	Taxable entity.Money `json:"taxable"`
+	Tax     entity.Money `json:"tax"`
 }
 
 type response struct {
+	OrderID    int64           `json:"orderId,omitempty"`
 	Items      []*responseItem `json:"items"`
 	TotalItems int             `json:"totalItems"`
-	TotalPrice float64         `json:"totalPrice"`
+	TotalPrice entity.Money    `json:"totalPrice"`
+	Currency   entity.Currency `json:"currency"`
+	// only if any cart promotion is applied
+	Discounts     []*discountResponse `json:"discounts,omitempty"`
+	DiscountPrice *entity.Money       `json:"discountPrice,omitempty"`
+	// promotions that are evaluated but not applied
+	NotMetPromotions []*appliedPromotionResponse `json:"notMetPromotions,omitempty"`
+	// only if checkout has tax region, price mode is exclusive or inclusive
+	TaxRegion string         `json:"taxRegion,omitempty"`
+	PriceMode string         `json:"priceMode,omitempty"`
+	Taxes     []*taxResponse `json:"taxes,omitempty"`
+	TaxTotal  *entity.Money  `json:"taxTotal,omitempty"`
+	// only on lenient mode
+	UnknownSerials []string `json:"unknownSerials,omitempty"`
+}
+
+type quoteResponseItem struct {
+	*responseItem
+	Available int  `json:"available"`
+	InStock   bool `json:"inStock"`
+}
+
+type quoteResponse struct {
+	Items      []*quoteResponseItem `json:"items"`
+	TotalItems int                  `json:"totalItems"`
+	TotalPrice entity.Money         `json:"totalPrice"`
+	Currency   entity.Currency      `json:"currency"`
+	// only if any cart promotion is applied
+	Discounts     []*discountResponse `json:"discounts,omitempty"`
+	DiscountPrice *entity.Money       `json:"discountPrice,omitempty"`
+	// promotions that are evaluated but not applied
+	NotMetPromotions []*appliedPromotionResponse `json:"notMetPromotions,omitempty"`
+	// only if checkout has tax region, price mode is exclusive or inclusive
+	TaxRegion string         `json:"taxRegion,omitempty"`
+	PriceMode string         `json:"priceMode,omitempty"`
+	Taxes     []*taxResponse `json:"taxes,omitempty"`
+	TaxTotal  *entity.Money  `json:"taxTotal,omitempty"`
+	// only on lenient mode
+	UnknownSerials []string `json:"unknownSerials,omitempty"`
 }
 
 func (h *CheckoutHandler) Submit(c echo.Context) error {
+	mapPayload, options, err := h.bindPayload(c)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.checkoutUC.Submit(c.Request().Context(), mapPayload, options)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, parseToResponse(resp))
+}
+
+// Quote calculate checkout price without reserving stock
+func (h *CheckoutHandler) Quote(c echo.Context) error {
+	mapPayload, options, err := h.bindPayload(c)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.checkoutUC.Quote(c.Request().Context(), mapPayload, options)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, parseToQuoteResponse(resp))
+}
+
+// bind payload into map of serial quantity, and return the checkout options
+func (h *CheckoutHandler) bindPayload(c echo.Context) (entity.MapProductSerialQuantity, entity.CheckoutOptions, error) {
+	var options entity.CheckoutOptions
 	p := new(payload)
 	// bind json payload
 	if err := c.Bind(p); err != nil {
-		return err
+		return nil, options, err
 	}
 	// validate payload
 	if err := c.Validate(p); err != nil {
-		return err
+		return nil, options, err
 	}
 
-	// map payload
-	mapPayload := make(map[string]int)
+	mapPayload, options := p.toCheckout()
+	return mapPayload, options, nil
+}
+
+// map payload into serial quantity and checkout options
+func (p *payload) toCheckout() (entity.MapProductSerialQuantity, entity.CheckoutOptions) {
+	mapPayload := make(entity.MapProductSerialQuantity)
 	for _, serial := range p.ProductSerials {
 		mapPayload[serial]++
 	}
-
-	resp, err := h.checkoutUC.Submit(mapPayload)
-	if err != nil {
-		return err
+	options := entity.CheckoutOptions{
+		Lenient:     p.Lenient,
+		CouponCodes: p.CouponCodes,
+		CustomerID:  p.CustomerID,
+		TaxRegion:   p.TaxRegion,
 	}
-
-	return h.parseToResponse(resp, c)
+	return mapPayload, options
 }
 
-func (h *CheckoutHandler) parseToResponse(p *entity.Checkout, c echo.Context) error {
+func parseToResponse(p *entity.Checkout) *response {
 	result := response{
-		TotalItems: p.TotalItem,
-		TotalPrice: p.TotalPrice,
+		OrderID:        p.OrderID,
+		TotalItems:     p.TotalItem,
+		TotalPrice:     p.TotalPrice,
+		Currency:       p.TotalPrice.Currency,
+		UnknownSerials: p.UnknownSerials,
+	}
+
+	for _, discount := range p.Discounts {
+		result.Discounts = append(result.Discounts, &discountResponse{
+			CartPromotionID: discount.CartPromotionID,
+			Type:            int(discount.Type),
+			Description:     discount.Description,
+			Amount:          discount.Amount,
+			CouponCode:      discount.CouponCode,
+		})
+	}
+	if len(p.Discounts) > 0 {
+		result.DiscountPrice = &p.DiscountPrice
+	}
+	for _, notMet := range p.NotMetPromotions {
+		result.NotMetPromotions = append(result.NotMetPromotions, parseToAppliedPromotionResponse(notMet))
+	}
+	if p.TaxRegion != "" {
+		result.TaxRegion = p.TaxRegion
+		result.PriceMode = p.TaxPriceMode.String()
+		result.TaxTotal = &p.TaxPrice
+		for _, tax := range p.Taxes {
+			result.Taxes = append(result.Taxes, &taxResponse{
+				TaxClass: tax.TaxClass,
+				Rate:     taxRateToPercent(tax.Rate),
+				Taxable:  tax.TaxableAmount,
+				Tax:      tax.Amount,
+			})
+		}
 	}
 
 	for _, item := range p.Items {
-		result.Items = append(result.Items, &responseItem{
+		respItem := responseItem{
 			Serial:   item.Product.Serial,
 			Name:     item.Product.Name,
 			Quantity: item.Quantity,
 			Price:    item.Product.Price,
 			SubTotal: item.SubTotalPrice,
+			BundleID: item.BundleID,
+		}
+		if item.BundleID != 0 {
+			respItem.BundleDiscount = &item.BundleDiscount
+		}
+		for _, applied := range item.AppliedPromotions {
+			respItem.Promotions = append(respItem.Promotions, parseToAppliedPromotionResponse(applied))
+		}
+		result.Items = append(result.Items, &respItem)
+	}
+
+	return &result
+}
+
+func parseToQuoteResponse(p *entity.CheckoutQuote) *quoteResponse {
+	checkout := parseToResponse(p.Checkout)
+	result := quoteResponse{
+		TotalItems:       checkout.TotalItems,
+		TotalPrice:       checkout.TotalPrice,
+		Currency:         checkout.Currency,
+		Discounts:        checkout.Discounts,
+		DiscountPrice:    checkout.DiscountPrice,
+		NotMetPromotions: checkout.NotMetPromotions,
+		TaxRegion:        checkout.TaxRegion,
+		PriceMode:        checkout.PriceMode,
+		Taxes:            checkout.Taxes,
+		TaxTotal:         checkout.TaxTotal,
+		UnknownSerials:   checkout.UnknownSerials,
+	}
+	for i, item := range p.Items {
+		available := p.AvailableQuantity[item.Product.ID]
+		result.Items = append(result.Items, &quoteResponseItem{
+			responseItem: checkout.Items[i],
+			Available:    available,
+			InStock:      available >= item.Quantity,
 		})
 	}
 
-	return c.JSON(http.StatusOK, result)
+	return &result
+}
 
+func parseToAppliedPromotionResponse(p *entity.AppliedPromotion) *appliedPromotionResponse {
+	result := appliedPromotionResponse{
+		Source:      p.Source,
+		ID:          p.ID,
+		Type:        p.Type,
+		Description: p.Description,
+		Reason:      p.Reason,
+	}
+	if p.Reason == "" {
+		result.Saved = &p.Saved
+	}
+	return &result
 }
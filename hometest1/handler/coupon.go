@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"hometest1/core/entity"
+	"hometest1/core/module"
+
+	"github.com/labstack/echo/v4"
+)
+
+type CouponHandler struct {
+	couponUC module.CouponUsecase
+}
+
+func NewCouponHandler(couponUC module.CouponUsecase) *CouponHandler {
+	return &CouponHandler{couponUC}
+}
+
+type couponPayload struct {
+	Code            string `json:"code" validate:"required,max=50"`
+	CartPromotionID int64  `json:"cartPromotionId" validate:"required"`
+	// 0 means unlimited
+	MaxRedemptions   int `json:"maxRedemptions" validate:"min=0"`
+	PerCustomerLimit int `json:"perCustomerLimit" validate:"min=0"`
+	// optional expiry time, RFC 3339 format
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
+
+type couponResponse struct {
+	ID               int64      `json:"id"`
+	Code             string     `json:"code"`
+	CartPromotionID  int64      `json:"cartPromotionId"`
+	MaxRedemptions   int        `json:"maxRedemptions"`
+	PerCustomerLimit int        `json:"perCustomerLimit"`
+	RedeemedCount    int        `json:"redeemedCount"`
+	ExpiresAt        *time.Time `json:"expiresAt,omitempty"`
+	Expired          bool       `json:"expired"`
+}
+
+type couponListResponse struct {
+	Data  []*couponResponse `json:"data"`
+	Page  int               `json:"page"`
+	Limit int               `json:"limit"`
+	Total int64             `json:"total"`
+}
+
+func (h *CouponHandler) Create(c echo.Context) error {
+	p, err := h.bindPayload(c)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.couponUC.Create(c.Request().Context(), p)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, parseToCouponResponse(resp))
+}
+
+func (h *CouponHandler) Update(c echo.Context) error {
+	couponID, err := parseIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+	p, err := h.bindPayload(c)
+	if err != nil {
+		return err
+	}
+	p.ID = couponID
+
+	resp, err := h.couponUC.Update(c.Request().Context(), p)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, parseToCouponResponse(resp))
+}
+
+func (h *CouponHandler) Delete(c echo.Context) error {
+	couponID, err := parseIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	err = h.couponUC.Delete(c.Request().Context(), couponID)
+	if err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *CouponHandler) List(c echo.Context) error {
+	// invalid value will be normalized by usecase
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+
+	resp, err := h.couponUC.List(c.Request().Context(), page, limit)
+	if err != nil {
+		return err
+	}
+
+	result := couponListResponse{
+		Data:  []*couponResponse{},
+		Page:  resp.Page,
+		Limit: resp.Limit,
+		Total: resp.Total,
+	}
+	for _, coupon := range resp.Coupons {
+		result.Data = append(result.Data, parseToCouponResponse(coupon))
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+func (h *CouponHandler) bindPayload(c echo.Context) (*entity.Coupon, error) {
+	p := new(couponPayload)
+	// bind json payload
+	if err := c.Bind(p); err != nil {
+		return nil, err
+	}
+	// validate payload
+	if err := c.Validate(p); err != nil {
+		return nil, err
+	}
+
+	return &entity.Coupon{
+		Code:             p.Code,
+		CartPromotionID:  p.CartPromotionID,
+		MaxRedemptions:   p.MaxRedemptions,
+		PerCustomerLimit: p.PerCustomerLimit,
+		ExpiresAt:        p.ExpiresAt,
+	}, nil
+}
+
+func parseToCouponResponse(c *entity.Coupon) *couponResponse {
+	return &couponResponse{
+		ID:               c.ID,
+		Code:             c.Code,
+		CartPromotionID:  c.CartPromotionID,
+		MaxRedemptions:   c.MaxRedemptions,
+		PerCustomerLimit: c.PerCustomerLimit,
+		RedeemedCount:    c.RedeemedCount,
+		ExpiresAt:        c.ExpiresAt,
+		Expired:          c.IsExpired(time.Now()),
+	}
+}
@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"hometest1/core/entity"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+// TranslateContextError report server error of timed out or cancelled request as retryable 503,
+// because database driver returns its own error. It must be called before the request context is released
+func TranslateContextError(ctx context.Context, err error) error {
+	if ctxErr := ctx.Err(); err != nil && ctxErr != nil && isServerError(err) {
+		return entity.NewInternalError(ctxErr)
+	}
+	return err
+}
+
+// error that is not reported with client error code
+func isServerError(err error) bool {
+	switch castedErr := err.(type) {
+	case entity.Err:
+		return castedErr.GetCode() >= http.StatusInternalServerError
+	case entity.UnknownSerialsErr, validator.ValidationErrors:
+		return false
+	case *echo.HTTPError:
+		return castedErr.Code >= http.StatusInternalServerError
+	}
+	return true
+}
@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+
+	"hometest1/core/module"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	idempotencyKeyHeader     = "Idempotency-Key"
+	idempotentReplayedHeader = "Idempotent-Replayed"
+)
+
+type IdempotencyHandler struct {
+	idempotencyUC module.IdempotencyUsecase
+}
+
+func NewIdempotencyHandler(idempotencyUC module.IdempotencyUsecase) *IdempotencyHandler {
+	return &IdempotencyHandler{idempotencyUC}
+}
+
+// responseRecorder copies response body written to the client
+type responseRecorder struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+// Middleware replays the stored response of request with the same Idempotency-Key header.
+// Request without the header is not affected
+func (h *IdempotencyHandler) Middleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		key := c.Request().Header.Get(idempotencyKeyHeader)
+		if key == "" {
+			return next(c)
+		}
+
+		// hash request, then restore the body for the handler
+		body, err := io.ReadAll(c.Request().Body)
+		if err != nil {
+			return err
+		}
+		c.Request().Body = io.NopCloser(bytes.NewReader(body))
+		hash := sha256.New()
+		hash.Write([]byte(c.Request().Method + " " + c.Request().URL.Path + "\n"))
+		hash.Write(body)
+
+		stored, err := h.idempotencyUC.Begin(c.Request().Context(), key, hex.EncodeToString(hash.Sum(nil)))
+		if err != nil {
+			return err
+		}
+		if stored.IsCompleted() {
+			c.Response().Header().Set(idempotentReplayedHeader, "true")
+			return c.Blob(stored.ResponseCode, echo.MIMEApplicationJSONCharsetUTF8, []byte(stored.ResponseBody))
+		}
+
+		// error is rendered here, so error response is stored too
+		recorder := &responseRecorder{ResponseWriter: c.Response().Writer}
+		c.Response().Writer = recorder
+		// request context is not released yet, so timed out request is rendered as 503
+		if err := next(c); err != nil {
+			c.Error(TranslateContextError(c.Request().Context(), err))
+		}
+
+		// response is already sent, failing to store it only logs the error.
+		// Request context may be already timed out, the key must still be completed or released
+		err = h.idempotencyUC.Complete(context.WithoutCancel(c.Request().Context()), stored, c.Response().Status, recorder.body.Bytes())
+		if err != nil {
+			c.Logger().Error(err)
+		}
+		return nil
+	}
+}
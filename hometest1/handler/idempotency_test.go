@@ -0,0 +1,80 @@
+package handler_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"hometest1/core/entity"
+	"hometest1/core/module"
+	repomocks "hometest1/core/repository/mocks"
+	"hometest1/handler"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+// render error code of entity error like the server error handler
+func testErrorHandler(err error, c echo.Context) {
+	code := http.StatusInternalServerError
+	if entityError, ok := err.(entity.Err); ok {
+		code = entityError.GetCode()
+	}
+	c.JSON(code, map[string]string{"message": err.Error()})
+}
+
+func Test_IdempotencyMiddleware(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	idempotencyRepo := repomocks.NewMockIdempotencyRepo(ctrl)
+	h := handler.NewIdempotencyHandler(module.NewIdempotencyUsecase(idempotencyRepo))
+	e := echo.New()
+	e.HTTPErrorHandler = testErrorHandler
+
+	t.Run("positive, timed out request with idempotency key is service unavailable and releases the key", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 0)
+		defer cancel()
+		<-ctx.Done()
+
+		idempotencyRepo.EXPECT().CreateKey(gomock.Any(), gomock.Any()).Return(true, nil).Times(1)
+		// server error is not stored, client can retry with the same key
+		idempotencyRepo.EXPECT().DeleteKey(gomock.Any(), gomock.Any()).Return(true, nil).Times(1)
+
+		req := httptest.NewRequest(http.MethodPost, "/checkout", strings.NewReader(`{"cartId":1}`)).WithContext(ctx)
+		req.Header.Set("Idempotency-Key", "pos-1")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := h.Middleware(func(c echo.Context) error {
+			return errors.New("driver: bad connection")
+		})(c)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+		assert.Contains(t, rec.Body.String(), entity.RequestTimeout)
+	})
+
+	t.Run("negative, client error of timed out request is not translated", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 0)
+		defer cancel()
+		<-ctx.Done()
+
+		idempotencyRepo.EXPECT().CreateKey(gomock.Any(), gomock.Any()).Return(true, nil).Times(1)
+		idempotencyRepo.EXPECT().SaveResponse(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+		req := httptest.NewRequest(http.MethodPost, "/checkout", strings.NewReader(`{"cartId":1}`)).WithContext(ctx)
+		req.Header.Set("Idempotency-Key", "neg-1")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := h.Middleware(func(c echo.Context) error {
+			return entity.NewError(entity.CartNotFound, http.StatusNotFound)
+		})(c)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
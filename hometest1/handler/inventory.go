@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"hometest1/core/entity"
+	"hometest1/core/module"
+
+	"github.com/labstack/echo/v4"
+)
+
+type InventoryHandler struct {
+	inventoryUC  module.InventoryUsecase
+	stockAlertUC module.StockAlertUsecase
+}
+
+func NewInventoryHandler(inventoryUC module.InventoryUsecase, stockAlertUC module.StockAlertUsecase) *InventoryHandler {
+	return &InventoryHandler{inventoryUC, stockAlertUC}
+}
+
+type restockPayload struct {
+	Quantity  int    `json:"quantity" validate:"required,min=1"`
+	Reference string `json:"reference" validate:"max=100"`
+}
+
+type thresholdPayload struct {
+	Threshold int `json:"threshold" validate:"min=0"`
+}
+
+type thresholdResponse struct {
+	Serial    string `json:"serial"`
+	Threshold int    `json:"threshold"`
+}
+
+type stockMovementResponse struct {
+	Quantity  int       `json:"quantity"`
+	Reason    string    `json:"reason"`
+	Reference string    `json:"reference"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type stockMovementListResponse struct {
+	Product *productResponse         `json:"product"`
+	Data    []*stockMovementResponse `json:"data"`
+	Page    int                      `json:"page"`
+	Limit   int                      `json:"limit"`
+	Total   int64                    `json:"total"`
+}
+
+func (h *InventoryHandler) Restock(c echo.Context) error {
+	p := new(restockPayload)
+	// bind json payload
+	if err := c.Bind(p); err != nil {
+		return err
+	}
+	// validate payload
+	if err := c.Validate(p); err != nil {
+		return err
+	}
+
+	resp, err := h.inventoryUC.Restock(c.Request().Context(), c.Param("serial"), p.Quantity, p.Reference)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, parseToProductResponse(resp.Product, &resp.Quantity))
+}
+
+func (h *InventoryHandler) History(c echo.Context) error {
+	// invalid value will be normalized by usecase
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+
+	resp, err := h.inventoryUC.History(c.Request().Context(), c.Param("serial"), page, limit)
+	if err != nil {
+		return err
+	}
+
+	result := stockMovementListResponse{
+		Product: parseToProductResponse(resp.Product, nil),
+		Data:    []*stockMovementResponse{},
+		Page:    resp.Page,
+		Limit:   resp.Limit,
+		Total:   resp.Total,
+	}
+	for _, movement := range resp.Movements {
+		result.Data = append(result.Data, parseToStockMovementResponse(movement))
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+func (h *InventoryHandler) SetThreshold(c echo.Context) error {
+	p := new(thresholdPayload)
+	// bind json payload
+	if err := c.Bind(p); err != nil {
+		return err
+	}
+	// validate payload
+	if err := c.Validate(p); err != nil {
+		return err
+	}
+
+	resp, err := h.stockAlertUC.SetThreshold(c.Request().Context(), c.Param("serial"), p.Threshold)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, thresholdResponse{Serial: c.Param("serial"), Threshold: resp.Threshold})
+}
+
+func parseToStockMovementResponse(m *entity.StockMovement) *stockMovementResponse {
+	return &stockMovementResponse{
+		Quantity:  m.Quantity,
+		Reason:    m.Reason.String(),
+		Reference: m.Reference,
+		CreatedAt: m.CreatedAt,
+	}
+}
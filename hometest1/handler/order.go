@@ -0,0 +1,222 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"hometest1/core/entity"
+	"hometest1/core/module"
+
+	"github.com/labstack/echo/v4"
+)
+
+type OrderHandler struct {
+	orderUC module.OrderUsecase
+}
+
+func NewOrderHandler(orderUC module.OrderUsecase) *OrderHandler {
+	return &OrderHandler{orderUC}
+}
+
+type orderResponseItem struct {
+	Serial      string       `json:"serial"`
+	Name        string       `json:"name"`
+	Quantity    int          `json:"quantity"`
+	Price       entity.Money `json:"price"`
+	SubTotal    entity.Money `json:"subTotal"`
+	PromotionID int64        `json:"promotionId,omitempty"`
+	BundleID    int64        `json:"bundleId,omitempty"`
+	// quantity given back by returns and cancellation
+	ReturnedQuantity int `json:"returnedQuantity"`
+}
+
+type orderResponse struct {
+	ID         int64                `json:"id"`
+	Status     string               `json:"status"`
+	Items      []*orderResponseItem `json:"items,omitempty"`
+	TotalItems int                  `json:"totalItems"`
+	TotalPrice entity.Money         `json:"totalPrice"`
+	// total discount of cart promotions
+	DiscountPrice entity.Money `json:"discountPrice"`
+	// total refund of returns and cancellation
+	RefundedPrice entity.Money    `json:"refundedPrice"`
+	Currency      entity.Currency `json:"currency"`
+	// only if order has tax region, VAT is shown separately on inclusive mode
+	TaxRegion string         `json:"taxRegion,omitempty"`
+	PriceMode string         `json:"priceMode,omitempty"`
+	Taxes     []*taxResponse `json:"taxes,omitempty"`
+	TaxPrice  *entity.Money  `json:"taxPrice,omitempty"`
+	CreatedAt time.Time      `json:"createdAt"`
+}
+
+type returnPayload struct {
+	// returned serial, repeated for each returned item
+	ProductSerials []string `json:"productSerials" validate:"required"`
+}
+
+type returnResponseItem struct {
+	Serial   string `json:"serial"`
+	Name     string `json:"name"`
+	Quantity int    `json:"quantity"`
+}
+
+type returnResponse struct {
+	ID       int64                 `json:"id"`
+	Items    []*returnResponseItem `json:"items"`
+	Refund   entity.Money          `json:"refund"`
+	Currency entity.Currency       `json:"currency"`
+	// order after the return
+	Order *orderResponse `json:"order"`
+}
+
+type orderListResponse struct {
+	Data  []*orderResponse `json:"data"`
+	Page  int              `json:"page"`
+	Limit int              `json:"limit"`
+	Total int64            `json:"total"`
+}
+
+func (h *OrderHandler) Get(c echo.Context) error {
+	orderID, err := parseIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.orderUC.Get(c.Request().Context(), orderID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, parseToOrderResponse(resp))
+}
+
+func (h *OrderHandler) List(c echo.Context) error {
+	// invalid value will be normalized by usecase
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+
+	resp, err := h.orderUC.List(c.Request().Context(), page, limit)
+	if err != nil {
+		return err
+	}
+
+	result := orderListResponse{
+		Data:  []*orderResponse{},
+		Page:  resp.Page,
+		Limit: resp.Limit,
+		Total: resp.Total,
+	}
+	for _, order := range resp.Orders {
+		result.Data = append(result.Data, parseToOrderResponse(order))
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+// Return items of the order, refund is reduced by free items that are no longer given
+func (h *OrderHandler) Return(c echo.Context) error {
+	orderID, err := parseIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+	p := new(returnPayload)
+	// bind json payload
+	if err := c.Bind(p); err != nil {
+		return err
+	}
+	// validate payload
+	if err := c.Validate(p); err != nil {
+		return err
+	}
+
+	mapPayload := make(entity.MapProductSerialQuantity)
+	for _, serial := range p.ProductSerials {
+		mapPayload[serial]++
+	}
+	resp, err := h.orderUC.Return(c.Request().Context(), orderID, mapPayload)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, parseToReturnResponse(resp))
+}
+
+// Cancel the order, all kept items are returned
+func (h *OrderHandler) Cancel(c echo.Context) error {
+	orderID, err := parseIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.orderUC.Cancel(c.Request().Context(), orderID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, parseToReturnResponse(resp))
+}
+
+func parseToReturnResponse(p *entity.OrderReturn) *returnResponse {
+	result := returnResponse{
+		ID:       p.ID,
+		Items:    []*returnResponseItem{},
+		Refund:   p.RefundPrice,
+		Currency: p.RefundPrice.Currency,
+		Order:    parseToOrderResponse(p.Order),
+	}
+
+	mapProduct := make(map[int64]*entity.Product)
+	for _, item := range p.Order.Items {
+		mapProduct[item.ProductID] = item.Product
+	}
+	for _, item := range p.Items {
+		respItem := returnResponseItem{Quantity: item.Quantity}
+		if product := mapProduct[item.ProductID]; product != nil {
+			respItem.Serial = product.Serial
+			respItem.Name = product.Name
+		}
+		result.Items = append(result.Items, &respItem)
+	}
+	return &result
+}
+
+func parseToOrderResponse(p *entity.Order) *orderResponse {
+	result := orderResponse{
+		ID:            p.ID,
+		Status:        p.Status.String(),
+		TotalItems:    p.TotalItem,
+		TotalPrice:    p.TotalPrice,
+		DiscountPrice: p.DiscountPrice,
+		RefundedPrice: p.RefundedPrice,
+		Currency:      p.TotalPrice.Currency,
+		CreatedAt:     p.CreatedAt,
+	}
+	if p.TaxRegion != "" {
+		result.TaxRegion = p.TaxRegion
+		result.PriceMode = p.TaxPriceMode.String()
+		result.TaxPrice = &p.TaxPrice
+		for _, tax := range p.Taxes {
+			result.Taxes = append(result.Taxes, &taxResponse{
+				TaxClass: tax.TaxClass,
+				Rate:     taxRateToPercent(tax.Rate),
+				Taxable:  tax.TaxablePrice,
+				Tax:      tax.TaxPrice,
+			})
+		}
+	}
+
+	for _, item := range p.Items {
+		respItem := orderResponseItem{
+			Quantity:         item.Quantity,
+			Price:            item.UnitPrice,
+			SubTotal:         item.SubTotalPrice,
+			PromotionID:      item.PromotionID,
+			BundleID:         item.BundleID,
+			ReturnedQuantity: item.ReturnedQuantity,
+		}
+		if item.Product != nil {
+			respItem.Serial = item.Product.Serial
+			respItem.Name = item.Product.Name
+		}
+		result.Items = append(result.Items, &respItem)
+	}
+
+	return &result
+}
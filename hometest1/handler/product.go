@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"hometest1/core/entity"
+	"hometest1/core/module"
+
+	"github.com/labstack/echo/v4"
+)
+
+type ProductHandler struct {
+	productUC module.ProductUsecase
+}
+
+func NewProductHandler(productUC module.ProductUsecase) *ProductHandler {
+	return &ProductHandler{productUC}
+}
+
+type createProductPayload struct {
+	Serial   string       `json:"serial" validate:"required,max=20"`
+	Name     string       `json:"name" validate:"required,max=255"`
+	Price    entity.Money `json:"price" validate:"gt=0"`
+	Quantity int          `json:"quantity" validate:"min=0"`
+	TaxClass string       `json:"taxClass" validate:"max=50"`
+}
+
+type updateProductPayload struct {
+	Name     string       `json:"name" validate:"required,max=255"`
+	Price    entity.Money `json:"price" validate:"gt=0"`
+	TaxClass string       `json:"taxClass" validate:"max=50"`
+}
+
+type productResponse struct {
+	Serial   string       `json:"serial"`
+	Name     string       `json:"name"`
+	Price    entity.Money `json:"price"`
+	TaxClass string       `json:"taxClass"`
+	Quantity *int         `json:"quantity,omitempty"`
+}
+
+type productListResponse struct {
+	Data  []*productResponse `json:"data"`
+	Page  int                `json:"page"`
+	Limit int                `json:"limit"`
+	Total int64              `json:"total"`
+}
+
+func (h *ProductHandler) Create(c echo.Context) error {
+	p := new(createProductPayload)
+	// bind json payload
+	if err := c.Bind(p); err != nil {
+		return err
+	}
+	// validate payload
+	if err := c.Validate(p); err != nil {
+		return err
+	}
+
+	resp, err := h.productUC.Create(c.Request().Context(), &entity.Product{
+		Serial:   p.Serial,
+		Name:     p.Name,
+		Price:    p.Price,
+		TaxClass: p.TaxClass,
+	}, p.Quantity)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, parseToProductResponse(resp.Product, &resp.Quantity))
+}
+
+func (h *ProductHandler) Update(c echo.Context) error {
+	p := new(updateProductPayload)
+	// bind json payload
+	if err := c.Bind(p); err != nil {
+		return err
+	}
+	// validate payload
+	if err := c.Validate(p); err != nil {
+		return err
+	}
+
+	resp, err := h.productUC.Update(c.Request().Context(), c.Param("serial"), p.Name, p.Price, p.TaxClass)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, parseToProductResponse(resp, nil))
+}
+
+func (h *ProductHandler) Delete(c echo.Context) error {
+	err := h.productUC.Delete(c.Request().Context(), c.Param("serial"))
+	if err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *ProductHandler) List(c echo.Context) error {
+	// invalid value will be normalized by usecase
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+
+	resp, err := h.productUC.List(c.Request().Context(), page, limit)
+	if err != nil {
+		return err
+	}
+
+	result := productListResponse{
+		Data:  []*productResponse{},
+		Page:  resp.Page,
+		Limit: resp.Limit,
+		Total: resp.Total,
+	}
+	for _, product := range resp.Products {
+		result.Data = append(result.Data, parseToProductResponse(product.Product, &product.Quantity))
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+func parseToProductResponse(p *entity.Product, quantity *int) *productResponse {
+	return &productResponse{
+		Serial:   p.Serial,
+		Name:     p.Name,
+		Price:    p.Price,
+		TaxClass: p.TaxClass,
+		Quantity: quantity,
+	}
+}
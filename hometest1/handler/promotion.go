@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"hometest1/core/entity"
+	"hometest1/core/module"
+
+	"github.com/labstack/echo/v4"
+)
+
+type PromotionHandler struct {
+	promoUC module.PromotionUsecase
+}
+
+func NewPromotionHandler(promoUC module.PromotionUsecase) *PromotionHandler {
+	return &PromotionHandler{promoUC}
+}
+
+type promotionPayload struct {
+	Type               int    `json:"type" validate:"required"`
+	ProductSerial      string `json:"productSerial" validate:"required"`
+	MatchQuantity      int    `json:"matchQuantity" validate:"min=0"`
+	PromoValue         int    `json:"promoValue" validate:"min=0"`
+	PromoProductSerial string `json:"promoProductSerial"`
+	Priority           int    `json:"priority"`
+	// 0: stackable, 1: exclusive, 2: best-of
+	Stacking int `json:"stacking"`
+	// optional promotion period, RFC 3339 format
+	StartsAt *time.Time `json:"startsAt"`
+	EndsAt   *time.Time `json:"endsAt"`
+}
+
+type promotionResponse struct {
+	ID                 int64                  `json:"id"`
+	Type               int                    `json:"type"`
+	ProductSerial      string                 `json:"productSerial"`
+	MatchQuantity      int                    `json:"matchQuantity"`
+	PromoValue         int                    `json:"promoValue"`
+	PromoProductSerial string                 `json:"promoProductSerial,omitempty"`
+	Priority           int                    `json:"priority"`
+	Stacking           int                    `json:"stacking"`
+	StartsAt           *time.Time             `json:"startsAt,omitempty"`
+	EndsAt             *time.Time             `json:"endsAt,omitempty"`
+	Status             entity.PromotionStatus `json:"status"`
+}
+
+type promotionListResponse struct {
+	Data  []*promotionResponse `json:"data"`
+	Page  int                  `json:"page"`
+	Limit int                  `json:"limit"`
+	Total int64                `json:"total"`
+}
+
+func (h *PromotionHandler) Create(c echo.Context) error {
+	p, err := h.bindPayload(c)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.promoUC.Create(c.Request().Context(), p)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, parseToPromotionResponse(resp))
+}
+
+func (h *PromotionHandler) Update(c echo.Context) error {
+	promotionID, err := parseIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+	p, err := h.bindPayload(c)
+	if err != nil {
+		return err
+	}
+	p.ID = promotionID
+
+	resp, err := h.promoUC.Update(c.Request().Context(), p)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, parseToPromotionResponse(resp))
+}
+
+func (h *PromotionHandler) Delete(c echo.Context) error {
+	promotionID, err := parseIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	err = h.promoUC.Delete(c.Request().Context(), promotionID)
+	if err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *PromotionHandler) List(c echo.Context) error {
+	// invalid value will be normalized by usecase
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+
+	resp, err := h.promoUC.List(c.Request().Context(), page, limit)
+	if err != nil {
+		return err
+	}
+
+	result := promotionListResponse{
+		Data:  []*promotionResponse{},
+		Page:  resp.Page,
+		Limit: resp.Limit,
+		Total: resp.Total,
+	}
+	for _, promo := range resp.Promotions {
+		result.Data = append(result.Data, parseToPromotionResponse(promo))
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+func (h *PromotionHandler) bindPayload(c echo.Context) (*entity.PromotionDetail, error) {
+	p := new(promotionPayload)
+	// bind json payload
+	if err := c.Bind(p); err != nil {
+		return nil, err
+	}
+	// validate payload
+	if err := c.Validate(p); err != nil {
+		return nil, err
+	}
+
+	result := entity.PromotionDetail{
+		Promotion: &entity.Promotion{
+			Type:          entity.PromotionType(p.Type),
+			MatchQuantity: p.MatchQuantity,
+			PromoValue:    p.PromoValue,
+			Priority:      p.Priority,
+			Stacking:      entity.PromotionStacking(p.Stacking),
+			StartsAt:      p.StartsAt,
+			EndsAt:        p.EndsAt,
+		},
+		Product: &entity.Product{Serial: p.ProductSerial},
+	}
+	if p.PromoProductSerial != "" {
+		result.PromoProduct = &entity.Product{Serial: p.PromoProductSerial}
+	}
+	return &result, nil
+}
+
+func parseToPromotionResponse(p *entity.PromotionDetail) *promotionResponse {
+	result := promotionResponse{
+		ID:            p.ID,
+		Type:          int(p.Type),
+		MatchQuantity: p.MatchQuantity,
+		PromoValue:    p.PromoValue,
+		Priority:      p.Priority,
+		Stacking:      int(p.Stacking),
+		StartsAt:      p.StartsAt,
+		EndsAt:        p.EndsAt,
+		Status:        p.Status(time.Now()),
+	}
+	if p.Product != nil {
+		result.ProductSerial = p.Product.Serial
+	}
+	if p.PromoProduct != nil {
+		result.PromoProductSerial = p.PromoProduct.Serial
+	}
+	return &result
+}
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"hometest1/core/entity"
+	"hometest1/core/module"
+
+	"github.com/labstack/echo/v4"
+)
+
+type ReservationHandler struct {
+	reservationUC module.ReservationUsecase
+}
+
+func NewReservationHandler(reservationUC module.ReservationUsecase) *ReservationHandler {
+	return &ReservationHandler{reservationUC}
+}
+
+type reservationPayload struct {
+	payload
+	// reservation time in minutes, default 15
+	TTLMinutes int `json:"ttlMinutes" validate:"min=0,max=60"`
+}
+
+type confirmReservationPayload struct {
+	CouponCodes []string `json:"couponCodes"`
+	// default to customer of the reservation
+	CustomerID string `json:"customerId" validate:"max=100"`
+	// code of tax region, default region is used if it is empty
+	TaxRegion string `json:"taxRegion" validate:"max=10"`
+}
+
+type reservationResponse struct {
+	ID        int64     `json:"id"`
+	Status    string    `json:"status"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	Checkout  *response `json:"checkout"`
+}
+
+func (h *ReservationHandler) Reserve(c echo.Context) error {
+	p := new(reservationPayload)
+	// bind json payload
+	if err := c.Bind(p); err != nil {
+		return err
+	}
+	// validate payload
+	if err := c.Validate(p); err != nil {
+		return err
+	}
+
+	mapPayload, options := p.toCheckout()
+	resp, err := h.reservationUC.Reserve(c.Request().Context(), mapPayload, options, p.TTLMinutes)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, reservationResponse{
+		ID:        resp.ID,
+		Status:    resp.Status.String(),
+		ExpiresAt: resp.ExpiresAt,
+		Checkout:  parseToResponse(resp.Checkout),
+	})
+}
+
+// Confirm submit checkout of the reservation
+func (h *ReservationHandler) Confirm(c echo.Context) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+	p := new(confirmReservationPayload)
+	// bind json payload
+	if err := c.Bind(p); err != nil {
+		return err
+	}
+	// validate payload
+	if err := c.Validate(p); err != nil {
+		return err
+	}
+
+	resp, err := h.reservationUC.Confirm(c.Request().Context(), id, entity.CheckoutOptions{
+		CouponCodes: p.CouponCodes,
+		CustomerID:  p.CustomerID,
+		TaxRegion:   p.TaxRegion,
+	})
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, parseToResponse(resp))
+}
+
+// Release stock held by the reservation
+func (h *ReservationHandler) Release(c echo.Context) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	err = h.reservationUC.Release(c.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
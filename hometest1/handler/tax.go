@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"math"
+	"net/http"
+	"time"
+
+	"hometest1/core/entity"
+	"hometest1/core/module"
+
+	"github.com/labstack/echo/v4"
+)
+
+type TaxHandler struct {
+	taxUC module.TaxUsecase
+}
+
+func NewTaxHandler(taxUC module.TaxUsecase) *TaxHandler {
+	return &TaxHandler{taxUC}
+}
+
+type taxRegionPayload struct {
+	Name string `json:"name" validate:"required,max=255"`
+	// exclusive or inclusive
+	PriceMode string            `json:"priceMode" validate:"required"`
+	IsDefault bool              `json:"isDefault"`
+	Rates     []*taxRatePayload `json:"rates" validate:"dive"`
+}
+
+type taxRatePayload struct {
+	TaxClass string `json:"taxClass" validate:"required,max=50"`
+	// percent, eg: 19.5 is 19.5%
+	Rate float64 `json:"rate" validate:"min=0,max=100"`
+}
+
+type taxRegionResponse struct {
+	Code      string             `json:"code"`
+	Name      string             `json:"name"`
+	PriceMode string             `json:"priceMode"`
+	IsDefault bool               `json:"isDefault"`
+	Rates     []*taxRateResponse `json:"rates"`
+	UpdatedAt time.Time          `json:"updatedAt"`
+}
+
+type taxRateResponse struct {
+	TaxClass string  `json:"taxClass"`
+	Rate     float64 `json:"rate"`
+}
+
+func (h *TaxHandler) Save(c echo.Context) error {
+	p := new(taxRegionPayload)
+	// bind json payload
+	if err := c.Bind(p); err != nil {
+		return err
+	}
+	// validate payload
+	if err := c.Validate(p); err != nil {
+		return err
+	}
+
+	region := &entity.TaxRegion{
+		Code:      c.Param("code"),
+		Name:      p.Name,
+		PriceMode: entity.ParseTaxPriceMode(p.PriceMode),
+		IsDefault: p.IsDefault,
+	}
+	for _, rate := range p.Rates {
+		region.Rates = append(region.Rates, &entity.TaxRate{
+			TaxClass: rate.TaxClass,
+			Rate:     percentToTaxRate(rate.Rate),
+		})
+	}
+
+	resp, err := h.taxUC.Save(c.Request().Context(), region)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, parseToTaxRegionResponse(resp))
+}
+
+func (h *TaxHandler) List(c echo.Context) error {
+	resp, err := h.taxUC.List(c.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	result := []*taxRegionResponse{}
+	for _, region := range resp {
+		result = append(result, parseToTaxRegionResponse(region))
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+func parseToTaxRegionResponse(r *entity.TaxRegion) *taxRegionResponse {
+	result := &taxRegionResponse{
+		Code:      r.Code,
+		Name:      r.Name,
+		PriceMode: r.PriceMode.String(),
+		IsDefault: r.IsDefault,
+		Rates:     []*taxRateResponse{},
+		UpdatedAt: r.UpdatedAt,
+	}
+	for _, rate := range r.Rates {
+		result.Rates = append(result.Rates, &taxRateResponse{
+			TaxClass: rate.TaxClass,
+			Rate:     taxRateToPercent(rate.Rate),
+		})
+	}
+	return result
+}
+
+// convert percent into basis point, eg: 19.5 into 1950
+func percentToTaxRate(percent float64) int {
+	return int(math.Round(percent * entity.TaxRateScale / 100))
+}
+
+// convert basis point into percent, eg: 1950 into 19.5
+func taxRateToPercent(rate int) float64 {
+	return float64(rate) * 100 / entity.TaxRateScale
+}
@@ -1,20 +1,39 @@
 package main
 
 import (
+	"context"
+	"crypto/subtle"
 	"flag"
 	"fmt"
 	"hometest1/config"
 	"hometest1/core/entity"
 	"hometest1/core/module"
+	"hometest1/core/repository"
 	"hometest1/handler"
+	"hometest1/migration"
+	"hometest1/notifier"
+	"hometest1/publisher"
+	cartrepository "hometest1/repository/cart-repository"
+	idempotencyrepository "hometest1/repository/idempotency-repository"
+	inventoryrepository "hometest1/repository/inventory-repository"
+	memoryrepository "hometest1/repository/memory-repository"
+	orderrepository "hometest1/repository/order-repository"
+	outboxrepository "hometest1/repository/outbox-repository"
 	productrepository "hometest1/repository/product-repository"
 	promotionrepository "hometest1/repository/promotion-repository"
+	reservationrepository "hometest1/repository/reservation-repository"
+	taxrepository "hometest1/repository/tax-repository"
 	"log"
 	"net/http"
+	"os"
+	"reflect"
+	"strings"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/joho/godotenv"
 	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
 )
 
 type CustomValidator struct {
@@ -25,9 +44,16 @@ func (cv *CustomValidator) Validate(i interface{}) error {
 	return cv.validator.Struct(i)
 }
 
+// number of stock alerts waiting to be sent, new alert is dropped if the queue is full
+const stockAlertQueueSize = 100
+
 var loadDotEnv = flag.Bool("loadDotEnv", false, "load .env file into ENV")
 
 func main() {
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
 	flag.Parse()
 
 	// load .env file?
@@ -43,29 +69,309 @@ func main() {
 	db := config.Connect()
 
 	// load repository
-	productRepo := productrepository.New(db)
-	promoRepo := promotionrepository.New(db)
+	var (
+		productRepo     repository.ProductRepo
+		promoRepo       repository.PromotionRepo
+		cartRepo        repository.CartRepo
+		orderRepo       repository.OrderRepo
+		inventoryRepo   repository.InventoryRepo
+		idempotencyRepo repository.IdempotencyRepo
+		reservationRepo repository.ReservationRepo
+		taxRepo         repository.TaxRepo
+		outboxRepo      repository.OutboxRepo
+	)
+	if db == nil {
+		// memory driver, repositories share the store like tables of a database
+		store := memoryrepository.NewStore()
+		productRepo = memoryrepository.NewProductRepo(store)
+		promoRepo = memoryrepository.NewPromotionRepo(store)
+		cartRepo = memoryrepository.NewCartRepo(store)
+		orderRepo = memoryrepository.NewOrderRepo(store)
+		inventoryRepo = memoryrepository.NewInventoryRepo(store)
+		idempotencyRepo = memoryrepository.NewIdempotencyRepo(store)
+		reservationRepo = memoryrepository.NewReservationRepo(store)
+		taxRepo = memoryrepository.NewTaxRepo(store)
+		outboxRepo = memoryrepository.NewOutboxRepo(store)
+	} else {
+		productRepo = productrepository.New(db)
+		promoRepo = promotionrepository.New(db)
+		cartRepo = cartrepository.New(db)
+		orderRepo = orderrepository.New(db)
+		inventoryRepo = inventoryrepository.New(db)
+		idempotencyRepo = idempotencyrepository.New(db)
+		reservationRepo = reservationrepository.New(db)
+		taxRepo = taxrepository.New(db)
+		outboxRepo = outboxrepository.New(db)
+	}
 
 	// load usecase
-	checkoutUC := module.NewCheckoutUsecase(productRepo, promoRepo)
+	promoRules := module.DefaultPromotionRules()
+	stockAlertUC := module.NewStockAlertUsecase(inventoryRepo, productRepo, newStockAlertNotifier(cfg))
+	checkoutUC := module.NewCheckoutUsecaseWithStockAlert(productRepo, promoRepo, promoRules, taxRepo, stockAlertUC)
+	cartUC := module.NewCartUsecase(cartRepo, productRepo, checkoutUC)
+	orderUC := module.NewOrderUsecase(orderRepo, productRepo, checkoutUC)
+	productUC := module.NewProductUsecase(productRepo)
+	promoUC := module.NewPromotionUsecase(promoRepo, productRepo, promoRules)
+	cartPromoUC := module.NewCartPromotionUsecase(promoRepo, productRepo)
+	couponUC := module.NewCouponUsecase(promoRepo)
+	bundleUC := module.NewBundleUsecase(promoRepo, productRepo)
+	inventoryUC := module.NewInventoryUsecase(inventoryRepo, productRepo)
+	idempotencyUC := module.NewIdempotencyUsecase(idempotencyRepo)
+	reservationUC := module.NewReservationUsecase(reservationRepo, productRepo, checkoutUC)
+	taxUC := module.NewTaxUsecase(taxRepo)
+	catalogUC := module.NewCatalogUsecase(productRepo, inventoryRepo, promoRepo, promoUC, promoRules)
+
+	// run command, serve http if there is no command
+	if flag.NArg() > 0 && flag.Arg(0) != "serve" {
+		err := runCommand(db, catalogUC, flag.Args())
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	// memory driver has no table to migrate
+	if cfg.AutoMigrate && db != nil {
+		migrator, err := migration.New(db)
+		if err == nil {
+			err = migrateUp(context.Background(), migrator)
+		}
+		if err != nil {
+			log.Fatalf("Error migrating database: %s", err.Error())
+		}
+	}
 
 	// load handler
 	checkoutHandler := handler.NewCheckoutHandler(checkoutUC)
+	cartHandler := handler.NewCartHandler(cartUC)
+	orderHandler := handler.NewOrderHandler(orderUC)
+	productHandler := handler.NewProductHandler(productUC)
+	promoHandler := handler.NewPromotionHandler(promoUC)
+	cartPromoHandler := handler.NewCartPromotionHandler(cartPromoUC)
+	couponHandler := handler.NewCouponHandler(couponUC)
+	bundleHandler := handler.NewBundleHandler(bundleUC)
+	inventoryHandler := handler.NewInventoryHandler(inventoryUC, stockAlertUC)
+	idempotencyHandler := handler.NewIdempotencyHandler(idempotencyUC)
+	reservationHandler := handler.NewReservationHandler(reservationUC)
+	taxHandler := handler.NewTaxHandler(taxUC)
+	catalogHandler := handler.NewCatalogHandler(catalogUC)
 
 	// load echo framework
 	e := echo.New()
 	// set echo validator
-	e.Validator = &CustomValidator{validator: validator.New()}
+	e.Validator = &CustomValidator{validator: newValidator()}
 	// set error handler
 	e.HTTPErrorHandler = errorHandler
+	// cancel database queries of slow or disconnected request
+	e.Use(requestTimeout(cfg.RequestTimeout))
 
 	// route
-	e.POST("/checkout", checkoutHandler.Submit)
+	// retried checkout with the same Idempotency-Key header is replayed, stock is not reduced twice
+	e.POST("/checkout", checkoutHandler.Submit, idempotencyHandler.Middleware)
+	e.POST("/checkout/quote", checkoutHandler.Quote)
+	e.POST("/carts", cartHandler.Create)
+	e.GET("/carts/:id", cartHandler.Get)
+	e.PUT("/carts/:id/items/:serial", cartHandler.SetItem)
+	e.DELETE("/carts/:id/items/:serial", cartHandler.RemoveItem)
+	e.POST("/carts/:id/checkout", cartHandler.Checkout)
+	e.POST("/reservations", reservationHandler.Reserve)
+	e.POST("/reservations/:id/confirm", reservationHandler.Confirm)
+	e.DELETE("/reservations/:id", reservationHandler.Release)
+
+	// admin route
+	admin := e.Group("/admin", adminAuth(cfg.AdminApiKey))
+	admin.GET("/products", productHandler.List)
+	admin.POST("/products", productHandler.Create)
+	admin.PUT("/products/:serial", productHandler.Update)
+	admin.DELETE("/products/:serial", productHandler.Delete)
+	admin.GET("/promotions", promoHandler.List)
+	admin.POST("/promotions", promoHandler.Create)
+	admin.PUT("/promotions/:id", promoHandler.Update)
+	admin.DELETE("/promotions/:id", promoHandler.Delete)
+	admin.GET("/cart-promotions", cartPromoHandler.List)
+	admin.POST("/cart-promotions", cartPromoHandler.Create)
+	admin.PUT("/cart-promotions/:id", cartPromoHandler.Update)
+	admin.DELETE("/cart-promotions/:id", cartPromoHandler.Delete)
+	admin.GET("/coupons", couponHandler.List)
+	admin.POST("/coupons", couponHandler.Create)
+	admin.PUT("/coupons/:id", couponHandler.Update)
+	admin.DELETE("/coupons/:id", couponHandler.Delete)
+	admin.GET("/bundles", bundleHandler.List)
+	admin.POST("/bundles", bundleHandler.Create)
+	admin.PUT("/bundles/:id", bundleHandler.Update)
+	admin.DELETE("/bundles/:id", bundleHandler.Delete)
+	admin.GET("/tax-regions", taxHandler.List)
+	admin.PUT("/tax-regions/:code", taxHandler.Save)
+	admin.POST("/catalog/import", catalogHandler.Import, middleware.BodyLimit("10M"))
+	admin.GET("/catalog/export", catalogHandler.Export)
+
+	registerOrderRoutes(e, orderHandler, cfg.AdminApiKey)
+
+	// warehouse route, authenticated as admin
+	inventory := e.Group("/inventory", adminAuth(cfg.AdminApiKey))
+	inventory.POST("/:serial/restock", inventoryHandler.Restock)
+	inventory.GET("/:serial/movements", inventoryHandler.History)
+	inventory.PUT("/:serial/threshold", inventoryHandler.SetThreshold)
+
+	// expire reservations in background
+	go sweepReservations(reservationUC, cfg.ReservationSweepInterval)
+
+	// deliver checkout events in background, events are kept in outbox if no sink is set
+	outboxSink, err := newOutboxSink(cfg)
+	if err != nil {
+		log.Fatalf("Error loading outbox sink: %s", err.Error())
+	}
+	if outboxSink != nil {
+		outboxUC := module.NewOutboxUsecase(outboxRepo, outboxSink, module.OutboxOptions{
+			BatchSize:  cfg.OutboxBatchSize,
+			Lease:      cfg.OutboxLease,
+			Backoff:    cfg.OutboxRetryBackoff,
+			MaxBackoff: cfg.OutboxMaxBackoff,
+		})
+		go dispatchOutbox(outboxUC, cfg.OutboxPollInterval, cfg.OutboxLease)
+	}
 
 	// run
 	e.Logger.Fatal(e.Start(":" + cfg.HttpPort))
 }
 
+// send stock alert to webhook if it is set, otherwise write it to log.
+// Alert is sent in background with retry
+func newStockAlertNotifier(cfg config.Config) module.StockAlertNotifier {
+	var result module.StockAlertNotifier = notifier.NewLog(log.Default())
+	if cfg.StockAlertWebhookURL != "" {
+		result = notifier.NewWebhook(cfg.StockAlertWebhookURL, cfg.StockAlertTimeout)
+	}
+	result = notifier.NewRetry(result, cfg.StockAlertRetryAttempts, cfg.StockAlertRetryBackoff)
+	return notifier.NewQueue(result, stockAlertQueueSize, log.Default())
+}
+
+// combine outbox sinks of config, return nil if no sink is set
+func newOutboxSink(cfg config.Config) (module.OutboxSink, error) {
+	var sinks []module.OutboxSink
+	for _, name := range cfg.OutboxSinks {
+		switch strings.TrimSpace(name) {
+		case "":
+		case "stdout":
+			sinks = append(sinks, publisher.NewWriter(os.Stdout))
+		case "file":
+			sink, err := publisher.NewFile(cfg.OutboxFilePath)
+			if err != nil {
+				return nil, err
+			}
+			sinks = append(sinks, sink)
+		case "webhook":
+			if cfg.OutboxWebhookURL == "" {
+				return nil, fmt.Errorf("OUTBOX_WEBHOOK_URL is required by webhook sink")
+			}
+			sinks = append(sinks, publisher.NewWebhook(cfg.OutboxWebhookURL, cfg.OutboxTimeout))
+		default:
+			return nil, fmt.Errorf("unknown outbox sink %q", name)
+		}
+	}
+	switch len(sinks) {
+	case 0:
+		return nil, nil
+	case 1:
+		return sinks[0], nil
+	}
+	return publisher.NewFanout(sinks...), nil
+}
+
+// deliver due outbox events every interval, failed events are retried by their backoff
+func dispatchOutbox(outboxUC module.OutboxUsecase, interval, lease time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		failed, err := dispatchOutboxEvents(outboxUC, lease)
+		if err != nil {
+			log.Printf("Error dispatching outbox: %s", err.Error())
+			continue
+		}
+		if failed > 0 {
+			log.Printf("Failed to deliver %d outbox events, they are retried later", failed)
+		}
+	}
+}
+
+// deliver one batch within the lease, event that is not finished is taken again after its lease
+func dispatchOutboxEvents(outboxUC module.OutboxUsecase, lease time.Duration) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), lease)
+	defer cancel()
+	_, failed, err := outboxUC.Dispatch(ctx)
+	return failed, err
+}
+
+// expire reservations every interval, expired reservation already stops holding stock,
+// sweeper only marks its status
+func sweepReservations(reservationUC module.ReservationUsecase, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		expired, err := expireReservations(reservationUC, interval)
+		if err != nil {
+			log.Printf("Error expiring reservations: %s", err.Error())
+			continue
+		}
+		if expired > 0 {
+			log.Printf("Expired %d reservations", expired)
+		}
+	}
+}
+
+// expire reservations within the sweep interval, so a stuck sweep does not overlap the next one
+func expireReservations(reservationUC module.ReservationUsecase, interval time.Duration) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), interval)
+	defer cancel()
+	return reservationUC.ExpireReservations(ctx)
+}
+
+// set deadline of request context, client disconnect also cancels the context
+func requestTimeout(timeout time.Duration) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx, cancel := context.WithTimeout(c.Request().Context(), timeout)
+			defer cancel()
+			c.SetRequest(c.Request().WithContext(ctx))
+			// checked before cancel, error handler always sees the context cancelled
+			return handler.TranslateContextError(ctx, next(c))
+		}
+	}
+}
+
+func newValidator() *validator.Validate {
+	validate := validator.New()
+
+	// validate money by its minor unit amount, eg: `validate:"gt=0"`
+	validate.RegisterCustomTypeFunc(func(field reflect.Value) interface{} {
+		if money, ok := field.Interface().(entity.Money); ok {
+			return money.Amount
+		}
+		return nil
+	}, entity.Money{})
+
+	return validate
+}
+
+// authenticate admin with bearer token
+// order routes are authenticated as admin, orders show refunds, discounts and customer,
+// and returns restore stock and record refund
+func registerOrderRoutes(e *echo.Echo, orderHandler *handler.OrderHandler, apiKey string) {
+	orders := e.Group("/orders", adminAuth(apiKey))
+	orders.GET("", orderHandler.List)
+	orders.GET("/:id", orderHandler.Get)
+	orders.POST("/:id/cancel", orderHandler.Cancel)
+	orders.POST("/:id/returns", orderHandler.Return)
+}
+
+func adminAuth(apiKey string) echo.MiddlewareFunc {
+	return middleware.KeyAuth(func(key string, c echo.Context) (bool, error) {
+		if apiKey == "" {
+			return false, nil
+		}
+		return subtle.ConstantTimeCompare([]byte(key), []byte(apiKey)) == 1, nil
+	})
+}
+
 func errorHandler(err error, c echo.Context) {
 	report, ok := err.(*echo.HTTPError)
 	if !ok {
@@ -73,11 +379,18 @@ func errorHandler(err error, c echo.Context) {
 	}
 
 	if castedObject, ok := err.(validator.ValidationErrors); ok {
+		report.Code = http.StatusBadRequest
 		for _, err := range castedObject {
 			switch err.Tag() {
 			case "required":
 				report.Message = fmt.Sprintf("%s is required",
 					err.Field())
+			case "max":
+				report.Message = fmt.Sprintf("%s must not exceed %s",
+					err.Field(), err.Param())
+			case "min", "gt", "gte":
+				report.Message = fmt.Sprintf("%s is too small",
+					err.Field())
 			}
 		}
 	}
@@ -86,5 +399,14 @@ func errorHandler(err error, c echo.Context) {
 		report.Code = entityError.GetCode()
 	}
 
+	// list unknown serials, so client can fix the payload
+	if serialsError, ok := err.(entity.UnknownSerialsErr); ok {
+		c.JSON(serialsError.GetCode(), map[string]interface{}{
+			"message":        serialsError.Error(),
+			"unknownSerials": serialsError.Serials,
+		})
+		return
+	}
+
 	c.JSON(report.Code, report)
 }
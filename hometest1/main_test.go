@@ -0,0 +1,62 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"hometest1/core/module"
+	repomocks "hometest1/core/repository/mocks"
+	"hometest1/handler"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_OrderRoutes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	orderRepo := repomocks.NewMockOrderRepo(ctrl)
+	productRepo := repomocks.NewMockProductRepo(ctrl)
+	promoRepo := repomocks.NewMockPromotionRepo(ctrl)
+	orderUC := module.NewOrderUsecase(orderRepo, productRepo, module.NewCheckoutUsecase(productRepo, promoRepo))
+
+	e := echo.New()
+	e.HTTPErrorHandler = errorHandler
+	registerOrderRoutes(e, handler.NewOrderHandler(orderUC), "secret")
+
+	request := func(method, path, apiKey string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		if apiKey != "" {
+			req.Header.Set(echo.HeaderAuthorization, "Bearer "+apiKey)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("positive, admin lists orders", func(t *testing.T) {
+		orderRepo.EXPECT().GetOrders(gomock.Any(), module.DefaultPageLimit, 0).Return(nil, int64(0), nil).Times(1)
+
+		rec := request(http.MethodGet, "/orders", "secret")
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("negative, orders need api key", func(t *testing.T) {
+		for _, path := range []string{"/orders", "/orders/1"} {
+			rec := request(http.MethodGet, path, "")
+			assert.Equal(t, http.StatusBadRequest, rec.Code, path)
+		}
+	})
+
+	t.Run("negative, wrong api key", func(t *testing.T) {
+		for _, path := range []string{"/orders", "/orders/1"} {
+			rec := request(http.MethodGet, path, "wrong")
+			assert.Equal(t, http.StatusUnauthorized, rec.Code, path)
+		}
+		rec := request(http.MethodPost, "/orders/1/cancel", "wrong")
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+}
@@ -1,10 +0,0 @@
-CREATE TABLE `product` (
-  `id` bigint UNSIGNED NOT NULL AUTO_INCREMENT,
-  `serial` varchar(20) COLLATE utf8mb4_unicode_ci NOT NULL,
-  `name` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
-  `price` double(10,2) NOT NULL DEFAULT 0,
-  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
-
-  PRIMARY KEY (`id`),
-  UNIQUE KEY `product_UNQ1` (`serial`)
-);
\ No newline at end of file
@@ -1,9 +0,0 @@
-CREATE TABLE `product_quantity` (
-  `id` bigint UNSIGNED NOT NULL AUTO_INCREMENT,
-  `product_id` bigint UNSIGNED NOT NULL,
-  `quantity` int UNSIGNED NOT NULL DEFAULT 0,
-  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
-
-  PRIMARY KEY (`id`),
-  FOREIGN KEY `product_quantity_FK1` (`product_id`) REFERENCES `product` (`id`)
-);
\ No newline at end of file
@@ -0,0 +1,251 @@
+// Package migration embeds versioned database migrations of every driver,
+// applied migrations are recorded in schema_migrations table
+package migration
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// migration files are <driver>/<version>_<name>.up.sql and <driver>/<version>_<name>.down.sql
+//
+//go:embed mysql/*.sql sqlite/*.sql postgres/*.sql
+var files embed.FS
+
+var fileNamePattern = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// SchemaMigration is applied migration
+type SchemaMigration struct {
+	Version   int64 `gorm:"primaryKey;autoIncrement:false"`
+	Name      string
+	AppliedAt time.Time
+}
+
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// MigrationStatus is migration with its applied time, nil if it is pending
+type MigrationStatus struct {
+	*Migration
+	AppliedAt *time.Time
+}
+
+type Migrator struct {
+	db         *gorm.DB
+	migrations []*Migration
+}
+
+// create migrator with embedded migrations of the database driver
+func New(db *gorm.DB) (*Migrator, error) {
+	migrations, err := Load(db.Dialector.Name())
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db, migrations}, nil
+}
+
+// get embedded migrations of the driver sorted by version
+func Load(driver string) ([]*Migration, error) {
+	entries, err := fs.ReadDir(files, driver)
+	if err != nil {
+		return nil, fmt.Errorf("migrations of driver %s are not found", driver)
+	}
+
+	mapVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		match := fileNamePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name %s", entry.Name())
+		}
+		version, _ := strconv.ParseInt(match[1], 10, 64)
+		content, err := files.ReadFile(path.Join(driver, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := mapVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			mapVersion[version] = migration
+		}
+		if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration version %d has different names", version)
+		}
+		if match[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	var result []*Migration
+	for _, migration := range mapVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s must have up and down file", migration.Version, migration.Name)
+		}
+		result = append(result, migration)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Version < result[j].Version })
+	return result, nil
+}
+
+// apply pending migrations by version, return applied migrations
+func (m *Migrator) Up(ctx context.Context) ([]*Migration, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*Migration
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+		err = m.run(ctx, migration.Up, func(tx *gorm.DB) error {
+			return tx.Create(&SchemaMigration{Version: migration.Version, Name: migration.Name, AppliedAt: time.Now()}).Error
+		})
+		if err != nil {
+			return result, fmt.Errorf("migrate up %d_%s: %w", migration.Version, migration.Name, err)
+		}
+		result = append(result, migration)
+	}
+	return result, nil
+}
+
+// roll back the latest applied migrations, return rolled back migrations
+func (m *Migrator) Down(ctx context.Context, steps int) ([]*Migration, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var versions []int64
+	for version := range applied {
+		versions = append(versions, version)
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i] > versions[j] })
+
+	mapVersion := make(map[int64]*Migration)
+	for _, migration := range m.migrations {
+		mapVersion[migration.Version] = migration
+	}
+
+	var result []*Migration
+	for _, version := range versions[:min(steps, len(versions))] {
+		migration, ok := mapVersion[version]
+		if !ok {
+			return result, fmt.Errorf("migration %d_%s is not found in this binary", version, applied[version].Name)
+		}
+		err = m.run(ctx, migration.Down, func(tx *gorm.DB) error {
+			return tx.Delete(&SchemaMigration{}, version).Error
+		})
+		if err != nil {
+			return result, fmt.Errorf("migrate down %d_%s: %w", migration.Version, migration.Name, err)
+		}
+		result = append(result, migration)
+	}
+	return result, nil
+}
+
+// get every migration with its applied time, including applied migration that is not found in this binary
+func (m *Migrator) Status(ctx context.Context) ([]*MigrationStatus, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*MigrationStatus
+	for _, migration := range m.migrations {
+		status := &MigrationStatus{Migration: migration}
+		if row, ok := applied[migration.Version]; ok {
+			status.AppliedAt = &row.AppliedAt
+			delete(applied, migration.Version)
+		}
+		result = append(result, status)
+	}
+	for _, row := range applied {
+		result = append(result, &MigrationStatus{
+			Migration: &Migration{Version: row.Version, Name: row.Name},
+			AppliedAt: &row.AppliedAt,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Version < result[j].Version })
+	return result, nil
+}
+
+// create schema_migrations table if not exists, then get applied migrations
+// return map[int64] where int64 = version
+func (m *Migrator) applied(ctx context.Context) (map[int64]*SchemaMigration, error) {
+	err := m.db.WithContext(ctx).Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+  version bigint NOT NULL PRIMARY KEY,
+  name varchar(255) NOT NULL,
+  applied_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP
+)`).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []*SchemaMigration
+	err = m.db.WithContext(ctx).Order("version asc").Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[int64]*SchemaMigration)
+	for _, row := range rows {
+		result[row.Version] = row
+	}
+	return result, nil
+}
+
+// run statements of the migration and record it in one transaction.
+// Mysql commits schema change implicitly, so failed mysql migration may be applied partially
+func (m *Migrator) run(ctx context.Context, sql string, record func(tx *gorm.DB) error) error {
+	return m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, statement := range SplitStatements(sql) {
+			err := tx.Exec(statement).Error
+			if err != nil {
+				return err
+			}
+		}
+		return record(tx)
+	})
+}
+
+// split sql by semicolon at end of line, comment only statement is skipped
+func SplitStatements(sql string) []string {
+	var result []string
+	for _, statement := range strings.Split(sql, ";\n") {
+		statement = strings.TrimSuffix(strings.TrimSpace(statement), ";")
+		if hasCode(statement) {
+			result = append(result, statement)
+		}
+	}
+	return result
+}
+
+func hasCode(statement string) bool {
+	for _, line := range strings.Split(statement, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "--") {
+			return true
+		}
+	}
+	return false
+}
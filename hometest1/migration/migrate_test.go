@@ -0,0 +1,135 @@
+package migration_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"hometest1/core/entity"
+	"hometest1/migration"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/schema"
+)
+
+func initDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")+"?_foreign_keys=on"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+		NamingStrategy: schema.NamingStrategy{
+			SingularTable: true,
+		},
+	})
+	if err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	return db
+}
+
+func Test_Load(t *testing.T) {
+	t.Run("positive, every driver has the same versions", func(t *testing.T) {
+		mysql, err := migration.Load("mysql")
+		assert.Nil(t, err)
+		assert.NotEmpty(t, mysql)
+		for _, driver := range []string{"sqlite", "postgres"} {
+			migrations, err := migration.Load(driver)
+			assert.Nil(t, err)
+			assert.Equal(t, len(mysql), len(migrations))
+			for i, m := range migrations {
+				assert.Equal(t, mysql[i].Version, m.Version)
+				assert.Equal(t, mysql[i].Name, m.Name)
+			}
+		}
+	})
+
+	t.Run("negative, unknown driver", func(t *testing.T) {
+		_, err := migration.Load("oracle")
+		assert.EqualError(t, err, "migrations of driver oracle are not found")
+	})
+}
+
+func Test_SplitStatements(t *testing.T) {
+	statements := migration.SplitStatements("-- comment\nCREATE TABLE a (\n  id int\n);\n\n-- only comment;\nDROP TABLE b;")
+	assert.Equal(t, []string{"-- comment\nCREATE TABLE a (\n  id int\n)", "DROP TABLE b"}, statements)
+}
+
+func Test_Migrator(t *testing.T) {
+	ctx := context.Background()
+	db := initDB(t)
+	migrator, err := migration.New(db)
+	assert.Nil(t, err)
+	migrations, _ := migration.Load("sqlite")
+
+	t.Run("positive, up applies pending migrations once", func(t *testing.T) {
+		applied, err := migrator.Up(ctx)
+		assert.Nil(t, err)
+		assert.Len(t, applied, len(migrations))
+
+		applied, err = migrator.Up(ctx)
+		assert.Nil(t, err)
+		assert.Empty(t, applied)
+
+		statuses, err := migrator.Status(ctx)
+		assert.Nil(t, err)
+		for _, status := range statuses {
+			assert.NotNil(t, status.AppliedAt)
+		}
+		assert.True(t, db.Migrator().HasTable("product"))
+	})
+
+	t.Run("positive, down rolls back the latest migration", func(t *testing.T) {
+		rolledBack, err := migrator.Down(ctx, len(migrations)+1)
+		assert.Nil(t, err)
+		assert.Len(t, rolledBack, len(migrations))
+		assert.Equal(t, migrations[len(migrations)-1].Version, rolledBack[0].Version)
+
+		statuses, err := migrator.Status(ctx)
+		assert.Nil(t, err)
+		for _, status := range statuses {
+			assert.Nil(t, status.AppliedAt)
+		}
+		assert.False(t, db.Migrator().HasTable("product"))
+	})
+}
+
+func Test_Seed(t *testing.T) {
+	ctx := context.Background()
+	db := initDB(t)
+	migrator, err := migration.New(db)
+	assert.Nil(t, err)
+	_, err = migrator.Up(ctx)
+	assert.Nil(t, err)
+
+	t.Run("positive, seed is idempotent", func(t *testing.T) {
+		result, err := migration.Seed(ctx, db)
+		assert.Nil(t, err)
+		assert.Equal(t, &migration.SeedResult{Products: 4, Promotions: 3, TaxRegions: 2}, result)
+
+		result, err = migration.Seed(ctx, db)
+		assert.Nil(t, err)
+		assert.Equal(t, &migration.SeedResult{}, result)
+
+		var count int64
+		db.Model(&entity.ProductQuantity{}).Count(&count)
+		assert.Equal(t, int64(4), count)
+		db.Model(&entity.TaxRate{}).Count(&count)
+		assert.Equal(t, int64(3), count)
+	})
+
+	t.Run("positive, existing and deleted rows are kept", func(t *testing.T) {
+		err := db.Model(&entity.Product{}).Where("serial = ?", "120P90").Update("name", "Renamed").Error
+		assert.Nil(t, err)
+		err = db.Where("serial = ?", "234234").Delete(&entity.Product{}).Error
+		assert.Nil(t, err)
+
+		result, err := migration.Seed(ctx, db)
+		assert.Nil(t, err)
+		assert.Equal(t, &migration.SeedResult{}, result)
+
+		var product entity.Product
+		db.Where("serial = ?", "120P90").Take(&product)
+		assert.Equal(t, "Renamed", product.Name)
+	})
+}
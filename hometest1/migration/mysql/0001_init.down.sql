@@ -0,0 +1,23 @@
+-- drop every table of the baseline schema
+DROP TABLE IF EXISTS `order_tax`;
+DROP TABLE IF EXISTS `tax_rate`;
+DROP TABLE IF EXISTS `tax_region`;
+DROP TABLE IF EXISTS `order_discount`;
+DROP TABLE IF EXISTS `order_return_item`;
+DROP TABLE IF EXISTS `order_return`;
+DROP TABLE IF EXISTS `reservation_item`;
+DROP TABLE IF EXISTS `reservation`;
+DROP TABLE IF EXISTS `idempotency_key`;
+DROP TABLE IF EXISTS `bundle_item`;
+DROP TABLE IF EXISTS `bundle`;
+DROP TABLE IF EXISTS `coupon_redemption`;
+DROP TABLE IF EXISTS `coupon`;
+DROP TABLE IF EXISTS `cart_promotion`;
+DROP TABLE IF EXISTS `stock_movement`;
+DROP TABLE IF EXISTS `order_item`;
+DROP TABLE IF EXISTS `order`;
+DROP TABLE IF EXISTS `cart_item`;
+DROP TABLE IF EXISTS `cart`;
+DROP TABLE IF EXISTS `promotion`;
+DROP TABLE IF EXISTS `product_quantity`;
+DROP TABLE IF EXISTS `product`;
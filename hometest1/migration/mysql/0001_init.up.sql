@@ -0,0 +1,295 @@
+-- baseline schema.
+-- existing tables are kept, so database created before versioned migrations is recorded as migrated
+CREATE TABLE IF NOT EXISTS `product` (
+  `id` bigint UNSIGNED NOT NULL AUTO_INCREMENT,
+  `serial` varchar(20) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `name` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `price` decimal(10,2) NOT NULL DEFAULT 0,
+  `tax_class` varchar(50) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT 'standard',
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `deleted_at` timestamp NULL DEFAULT NULL,
+
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `product_UNQ1` (`serial`)
+);
+
+CREATE TABLE IF NOT EXISTS `product_quantity` (
+  `id` bigint UNSIGNED NOT NULL AUTO_INCREMENT,
+  `product_id` bigint UNSIGNED NOT NULL,
+  `quantity` int UNSIGNED NOT NULL DEFAULT 0,
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+  PRIMARY KEY (`id`),
+  FOREIGN KEY `product_quantity_FK1` (`product_id`) REFERENCES `product` (`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `promotion` (
+  `id` bigint UNSIGNED NOT NULL AUTO_INCREMENT,
+  `type` int UNSIGNED NOT NULL,
+  `product_id` bigint UNSIGNED NOT NULL,
+  `match_quantity` int UNSIGNED NOT NULL DEFAULT 0,
+  `promo_value` int UNSIGNED NOT NULL DEFAULT 0,
+  `promo_product_id` bigint UNSIGNED NOT NULL DEFAULT 0,
+  `priority` int NOT NULL DEFAULT 0,
+  `stacking` tinyint UNSIGNED NOT NULL DEFAULT 0,
+  `starts_at` timestamp NULL DEFAULT NULL,
+  `ends_at` timestamp NULL DEFAULT NULL,
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `deleted_at` timestamp NULL DEFAULT NULL,
+
+  PRIMARY KEY (`id`),
+  FOREIGN KEY `promotion_FK1` (`product_id`) REFERENCES `product` (`id`),
+  KEY `promotion_IDX1` (`promo_product_id`),
+  KEY `promotion_IDX2` (`product_id`, `starts_at`, `ends_at`)
+);
+
+CREATE TABLE IF NOT EXISTS `cart` (
+  `id` bigint UNSIGNED NOT NULL AUTO_INCREMENT,
+  `status` tinyint UNSIGNED NOT NULL DEFAULT 1,
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+  PRIMARY KEY (`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `cart_item` (
+  `id` bigint UNSIGNED NOT NULL AUTO_INCREMENT,
+  `cart_id` bigint UNSIGNED NOT NULL,
+  `product_id` bigint UNSIGNED NOT NULL,
+  `quantity` int UNSIGNED NOT NULL DEFAULT 0,
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `cart_item_UNQ1` (`cart_id`, `product_id`),
+  FOREIGN KEY `cart_item_FK1` (`cart_id`) REFERENCES `cart` (`id`),
+  FOREIGN KEY `cart_item_FK2` (`product_id`) REFERENCES `product` (`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `order` (
+  `id` bigint UNSIGNED NOT NULL AUTO_INCREMENT,
+  `status` tinyint UNSIGNED NOT NULL DEFAULT 1,
+  `total_item` int UNSIGNED NOT NULL DEFAULT 0,
+  `total_price` decimal(10,2) NOT NULL DEFAULT 0,
+  `discount_price` decimal(10,2) NOT NULL DEFAULT 0,
+  `refunded_price` decimal(10,2) NOT NULL DEFAULT 0,
+  `tax_region` varchar(10) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `tax_price_mode` tinyint UNSIGNED NOT NULL DEFAULT 0,
+  `tax_price` decimal(10,2) NOT NULL DEFAULT 0,
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+  PRIMARY KEY (`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `order_item` (
+  `id` bigint UNSIGNED NOT NULL AUTO_INCREMENT,
+  `order_id` bigint UNSIGNED NOT NULL,
+  `product_id` bigint UNSIGNED NOT NULL,
+  `unit_price` decimal(10,2) NOT NULL DEFAULT 0,
+  `quantity` int UNSIGNED NOT NULL DEFAULT 0,
+  `sub_total_price` decimal(10,2) NOT NULL DEFAULT 0,
+  `promotion_id` bigint UNSIGNED NOT NULL DEFAULT 0,
+  `bundle_id` bigint UNSIGNED NOT NULL DEFAULT 0,
+  `returned_quantity` int UNSIGNED NOT NULL DEFAULT 0,
+
+  PRIMARY KEY (`id`),
+  FOREIGN KEY `order_item_FK1` (`order_id`) REFERENCES `order` (`id`),
+  FOREIGN KEY `order_item_FK2` (`product_id`) REFERENCES `product` (`id`),
+  KEY `order_item_IDX1` (`promotion_id`)
+);
+
+CREATE TABLE IF NOT EXISTS `stock_movement` (
+  `id` bigint UNSIGNED NOT NULL AUTO_INCREMENT,
+  `product_id` bigint UNSIGNED NOT NULL,
+  `quantity` int NOT NULL DEFAULT 0,
+  `reason` tinyint UNSIGNED NOT NULL DEFAULT 0,
+  `reference` varchar(100) NOT NULL DEFAULT '',
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+  PRIMARY KEY (`id`),
+  FOREIGN KEY `stock_movement_FK1` (`product_id`) REFERENCES `product` (`id`),
+  KEY `stock_movement_IDX1` (`product_id`, `created_at`)
+);
+
+CREATE TABLE IF NOT EXISTS `cart_promotion` (
+  `id` bigint UNSIGNED NOT NULL AUTO_INCREMENT,
+  `type` int UNSIGNED NOT NULL,
+  `min_spend` decimal(10,2) NOT NULL DEFAULT 0,
+  `promo_value` int UNSIGNED NOT NULL DEFAULT 0,
+  `promo_amount` decimal(10,2) NOT NULL DEFAULT 0,
+  `promo_product_id` bigint UNSIGNED NOT NULL DEFAULT 0,
+  `priority` int NOT NULL DEFAULT 0,
+  `stacking` tinyint UNSIGNED NOT NULL DEFAULT 0,
+  `starts_at` timestamp NULL DEFAULT NULL,
+  `ends_at` timestamp NULL DEFAULT NULL,
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `deleted_at` timestamp NULL DEFAULT NULL,
+
+  PRIMARY KEY (`id`),
+  KEY `cart_promotion_IDX1` (`starts_at`, `ends_at`)
+);
+
+CREATE TABLE IF NOT EXISTS `coupon` (
+  `id` bigint UNSIGNED NOT NULL AUTO_INCREMENT,
+  `code` varchar(50) NOT NULL,
+  `cart_promotion_id` bigint UNSIGNED NOT NULL,
+  `max_redemptions` int UNSIGNED NOT NULL DEFAULT 0,
+  `per_customer_limit` int UNSIGNED NOT NULL DEFAULT 0,
+  `redeemed_count` int UNSIGNED NOT NULL DEFAULT 0,
+  `expires_at` timestamp NULL DEFAULT NULL,
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `deleted_at` timestamp NULL DEFAULT NULL,
+
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `coupon_UNQ1` (`code`),
+  FOREIGN KEY `coupon_FK1` (`cart_promotion_id`) REFERENCES `cart_promotion` (`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `coupon_redemption` (
+  `id` bigint UNSIGNED NOT NULL AUTO_INCREMENT,
+  `coupon_id` bigint UNSIGNED NOT NULL,
+  `order_id` bigint UNSIGNED NOT NULL,
+  `customer_id` varchar(100) NOT NULL DEFAULT '',
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+  PRIMARY KEY (`id`),
+  FOREIGN KEY `coupon_redemption_FK1` (`coupon_id`) REFERENCES `coupon` (`id`),
+  FOREIGN KEY `coupon_redemption_FK2` (`order_id`) REFERENCES `order` (`id`),
+  KEY `coupon_redemption_IDX1` (`coupon_id`, `customer_id`)
+);
+
+CREATE TABLE IF NOT EXISTS `bundle` (
+  `id` bigint UNSIGNED NOT NULL AUTO_INCREMENT,
+  `type` int UNSIGNED NOT NULL,
+  `name` varchar(100) NOT NULL DEFAULT '',
+  `price` decimal(10,2) NOT NULL DEFAULT 0,
+  `match_quantity` int UNSIGNED NOT NULL DEFAULT 0,
+  `promo_value` int UNSIGNED NOT NULL DEFAULT 0,
+  `priority` int NOT NULL DEFAULT 0,
+  `starts_at` timestamp NULL DEFAULT NULL,
+  `ends_at` timestamp NULL DEFAULT NULL,
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `deleted_at` timestamp NULL DEFAULT NULL,
+
+  PRIMARY KEY (`id`),
+  KEY `bundle_IDX1` (`starts_at`, `ends_at`)
+);
+
+CREATE TABLE IF NOT EXISTS `bundle_item` (
+  `id` bigint UNSIGNED NOT NULL AUTO_INCREMENT,
+  `bundle_id` bigint UNSIGNED NOT NULL,
+  `product_id` bigint UNSIGNED NOT NULL,
+  `quantity` int UNSIGNED NOT NULL DEFAULT 0,
+
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `bundle_item_UNQ1` (`bundle_id`, `product_id`),
+  FOREIGN KEY `bundle_item_FK1` (`bundle_id`) REFERENCES `bundle` (`id`),
+  FOREIGN KEY `bundle_item_FK2` (`product_id`) REFERENCES `product` (`id`),
+  KEY `bundle_item_IDX1` (`product_id`)
+);
+
+CREATE TABLE IF NOT EXISTS `idempotency_key` (
+  `id` bigint UNSIGNED NOT NULL AUTO_INCREMENT,
+  `key` varchar(255) NOT NULL,
+  `request_hash` char(64) NOT NULL,
+  `response_code` smallint UNSIGNED NOT NULL DEFAULT 0,
+  `response_body` mediumtext NOT NULL,
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idempotency_key_UNQ1` (`key`)
+);
+
+CREATE TABLE IF NOT EXISTS `reservation` (
+  `id` bigint UNSIGNED NOT NULL AUTO_INCREMENT,
+  `status` tinyint UNSIGNED NOT NULL DEFAULT 0,
+  `customer_id` varchar(100) NOT NULL DEFAULT '',
+  `order_id` bigint UNSIGNED NOT NULL DEFAULT 0,
+  `expires_at` timestamp NOT NULL,
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+  PRIMARY KEY (`id`),
+  KEY `reservation_IDX1` (`status`, `expires_at`)
+);
+
+CREATE TABLE IF NOT EXISTS `reservation_item` (
+  `id` bigint UNSIGNED NOT NULL AUTO_INCREMENT,
+  `reservation_id` bigint UNSIGNED NOT NULL,
+  `product_id` bigint UNSIGNED NOT NULL,
+  `quantity` int UNSIGNED NOT NULL DEFAULT 0,
+  `ordered_quantity` int UNSIGNED NOT NULL DEFAULT 0,
+
+  PRIMARY KEY (`id`),
+  KEY `reservation_item_IDX1` (`reservation_id`),
+  KEY `reservation_item_IDX2` (`product_id`)
+);
+
+CREATE TABLE IF NOT EXISTS `order_return` (
+  `id` bigint UNSIGNED NOT NULL AUTO_INCREMENT,
+  `order_id` bigint UNSIGNED NOT NULL,
+  `refund_price` decimal(10,2) NOT NULL DEFAULT 0,
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+  PRIMARY KEY (`id`),
+  FOREIGN KEY `order_return_FK1` (`order_id`) REFERENCES `order` (`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `order_return_item` (
+  `id` bigint UNSIGNED NOT NULL AUTO_INCREMENT,
+  `order_return_id` bigint UNSIGNED NOT NULL,
+  `order_item_id` bigint UNSIGNED NOT NULL,
+  `product_id` bigint UNSIGNED NOT NULL,
+  `quantity` int UNSIGNED NOT NULL DEFAULT 0,
+
+  PRIMARY KEY (`id`),
+  FOREIGN KEY `order_return_item_FK1` (`order_return_id`) REFERENCES `order_return` (`id`),
+  FOREIGN KEY `order_return_item_FK2` (`order_item_id`) REFERENCES `order_item` (`id`),
+  FOREIGN KEY `order_return_item_FK3` (`product_id`) REFERENCES `product` (`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `order_discount` (
+  `id` bigint UNSIGNED NOT NULL AUTO_INCREMENT,
+  `order_id` bigint UNSIGNED NOT NULL,
+  `product_id` bigint UNSIGNED NOT NULL DEFAULT 0,
+  `source` varchar(20) NOT NULL,
+  `source_id` bigint UNSIGNED NOT NULL,
+  `amount` decimal(10,2) NOT NULL DEFAULT 0,
+
+  PRIMARY KEY (`id`),
+  FOREIGN KEY `order_discount_FK1` (`order_id`) REFERENCES `order` (`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `tax_region` (
+  `id` bigint UNSIGNED NOT NULL AUTO_INCREMENT,
+  `code` varchar(10) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `name` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `price_mode` tinyint UNSIGNED NOT NULL DEFAULT 1,
+  `is_default` tinyint(1) NOT NULL DEFAULT 0,
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `tax_region_UNQ1` (`code`)
+);
+
+CREATE TABLE IF NOT EXISTS `tax_rate` (
+  `id` bigint UNSIGNED NOT NULL AUTO_INCREMENT,
+  `tax_region_id` bigint UNSIGNED NOT NULL,
+  `tax_class` varchar(50) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `rate` int UNSIGNED NOT NULL DEFAULT 0,
+
+  PRIMARY KEY (`id`),
+  FOREIGN KEY `tax_rate_FK1` (`tax_region_id`) REFERENCES `tax_region` (`id`),
+  UNIQUE KEY `tax_rate_UNQ1` (`tax_region_id`, `tax_class`)
+);
+
+CREATE TABLE IF NOT EXISTS `order_tax` (
+  `id` bigint UNSIGNED NOT NULL AUTO_INCREMENT,
+  `order_id` bigint UNSIGNED NOT NULL,
+  `tax_class` varchar(50) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `rate` int UNSIGNED NOT NULL DEFAULT 0,
+  `taxable_price` decimal(10,2) NOT NULL DEFAULT 0,
+  `tax_price` decimal(10,2) NOT NULL DEFAULT 0,
+
+  PRIMARY KEY (`id`),
+  FOREIGN KEY `order_tax_FK1` (`order_id`) REFERENCES `order` (`id`)
+);
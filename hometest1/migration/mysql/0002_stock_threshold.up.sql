@@ -0,0 +1,11 @@
+-- reorder threshold of product, alert is sent when checkout takes stock down to the threshold
+CREATE TABLE IF NOT EXISTS `stock_threshold` (
+  `id` bigint UNSIGNED NOT NULL AUTO_INCREMENT,
+  `product_id` bigint UNSIGNED NOT NULL,
+  `threshold` int UNSIGNED NOT NULL DEFAULT 0,
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `stock_threshold_UNQ1` (`product_id`),
+  FOREIGN KEY `stock_threshold_FK1` (`product_id`) REFERENCES `product` (`id`)
+);
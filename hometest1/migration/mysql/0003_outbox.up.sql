@@ -0,0 +1,15 @@
+-- events written in the same transaction with their change, dispatcher delivers them at least once
+CREATE TABLE IF NOT EXISTS `outbox` (
+  `id` bigint UNSIGNED NOT NULL AUTO_INCREMENT,
+  `event_type` varchar(50) NOT NULL,
+  `aggregate_id` bigint UNSIGNED NOT NULL DEFAULT 0,
+  `payload` mediumtext NOT NULL,
+  `attempts` int UNSIGNED NOT NULL DEFAULT 0,
+  `next_attempt_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `last_error` varchar(255) NOT NULL DEFAULT '',
+  `delivered_at` timestamp NULL DEFAULT NULL,
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+  PRIMARY KEY (`id`),
+  KEY `outbox_IDX1` (`delivered_at`, `next_attempt_at`)
+);
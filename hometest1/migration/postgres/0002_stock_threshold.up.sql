@@ -0,0 +1,9 @@
+-- reorder threshold of product, alert is sent when checkout takes stock down to the threshold
+CREATE TABLE IF NOT EXISTS "stock_threshold" (
+  "id" bigserial PRIMARY KEY,
+  "product_id" bigint NOT NULL REFERENCES "product" ("id"),
+  "threshold" integer NOT NULL DEFAULT 0,
+  "updated_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "stock_threshold_UNQ1" ON "stock_threshold" ("product_id");
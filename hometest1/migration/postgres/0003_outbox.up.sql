@@ -0,0 +1,14 @@
+-- events written in the same transaction with their change, dispatcher delivers them at least once
+CREATE TABLE IF NOT EXISTS "outbox" (
+  "id" bigserial PRIMARY KEY,
+  "event_type" varchar(50) NOT NULL,
+  "aggregate_id" bigint NOT NULL DEFAULT 0,
+  "payload" text NOT NULL,
+  "attempts" integer NOT NULL DEFAULT 0,
+  "next_attempt_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "last_error" varchar(255) NOT NULL DEFAULT '',
+  "delivered_at" timestamptz NULL,
+  "created_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS "outbox_IDX1" ON "outbox" ("delivered_at", "next_attempt_at");
@@ -0,0 +1,176 @@
+package migration
+
+import (
+	"context"
+
+	"hometest1/core/entity"
+
+	"gorm.io/gorm"
+)
+
+// seed product with initial stock
+type seedProduct struct {
+	Serial   string
+	Name     string
+	Price    int64
+	Quantity int
+}
+
+// seed promotion of product, looked up by serial
+type seedPromotion struct {
+	Type               entity.PromotionType
+	Serial             string
+	MatchQuantity      int
+	PromoValue         int
+	PromoProductSerial string
+}
+
+var seedProducts = []seedProduct{
+	{"120P90", "Google Home", 4999, 10},
+	{"43N23P", "MacBook Pro", 539999, 5},
+	{"A304SD", "Alexa Speaker", 10950, 10},
+	{"234234", "Raspberry Pi B", 3000, 2},
+}
+
+var seedPromotions = []seedPromotion{
+	{entity.BonusItem, "43N23P", 1, 1, "234234"},
+	{entity.BuyItemsForReducePrice, "120P90", 3, 2, ""},
+	{entity.DiscountInPercent, "A304SD", 3, 10, ""},
+}
+
+var seedTaxRegions = []*entity.TaxRegion{
+	{Code: "US-CA", Name: "California", PriceMode: entity.TaxExclusive, Rates: []*entity.TaxRate{
+		{TaxClass: entity.DefaultTaxClass, Rate: 725},
+	}},
+	{Code: "DE", Name: "Germany", PriceMode: entity.TaxInclusive, Rates: []*entity.TaxRate{
+		{TaxClass: entity.DefaultTaxClass, Rate: 1900},
+		{TaxClass: "reduced", Rate: 700},
+	}},
+}
+
+// SeedResult is number of rows created by seed
+type SeedResult struct {
+	Products   int
+	Promotions int
+	TaxRegions int
+}
+
+// Seed insert sample data that does not exist yet, so it is safe to run many times.
+// Products are matched by serial, promotions by product and type, tax regions by code.
+// Existing and soft deleted rows are not changed
+func Seed(ctx context.Context, db *gorm.DB) (*SeedResult, error) {
+	result := &SeedResult{}
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		mapProduct := make(map[string]*entity.Product)
+		for _, seed := range seedProducts {
+			product, created, err := seedProductIfNotExists(tx, seed)
+			if err != nil {
+				return err
+			}
+			mapProduct[seed.Serial] = product
+			if created {
+				result.Products++
+			}
+		}
+
+		for _, seed := range seedPromotions {
+			created, err := seedPromotionIfNotExists(tx, seed, mapProduct)
+			if err != nil {
+				return err
+			}
+			if created {
+				result.Promotions++
+			}
+		}
+
+		for _, seed := range seedTaxRegions {
+			created, err := seedTaxRegionIfNotExists(tx, seed)
+			if err != nil {
+				return err
+			}
+			if created {
+				result.TaxRegions++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func seedProductIfNotExists(tx *gorm.DB, seed seedProduct) (*entity.Product, bool, error) {
+	var product entity.Product
+	err := tx.Unscoped().Where("serial = ?", seed.Serial).Limit(1).Find(&product).Error
+	if err != nil || product.ID != 0 {
+		return &product, false, err
+	}
+
+	product = entity.Product{
+		Serial:   seed.Serial,
+		Name:     seed.Name,
+		Price:    entity.NewMoney(seed.Price),
+		TaxClass: entity.DefaultTaxClass,
+	}
+	err = tx.Create(&product).Error
+	if err != nil {
+		return nil, false, err
+	}
+	err = tx.Create(&entity.ProductQuantity{ProductID: product.ID, Quantity: seed.Quantity}).Error
+	if err != nil {
+		return nil, false, err
+	}
+	err = tx.Create(&entity.StockMovement{
+		ProductID: product.ID,
+		Quantity:  seed.Quantity,
+		Reason:    entity.StockRestock,
+		Reference: "initial stock",
+	}).Error
+	if err != nil {
+		return nil, false, err
+	}
+	return &product, true, nil
+}
+
+func seedPromotionIfNotExists(tx *gorm.DB, seed seedPromotion, mapProduct map[string]*entity.Product) (bool, error) {
+	productID := mapProduct[seed.Serial].ID
+	var count int64
+	err := tx.Unscoped().Model(&entity.Promotion{}).
+		Where("product_id = ? AND type = ?", productID, seed.Type).
+		Count(&count).
+		Error
+	if err != nil || count > 0 {
+		return false, err
+	}
+
+	promo := entity.Promotion{
+		Type:          seed.Type,
+		ProductID:     productID,
+		MatchQuantity: seed.MatchQuantity,
+		PromoValue:    seed.PromoValue,
+	}
+	if seed.PromoProductSerial != "" {
+		promo.PromoProductID = mapProduct[seed.PromoProductSerial].ID
+	}
+	return true, tx.Create(&promo).Error
+}
+
+func seedTaxRegionIfNotExists(tx *gorm.DB, seed *entity.TaxRegion) (bool, error) {
+	var count int64
+	err := tx.Model(&entity.TaxRegion{}).Where("code = ?", seed.Code).Count(&count).Error
+	if err != nil || count > 0 {
+		return false, err
+	}
+
+	region := entity.TaxRegion{Code: seed.Code, Name: seed.Name, PriceMode: seed.PriceMode}
+	err = tx.Create(&region).Error
+	if err != nil {
+		return false, err
+	}
+	var rates []*entity.TaxRate
+	for _, rate := range seed.Rates {
+		rates = append(rates, &entity.TaxRate{TaxRegionID: region.ID, TaxClass: rate.TaxClass, Rate: rate.Rate})
+	}
+	return true, tx.Create(&rates).Error
+}
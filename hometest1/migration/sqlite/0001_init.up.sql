@@ -0,0 +1,234 @@
+-- baseline schema, same tables as mysql migration.
+-- existing tables are kept
+CREATE TABLE IF NOT EXISTS "product" (
+  "id" integer PRIMARY KEY AUTOINCREMENT,
+  "serial" varchar(20) NOT NULL,
+  "name" varchar(255) NOT NULL,
+  "price" decimal(10,2) NOT NULL DEFAULT 0,
+  "tax_class" varchar(50) NOT NULL DEFAULT 'standard',
+  "updated_at" datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "deleted_at" datetime NULL DEFAULT NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "product_UNQ1" ON "product" ("serial");
+
+CREATE TABLE IF NOT EXISTS "product_quantity" (
+  "id" integer PRIMARY KEY AUTOINCREMENT,
+  "product_id" integer NOT NULL REFERENCES "product" ("id"),
+  "quantity" integer NOT NULL DEFAULT 0,
+  "updated_at" datetime NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS "promotion" (
+  "id" integer PRIMARY KEY AUTOINCREMENT,
+  "type" integer NOT NULL,
+  "product_id" integer NOT NULL REFERENCES "product" ("id"),
+  "match_quantity" integer NOT NULL DEFAULT 0,
+  "promo_value" integer NOT NULL DEFAULT 0,
+  "promo_product_id" integer NOT NULL DEFAULT 0,
+  "priority" integer NOT NULL DEFAULT 0,
+  "stacking" integer NOT NULL DEFAULT 0,
+  "starts_at" datetime NULL DEFAULT NULL,
+  "ends_at" datetime NULL DEFAULT NULL,
+  "updated_at" datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "deleted_at" datetime NULL DEFAULT NULL
+);
+CREATE INDEX IF NOT EXISTS "promotion_IDX1" ON "promotion" ("promo_product_id");
+CREATE INDEX IF NOT EXISTS "promotion_IDX2" ON "promotion" ("product_id", "starts_at", "ends_at");
+
+CREATE TABLE IF NOT EXISTS "cart" (
+  "id" integer PRIMARY KEY AUTOINCREMENT,
+  "status" integer NOT NULL DEFAULT 1,
+  "updated_at" datetime NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS "cart_item" (
+  "id" integer PRIMARY KEY AUTOINCREMENT,
+  "cart_id" integer NOT NULL REFERENCES "cart" ("id"),
+  "product_id" integer NOT NULL REFERENCES "product" ("id"),
+  "quantity" integer NOT NULL DEFAULT 0,
+  "updated_at" datetime NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "cart_item_UNQ1" ON "cart_item" ("cart_id", "product_id");
+
+CREATE TABLE IF NOT EXISTS "order" (
+  "id" integer PRIMARY KEY AUTOINCREMENT,
+  "status" integer NOT NULL DEFAULT 1,
+  "total_item" integer NOT NULL DEFAULT 0,
+  "total_price" decimal(10,2) NOT NULL DEFAULT 0,
+  "discount_price" decimal(10,2) NOT NULL DEFAULT 0,
+  "refunded_price" decimal(10,2) NOT NULL DEFAULT 0,
+  "tax_region" varchar(10) NOT NULL DEFAULT '',
+  "tax_price_mode" integer NOT NULL DEFAULT 0,
+  "tax_price" decimal(10,2) NOT NULL DEFAULT 0,
+  "created_at" datetime NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS "order_item" (
+  "id" integer PRIMARY KEY AUTOINCREMENT,
+  "order_id" integer NOT NULL REFERENCES "order" ("id"),
+  "product_id" integer NOT NULL REFERENCES "product" ("id"),
+  "unit_price" decimal(10,2) NOT NULL DEFAULT 0,
+  "quantity" integer NOT NULL DEFAULT 0,
+  "sub_total_price" decimal(10,2) NOT NULL DEFAULT 0,
+  "promotion_id" integer NOT NULL DEFAULT 0,
+  "bundle_id" integer NOT NULL DEFAULT 0,
+  "returned_quantity" integer NOT NULL DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS "order_item_IDX1" ON "order_item" ("promotion_id");
+
+CREATE TABLE IF NOT EXISTS "stock_movement" (
+  "id" integer PRIMARY KEY AUTOINCREMENT,
+  "product_id" integer NOT NULL REFERENCES "product" ("id"),
+  "quantity" integer NOT NULL DEFAULT 0,
+  "reason" integer NOT NULL DEFAULT 0,
+  "reference" varchar(100) NOT NULL DEFAULT '',
+  "created_at" datetime NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS "stock_movement_IDX1" ON "stock_movement" ("product_id", "created_at");
+
+CREATE TABLE IF NOT EXISTS "cart_promotion" (
+  "id" integer PRIMARY KEY AUTOINCREMENT,
+  "type" integer NOT NULL,
+  "min_spend" decimal(10,2) NOT NULL DEFAULT 0,
+  "promo_value" integer NOT NULL DEFAULT 0,
+  "promo_amount" decimal(10,2) NOT NULL DEFAULT 0,
+  "promo_product_id" integer NOT NULL DEFAULT 0,
+  "priority" integer NOT NULL DEFAULT 0,
+  "stacking" integer NOT NULL DEFAULT 0,
+  "starts_at" datetime NULL DEFAULT NULL,
+  "ends_at" datetime NULL DEFAULT NULL,
+  "updated_at" datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "deleted_at" datetime NULL DEFAULT NULL
+);
+CREATE INDEX IF NOT EXISTS "cart_promotion_IDX1" ON "cart_promotion" ("starts_at", "ends_at");
+
+CREATE TABLE IF NOT EXISTS "coupon" (
+  "id" integer PRIMARY KEY AUTOINCREMENT,
+  "code" varchar(50) NOT NULL,
+  "cart_promotion_id" integer NOT NULL REFERENCES "cart_promotion" ("id"),
+  "max_redemptions" integer NOT NULL DEFAULT 0,
+  "per_customer_limit" integer NOT NULL DEFAULT 0,
+  "redeemed_count" integer NOT NULL DEFAULT 0,
+  "expires_at" datetime NULL DEFAULT NULL,
+  "updated_at" datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "deleted_at" datetime NULL DEFAULT NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "coupon_UNQ1" ON "coupon" ("code");
+
+CREATE TABLE IF NOT EXISTS "coupon_redemption" (
+  "id" integer PRIMARY KEY AUTOINCREMENT,
+  "coupon_id" integer NOT NULL REFERENCES "coupon" ("id"),
+  "order_id" integer NOT NULL REFERENCES "order" ("id"),
+  "customer_id" varchar(100) NOT NULL DEFAULT '',
+  "created_at" datetime NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS "coupon_redemption_IDX1" ON "coupon_redemption" ("coupon_id", "customer_id");
+
+CREATE TABLE IF NOT EXISTS "bundle" (
+  "id" integer PRIMARY KEY AUTOINCREMENT,
+  "type" integer NOT NULL,
+  "name" varchar(100) NOT NULL DEFAULT '',
+  "price" decimal(10,2) NOT NULL DEFAULT 0,
+  "match_quantity" integer NOT NULL DEFAULT 0,
+  "promo_value" integer NOT NULL DEFAULT 0,
+  "priority" integer NOT NULL DEFAULT 0,
+  "starts_at" datetime NULL DEFAULT NULL,
+  "ends_at" datetime NULL DEFAULT NULL,
+  "updated_at" datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "deleted_at" datetime NULL DEFAULT NULL
+);
+CREATE INDEX IF NOT EXISTS "bundle_IDX1" ON "bundle" ("starts_at", "ends_at");
+
+CREATE TABLE IF NOT EXISTS "bundle_item" (
+  "id" integer PRIMARY KEY AUTOINCREMENT,
+  "bundle_id" integer NOT NULL REFERENCES "bundle" ("id"),
+  "product_id" integer NOT NULL REFERENCES "product" ("id"),
+  "quantity" integer NOT NULL DEFAULT 0
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "bundle_item_UNQ1" ON "bundle_item" ("bundle_id", "product_id");
+CREATE INDEX IF NOT EXISTS "bundle_item_IDX1" ON "bundle_item" ("product_id");
+
+CREATE TABLE IF NOT EXISTS "idempotency_key" (
+  "id" integer PRIMARY KEY AUTOINCREMENT,
+  "key" varchar(255) NOT NULL,
+  "request_hash" char(64) NOT NULL,
+  "response_code" integer NOT NULL DEFAULT 0,
+  "response_body" text NOT NULL,
+  "created_at" datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" datetime NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idempotency_key_UNQ1" ON "idempotency_key" ("key");
+
+CREATE TABLE IF NOT EXISTS "reservation" (
+  "id" integer PRIMARY KEY AUTOINCREMENT,
+  "status" integer NOT NULL DEFAULT 0,
+  "customer_id" varchar(100) NOT NULL DEFAULT '',
+  "order_id" integer NOT NULL DEFAULT 0,
+  "expires_at" datetime NOT NULL,
+  "created_at" datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" datetime NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS "reservation_IDX1" ON "reservation" ("status", "expires_at");
+
+CREATE TABLE IF NOT EXISTS "reservation_item" (
+  "id" integer PRIMARY KEY AUTOINCREMENT,
+  "reservation_id" integer NOT NULL,
+  "product_id" integer NOT NULL,
+  "quantity" integer NOT NULL DEFAULT 0,
+  "ordered_quantity" integer NOT NULL DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS "reservation_item_IDX1" ON "reservation_item" ("reservation_id");
+CREATE INDEX IF NOT EXISTS "reservation_item_IDX2" ON "reservation_item" ("product_id");
+
+CREATE TABLE IF NOT EXISTS "order_return" (
+  "id" integer PRIMARY KEY AUTOINCREMENT,
+  "order_id" integer NOT NULL REFERENCES "order" ("id"),
+  "refund_price" decimal(10,2) NOT NULL DEFAULT 0,
+  "created_at" datetime NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS "order_return_item" (
+  "id" integer PRIMARY KEY AUTOINCREMENT,
+  "order_return_id" integer NOT NULL REFERENCES "order_return" ("id"),
+  "order_item_id" integer NOT NULL REFERENCES "order_item" ("id"),
+  "product_id" integer NOT NULL REFERENCES "product" ("id"),
+  "quantity" integer NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS "order_discount" (
+  "id" integer PRIMARY KEY AUTOINCREMENT,
+  "order_id" integer NOT NULL REFERENCES "order" ("id"),
+  "product_id" integer NOT NULL DEFAULT 0,
+  "source" varchar(20) NOT NULL,
+  "source_id" integer NOT NULL,
+  "amount" decimal(10,2) NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS "order_discount_IDX1" ON "order_discount" ("order_id");
+
+CREATE TABLE IF NOT EXISTS "tax_region" (
+  "id" integer PRIMARY KEY AUTOINCREMENT,
+  "code" varchar(10) NOT NULL,
+  "name" varchar(255) NOT NULL,
+  "price_mode" integer NOT NULL DEFAULT 1,
+  "is_default" boolean NOT NULL DEFAULT 0,
+  "updated_at" datetime NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "tax_region_UNQ1" ON "tax_region" ("code");
+
+CREATE TABLE IF NOT EXISTS "tax_rate" (
+  "id" integer PRIMARY KEY AUTOINCREMENT,
+  "tax_region_id" integer NOT NULL REFERENCES "tax_region" ("id"),
+  "tax_class" varchar(50) NOT NULL,
+  "rate" integer NOT NULL DEFAULT 0
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "tax_rate_UNQ1" ON "tax_rate" ("tax_region_id", "tax_class");
+
+CREATE TABLE IF NOT EXISTS "order_tax" (
+  "id" integer PRIMARY KEY AUTOINCREMENT,
+  "order_id" integer NOT NULL REFERENCES "order" ("id"),
+  "tax_class" varchar(50) NOT NULL,
+  "rate" integer NOT NULL DEFAULT 0,
+  "taxable_price" decimal(10,2) NOT NULL DEFAULT 0,
+  "tax_price" decimal(10,2) NOT NULL DEFAULT 0
+);
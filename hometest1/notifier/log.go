@@ -0,0 +1,24 @@
+// Package notifier delivers stock alerts, notifiers can be wrapped with Retry and Queue
+package notifier
+
+import (
+	"context"
+	"log"
+
+	"hometest1/core/entity"
+)
+
+// Log write stock alert into the logger
+type Log struct {
+	logger *log.Logger
+}
+
+func NewLog(logger *log.Logger) *Log {
+	return &Log{logger}
+}
+
+func (n *Log) Notify(ctx context.Context, alert *entity.StockAlert) error {
+	n.logger.Printf("Low stock: %s(%s) has %d items, reorder threshold is %d, order %d",
+		alert.Product.Name, alert.Product.Serial, alert.Quantity, alert.Threshold, alert.OrderID)
+	return nil
+}
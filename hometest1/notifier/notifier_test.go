@@ -0,0 +1,132 @@
+package notifier_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"hometest1/core/entity"
+	"hometest1/notifier"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// notifier that fails the first attempts, then sends alert into channel
+type flakyNotifier struct {
+	failures int
+	calls    int
+	alerts   chan *entity.StockAlert
+}
+
+func (n *flakyNotifier) Notify(ctx context.Context, alert *entity.StockAlert) error {
+	n.calls++
+	if n.calls <= n.failures {
+		return errors.New("connection refused")
+	}
+	n.alerts <- alert
+	return nil
+}
+
+var alert = &entity.StockAlert{
+	Product:   &entity.Product{ID: 4, Serial: "234234", Name: "Raspberry Pi B"},
+	Quantity:  2,
+	Threshold: 3,
+	OrderID:   7,
+	CreatedAt: time.Date(2023, 5, 16, 12, 0, 0, 0, time.UTC),
+}
+
+func Test_Webhook(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("positive", func(t *testing.T) {
+		var body map[string]interface{}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			data, _ := io.ReadAll(r.Body)
+			_ = json.Unmarshal(data, &body)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		err := notifier.NewWebhook(server.URL, time.Second).Notify(ctx, alert)
+		assert.Nil(t, err)
+		assert.Equal(t, map[string]interface{}{
+			"event":     "stock.low",
+			"serial":    "234234",
+			"name":      "Raspberry Pi B",
+			"quantity":  float64(2),
+			"threshold": float64(3),
+			"orderId":   float64(7),
+			"createdAt": "2023-05-16T12:00:00Z",
+		}, body)
+	})
+
+	t.Run("negative, webhook responds error", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer server.Close()
+
+		err := notifier.NewWebhook(server.URL, time.Second).Notify(ctx, alert)
+		assert.EqualError(t, err, "webhook responds 502 Bad Gateway")
+	})
+}
+
+func Test_Retry(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("positive, succeed on the last attempt", func(t *testing.T) {
+		next := &flakyNotifier{failures: 2, alerts: make(chan *entity.StockAlert, 1)}
+		err := notifier.NewRetry(next, 3, time.Millisecond).Notify(ctx, alert)
+		assert.Nil(t, err)
+		assert.Equal(t, 3, next.calls)
+	})
+
+	t.Run("negative, every attempt fails", func(t *testing.T) {
+		next := &flakyNotifier{failures: 3}
+		err := notifier.NewRetry(next, 2, time.Millisecond).Notify(ctx, alert)
+		assert.EqualError(t, err, "failed after 2 attempts: connection refused")
+		assert.Equal(t, 2, next.calls)
+	})
+
+	t.Run("negative, cancelled while waiting", func(t *testing.T) {
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+		next := &flakyNotifier{failures: 3}
+		err := notifier.NewRetry(next, 3, time.Hour).Notify(cancelled, alert)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, 1, next.calls)
+	})
+}
+
+func Test_Queue(t *testing.T) {
+	var logs bytes.Buffer
+	logger := log.New(&logs, "", 0)
+
+	t.Run("positive, deliver in background", func(t *testing.T) {
+		next := &flakyNotifier{alerts: make(chan *entity.StockAlert, 1)}
+		err := notifier.NewQueue(next, 1, logger).Notify(context.Background(), alert)
+		assert.Nil(t, err)
+
+		select {
+		case delivered := <-next.alerts:
+			assert.Equal(t, alert, delivered)
+		case <-time.After(time.Second):
+			t.Fatal("alert is not delivered")
+		}
+	})
+
+	t.Run("positive, log", func(t *testing.T) {
+		err := notifier.NewLog(logger).Notify(context.Background(), alert)
+		assert.Nil(t, err)
+		assert.Contains(t, logs.String(), "Low stock: Raspberry Pi B(234234) has 2 items, reorder threshold is 3, order 7")
+	})
+}
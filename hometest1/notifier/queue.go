@@ -0,0 +1,47 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"hometest1/core/entity"
+	"hometest1/core/module"
+)
+
+var ErrQueueFull = errors.New("stock alert queue is full")
+
+// Queue deliver alerts in background, so checkout does not wait for slow notifier.
+// Failed delivery is logged, alert is dropped if the queue is full
+type Queue struct {
+	next   module.StockAlertNotifier
+	alerts chan *entity.StockAlert
+	logger *log.Logger
+}
+
+// create queue with size of buffered alerts and start delivering them
+func NewQueue(next module.StockAlertNotifier, size int, logger *log.Logger) *Queue {
+	queue := &Queue{next, make(chan *entity.StockAlert, size), logger}
+	go queue.run()
+	return queue
+}
+
+func (n *Queue) Notify(ctx context.Context, alert *entity.StockAlert) error {
+	select {
+	case n.alerts <- alert:
+		return nil
+	default:
+		n.logger.Printf("Error sending stock alert of %s: %s", alert.Product.Serial, ErrQueueFull.Error())
+		return ErrQueueFull
+	}
+}
+
+func (n *Queue) run() {
+	for alert := range n.alerts {
+		// alert outlives the request that creates it
+		err := n.next.Notify(context.Background(), alert)
+		if err != nil {
+			n.logger.Printf("Error sending stock alert of %s: %s", alert.Product.Serial, err.Error())
+		}
+	}
+}
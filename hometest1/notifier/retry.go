@@ -0,0 +1,43 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"hometest1/core/entity"
+	"hometest1/core/module"
+)
+
+// Retry notify again after failure, delay is doubled after every failed attempt
+type Retry struct {
+	next module.StockAlertNotifier
+	// number of attempts including the first one
+	attempts int
+	backoff  time.Duration
+}
+
+// backoff is delay before the second attempt
+func NewRetry(next module.StockAlertNotifier, attempts int, backoff time.Duration) *Retry {
+	return &Retry{next, max(attempts, 1), backoff}
+}
+
+func (n *Retry) Notify(ctx context.Context, alert *entity.StockAlert) error {
+	delay := n.backoff
+	for attempt := 1; ; attempt++ {
+		err := n.next.Notify(ctx, alert)
+		if err == nil {
+			return nil
+		}
+		if attempt == n.attempts {
+			return fmt.Errorf("failed after %d attempts: %w", attempt, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
@@ -0,0 +1,66 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"hometest1/core/entity"
+)
+
+// StockAlertEvent is name of stock alert event in webhook payload
+const StockAlertEvent = "stock.low"
+
+// Webhook post stock alert as json, response other than 2xx is an error
+type Webhook struct {
+	url    string
+	client *http.Client
+}
+
+type webhookPayload struct {
+	Event     string    `json:"event"`
+	Serial    string    `json:"serial"`
+	Name      string    `json:"name"`
+	Quantity  int       `json:"quantity"`
+	Threshold int       `json:"threshold"`
+	OrderID   int64     `json:"orderId"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// timeout is for each request, including reading the response
+func NewWebhook(url string, timeout time.Duration) *Webhook {
+	return &Webhook{url, &http.Client{Timeout: timeout}}
+}
+
+func (n *Webhook) Notify(ctx context.Context, alert *entity.StockAlert) error {
+	body, err := json.Marshal(webhookPayload{
+		Event:     StockAlertEvent,
+		Serial:    alert.Product.Serial,
+		Name:      alert.Product.Name,
+		Quantity:  alert.Quantity,
+		Threshold: alert.Threshold,
+		OrderID:   alert.OrderID,
+		CreatedAt: alert.CreatedAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook responds %s", resp.Status)
+	}
+	return nil
+}
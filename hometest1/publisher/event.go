@@ -0,0 +1,28 @@
+// Package publisher delivers outbox events to webhook, file or stdout, sinks can be combined with Fanout
+package publisher
+
+import (
+	"encoding/json"
+	"time"
+
+	"hometest1/core/entity"
+)
+
+// event is the published json, consumer deduplicates redelivered event by its id
+type event struct {
+	ID          int64           `json:"id"`
+	Type        string          `json:"type"`
+	AggregateID int64           `json:"aggregateId"`
+	CreatedAt   time.Time       `json:"createdAt"`
+	Data        json.RawMessage `json:"data"`
+}
+
+func marshalEvent(outbox *entity.Outbox) ([]byte, error) {
+	return json.Marshal(event{
+		ID:          outbox.ID,
+		Type:        outbox.EventType,
+		AggregateID: outbox.AggregateID,
+		CreatedAt:   outbox.CreatedAt,
+		Data:        json.RawMessage(outbox.Payload),
+	})
+}
@@ -0,0 +1,30 @@
+package publisher
+
+import (
+	"context"
+	"errors"
+
+	"hometest1/core/entity"
+	"hometest1/core/module"
+)
+
+// Fanout publish event to every sink, event is failed if any sink fails.
+// Failed event is published again to every sink, so other sinks may receive it twice
+type Fanout struct {
+	sinks []module.OutboxSink
+}
+
+func NewFanout(sinks ...module.OutboxSink) *Fanout {
+	return &Fanout{sinks}
+}
+
+func (p *Fanout) Publish(ctx context.Context, outbox *entity.Outbox) error {
+	var errs []error
+	for _, sink := range p.sinks {
+		err := sink.Publish(ctx, outbox)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,106 @@
+package publisher_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"hometest1/core/entity"
+	"hometest1/publisher"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// sink that always fails
+type failingSink struct{}
+
+func (failingSink) Publish(ctx context.Context, outbox *entity.Outbox) error {
+	return errors.New("connection refused")
+}
+
+var outbox = &entity.Outbox{
+	ID:          3,
+	EventType:   entity.OrderPlacedEvent,
+	AggregateID: 7,
+	Payload:     `{"orderId":7}`,
+	CreatedAt:   time.Date(2023, 5, 16, 12, 0, 0, 0, time.UTC),
+}
+
+const eventJSON = `{"id":3,"type":"OrderPlaced","aggregateId":7,"createdAt":"2023-05-16T12:00:00Z","data":{"orderId":7}}`
+
+func Test_Webhook(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("positive", func(t *testing.T) {
+		var body []byte
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			body, _ = io.ReadAll(r.Body)
+			w.WriteHeader(http.StatusAccepted)
+		}))
+		defer server.Close()
+
+		err := publisher.NewWebhook(server.URL, time.Second).Publish(ctx, outbox)
+		assert.Nil(t, err)
+		assert.Equal(t, eventJSON, string(body))
+	})
+
+	t.Run("negative, webhook responds error", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		err := publisher.NewWebhook(server.URL, time.Second).Publish(ctx, outbox)
+		assert.EqualError(t, err, "webhook responds 503 Service Unavailable")
+	})
+}
+
+func Test_Writer(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("positive, one json line for each event", func(t *testing.T) {
+		var buf bytes.Buffer
+		sink := publisher.NewWriter(&buf)
+		assert.Nil(t, sink.Publish(ctx, outbox))
+		assert.Nil(t, sink.Publish(ctx, outbox))
+		assert.Equal(t, eventJSON+"\n"+eventJSON+"\n", buf.String())
+	})
+
+	t.Run("positive, file is appended", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "events.jsonl")
+		err := os.WriteFile(path, []byte(eventJSON+"\n"), 0644)
+		assert.Nil(t, err)
+
+		sink, err := publisher.NewFile(path)
+		assert.Nil(t, err)
+		assert.Nil(t, sink.Publish(ctx, outbox))
+		data, err := os.ReadFile(path)
+		assert.Nil(t, err)
+		assert.Equal(t, eventJSON+"\n"+eventJSON+"\n", string(data))
+	})
+
+	t.Run("negative, directory does not exist", func(t *testing.T) {
+		_, err := publisher.NewFile(filepath.Join(t.TempDir(), "missing", "events.jsonl"))
+		assert.NotNil(t, err)
+	})
+}
+
+func Test_Fanout(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("negative, other sinks still receive the event", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := publisher.NewFanout(failingSink{}, publisher.NewWriter(&buf)).Publish(ctx, outbox)
+		assert.EqualError(t, err, "connection refused")
+		assert.Equal(t, eventJSON+"\n", buf.String())
+	})
+}
@@ -0,0 +1,44 @@
+package publisher
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"hometest1/core/entity"
+)
+
+// Webhook post event as json, response other than 2xx is an error
+type Webhook struct {
+	url    string
+	client *http.Client
+}
+
+// timeout is for each request, including reading the response
+func NewWebhook(url string, timeout time.Duration) *Webhook {
+	return &Webhook{url, &http.Client{Timeout: timeout}}
+}
+
+func (p *Webhook) Publish(ctx context.Context, outbox *entity.Outbox) error {
+	body, err := marshalEvent(outbox)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook responds %s", resp.Status)
+	}
+	return nil
+}
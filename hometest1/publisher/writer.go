@@ -0,0 +1,50 @@
+package publisher
+
+import (
+	"context"
+	"io"
+	"os"
+	"sync"
+
+	"hometest1/core/entity"
+)
+
+// Writer write every event as one json line, eg: stdout or file
+type Writer struct {
+	mu sync.Mutex
+	w  io.Writer
+	// only for file, nil for other writer
+	file *os.File
+}
+
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+// NewFile append events to the file, the file is created if it does not exist
+func NewFile(path string) (*Writer, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &Writer{w: file, file: file}, nil
+}
+
+func (p *Writer) Publish(ctx context.Context, outbox *entity.Outbox) error {
+	line, err := marshalEvent(outbox)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_, err = p.w.Write(append(line, '\n'))
+	if err != nil {
+		return err
+	}
+	// event is marked delivered after publish, so it must be on disk
+	if p.file != nil {
+		return p.file.Sync()
+	}
+	return nil
+}
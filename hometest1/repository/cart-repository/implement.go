@@ -0,0 +1,57 @@
+package cartrepository
+
+import (
+	"context"
+	"hometest1/core/entity"
+	"hometest1/core/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type repo struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) repository.CartRepo {
+	return &repo{db}
+}
+
+func (r *repo) CreateCart(ctx context.Context) (*entity.Cart, error) {
+	cart := entity.Cart{Status: entity.CartOpen}
+	err := r.db.WithContext(ctx).Create(&cart).Error
+	if err != nil {
+		return nil, err
+	}
+	return &cart, nil
+}
+
+func (r *repo) GetCart(ctx context.Context, id int64) (*entity.Cart, error) {
+	var cart entity.Cart
+	err := r.db.WithContext(ctx).Where("id = ?", id).Limit(1).Find(&cart).Error
+	if err != nil {
+		return nil, err
+	}
+	if cart.ID == 0 {
+		return nil, nil
+	}
+
+	// get cart items
+	err = r.db.WithContext(ctx).Where("cart_id = ?", id).Order("id asc").Find(&cart.Items).Error
+	if err != nil {
+		return nil, err
+	}
+	return &cart, nil
+}
+
+func (r *repo) SaveCartItem(ctx context.Context, item *entity.CartItem) error {
+	// cart_item has unique key on cart_id and product_id, mysql ignores the conflict columns
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "cart_id"}, {Name: "product_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"quantity", "updated_at"}),
+	}).Create(item).Error
+}
+
+func (r *repo) DeleteCartItem(ctx context.Context, cartID int64, productID int64) error {
+	return r.db.WithContext(ctx).Where("cart_id = ? and product_id = ?", cartID, productID).Delete(&entity.CartItem{}).Error
+}
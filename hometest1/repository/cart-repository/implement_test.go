@@ -0,0 +1,126 @@
+package cartrepository_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"regexp"
+	"testing"
+	"time"
+
+	"hometest1/core/entity"
+	"hometest1/core/repository"
+	cartrepository "hometest1/repository/cart-repository"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/schema"
+)
+
+type AnyTime struct{}
+
+// Match satisfies sqlmock.Argument interface
+func (a AnyTime) Match(v driver.Value) bool {
+	_, ok := v.(time.Time)
+	return ok
+}
+
+func initRepo(db *sql.DB, mock sqlmock.Sqlmock) (repository.CartRepo, error) {
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT VERSION()")).
+		WillReturnRows(sqlmock.NewRows([]string{"VERSION()"}).AddRow("5.7.25-log"))
+	gdb, err := gorm.Open(mysql.New(mysql.Config{
+		Conn: db,
+	}), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.LogLevel(logger.Info)),
+		NamingStrategy: schema.NamingStrategy{
+			SingularTable: true,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cartrepository.New(gdb), nil
+}
+
+func Test_GetCart(t *testing.T) {
+	// mock db
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	defer db.Close()
+
+	// init repo
+	repo, err := initRepo(db, mock)
+	if err != nil {
+		t.Errorf("error initRepo: %s", err.Error())
+		return
+	}
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+
+	t.Run("positive", func(t *testing.T) {
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `cart` WHERE id = ? LIMIT ?")).
+			WithArgs(1, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "status", "updated_at"}).AddRow(1, 1, dayCreated))
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `cart_item` WHERE cart_id = ? ORDER BY id asc")).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "cart_id", "product_id", "quantity", "updated_at"}).
+				AddRow(1, 1, 2, 1, dayCreated).
+				AddRow(2, 1, 4, 2, dayCreated))
+
+		resp, err := repo.GetCart(context.Background(), 1)
+		assert.Nil(t, err)
+		assert.Equal(t, &entity.Cart{
+			ID:        1,
+			Status:    entity.CartOpen,
+			UpdatedAt: dayCreated,
+			Items: []*entity.CartItem{
+				{ID: 1, CartID: 1, ProductID: 2, Quantity: 1, UpdatedAt: dayCreated},
+				{ID: 2, CartID: 1, ProductID: 4, Quantity: 2, UpdatedAt: dayCreated},
+			},
+		}, resp)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `cart` WHERE id = ? LIMIT ?")).
+			WithArgs(2, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "status", "updated_at"}))
+
+		resp, err := repo.GetCart(context.Background(), 2)
+		assert.Nil(t, err)
+		assert.Nil(t, resp)
+	})
+}
+
+func Test_SaveCartItem(t *testing.T) {
+	// mock db
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	defer db.Close()
+
+	// init repo
+	repo, err := initRepo(db, mock)
+	if err != nil {
+		t.Errorf("error initRepo: %s", err.Error())
+		return
+	}
+
+	t.Run("positive", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `cart_item` (`cart_id`,`product_id`,`quantity`,`updated_at`) VALUES (?,?,?,?) ON DUPLICATE KEY UPDATE `quantity`=VALUES(`quantity`),`updated_at`=VALUES(`updated_at`)")).
+			WithArgs(1, 2, 3, AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		err := repo.SaveCartItem(context.Background(), &entity.CartItem{CartID: 1, ProductID: 2, Quantity: 3})
+		assert.Nil(t, err)
+	})
+}
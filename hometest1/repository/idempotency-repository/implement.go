@@ -0,0 +1,57 @@
+package idempotencyrepository
+
+import (
+	"context"
+	"errors"
+
+	"hometest1/core/entity"
+	"hometest1/core/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type repo struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) repository.IdempotencyRepo {
+	return &repo{db}
+}
+
+func (r *repo) CreateKey(ctx context.Context, key *entity.IdempotencyKey) (bool, error) {
+	// unique key makes concurrent requests with the same key fail here
+	err := r.db.WithContext(ctx).Create(key).Error
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// key is reserved word, column is quoted by the dialector
+func (r *repo) GetKey(ctx context.Context, key string) (*entity.IdempotencyKey, error) {
+	var result entity.IdempotencyKey
+	err := r.db.WithContext(ctx).Where(clause.Eq{Column: "key", Value: key}).Limit(1).Find(&result).Error
+	if err != nil {
+		return nil, err
+	}
+	if result.ID == 0 {
+		return nil, nil
+	}
+	return &result, nil
+}
+
+func (r *repo) SaveResponse(ctx context.Context, key *entity.IdempotencyKey) error {
+	return r.db.WithContext(ctx).Model(key).Select("response_code", "response_body", "updated_at").Updates(key).Error
+}
+
+func (r *repo) DeleteKey(ctx context.Context, key *entity.IdempotencyKey) (bool, error) {
+	result := r.db.WithContext(ctx).Where("id = ? AND response_code = ?", key.ID, 0).Delete(&entity.IdempotencyKey{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
@@ -0,0 +1,181 @@
+package idempotencyrepository_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"regexp"
+	"testing"
+	"time"
+
+	"hometest1/core/entity"
+	"hometest1/core/repository"
+	idempotencyrepository "hometest1/repository/idempotency-repository"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/schema"
+)
+
+type AnyTime struct{}
+
+// Match satisfies sqlmock.Argument interface
+func (a AnyTime) Match(v driver.Value) bool {
+	_, ok := v.(time.Time)
+	return ok
+}
+
+func initRepo(db *sql.DB, mock sqlmock.Sqlmock) (repository.IdempotencyRepo, error) {
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT VERSION()")).
+		WillReturnRows(sqlmock.NewRows([]string{"VERSION()"}).AddRow("5.7.25-log"))
+	gdb, err := gorm.Open(mysql.New(mysql.Config{
+		Conn: db,
+	}), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.LogLevel(logger.Info)),
+		NamingStrategy: schema.NamingStrategy{
+			SingularTable: true,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return idempotencyrepository.New(gdb), nil
+}
+
+func Test_CreateKey(t *testing.T) {
+	// mock db
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	defer db.Close()
+
+	// init repo
+	repo, err := initRepo(db, mock)
+	if err != nil {
+		t.Errorf("error initRepo: %s", err.Error())
+		return
+	}
+	query := "INSERT INTO `idempotency_key` (`key`,`request_hash`,`response_code`,`response_body`,`created_at`,`updated_at`) VALUES (?,?,?,?,?,?)"
+
+	t.Run("positive", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(query)).
+			WithArgs("pos-1", "abc", 0, "", AnyTime{}, AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		key := &entity.IdempotencyKey{Key: "pos-1", RequestHash: "abc"}
+		created, err := repo.CreateKey(context.Background(), key)
+		assert.Nil(t, err)
+		assert.True(t, created)
+		assert.Equal(t, int64(1), key.ID)
+	})
+
+	t.Run("positive, key already exists", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(query)).
+			WithArgs("pos-1", "abc", 0, "", AnyTime{}, AnyTime{}).
+			WillReturnError(gorm.ErrDuplicatedKey)
+		mock.ExpectRollback()
+
+		created, err := repo.CreateKey(context.Background(), &entity.IdempotencyKey{Key: "pos-1", RequestHash: "abc"})
+		assert.Nil(t, err)
+		assert.False(t, created)
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func Test_GetKey(t *testing.T) {
+	// mock db
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	defer db.Close()
+
+	// init repo
+	repo, err := initRepo(db, mock)
+	if err != nil {
+		t.Errorf("error initRepo: %s", err.Error())
+		return
+	}
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+	query := "SELECT * FROM `idempotency_key` WHERE `key` = ? LIMIT ?"
+
+	t.Run("positive", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta(query)).
+			WithArgs("pos-1", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "key", "request_hash", "response_code", "response_body", "created_at", "updated_at"}).
+				AddRow(1, "pos-1", "abc", 200, `{"orderId":1}`, dayCreated, dayCreated))
+
+		resp, err := repo.GetKey(context.Background(), "pos-1")
+		assert.Nil(t, err)
+		assert.Equal(t, &entity.IdempotencyKey{ID: 1, Key: "pos-1", RequestHash: "abc", ResponseCode: 200, ResponseBody: `{"orderId":1}`, CreatedAt: dayCreated, UpdatedAt: dayCreated}, resp)
+	})
+
+	t.Run("positive, not found", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta(query)).
+			WithArgs("pos-2", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		resp, err := repo.GetKey(context.Background(), "pos-2")
+		assert.Nil(t, err)
+		assert.Nil(t, resp)
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func Test_DeleteKey(t *testing.T) {
+	// mock db
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	defer db.Close()
+
+	// init repo
+	repo, err := initRepo(db, mock)
+	if err != nil {
+		t.Errorf("error initRepo: %s", err.Error())
+		return
+	}
+	query := "DELETE FROM `idempotency_key` WHERE id = ? AND response_code = ?"
+
+	t.Run("positive, in progress key is deleted", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(query)).
+			WithArgs(1, 0).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		deleted, err := repo.DeleteKey(context.Background(), &entity.IdempotencyKey{ID: 1, Key: "pos-1"})
+		assert.Nil(t, err)
+		assert.True(t, deleted)
+	})
+
+	t.Run("negative, key is taken over by other request", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(query)).
+			WithArgs(2, 0).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		deleted, err := repo.DeleteKey(context.Background(), &entity.IdempotencyKey{ID: 2, Key: "neg-1"})
+		assert.Nil(t, err)
+		assert.False(t, deleted)
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,125 @@
+package inventoryrepository
+
+import (
+	"context"
+	"hometest1/core/entity"
+	"hometest1/core/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type repo struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) repository.InventoryRepo {
+	return &repo{db}
+}
+
+func (r *repo) Restock(ctx context.Context, productID int64, quantity int, reference string) (*entity.ProductQuantity, error) {
+	var result entity.ProductQuantity
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// lock for update product quantity
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("product_id = ?", productID).
+			Limit(1).
+			Find(&result).
+			Error
+		if err != nil {
+			return err
+		}
+
+		// product without quantity row is treated as empty stock
+		result.ProductID = productID
+		result.Quantity += quantity
+		err = tx.Save(&result).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(&entity.StockMovement{
+			ProductID: productID,
+			Quantity:  quantity,
+			Reason:    entity.StockRestock,
+			Reference: reference,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (r *repo) SetStock(ctx context.Context, productID int64, quantity int, reference string) (*entity.ProductQuantity, error) {
+	var result entity.ProductQuantity
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// lock for update product quantity, so checkout in between is not lost from the movement
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("product_id = ?", productID).
+			Limit(1).
+			Find(&result).
+			Error
+		if err != nil {
+			return err
+		}
+
+		delta := quantity - result.Quantity
+		if delta == 0 && result.ID != 0 {
+			return nil
+		}
+		result.ProductID = productID
+		result.Quantity = quantity
+		err = tx.Save(&result).Error
+		if err != nil || delta == 0 {
+			return err
+		}
+
+		reason := entity.StockRestock
+		if delta < 0 {
+			reason = entity.StockAdjustment
+		}
+		return tx.Create(&entity.StockMovement{
+			ProductID: productID,
+			Quantity:  delta,
+			Reason:    reason,
+			Reference: reference,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (r *repo) GetStockMovements(ctx context.Context, productID int64, limit, offset int) ([]*entity.StockMovement, int64, error) {
+	var total int64
+	err := r.db.WithContext(ctx).Model(&entity.StockMovement{}).Where("product_id = ?", productID).Count(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var result []*entity.StockMovement
+	err = r.db.WithContext(ctx).Where("product_id = ?", productID).Order("id desc").Limit(limit).Offset(offset).Find(&result).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return result, total, nil
+}
+
+func (r *repo) GetStockThresholds(ctx context.Context, productIDs []int64) ([]*entity.StockThreshold, error) {
+	var result []*entity.StockThreshold
+	err := r.db.WithContext(ctx).Where("product_id in (?)", productIDs).Find(&result).Error
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (r *repo) SaveStockThreshold(ctx context.Context, threshold *entity.StockThreshold) error {
+	// stock_threshold has unique key on product_id, mysql ignores the conflict columns
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "product_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"threshold", "updated_at"}),
+	}).Create(threshold).Error
+}
@@ -0,0 +1,230 @@
+package inventoryrepository_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"regexp"
+	"testing"
+	"time"
+
+	"hometest1/core/entity"
+	"hometest1/core/repository"
+	inventoryrepository "hometest1/repository/inventory-repository"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/schema"
+)
+
+type AnyTime struct{}
+
+// Match satisfies sqlmock.Argument interface
+func (a AnyTime) Match(v driver.Value) bool {
+	_, ok := v.(time.Time)
+	return ok
+}
+
+func initRepo(db *sql.DB, mock sqlmock.Sqlmock) (repository.InventoryRepo, error) {
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT VERSION()")).
+		WillReturnRows(sqlmock.NewRows([]string{"VERSION()"}).AddRow("5.7.25-log"))
+	gdb, err := gorm.Open(mysql.New(mysql.Config{
+		Conn: db,
+	}), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.LogLevel(logger.Info)),
+		NamingStrategy: schema.NamingStrategy{
+			SingularTable: true,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return inventoryrepository.New(gdb), nil
+}
+
+func Test_Restock(t *testing.T) {
+	// mock db
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	defer db.Close()
+
+	// init repo
+	repo, err := initRepo(db, mock)
+	if err != nil {
+		t.Errorf("error initRepo: %s", err.Error())
+		return
+	}
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+
+	t.Run("positive, existing stock", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `product_quantity` WHERE product_id = ? LIMIT ? FOR UPDATE")).
+			WithArgs(1, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "quantity", "updated_at"}).AddRow(1, 1, 10, dayCreated))
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE `product_quantity` SET `product_id`=?,`quantity`=?,`updated_at`=? WHERE `id` = ?")).
+			WithArgs(1, 15, AnyTime{}, 1).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `stock_movement` (`product_id`,`quantity`,`reason`,`reference`,`created_at`) VALUES (?,?,?,?,?)")).
+			WithArgs(1, 5, entity.StockRestock, "PO-001", AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		resp, err := repo.Restock(context.Background(), 1, 5, "PO-001")
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), resp.ID)
+		assert.Equal(t, 15, resp.Quantity)
+	})
+
+	t.Run("positive, product has no stock row", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `product_quantity` WHERE product_id = ? LIMIT ? FOR UPDATE")).
+			WithArgs(2, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "quantity", "updated_at"}))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `product_quantity` (`product_id`,`quantity`,`updated_at`) VALUES (?,?,?)")).
+			WithArgs(2, 5, AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(2, 1))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `stock_movement` (`product_id`,`quantity`,`reason`,`reference`,`created_at`) VALUES (?,?,?,?,?)")).
+			WithArgs(2, 5, entity.StockRestock, "", AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(2, 1))
+		mock.ExpectCommit()
+
+		resp, err := repo.Restock(context.Background(), 2, 5, "")
+		assert.Nil(t, err)
+		assert.Equal(t, int64(2), resp.ID)
+		assert.Equal(t, 5, resp.Quantity)
+	})
+}
+
+func Test_SetStock(t *testing.T) {
+	// mock db
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	defer db.Close()
+
+	// init repo
+	repo, err := initRepo(db, mock)
+	if err != nil {
+		t.Errorf("error initRepo: %s", err.Error())
+		return
+	}
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+
+	t.Run("positive, lower stock is adjustment", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `product_quantity` WHERE product_id = ? LIMIT ? FOR UPDATE")).
+			WithArgs(1, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "quantity", "updated_at"}).AddRow(1, 1, 10, dayCreated))
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE `product_quantity` SET `product_id`=?,`quantity`=?,`updated_at`=? WHERE `id` = ?")).
+			WithArgs(1, 4, AnyTime{}, 1).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `stock_movement` (`product_id`,`quantity`,`reason`,`reference`,`created_at`) VALUES (?,?,?,?,?)")).
+			WithArgs(1, -6, entity.StockAdjustment, "catalog import", AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		resp, err := repo.SetStock(context.Background(), 1, 4, "catalog import")
+		assert.Nil(t, err)
+		assert.Equal(t, 4, resp.Quantity)
+	})
+
+	t.Run("positive, unchanged stock is not recorded", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `product_quantity` WHERE product_id = ? LIMIT ? FOR UPDATE")).
+			WithArgs(1, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "quantity", "updated_at"}).AddRow(1, 1, 4, dayCreated))
+		mock.ExpectCommit()
+
+		resp, err := repo.SetStock(context.Background(), 1, 4, "catalog import")
+		assert.Nil(t, err)
+		assert.Equal(t, 4, resp.Quantity)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
+
+func Test_GetStockMovements(t *testing.T) {
+	// mock db
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	defer db.Close()
+
+	// init repo
+	repo, err := initRepo(db, mock)
+	if err != nil {
+		t.Errorf("error initRepo: %s", err.Error())
+		return
+	}
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+
+	t.Run("positive", func(t *testing.T) {
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `stock_movement` WHERE product_id = ?")).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).AddRow(2))
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `stock_movement` WHERE product_id = ? ORDER BY id desc LIMIT ?")).
+			WithArgs(1, 10).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "quantity", "reason", "reference", "created_at"}).
+				AddRow(2, 1, -1, 1, "order:1", dayCreated).
+				AddRow(1, 1, 10, 2, "initial stock", dayCreated))
+
+		resp, total, err := repo.GetStockMovements(context.Background(), 1, 10, 0)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(2), total)
+		assert.Equal(t, []*entity.StockMovement{
+			{ID: 2, ProductID: 1, Quantity: -1, Reason: entity.StockSale, Reference: "order:1", CreatedAt: dayCreated},
+			{ID: 1, ProductID: 1, Quantity: 10, Reason: entity.StockRestock, Reference: "initial stock", CreatedAt: dayCreated},
+		}, resp)
+	})
+}
+
+func Test_StockThreshold(t *testing.T) {
+	// mock db
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	defer db.Close()
+
+	// init repo
+	repo, err := initRepo(db, mock)
+	if err != nil {
+		t.Errorf("error initRepo: %s", err.Error())
+		return
+	}
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+
+	t.Run("positive, save", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `stock_threshold` (`product_id`,`threshold`,`updated_at`) VALUES (?,?,?) ON DUPLICATE KEY UPDATE `threshold`=VALUES(`threshold`),`updated_at`=VALUES(`updated_at`)")).
+			WithArgs(4, 3, AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		err := repo.SaveStockThreshold(context.Background(), &entity.StockThreshold{ProductID: 4, Threshold: 3})
+		assert.Nil(t, err)
+	})
+
+	t.Run("positive, get", func(t *testing.T) {
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `stock_threshold` WHERE product_id in (?,?)")).
+			WithArgs(2, 4).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "threshold", "updated_at"}).AddRow(1, 4, 3, dayCreated))
+
+		resp, err := repo.GetStockThresholds(context.Background(), []int64{2, 4})
+		assert.Nil(t, err)
+		assert.Equal(t, []*entity.StockThreshold{{ID: 1, ProductID: 4, Threshold: 3, UpdatedAt: dayCreated}}, resp)
+	})
+}
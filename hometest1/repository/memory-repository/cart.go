@@ -0,0 +1,91 @@
+package memoryrepository
+
+import (
+	"context"
+
+	"hometest1/core/entity"
+	"hometest1/core/repository"
+)
+
+type cartRepo struct {
+	store *Store
+}
+
+func NewCartRepo(store *Store) repository.CartRepo {
+	return &cartRepo{store}
+}
+
+func (r *cartRepo) CreateCart(ctx context.Context) (*entity.Cart, error) {
+	if err := r.store.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.store.unlock()
+
+	cart := &entity.Cart{
+		ID:        r.store.nextID("cart"),
+		Status:    entity.CartOpen,
+		UpdatedAt: r.store.clock(),
+	}
+	r.store.carts[cart.ID] = cart
+	return cloneCart(cart), nil
+}
+
+func (r *cartRepo) GetCart(ctx context.Context, id int64) (*entity.Cart, error) {
+	if err := r.store.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.store.unlock()
+
+	cart, ok := r.store.carts[id]
+	if !ok {
+		return nil, nil
+	}
+	return cloneCart(cart), nil
+}
+
+func (r *cartRepo) SaveCartItem(ctx context.Context, item *entity.CartItem) error {
+	if err := r.store.lock(ctx); err != nil {
+		return err
+	}
+	defer r.store.unlock()
+
+	cart, ok := r.store.carts[item.CartID]
+	if !ok {
+		return nil
+	}
+	item.UpdatedAt = r.store.clock()
+
+	// product is unique in the cart, like unique key of cart_item
+	for _, existing := range cart.Items {
+		if existing.ProductID == item.ProductID {
+			item.ID = existing.ID
+			existing.Quantity = item.Quantity
+			existing.UpdatedAt = item.UpdatedAt
+			return nil
+		}
+	}
+	item.ID = r.store.nextID("cart_item")
+	clone := *item
+	cart.Items = append(cart.Items, &clone)
+	return nil
+}
+
+func (r *cartRepo) DeleteCartItem(ctx context.Context, cartID int64, productID int64) error {
+	if err := r.store.lock(ctx); err != nil {
+		return err
+	}
+	defer r.store.unlock()
+
+	cart, ok := r.store.carts[cartID]
+	if !ok {
+		return nil
+	}
+	items := cart.Items[:0]
+	for _, item := range cart.Items {
+		if item.ProductID != productID {
+			items = append(items, item)
+		}
+	}
+	cart.Items = items
+	return nil
+}
@@ -0,0 +1,50 @@
+package memoryrepository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"hometest1/core/entity"
+	memoryrepository "hometest1/repository/memory-repository"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Cart(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("positive, save, update and delete cart item", func(t *testing.T) {
+		repo := memoryrepository.NewCartRepo(memoryrepository.NewStoreWithClock(func() time.Time { return now }))
+		cart, err := repo.CreateCart(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, &entity.Cart{ID: 1, Status: entity.CartOpen, UpdatedAt: now}, cart)
+
+		err = repo.SaveCartItem(ctx, &entity.CartItem{CartID: cart.ID, ProductID: 2, Quantity: 1})
+		assert.Nil(t, err)
+		err = repo.SaveCartItem(ctx, &entity.CartItem{CartID: cart.ID, ProductID: 3, Quantity: 1})
+		assert.Nil(t, err)
+		// product is unique in the cart
+		err = repo.SaveCartItem(ctx, &entity.CartItem{CartID: cart.ID, ProductID: 2, Quantity: 4})
+		assert.Nil(t, err)
+		err = repo.DeleteCartItem(ctx, cart.ID, 3)
+		assert.Nil(t, err)
+
+		cart, err = repo.GetCart(ctx, cart.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, []*entity.CartItem{{ID: 1, CartID: 1, ProductID: 2, Quantity: 4, UpdatedAt: now}}, cart.Items)
+
+		// returned cart is a copy
+		cart.Items[0].Quantity = 10
+		cart, err = repo.GetCart(ctx, cart.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, 4, cart.Items[0].Quantity)
+	})
+
+	t.Run("negative, cart not found", func(t *testing.T) {
+		repo := memoryrepository.NewCartRepo(memoryrepository.NewStore())
+		cart, err := repo.GetCart(ctx, 1)
+		assert.Nil(t, err)
+		assert.Nil(t, cart)
+	})
+}
@@ -0,0 +1,80 @@
+package memoryrepository
+
+import (
+	"context"
+
+	"hometest1/core/entity"
+	"hometest1/core/repository"
+)
+
+type idempotencyRepo struct {
+	store *Store
+}
+
+func NewIdempotencyRepo(store *Store) repository.IdempotencyRepo {
+	return &idempotencyRepo{store}
+}
+
+func (r *idempotencyRepo) CreateKey(ctx context.Context, key *entity.IdempotencyKey) (bool, error) {
+	if err := r.store.lock(ctx); err != nil {
+		return false, err
+	}
+	defer r.store.unlock()
+
+	// key is unique, concurrent requests with the same key fail here
+	if _, ok := r.store.idempotencyKeys[key.Key]; ok {
+		return false, nil
+	}
+	now := r.store.clock()
+	key.ID = r.store.nextID("idempotency_key")
+	key.CreatedAt = now
+	key.UpdatedAt = now
+	clone := *key
+	r.store.idempotencyKeys[key.Key] = &clone
+	return true, nil
+}
+
+func (r *idempotencyRepo) GetKey(ctx context.Context, key string) (*entity.IdempotencyKey, error) {
+	if err := r.store.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.store.unlock()
+
+	existing, ok := r.store.idempotencyKeys[key]
+	if !ok {
+		return nil, nil
+	}
+	clone := *existing
+	return &clone, nil
+}
+
+func (r *idempotencyRepo) SaveResponse(ctx context.Context, key *entity.IdempotencyKey) error {
+	if err := r.store.lock(ctx); err != nil {
+		return err
+	}
+	defer r.store.unlock()
+
+	existing, ok := r.store.idempotencyKeys[key.Key]
+	if !ok || existing.ID != key.ID {
+		return nil
+	}
+	key.UpdatedAt = r.store.clock()
+	existing.ResponseCode = key.ResponseCode
+	existing.ResponseBody = key.ResponseBody
+	existing.UpdatedAt = key.UpdatedAt
+	return nil
+}
+
+func (r *idempotencyRepo) DeleteKey(ctx context.Context, key *entity.IdempotencyKey) (bool, error) {
+	if err := r.store.lock(ctx); err != nil {
+		return false, err
+	}
+	defer r.store.unlock()
+
+	existing, ok := r.store.idempotencyKeys[key.Key]
+	if !ok || existing.ID != key.ID || existing.IsCompleted() {
+		return false, nil
+	}
+	delete(r.store.idempotencyKeys, key.Key)
+	return true, nil
+}
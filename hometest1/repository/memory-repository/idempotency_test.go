@@ -0,0 +1,71 @@
+package memoryrepository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"hometest1/core/entity"
+	memoryrepository "hometest1/repository/memory-repository"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Idempotency(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("positive, key is created once until it is deleted", func(t *testing.T) {
+		repo := memoryrepository.NewIdempotencyRepo(memoryrepository.NewStoreWithClock(func() time.Time { return now }))
+		created, err := repo.CreateKey(ctx, &entity.IdempotencyKey{Key: "pos-1", RequestHash: "abc"})
+		assert.Nil(t, err)
+		assert.True(t, created)
+		created, err = repo.CreateKey(ctx, &entity.IdempotencyKey{Key: "pos-1", RequestHash: "abc"})
+		assert.Nil(t, err)
+		assert.False(t, created)
+
+		key, err := repo.GetKey(ctx, "pos-1")
+		assert.Nil(t, err)
+		key.ResponseCode = 200
+		key.ResponseBody = "{}"
+		err = repo.SaveResponse(ctx, key)
+		assert.Nil(t, err)
+		key, err = repo.GetKey(ctx, "pos-1")
+		assert.Nil(t, err)
+		assert.Equal(t, &entity.IdempotencyKey{ID: 1, Key: "pos-1", RequestHash: "abc", ResponseCode: 200, ResponseBody: "{}", CreatedAt: now, UpdatedAt: now}, key)
+
+		// completed key is not deleted
+		deleted, err := repo.DeleteKey(ctx, key)
+		assert.Nil(t, err)
+		assert.False(t, deleted)
+	})
+
+	t.Run("positive, in progress key is deleted once by its id", func(t *testing.T) {
+		repo := memoryrepository.NewIdempotencyRepo(memoryrepository.NewStoreWithClock(func() time.Time { return now }))
+		stale := &entity.IdempotencyKey{Key: "pos-2", RequestHash: "abc"}
+		created, err := repo.CreateKey(ctx, stale)
+		assert.Nil(t, err)
+		assert.True(t, created)
+
+		deleted, err := repo.DeleteKey(ctx, stale)
+		assert.Nil(t, err)
+		assert.True(t, deleted)
+		created, err = repo.CreateKey(ctx, &entity.IdempotencyKey{Key: "pos-2", RequestHash: "abc"})
+		assert.Nil(t, err)
+		assert.True(t, created)
+
+		// key created again by other request is not deleted with the stale id
+		deleted, err = repo.DeleteKey(ctx, stale)
+		assert.Nil(t, err)
+		assert.False(t, deleted)
+		key, err := repo.GetKey(ctx, "pos-2")
+		assert.Nil(t, err)
+		assert.NotNil(t, key)
+	})
+
+	t.Run("negative, key not found", func(t *testing.T) {
+		repo := memoryrepository.NewIdempotencyRepo(memoryrepository.NewStore())
+		key, err := repo.GetKey(ctx, "neg-1")
+		assert.Nil(t, err)
+		assert.Nil(t, key)
+	})
+}
@@ -0,0 +1,118 @@
+package memoryrepository
+
+import (
+	"context"
+
+	"hometest1/core/entity"
+	"hometest1/core/repository"
+)
+
+type inventoryRepo struct {
+	store *Store
+}
+
+func NewInventoryRepo(store *Store) repository.InventoryRepo {
+	return &inventoryRepo{store}
+}
+
+func (r *inventoryRepo) Restock(ctx context.Context, productID int64, quantity int, reference string) (*entity.ProductQuantity, error) {
+	if err := r.store.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.store.unlock()
+
+	qty := r.store.productQuantity(productID)
+	qty.Quantity += quantity
+	qty.UpdatedAt = r.store.clock()
+	r.store.addStockMovement(&entity.StockMovement{
+		ProductID: productID,
+		Quantity:  quantity,
+		Reason:    entity.StockRestock,
+		Reference: reference,
+	})
+	return cloneQuantity(qty), nil
+}
+
+func (r *inventoryRepo) SetStock(ctx context.Context, productID int64, quantity int, reference string) (*entity.ProductQuantity, error) {
+	if err := r.store.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.store.unlock()
+
+	qty := r.store.productQuantity(productID)
+	delta := quantity - qty.Quantity
+	if delta == 0 {
+		return cloneQuantity(qty), nil
+	}
+	qty.Quantity = quantity
+	qty.UpdatedAt = r.store.clock()
+
+	reason := entity.StockRestock
+	if delta < 0 {
+		reason = entity.StockAdjustment
+	}
+	r.store.addStockMovement(&entity.StockMovement{
+		ProductID: productID,
+		Quantity:  delta,
+		Reason:    reason,
+		Reference: reference,
+	})
+	return cloneQuantity(qty), nil
+}
+
+func (r *inventoryRepo) GetStockMovements(ctx context.Context, productID int64, limit, offset int) ([]*entity.StockMovement, int64, error) {
+	if err := r.store.lock(ctx); err != nil {
+		return nil, 0, err
+	}
+	defer r.store.unlock()
+
+	// newest first, movements are appended in id order
+	var result []*entity.StockMovement
+	for i := len(r.store.stockMovements) - 1; i >= 0; i-- {
+		if movement := r.store.stockMovements[i]; movement.ProductID == productID {
+			result = append(result, movement)
+		}
+	}
+	total := int64(len(result))
+
+	result = paginate(result, limit, offset)
+	for i, movement := range result {
+		clone := *movement
+		result[i] = &clone
+	}
+	return result, total, nil
+}
+
+func (r *inventoryRepo) GetStockThresholds(ctx context.Context, productIDs []int64) ([]*entity.StockThreshold, error) {
+	if err := r.store.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.store.unlock()
+
+	var result []*entity.StockThreshold
+	for _, id := range uniqueIDs(productIDs) {
+		if threshold, ok := r.store.thresholds[id]; ok {
+			clone := *threshold
+			result = append(result, &clone)
+		}
+	}
+	return result, nil
+}
+
+func (r *inventoryRepo) SaveStockThreshold(ctx context.Context, threshold *entity.StockThreshold) error {
+	if err := r.store.lock(ctx); err != nil {
+		return err
+	}
+	defer r.store.unlock()
+
+	// product has one threshold, like unique key of stock_threshold
+	threshold.UpdatedAt = r.store.clock()
+	if existing, ok := r.store.thresholds[threshold.ProductID]; ok {
+		threshold.ID = existing.ID
+	} else {
+		threshold.ID = r.store.nextID("stock_threshold")
+	}
+	clone := *threshold
+	r.store.thresholds[threshold.ProductID] = &clone
+	return nil
+}
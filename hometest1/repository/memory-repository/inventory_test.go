@@ -0,0 +1,52 @@
+package memoryrepository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"hometest1/core/entity"
+	memoryrepository "hometest1/repository/memory-repository"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Inventory(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("positive, restock and set stock record movements", func(t *testing.T) {
+		store := memoryrepository.NewStoreWithClock(func() time.Time { return now })
+		repo := memoryrepository.NewInventoryRepo(store)
+		product := createProduct(t, memoryrepository.NewProductRepo(store), "A-1", 5)
+
+		qty, err := repo.Restock(ctx, product.ID, 3, "PO-1")
+		assert.Nil(t, err)
+		assert.Equal(t, 8, qty.Quantity)
+		qty, err = repo.SetStock(ctx, product.ID, 6, "count")
+		assert.Nil(t, err)
+		assert.Equal(t, 6, qty.Quantity)
+		// unchanged quantity is not recorded
+		_, err = repo.SetStock(ctx, product.ID, 6, "count")
+		assert.Nil(t, err)
+
+		movements, total, err := repo.GetStockMovements(ctx, product.ID, 2, 0)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(3), total)
+		assert.Equal(t, []*entity.StockMovement{
+			{ID: 3, ProductID: product.ID, Quantity: -2, Reason: entity.StockAdjustment, Reference: "count", CreatedAt: now},
+			{ID: 2, ProductID: product.ID, Quantity: 3, Reason: entity.StockRestock, Reference: "PO-1", CreatedAt: now},
+		}, movements)
+	})
+
+	t.Run("positive, threshold is replaced", func(t *testing.T) {
+		repo := memoryrepository.NewInventoryRepo(memoryrepository.NewStoreWithClock(func() time.Time { return now }))
+		err := repo.SaveStockThreshold(ctx, &entity.StockThreshold{ProductID: 1, Threshold: 2})
+		assert.Nil(t, err)
+		err = repo.SaveStockThreshold(ctx, &entity.StockThreshold{ProductID: 1, Threshold: 3})
+		assert.Nil(t, err)
+
+		thresholds, err := repo.GetStockThresholds(ctx, []int64{1, 2})
+		assert.Nil(t, err)
+		assert.Equal(t, []*entity.StockThreshold{{ID: 1, ProductID: 1, Threshold: 3, UpdatedAt: now}}, thresholds)
+	})
+}
@@ -0,0 +1,135 @@
+package memoryrepository
+
+import (
+	"context"
+	"net/http"
+	"sort"
+
+	"hometest1/core/entity"
+	"hometest1/core/repository"
+)
+
+type orderRepo struct {
+	store *Store
+}
+
+// NewOrderRepo read orders written by checkout of product repository in the same store
+func NewOrderRepo(store *Store) repository.OrderRepo {
+	return &orderRepo{store}
+}
+
+func (r *orderRepo) GetOrder(ctx context.Context, id int64) (*entity.Order, error) {
+	if err := r.store.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.store.unlock()
+
+	order, ok := r.store.orders[id]
+	if !ok {
+		return nil, nil
+	}
+	return cloneOrder(order), nil
+}
+
+func (r *orderRepo) GetOrders(ctx context.Context, limit, offset int) ([]*entity.Order, int64, error) {
+	if err := r.store.lock(ctx); err != nil {
+		return nil, 0, err
+	}
+	defer r.store.unlock()
+
+	var result []*entity.Order
+	for _, order := range r.store.orders {
+		result = append(result, order)
+	}
+	// newest first
+	sort.Slice(result, func(i, j int) bool { return result[i].ID > result[j].ID })
+	total := int64(len(result))
+
+	result = paginate(result, limit, offset)
+	for i, order := range result {
+		clone := *order
+		clone.Items = nil
+		clone.Taxes = nil
+		clone.Discounts = nil
+		result[i] = &clone
+	}
+	return result, total, nil
+}
+
+func (r *orderRepo) ReturnOrder(ctx context.Context, ret *entity.OrderReturn, status entity.OrderStatus) error {
+	if err := r.store.lock(ctx); err != nil {
+		return entity.NewInternalError(err)
+	}
+	defer r.store.unlock()
+
+	order, ok := r.store.orders[ret.OrderID]
+	if !ok {
+		return entity.NewError(entity.OrderNotFound, http.StatusNotFound)
+	}
+	// refund is computed from the order read before, it is wrong if other return is applied in between
+	if !isSameOrderState(order, ret.Order) {
+		return entity.NewError(entity.OrderChanged, http.StatusConflict)
+	}
+
+	now := r.store.clock()
+	ret.ID = r.store.nextID("order_return")
+	if ret.CreatedAt.IsZero() {
+		ret.CreatedAt = now
+	}
+	stored := *ret
+	stored.Order = nil
+	stored.Items = nil
+	mapItem := make(map[int64]*entity.OrderItem)
+	for _, item := range order.Items {
+		mapItem[item.ID] = item
+	}
+	for _, item := range ret.Items {
+		item.ID = r.store.nextID("order_return_item")
+		item.OrderReturnID = ret.ID
+		clone := *item
+		stored.Items = append(stored.Items, &clone)
+		if orderItem, ok := mapItem[item.OrderItemID]; ok {
+			orderItem.ReturnedQuantity += item.Quantity
+		}
+	}
+	r.store.orderReturns = append(r.store.orderReturns, &stored)
+	order.Status = status
+	order.RefundedPrice = order.RefundedPrice.Add(ret.RefundPrice)
+
+	// add returned quantity back to product quantity and record it as return movement
+	// map[int64] = product id, int = returned quantity
+	returned := make(map[int64]int)
+	var productIDs []int64
+	for _, item := range ret.Items {
+		if _, ok := returned[item.ProductID]; !ok {
+			productIDs = append(productIDs, item.ProductID)
+		}
+		returned[item.ProductID] += item.Quantity
+	}
+	reference := entity.OrderReturnReference(ret.ID)
+	for _, productID := range productIDs {
+		qty := r.store.productQuantity(productID)
+		qty.Quantity += returned[productID]
+		qty.UpdatedAt = now
+		r.store.addStockMovement(&entity.StockMovement{
+			ProductID: productID,
+			Quantity:  returned[productID],
+			Reason:    entity.StockReturn,
+			Reference: reference,
+		})
+	}
+	return nil
+}
+
+// compare status, refund and returned quantities of the order
+func isSameOrderState(order, expected *entity.Order) bool {
+	if expected == nil || order.Status != expected.Status || order.RefundedPrice != expected.RefundedPrice || len(order.Items) != len(expected.Items) {
+		return false
+	}
+	for i, item := range order.Items {
+		if item.ID != expected.Items[i].ID || item.ReturnedQuantity != expected.Items[i].ReturnedQuantity {
+			return false
+		}
+	}
+	return true
+}
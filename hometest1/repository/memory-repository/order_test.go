@@ -0,0 +1,106 @@
+package memoryrepository_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"hometest1/core/entity"
+	memoryrepository "hometest1/repository/memory-repository"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Order(t *testing.T) {
+	ctx := context.Background()
+
+	// store with an order of 2 items of product A-1, 2.00 off by cart promotion
+	initOrder := func(t *testing.T) (*memoryrepository.Store, *entity.Product) {
+		store := memoryrepository.NewStoreWithClock(func() time.Time { return now })
+		repo := memoryrepository.NewProductRepo(store)
+		product := createProduct(t, repo, "A-1", 5)
+		err := repo.SubmitCheckout(ctx, &entity.Checkout{
+			Items:         []*entity.CheckoutItem{{Product: product, Quantity: 2, SubTotalPrice: entity.NewMoney(2000)}},
+			TotalItem:     2,
+			TotalPrice:    entity.NewMoney(1800),
+			Discounts:     []*entity.CheckoutDiscount{{CartPromotionID: 1, Type: entity.CartDiscountAmount, Amount: entity.NewMoney(200)}},
+			DiscountPrice: entity.NewMoney(200),
+		})
+		assert.Nil(t, err)
+		return store, product
+	}
+
+	t.Run("positive, return restores stock", func(t *testing.T) {
+		store, product := initOrder(t)
+		orderRepo := memoryrepository.NewOrderRepo(store)
+		inventoryRepo := memoryrepository.NewInventoryRepo(store)
+
+		order, err := orderRepo.GetOrder(ctx, 1)
+		assert.Nil(t, err)
+		assert.Equal(t, entity.OrderPlaced, order.Status)
+		assert.Len(t, order.Items, 1)
+		assert.Equal(t, []*entity.OrderDiscount{
+			{ID: 1, OrderID: 1, Source: entity.SourceCartPromotion, SourceID: 1, Amount: entity.NewMoney(200)},
+		}, order.Discounts)
+
+		err = orderRepo.ReturnOrder(ctx, &entity.OrderReturn{
+			OrderID:     order.ID,
+			RefundPrice: entity.NewMoney(1000),
+			Items:       []*entity.OrderReturnItem{{OrderItemID: order.Items[0].ID, ProductID: product.ID, Quantity: 1}},
+			Order:       order,
+		}, entity.OrderPartiallyReturned)
+		assert.Nil(t, err)
+
+		order, err = orderRepo.GetOrder(ctx, 1)
+		assert.Nil(t, err)
+		assert.Equal(t, entity.OrderPartiallyReturned, order.Status)
+		assert.Equal(t, entity.NewMoney(1000), order.RefundedPrice)
+		assert.Equal(t, 1, order.Items[0].ReturnedQuantity)
+
+		movements, total, err := inventoryRepo.GetStockMovements(ctx, product.ID, 1, 0)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(3), total)
+		assert.Equal(t, &entity.StockMovement{
+			ID:        3,
+			ProductID: product.ID,
+			Quantity:  1,
+			Reason:    entity.StockReturn,
+			Reference: entity.OrderReturnReference(1),
+			CreatedAt: now,
+		}, movements[0])
+
+		orders, total, err := orderRepo.GetOrders(ctx, 10, 0)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), total)
+		assert.Empty(t, orders[0].Items)
+		assert.Empty(t, orders[0].Discounts)
+	})
+
+	t.Run("negative, order is changed by other return", func(t *testing.T) {
+		store, product := initOrder(t)
+		orderRepo := memoryrepository.NewOrderRepo(store)
+		order, err := orderRepo.GetOrder(ctx, 1)
+		assert.Nil(t, err)
+		ret := func() error {
+			return orderRepo.ReturnOrder(ctx, &entity.OrderReturn{
+				OrderID:     order.ID,
+				RefundPrice: entity.NewMoney(1000),
+				Items:       []*entity.OrderReturnItem{{OrderItemID: order.Items[0].ID, ProductID: product.ID, Quantity: 1}},
+				Order:       order,
+			}, entity.OrderPartiallyReturned)
+		}
+
+		assert.Nil(t, ret())
+		assert.Equal(t, entity.NewError(entity.OrderChanged, http.StatusConflict), ret())
+	})
+
+	t.Run("negative, order not found", func(t *testing.T) {
+		orderRepo := memoryrepository.NewOrderRepo(memoryrepository.NewStore())
+		order, err := orderRepo.GetOrder(ctx, 1)
+		assert.Nil(t, err)
+		assert.Nil(t, order)
+		err = orderRepo.ReturnOrder(ctx, &entity.OrderReturn{OrderID: 1}, entity.OrderReturned)
+		assert.Equal(t, entity.NewError(entity.OrderNotFound, http.StatusNotFound), err)
+	})
+}
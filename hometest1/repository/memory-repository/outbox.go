@@ -0,0 +1,86 @@
+package memoryrepository
+
+import (
+	"context"
+	"time"
+
+	"hometest1/core/entity"
+	"hometest1/core/repository"
+)
+
+type outboxRepo struct {
+	store *Store
+}
+
+// NewOutboxRepo read events written by checkout of product repository in the same store
+func NewOutboxRepo(store *Store) repository.OutboxRepo {
+	return &outboxRepo{store}
+}
+
+func (r *outboxRepo) GetDueEvents(ctx context.Context, now time.Time, limit int) ([]*entity.Outbox, error) {
+	if err := r.store.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.store.unlock()
+
+	// events are appended in id order
+	var result []*entity.Outbox
+	for _, event := range r.store.outbox {
+		if len(result) == limit {
+			break
+		}
+		if event.DeliveredAt == nil && !event.NextAttemptAt.After(now) {
+			result = append(result, cloneOutbox(event))
+		}
+	}
+	return result, nil
+}
+
+func (r *outboxRepo) ClaimEvent(ctx context.Context, id int64, now, leaseUntil time.Time) (bool, error) {
+	if err := r.store.lock(ctx); err != nil {
+		return false, err
+	}
+	defer r.store.unlock()
+
+	event := r.getEvent(id)
+	if event == nil || event.DeliveredAt != nil || event.NextAttemptAt.After(now) {
+		return false, nil
+	}
+	event.NextAttemptAt = leaseUntil
+	return true, nil
+}
+
+func (r *outboxRepo) MarkDelivered(ctx context.Context, id int64, deliveredAt time.Time) error {
+	if err := r.store.lock(ctx); err != nil {
+		return err
+	}
+	defer r.store.unlock()
+
+	if event := r.getEvent(id); event != nil {
+		event.DeliveredAt = &deliveredAt
+	}
+	return nil
+}
+
+func (r *outboxRepo) MarkFailed(ctx context.Context, event *entity.Outbox) error {
+	if err := r.store.lock(ctx); err != nil {
+		return err
+	}
+	defer r.store.unlock()
+
+	if stored := r.getEvent(event.ID); stored != nil {
+		stored.Attempts = event.Attempts
+		stored.LastError = event.LastError
+		stored.NextAttemptAt = event.NextAttemptAt
+	}
+	return nil
+}
+
+func (r *outboxRepo) getEvent(id int64) *entity.Outbox {
+	for _, event := range r.store.outbox {
+		if event.ID == id {
+			return event
+		}
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package memoryrepository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"hometest1/core/entity"
+	memoryrepository "hometest1/repository/memory-repository"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Outbox(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("positive, checkout writes event until it is delivered", func(t *testing.T) {
+		store := memoryrepository.NewStoreWithClock(func() time.Time { return now })
+		repo := memoryrepository.NewProductRepo(store)
+		outboxRepo := memoryrepository.NewOutboxRepo(store)
+		product := createProduct(t, repo, "A-1", 5)
+
+		err := repo.SubmitCheckout(ctx, &entity.Checkout{
+			Items:      []*entity.CheckoutItem{{Product: product, Quantity: 2, SubTotalPrice: entity.NewMoney(2000)}},
+			TotalItem:  2,
+			TotalPrice: entity.NewMoney(2000),
+		})
+		assert.Nil(t, err)
+
+		events, err := outboxRepo.GetDueEvents(ctx, now, 10)
+		assert.Nil(t, err)
+		assert.Equal(t, []*entity.Outbox{{
+			ID:          1,
+			EventType:   entity.OrderPlacedEvent,
+			AggregateID: 1,
+			Payload: `{"orderId":1,"customerId":"","reservationId":0,"totalItem":2,"totalPrice":20.00,"discountPrice":0.00,"taxRegion":"","taxPrice":0.00,"coupons":[],` +
+				`"items":[{"serial":"A-1","name":"Product A-1","unitPrice":10.00,"quantity":2,"freeQuantity":0,"subTotalPrice":20.00}]}`,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+		}}, events)
+
+		// only one dispatcher takes the event until the lease time
+		claimed, err := outboxRepo.ClaimEvent(ctx, 1, now, now.Add(time.Minute))
+		assert.Nil(t, err)
+		assert.True(t, claimed)
+		claimed, err = outboxRepo.ClaimEvent(ctx, 1, now, now.Add(time.Minute))
+		assert.Nil(t, err)
+		assert.False(t, claimed)
+
+		// failed event is due again after the backoff
+		err = outboxRepo.MarkFailed(ctx, &entity.Outbox{ID: 1, Attempts: 1, LastError: "connection refused", NextAttemptAt: now.Add(time.Second)})
+		assert.Nil(t, err)
+		events, err = outboxRepo.GetDueEvents(ctx, now, 10)
+		assert.Nil(t, err)
+		assert.Empty(t, events)
+		events, err = outboxRepo.GetDueEvents(ctx, now.Add(time.Second), 10)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, events[0].Attempts)
+		assert.Equal(t, "connection refused", events[0].LastError)
+
+		err = outboxRepo.MarkDelivered(ctx, 1, now.Add(time.Second))
+		assert.Nil(t, err)
+		events, err = outboxRepo.GetDueEvents(ctx, now.Add(time.Hour), 10)
+		assert.Nil(t, err)
+		assert.Empty(t, events)
+	})
+
+	t.Run("negative, failed checkout writes no event", func(t *testing.T) {
+		store := memoryrepository.NewStoreWithClock(func() time.Time { return now })
+		repo := memoryrepository.NewProductRepo(store)
+		outboxRepo := memoryrepository.NewOutboxRepo(store)
+		product := createProduct(t, repo, "A-1", 1)
+
+		err := repo.SubmitCheckout(ctx, &entity.Checkout{
+			Items: []*entity.CheckoutItem{{Product: product, Quantity: 2}},
+		})
+		assert.NotNil(t, err)
+
+		events, err := outboxRepo.GetDueEvents(ctx, now, 10)
+		assert.Nil(t, err)
+		assert.Empty(t, events)
+	})
+}
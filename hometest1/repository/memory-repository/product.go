@@ -0,0 +1,468 @@
+package memoryrepository
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"hometest1/core/entity"
+	"hometest1/core/repository"
+
+	"gorm.io/gorm"
+)
+
+type productRepo struct {
+	store *Store
+}
+
+func NewProductRepo(store *Store) repository.ProductRepo {
+	return &productRepo{store}
+}
+
+func (r *productRepo) GetProductBySerials(ctx context.Context, serials []string) ([]*entity.Product, error) {
+	if err := r.store.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.store.unlock()
+
+	mapSerial := make(map[string]bool)
+	for _, serial := range serials {
+		mapSerial[serial] = true
+	}
+	var result []*entity.Product
+	for _, product := range r.store.products {
+		if mapSerial[product.Serial] && !isDeleted(product.DeletedAt) {
+			result = append(result, cloneProduct(product))
+		}
+	}
+	sortByID(result, func(p *entity.Product) int64 { return p.ID })
+	return result, nil
+}
+
+func (r *productRepo) GetProductByIDs(ctx context.Context, ids []int64) ([]*entity.Product, error) {
+	return r.getProductByIDs(ctx, ids, false)
+}
+
+func (r *productRepo) GetProductByIDsWithDeleted(ctx context.Context, ids []int64) ([]*entity.Product, error) {
+	return r.getProductByIDs(ctx, ids, true)
+}
+
+func (r *productRepo) getProductByIDs(ctx context.Context, ids []int64, withDeleted bool) ([]*entity.Product, error) {
+	if err := r.store.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.store.unlock()
+
+	var result []*entity.Product
+	for _, id := range uniqueIDs(ids) {
+		product, ok := r.store.products[id]
+		if ok && (withDeleted || !isDeleted(product.DeletedAt)) {
+			result = append(result, cloneProduct(product))
+		}
+	}
+	return result, nil
+}
+
+func (r *productRepo) GetProducts(ctx context.Context, limit, offset int) ([]*entity.Product, int64, error) {
+	if err := r.store.lock(ctx); err != nil {
+		return nil, 0, err
+	}
+	defer r.store.unlock()
+
+	var result []*entity.Product
+	for _, product := range r.store.products {
+		if !isDeleted(product.DeletedAt) {
+			result = append(result, product)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Serial < result[j].Serial })
+	total := int64(len(result))
+
+	result = paginate(result, limit, offset)
+	for i, product := range result {
+		result[i] = cloneProduct(product)
+	}
+	return result, total, nil
+}
+
+func (r *productRepo) CreateProduct(ctx context.Context, product *entity.Product, quantity int) error {
+	if err := r.store.lock(ctx); err != nil {
+		return entity.NewInternalError(err)
+	}
+	defer r.store.unlock()
+
+	// serial is unique, including soft deleted product
+	for _, existing := range r.store.products {
+		if existing.Serial == product.Serial {
+			return entity.NewError(entity.SerialExists, http.StatusConflict)
+		}
+	}
+
+	now := r.store.clock()
+	product.ID = r.store.nextID("product")
+	product.UpdatedAt = now
+	r.store.products[product.ID] = cloneProduct(product)
+	r.store.quantities[product.ID] = &entity.ProductQuantity{
+		ID:        r.store.nextID("product_quantity"),
+		ProductID: product.ID,
+		Quantity:  quantity,
+		UpdatedAt: now,
+	}
+
+	// record initial stock
+	if quantity > 0 {
+		r.store.addStockMovement(&entity.StockMovement{
+			ProductID: product.ID,
+			Quantity:  quantity,
+			Reason:    entity.StockRestock,
+			Reference: "initial stock",
+		})
+	}
+	return nil
+}
+
+func (r *productRepo) UpdateProduct(ctx context.Context, product *entity.Product) error {
+	if err := r.store.lock(ctx); err != nil {
+		return err
+	}
+	defer r.store.unlock()
+
+	existing, ok := r.store.products[product.ID]
+	if !ok || isDeleted(existing.DeletedAt) {
+		return nil
+	}
+	product.UpdatedAt = r.store.clock()
+	existing.Name = product.Name
+	existing.Price = product.Price
+	existing.TaxClass = product.TaxClass
+	existing.UpdatedAt = product.UpdatedAt
+	return nil
+}
+
+func (r *productRepo) DeleteProduct(ctx context.Context, id int64) error {
+	if err := r.store.lock(ctx); err != nil {
+		return err
+	}
+	defer r.store.unlock()
+
+	existing, ok := r.store.products[id]
+	if ok && !isDeleted(existing.DeletedAt) {
+		existing.DeletedAt = gorm.DeletedAt{Time: r.store.clock(), Valid: true}
+	}
+	return nil
+}
+
+func (r *productRepo) GetProductQuantityByIDs(ctx context.Context, productIDs []int64) ([]*entity.ProductQuantity, error) {
+	if err := r.store.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.store.unlock()
+
+	return r.getProductQuantities(productIDs), nil
+}
+
+func (r *productRepo) GetAvailableQuantityByIDs(ctx context.Context, productIDs []int64) ([]*entity.ProductQuantity, error) {
+	if err := r.store.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.store.unlock()
+
+	result := r.getProductQuantities(productIDs)
+	held := r.heldQuantities(0)
+	for _, qty := range result {
+		qty.Quantity -= held[qty.ProductID]
+	}
+	return result, nil
+}
+
+func (r *productRepo) ReserveStock(ctx context.Context, reservation *entity.Reservation) error {
+	if err := r.store.lock(ctx); err != nil {
+		return entity.NewInternalError(err)
+	}
+	defer r.store.unlock()
+
+	held := r.heldQuantities(0)
+	for _, item := range reservation.Items {
+		available := -held[item.ProductID]
+		if qty, ok := r.store.quantities[item.ProductID]; ok {
+			available += qty.Quantity
+		}
+		if item.Quantity > available {
+			return entity.NewError(
+				fmt.Sprintf("reserve item %s(%s) exceeds available quantity, only %d items available",
+					item.Product.Name, item.Product.Serial, max(available, 0)),
+				http.StatusBadRequest)
+		}
+	}
+
+	now := r.store.clock()
+	reservation.ID = r.store.nextID("reservation")
+	if reservation.CreatedAt.IsZero() {
+		reservation.CreatedAt = now
+	}
+	if reservation.UpdatedAt.IsZero() {
+		reservation.UpdatedAt = now
+	}
+	stored := *reservation
+	stored.Items = nil
+	for _, item := range reservation.Items {
+		item.ID = r.store.nextID("reservation_item")
+		item.ReservationID = reservation.ID
+		clone := *item
+		clone.Product = nil
+		stored.Items = append(stored.Items, &clone)
+	}
+	r.store.reservations[reservation.ID] = &stored
+	return nil
+}
+
+func (r *productRepo) SubmitCheckout(ctx context.Context, payload *entity.Checkout) error {
+	if err := r.store.lock(ctx); err != nil {
+		return entity.NewInternalError(err)
+	}
+	defer r.store.unlock()
+
+	// validate everything before any change, so failed checkout changes nothing like rolled back transaction
+	cart, err := r.getOpenCart(payload.CartID)
+	if err != nil {
+		return err
+	}
+	reservation, err := r.getActiveReservation(payload.ReservationID)
+	if err != nil {
+		return err
+	}
+
+	// stock held by other active reservations cannot be sold
+	held := r.heldQuantities(payload.ReservationID)
+	newQuantities := make(map[int64]int)
+	for _, item := range payload.Items {
+		current, ok := newQuantities[item.Product.ID]
+		if !ok {
+			if qty, found := r.store.quantities[item.Product.ID]; found {
+				current = qty.Quantity
+			}
+		}
+		newQuantity := current - item.Quantity
+		if newQuantity < held[item.Product.ID] {
+			return entity.NewError(
+				fmt.Sprintf("checkout item %s(%s) exceeds existing quantity, only %d items remaining",
+					item.Product.Name, item.Product.Serial, max(current-held[item.Product.ID], 0)),
+				http.StatusBadRequest)
+		}
+		newQuantities[item.Product.ID] = newQuantity
+	}
+
+	coupons, err := r.validateCoupons(payload)
+	if err != nil {
+		return err
+	}
+
+	// update product quantity
+	now := r.store.clock()
+	for productID, quantity := range newQuantities {
+		qty := r.store.productQuantity(productID)
+		qty.Quantity = quantity
+		qty.UpdatedAt = now
+	}
+
+	r.createOrder(payload)
+	r.createCheckoutStockMovements(payload)
+	event, err := entity.NewOrderPlacedOutbox(payload, now)
+	if err != nil {
+		return entity.NewInternalError(err)
+	}
+	event.ID = r.store.nextID("outbox")
+	r.store.outbox = append(r.store.outbox, event)
+
+	// redeem coupons
+	for _, coupon := range coupons {
+		coupon.RedeemedCount++
+		r.store.redemptions = append(r.store.redemptions, &entity.CouponRedemption{
+			ID:         r.store.nextID("coupon_redemption"),
+			CouponID:   coupon.ID,
+			OrderID:    payload.OrderID,
+			CustomerID: payload.CustomerID,
+			CreatedAt:  now,
+		})
+	}
+
+	// confirm the reservation, its held stock is sold
+	if reservation != nil {
+		reservation.Status = entity.ReservationConfirmed
+		reservation.OrderID = payload.OrderID
+		reservation.UpdatedAt = now
+	}
+	if cart != nil {
+		cart.Status = entity.CartCheckedOut
+		cart.UpdatedAt = now
+	}
+	payload.RemainingStock = newQuantities
+	return nil
+}
+
+// get the cart of checkout and validate it is still open, return nil if checkout has no cart
+func (r *productRepo) getOpenCart(cartID int64) (*entity.Cart, error) {
+	if cartID == 0 {
+		return nil, nil
+	}
+
+	cart, ok := r.store.carts[cartID]
+	if !ok || cart.Status != entity.CartOpen {
+		return nil, entity.NewError(entity.CartClosed, http.StatusBadRequest)
+	}
+	return cart, nil
+}
+
+// get the reservation of checkout and validate it is still active, return nil if checkout has no reservation
+func (r *productRepo) getActiveReservation(reservationID int64) (*entity.Reservation, error) {
+	if reservationID == 0 {
+		return nil, nil
+	}
+
+	reservation, ok := r.store.reservations[reservationID]
+	if !ok {
+		return nil, entity.NewError(entity.ReservationNotFound, http.StatusNotFound)
+	}
+	if reservation.Status != entity.ReservationActive {
+		return nil, entity.NewError(entity.ReservationNotActive, http.StatusConflict)
+	}
+	if !reservation.IsActive(r.store.clock()) {
+		return nil, entity.NewError(entity.ReservationHasExpired, http.StatusConflict)
+	}
+	return reservation, nil
+}
+
+// validate the redemption limits of checkout coupons, return stored coupons
+func (r *productRepo) validateCoupons(payload *entity.Checkout) ([]*entity.Coupon, error) {
+	var result []*entity.Coupon
+	for _, payloadCoupon := range payload.Coupons {
+		coupon, ok := r.store.coupons[payloadCoupon.ID]
+		// coupon is deleted after checkout is rendered
+		if !ok || isDeleted(coupon.DeletedAt) {
+			return nil, entity.NewError(entity.CouponNotFound, http.StatusBadRequest)
+		}
+		if coupon.IsExhausted() {
+			return nil, entity.NewError(fmt.Sprintf("%s: %s", entity.CouponExhausted, coupon.Code), http.StatusBadRequest)
+		}
+		if coupon.PerCustomerLimit > 0 {
+			redeemed := 0
+			for _, redemption := range r.store.redemptions {
+				if redemption.CouponID == coupon.ID && redemption.CustomerID == payload.CustomerID {
+					redeemed++
+				}
+			}
+			if redeemed >= coupon.PerCustomerLimit {
+				return nil, entity.NewError(fmt.Sprintf("%s: %s", entity.CouponCustomerUsed, coupon.Code), http.StatusBadRequest)
+			}
+		}
+		result = append(result, coupon)
+	}
+	return result, nil
+}
+
+// insert order with its items, taxes and discounts, then set order id to checkout
+func (r *productRepo) createOrder(payload *entity.Checkout) {
+	order := &entity.Order{
+		ID:            r.store.nextID("order"),
+		Status:        entity.OrderPlaced,
+		TotalItem:     payload.TotalItem,
+		TotalPrice:    payload.TotalPrice,
+		DiscountPrice: payload.DiscountPrice,
+		TaxRegion:     payload.TaxRegion,
+		TaxPriceMode:  payload.TaxPriceMode,
+		TaxPrice:      payload.TaxPrice,
+		CreatedAt:     r.store.clock(),
+	}
+	for _, tax := range payload.Taxes {
+		order.Taxes = append(order.Taxes, &entity.OrderTax{
+			ID:           r.store.nextID("order_tax"),
+			OrderID:      order.ID,
+			TaxClass:     tax.TaxClass,
+			Rate:         tax.Rate,
+			TaxablePrice: tax.TaxableAmount,
+			TaxPrice:     tax.Amount,
+		})
+	}
+	for _, item := range payload.Items {
+		order.Items = append(order.Items, &entity.OrderItem{
+			ID:            r.store.nextID("order_item"),
+			OrderID:       order.ID,
+			ProductID:     item.Product.ID,
+			UnitPrice:     item.Product.Price,
+			Quantity:      item.Quantity,
+			SubTotalPrice: item.SubTotalPrice,
+			PromotionID:   item.PromotionID,
+			BundleID:      item.BundleID,
+		})
+	}
+	for _, discount := range payload.OrderDiscounts(order.ID) {
+		discount.ID = r.store.nextID("order_discount")
+		order.Discounts = append(order.Discounts, discount)
+	}
+	r.store.orders[order.ID] = order
+	payload.OrderID = order.ID
+}
+
+// record sold and free items as stock movement of the order
+func (r *productRepo) createCheckoutStockMovements(payload *entity.Checkout) {
+	reference := entity.OrderReference(payload.OrderID)
+	for _, item := range payload.Items {
+		if soldQuantity := item.Quantity - item.FreeQuantity; soldQuantity > 0 {
+			r.store.addStockMovement(&entity.StockMovement{
+				ProductID: item.Product.ID,
+				Quantity:  -soldQuantity,
+				Reason:    entity.StockSale,
+				Reference: reference,
+			})
+		}
+		if item.FreeQuantity > 0 {
+			r.store.addStockMovement(&entity.StockMovement{
+				ProductID: item.Product.ID,
+				Quantity:  -item.FreeQuantity,
+				Reason:    entity.StockFreeItem,
+				Reference: reference,
+			})
+		}
+	}
+}
+
+func (r *productRepo) getProductQuantities(productIDs []int64) []*entity.ProductQuantity {
+	var result []*entity.ProductQuantity
+	for _, id := range uniqueIDs(productIDs) {
+		if qty, ok := r.store.quantities[id]; ok {
+			result = append(result, cloneQuantity(qty))
+		}
+	}
+	return result
+}
+
+// quantity held by active reservations, except the given reservation
+// return map[int64] where int64 = product id
+func (r *productRepo) heldQuantities(exceptReservationID int64) map[int64]int {
+	now := r.store.clock()
+	result := make(map[int64]int)
+	for _, reservation := range r.store.reservations {
+		if reservation.ID == exceptReservationID || !reservation.IsActive(now) {
+			continue
+		}
+		for _, item := range reservation.Items {
+			result[item.ProductID] += item.Quantity
+		}
+	}
+	return result
+}
+
+// sorted ids without duplicate, like sql `in` condition
+func uniqueIDs(ids []int64) []int64 {
+	seen := make(map[int64]bool)
+	var result []int64
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i] < result[j] })
+	return result
+}
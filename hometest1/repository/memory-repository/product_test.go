@@ -0,0 +1,271 @@
+package memoryrepository_test
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"hometest1/core/entity"
+	"hometest1/core/repository"
+	memoryrepository "hometest1/repository/memory-repository"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// current time of the store clock
+var now = time.Date(2023, 5, 16, 12, 0, 0, 0, time.UTC)
+
+func initRepo() (repository.ProductRepo, repository.PromotionRepo) {
+	store := memoryrepository.NewStoreWithClock(func() time.Time { return now })
+	return memoryrepository.NewProductRepo(store), memoryrepository.NewPromotionRepo(store)
+}
+
+func createProduct(t *testing.T, repo repository.ProductRepo, serial string, quantity int) *entity.Product {
+	product := &entity.Product{Serial: serial, Name: "Product " + serial, Price: entity.NewMoney(1000)}
+	err := repo.CreateProduct(context.Background(), product, quantity)
+	assert.Nil(t, err)
+	return product
+}
+
+func Test_Product(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("positive, create, update and delete product", func(t *testing.T) {
+		repo, _ := initRepo()
+		a := createProduct(t, repo, "B-1", 5)
+		b := createProduct(t, repo, "A-1", 0)
+		assert.Equal(t, int64(1), a.ID)
+		assert.Equal(t, int64(2), b.ID)
+		assert.Equal(t, now, a.UpdatedAt)
+
+		// sorted by serial
+		products, total, err := repo.GetProducts(ctx, 10, 0)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(2), total)
+		assert.Equal(t, "A-1", products[0].Serial)
+		assert.Equal(t, "B-1", products[1].Serial)
+
+		// returned product is a copy
+		products[0].Name = "Changed"
+		found, err := repo.GetProductBySerials(ctx, []string{"A-1"})
+		assert.Nil(t, err)
+		assert.Equal(t, "Product A-1", found[0].Name)
+
+		a.Name = "Updated"
+		a.Price = entity.NewMoney(2000)
+		err = repo.UpdateProduct(ctx, a)
+		assert.Nil(t, err)
+		found, err = repo.GetProductByIDs(ctx, []int64{a.ID})
+		assert.Nil(t, err)
+		assert.Equal(t, "Updated", found[0].Name)
+		assert.Equal(t, entity.NewMoney(2000), found[0].Price)
+
+		err = repo.DeleteProduct(ctx, a.ID)
+		assert.Nil(t, err)
+		found, err = repo.GetProductByIDs(ctx, []int64{a.ID, b.ID})
+		assert.Nil(t, err)
+		assert.Len(t, found, 1)
+		found, err = repo.GetProductByIDsWithDeleted(ctx, []int64{a.ID, b.ID})
+		assert.Nil(t, err)
+		assert.Len(t, found, 2)
+
+		quantities, err := repo.GetProductQuantityByIDs(ctx, []int64{a.ID, b.ID})
+		assert.Nil(t, err)
+		assert.Equal(t, 5, quantities[0].Quantity)
+		assert.Equal(t, 0, quantities[1].Quantity)
+	})
+
+	t.Run("negative, serial exists", func(t *testing.T) {
+		repo, _ := initRepo()
+		product := createProduct(t, repo, "A-1", 1)
+		err := repo.DeleteProduct(ctx, product.ID)
+		assert.Nil(t, err)
+
+		// serial of deleted product is still unique
+		err = repo.CreateProduct(ctx, &entity.Product{Serial: "A-1"}, 0)
+		assert.Equal(t, entity.NewError(entity.SerialExists, http.StatusConflict), err)
+	})
+
+	t.Run("negative, request is cancelled", func(t *testing.T) {
+		repo, _ := initRepo()
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+		_, err := repo.GetProductBySerials(cancelled, []string{"A-1"})
+		assert.ErrorIs(t, err, context.Canceled)
+		err = repo.CreateProduct(cancelled, &entity.Product{Serial: "A-1"}, 0)
+		assert.Equal(t, entity.NewError(entity.RequestTimeout, http.StatusServiceUnavailable), err)
+	})
+}
+
+func Test_ReserveStock(t *testing.T) {
+	ctx := context.Background()
+	repo, _ := initRepo()
+	product := createProduct(t, repo, "A-1", 5)
+
+	t.Run("positive", func(t *testing.T) {
+		reservation := &entity.Reservation{
+			Status:    entity.ReservationActive,
+			ExpiresAt: now.Add(time.Minute),
+			Items:     []*entity.ReservationItem{{ProductID: product.ID, Quantity: 3, Product: product}},
+		}
+		err := repo.ReserveStock(ctx, reservation)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), reservation.ID)
+		assert.Equal(t, int64(1), reservation.Items[0].ReservationID)
+
+		available, err := repo.GetAvailableQuantityByIDs(ctx, []int64{product.ID})
+		assert.Nil(t, err)
+		assert.Equal(t, 2, available[0].Quantity)
+	})
+
+	t.Run("negative, exceeds available quantity", func(t *testing.T) {
+		err := repo.ReserveStock(ctx, &entity.Reservation{
+			Status:    entity.ReservationActive,
+			ExpiresAt: now.Add(time.Minute),
+			Items:     []*entity.ReservationItem{{ProductID: product.ID, Quantity: 3, Product: product}},
+		})
+		assert.Equal(t, entity.NewError("reserve item Product A-1(A-1) exceeds available quantity, only 2 items available", http.StatusBadRequest), err)
+	})
+}
+
+func Test_SubmitCheckout(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("positive, with reservation and coupon", func(t *testing.T) {
+		repo, promoRepo := initRepo()
+		product := createProduct(t, repo, "A-1", 5)
+		reservation := &entity.Reservation{
+			Status:    entity.ReservationActive,
+			ExpiresAt: now.Add(time.Minute),
+			Items:     []*entity.ReservationItem{{ProductID: product.ID, Quantity: 2, Product: product}},
+		}
+		err := repo.ReserveStock(ctx, reservation)
+		assert.Nil(t, err)
+		coupon := &entity.Coupon{Code: "SAVE", CartPromotionID: 1, MaxRedemptions: 1}
+		err = promoRepo.CreateCoupon(ctx, coupon)
+		assert.Nil(t, err)
+
+		payload := &entity.Checkout{
+			ReservationID: reservation.ID,
+			Items:         []*entity.CheckoutItem{{Product: product, Quantity: 2, SubTotalPrice: entity.NewMoney(2000)}},
+			TotalItem:     2,
+			TotalPrice:    entity.NewMoney(2000),
+			Coupons:       []*entity.Coupon{coupon},
+		}
+		err = repo.SubmitCheckout(ctx, payload)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), payload.OrderID)
+		assert.Equal(t, map[int64]int{product.ID: 3}, payload.RemainingStock)
+
+		quantities, err := repo.GetAvailableQuantityByIDs(ctx, []int64{product.ID})
+		assert.Nil(t, err)
+		assert.Equal(t, 3, quantities[0].Quantity)
+		coupon, err = promoRepo.GetCoupon(ctx, coupon.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, coupon.RedeemedCount)
+
+		// reservation is confirmed
+		err = repo.SubmitCheckout(ctx, payload)
+		assert.Equal(t, entity.NewError(entity.ReservationNotActive, http.StatusConflict), err)
+	})
+
+	t.Run("negative, held by other reservation, nothing is changed", func(t *testing.T) {
+		repo, promoRepo := initRepo()
+		product := createProduct(t, repo, "A-1", 5)
+		err := repo.ReserveStock(ctx, &entity.Reservation{
+			Status:    entity.ReservationActive,
+			ExpiresAt: now.Add(time.Minute),
+			Items:     []*entity.ReservationItem{{ProductID: product.ID, Quantity: 4, Product: product}},
+		})
+		assert.Nil(t, err)
+		coupon := &entity.Coupon{Code: "SAVE", CartPromotionID: 1}
+		err = promoRepo.CreateCoupon(ctx, coupon)
+		assert.Nil(t, err)
+
+		err = repo.SubmitCheckout(ctx, &entity.Checkout{
+			Items:   []*entity.CheckoutItem{{Product: product, Quantity: 2}},
+			Coupons: []*entity.Coupon{coupon},
+		})
+		assert.Equal(t, entity.NewError("checkout item Product A-1(A-1) exceeds existing quantity, only 1 items remaining", http.StatusBadRequest), err)
+
+		quantities, err := repo.GetProductQuantityByIDs(ctx, []int64{product.ID})
+		assert.Nil(t, err)
+		assert.Equal(t, 5, quantities[0].Quantity)
+		coupon, err = promoRepo.GetCoupon(ctx, coupon.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, 0, coupon.RedeemedCount)
+	})
+
+	t.Run("negative, coupon exhausted", func(t *testing.T) {
+		repo, promoRepo := initRepo()
+		product := createProduct(t, repo, "A-1", 5)
+		coupon := &entity.Coupon{Code: "SAVE", CartPromotionID: 1, PerCustomerLimit: 1}
+		err := promoRepo.CreateCoupon(ctx, coupon)
+		assert.Nil(t, err)
+		checkout := func() error {
+			return repo.SubmitCheckout(ctx, &entity.Checkout{
+				Items:      []*entity.CheckoutItem{{Product: product, Quantity: 1}},
+				Coupons:    []*entity.Coupon{coupon},
+				CustomerID: "customer-1",
+			})
+		}
+
+		assert.Nil(t, checkout())
+		assert.Equal(t, entity.NewError(entity.CouponCustomerUsed+": SAVE", http.StatusBadRequest), checkout())
+	})
+
+	t.Run("positive, cart is checked out once", func(t *testing.T) {
+		store := memoryrepository.NewStoreWithClock(func() time.Time { return now })
+		repo := memoryrepository.NewProductRepo(store)
+		cartRepo := memoryrepository.NewCartRepo(store)
+		product := createProduct(t, repo, "A-1", 5)
+		cart, err := cartRepo.CreateCart(ctx)
+		assert.Nil(t, err)
+		checkout := func() error {
+			return repo.SubmitCheckout(ctx, &entity.Checkout{
+				CartID: cart.ID,
+				Items:  []*entity.CheckoutItem{{Product: product, Quantity: 1}},
+			})
+		}
+
+		assert.Nil(t, checkout())
+		assert.Equal(t, entity.NewError(entity.CartClosed, http.StatusBadRequest), checkout())
+		cart, err = cartRepo.GetCart(ctx, cart.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, entity.CartCheckedOut, cart.Status)
+		quantities, err := repo.GetProductQuantityByIDs(ctx, []int64{product.ID})
+		assert.Nil(t, err)
+		assert.Equal(t, 4, quantities[0].Quantity)
+	})
+
+	t.Run("positive, concurrent checkouts do not oversell", func(t *testing.T) {
+		repo, _ := initRepo()
+		product := createProduct(t, repo, "A-1", 10)
+
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+		succeed := 0
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				err := repo.SubmitCheckout(ctx, &entity.Checkout{
+					Items: []*entity.CheckoutItem{{Product: product, Quantity: 1}},
+				})
+				if err == nil {
+					mu.Lock()
+					succeed++
+					mu.Unlock()
+				}
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, 10, succeed)
+		quantities, err := repo.GetProductQuantityByIDs(ctx, []int64{product.ID})
+		assert.Nil(t, err)
+		assert.Equal(t, 0, quantities[0].Quantity)
+	})
+}
@@ -0,0 +1,554 @@
+package memoryrepository
+
+import (
+	"context"
+	"net/http"
+	"sort"
+
+	"hometest1/core/entity"
+	"hometest1/core/repository"
+
+	"gorm.io/gorm"
+)
+
+type promotionRepo struct {
+	store *Store
+}
+
+func NewPromotionRepo(store *Store) repository.PromotionRepo {
+	return &promotionRepo{store}
+}
+
+func (r *promotionRepo) GetPromotionByProducts(ctx context.Context, products []*entity.Product) (map[int64][]*entity.Promotion, error) {
+	if err := r.store.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.store.unlock()
+
+	mapProduct := make(map[int64]bool)
+	for _, p := range products {
+		mapProduct[p.ID] = true
+	}
+
+	// get active promotions by product id
+	var promotions []*entity.Promotion
+	now := r.store.clock()
+	for _, promo := range r.store.promotions {
+		if mapProduct[promo.ProductID] && !isDeleted(promo.DeletedAt) && inPeriod(promo.StartsAt, promo.EndsAt, now) {
+			promotions = append(promotions, promo)
+		}
+	}
+	sortPromotionsByProduct(promotions)
+
+	// maping product
+	result := map[int64][]*entity.Promotion{}
+	for _, promo := range promotions {
+		result[promo.ProductID] = append(result[promo.ProductID], clonePromotion(promo))
+	}
+	return result, nil
+}
+
+func (r *promotionRepo) GetPromotionByIDsWithDeleted(ctx context.Context, ids []int64) ([]*entity.Promotion, error) {
+	if err := r.store.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.store.unlock()
+
+	var result []*entity.Promotion
+	for _, id := range uniqueIDs(ids) {
+		if promo, ok := r.store.promotions[id]; ok {
+			result = append(result, clonePromotion(promo))
+		}
+	}
+	sortPromotionsByProduct(result)
+	return result, nil
+}
+
+func (r *promotionRepo) GetUpcomingPromotionsByProduct(ctx context.Context, productID int64) ([]*entity.Promotion, error) {
+	if err := r.store.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.store.unlock()
+
+	return r.findPromotions(func(promo *entity.Promotion) bool {
+		return promo.ProductID == productID && notEnded(promo.EndsAt, r.store.clock())
+	}), nil
+}
+
+func (r *promotionRepo) GetPromotion(ctx context.Context, id int64) (*entity.Promotion, error) {
+	if err := r.store.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.store.unlock()
+
+	promo, ok := r.store.promotions[id]
+	if !ok || isDeleted(promo.DeletedAt) {
+		return nil, nil
+	}
+	return clonePromotion(promo), nil
+}
+
+func (r *promotionRepo) GetPromotions(ctx context.Context, limit, offset int) ([]*entity.Promotion, int64, error) {
+	if err := r.store.lock(ctx); err != nil {
+		return nil, 0, err
+	}
+	defer r.store.unlock()
+
+	result := r.findPromotions(func(*entity.Promotion) bool { return true })
+	return paginate(result, limit, offset), int64(len(result)), nil
+}
+
+func (r *promotionRepo) GetFreeItemPromotions(ctx context.Context) ([]*entity.Promotion, error) {
+	if err := r.store.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.store.unlock()
+
+	return r.findPromotions(func(promo *entity.Promotion) bool {
+		return promo.PromoProductID != 0 && notEnded(promo.EndsAt, r.store.clock())
+	}), nil
+}
+
+func (r *promotionRepo) CreatePromotion(ctx context.Context, promo *entity.Promotion) error {
+	if err := r.store.lock(ctx); err != nil {
+		return err
+	}
+	defer r.store.unlock()
+
+	promo.ID = r.store.nextID("promotion")
+	promo.UpdatedAt = r.store.clock()
+	r.store.promotions[promo.ID] = clonePromotion(promo)
+	return nil
+}
+
+func (r *promotionRepo) UpdatePromotion(ctx context.Context, promo *entity.Promotion) error {
+	if err := r.store.lock(ctx); err != nil {
+		return err
+	}
+	defer r.store.unlock()
+
+	existing, ok := r.store.promotions[promo.ID]
+	if !ok || isDeleted(existing.DeletedAt) {
+		return nil
+	}
+	promo.UpdatedAt = r.store.clock()
+	promo.DeletedAt = existing.DeletedAt
+	r.store.promotions[promo.ID] = clonePromotion(promo)
+	return nil
+}
+
+func (r *promotionRepo) DeletePromotion(ctx context.Context, id int64) error {
+	if err := r.store.lock(ctx); err != nil {
+		return err
+	}
+	defer r.store.unlock()
+
+	if promo, ok := r.store.promotions[id]; ok && !isDeleted(promo.DeletedAt) {
+		promo.DeletedAt = r.deletedAt()
+	}
+	return nil
+}
+
+func (r *promotionRepo) GetActiveCartPromotions(ctx context.Context, couponPromotionIDs []int64) ([]*entity.CartPromotion, error) {
+	if err := r.store.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.store.unlock()
+
+	// promotion linked to a coupon, including deleted coupon, needs the coupon code
+	couponPromotions := make(map[int64]bool)
+	for _, coupon := range r.store.coupons {
+		couponPromotions[coupon.CartPromotionID] = true
+	}
+	redeemed := make(map[int64]bool)
+	for _, id := range couponPromotionIDs {
+		redeemed[id] = true
+	}
+
+	var result []*entity.CartPromotion
+	now := r.store.clock()
+	for _, promo := range r.store.cartPromotions {
+		if isDeleted(promo.DeletedAt) || !inPeriod(promo.StartsAt, promo.EndsAt, now) {
+			continue
+		}
+		if couponPromotions[promo.ID] && !redeemed[promo.ID] {
+			continue
+		}
+		result = append(result, cloneCartPromotion(promo))
+	}
+	sortCartPromotions(result)
+	return result, nil
+}
+
+func (r *promotionRepo) GetCartPromotionByIDsWithDeleted(ctx context.Context, ids []int64) ([]*entity.CartPromotion, error) {
+	if err := r.store.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.store.unlock()
+
+	var result []*entity.CartPromotion
+	for _, id := range uniqueIDs(ids) {
+		if promo, ok := r.store.cartPromotions[id]; ok {
+			result = append(result, cloneCartPromotion(promo))
+		}
+	}
+	sortCartPromotions(result)
+	return result, nil
+}
+
+func (r *promotionRepo) GetCartPromotion(ctx context.Context, id int64) (*entity.CartPromotion, error) {
+	if err := r.store.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.store.unlock()
+
+	promo, ok := r.store.cartPromotions[id]
+	if !ok || isDeleted(promo.DeletedAt) {
+		return nil, nil
+	}
+	return cloneCartPromotion(promo), nil
+}
+
+func (r *promotionRepo) GetCartPromotions(ctx context.Context, limit, offset int) ([]*entity.CartPromotion, int64, error) {
+	if err := r.store.lock(ctx); err != nil {
+		return nil, 0, err
+	}
+	defer r.store.unlock()
+
+	var result []*entity.CartPromotion
+	for _, promo := range r.store.cartPromotions {
+		if !isDeleted(promo.DeletedAt) {
+			result = append(result, cloneCartPromotion(promo))
+		}
+	}
+	sortByID(result, func(p *entity.CartPromotion) int64 { return p.ID })
+	return paginate(result, limit, offset), int64(len(result)), nil
+}
+
+func (r *promotionRepo) CreateCartPromotion(ctx context.Context, promo *entity.CartPromotion) error {
+	if err := r.store.lock(ctx); err != nil {
+		return err
+	}
+	defer r.store.unlock()
+
+	promo.ID = r.store.nextID("cart_promotion")
+	promo.UpdatedAt = r.store.clock()
+	r.store.cartPromotions[promo.ID] = cloneCartPromotion(promo)
+	return nil
+}
+
+func (r *promotionRepo) UpdateCartPromotion(ctx context.Context, promo *entity.CartPromotion) error {
+	if err := r.store.lock(ctx); err != nil {
+		return err
+	}
+	defer r.store.unlock()
+
+	existing, ok := r.store.cartPromotions[promo.ID]
+	if !ok || isDeleted(existing.DeletedAt) {
+		return nil
+	}
+	promo.UpdatedAt = r.store.clock()
+	promo.DeletedAt = existing.DeletedAt
+	r.store.cartPromotions[promo.ID] = cloneCartPromotion(promo)
+	return nil
+}
+
+func (r *promotionRepo) DeleteCartPromotion(ctx context.Context, id int64) error {
+	if err := r.store.lock(ctx); err != nil {
+		return err
+	}
+	defer r.store.unlock()
+
+	if promo, ok := r.store.cartPromotions[id]; ok && !isDeleted(promo.DeletedAt) {
+		promo.DeletedAt = r.deletedAt()
+	}
+	return nil
+}
+
+func (r *promotionRepo) GetCouponsByCodes(ctx context.Context, codes []string) ([]*entity.Coupon, error) {
+	if err := r.store.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.store.unlock()
+
+	mapCode := make(map[string]bool)
+	for _, code := range codes {
+		mapCode[code] = true
+	}
+	var result []*entity.Coupon
+	for _, coupon := range r.store.coupons {
+		if mapCode[coupon.Code] && !isDeleted(coupon.DeletedAt) {
+			result = append(result, cloneCoupon(coupon))
+		}
+	}
+	sortByID(result, func(c *entity.Coupon) int64 { return c.ID })
+	return result, nil
+}
+
+func (r *promotionRepo) GetCoupon(ctx context.Context, id int64) (*entity.Coupon, error) {
+	if err := r.store.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.store.unlock()
+
+	coupon, ok := r.store.coupons[id]
+	if !ok || isDeleted(coupon.DeletedAt) {
+		return nil, nil
+	}
+	return cloneCoupon(coupon), nil
+}
+
+func (r *promotionRepo) GetCoupons(ctx context.Context, limit, offset int) ([]*entity.Coupon, int64, error) {
+	if err := r.store.lock(ctx); err != nil {
+		return nil, 0, err
+	}
+	defer r.store.unlock()
+
+	var result []*entity.Coupon
+	for _, coupon := range r.store.coupons {
+		if !isDeleted(coupon.DeletedAt) {
+			result = append(result, cloneCoupon(coupon))
+		}
+	}
+	sortByID(result, func(c *entity.Coupon) int64 { return c.ID })
+	return paginate(result, limit, offset), int64(len(result)), nil
+}
+
+func (r *promotionRepo) CreateCoupon(ctx context.Context, coupon *entity.Coupon) error {
+	if err := r.store.lock(ctx); err != nil {
+		return entity.NewInternalError(err)
+	}
+	defer r.store.unlock()
+
+	if r.couponCodeExists(coupon.Code, 0) {
+		return entity.NewError(entity.CouponCodeExists, http.StatusConflict)
+	}
+	coupon.ID = r.store.nextID("coupon")
+	coupon.UpdatedAt = r.store.clock()
+	r.store.coupons[coupon.ID] = cloneCoupon(coupon)
+	return nil
+}
+
+func (r *promotionRepo) UpdateCoupon(ctx context.Context, coupon *entity.Coupon) error {
+	if err := r.store.lock(ctx); err != nil {
+		return entity.NewInternalError(err)
+	}
+	defer r.store.unlock()
+
+	existing, ok := r.store.coupons[coupon.ID]
+	if !ok || isDeleted(existing.DeletedAt) {
+		return nil
+	}
+	if r.couponCodeExists(coupon.Code, coupon.ID) {
+		return entity.NewError(entity.CouponCodeExists, http.StatusConflict)
+	}
+
+	// redeemed count is only changed by checkout
+	coupon.UpdatedAt = r.store.clock()
+	existing.Code = coupon.Code
+	existing.CartPromotionID = coupon.CartPromotionID
+	existing.MaxRedemptions = coupon.MaxRedemptions
+	existing.PerCustomerLimit = coupon.PerCustomerLimit
+	existing.ExpiresAt = copyTime(coupon.ExpiresAt)
+	existing.UpdatedAt = coupon.UpdatedAt
+	return nil
+}
+
+func (r *promotionRepo) DeleteCoupon(ctx context.Context, id int64) error {
+	if err := r.store.lock(ctx); err != nil {
+		return err
+	}
+	defer r.store.unlock()
+
+	if coupon, ok := r.store.coupons[id]; ok && !isDeleted(coupon.DeletedAt) {
+		coupon.DeletedAt = r.deletedAt()
+	}
+	return nil
+}
+
+func (r *promotionRepo) GetActiveBundlesByProducts(ctx context.Context, productIDs []int64) ([]*entity.Bundle, error) {
+	if err := r.store.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.store.unlock()
+
+	mapProduct := make(map[int64]bool)
+	for _, id := range productIDs {
+		mapProduct[id] = true
+	}
+
+	var result []*entity.Bundle
+	now := r.store.clock()
+	for _, bundle := range r.store.bundles {
+		if isDeleted(bundle.DeletedAt) || !inPeriod(bundle.StartsAt, bundle.EndsAt, now) {
+			continue
+		}
+		for _, item := range bundle.Items {
+			if mapProduct[item.ProductID] {
+				result = append(result, cloneBundle(bundle))
+				break
+			}
+		}
+	}
+	sortBundles(result)
+	return result, nil
+}
+
+func (r *promotionRepo) GetBundleByIDsWithDeleted(ctx context.Context, ids []int64) ([]*entity.Bundle, error) {
+	if err := r.store.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.store.unlock()
+
+	var result []*entity.Bundle
+	for _, id := range uniqueIDs(ids) {
+		if bundle, ok := r.store.bundles[id]; ok {
+			result = append(result, cloneBundle(bundle))
+		}
+	}
+	sortBundles(result)
+	return result, nil
+}
+
+func (r *promotionRepo) GetBundle(ctx context.Context, id int64) (*entity.Bundle, error) {
+	if err := r.store.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.store.unlock()
+
+	bundle, ok := r.store.bundles[id]
+	if !ok || isDeleted(bundle.DeletedAt) {
+		return nil, nil
+	}
+	return cloneBundle(bundle), nil
+}
+
+func (r *promotionRepo) GetBundles(ctx context.Context, limit, offset int) ([]*entity.Bundle, int64, error) {
+	if err := r.store.lock(ctx); err != nil {
+		return nil, 0, err
+	}
+	defer r.store.unlock()
+
+	var result []*entity.Bundle
+	for _, bundle := range r.store.bundles {
+		if !isDeleted(bundle.DeletedAt) {
+			result = append(result, cloneBundle(bundle))
+		}
+	}
+	sortByID(result, func(b *entity.Bundle) int64 { return b.ID })
+	return paginate(result, limit, offset), int64(len(result)), nil
+}
+
+func (r *promotionRepo) CreateBundle(ctx context.Context, bundle *entity.Bundle) error {
+	if err := r.store.lock(ctx); err != nil {
+		return err
+	}
+	defer r.store.unlock()
+
+	bundle.ID = r.store.nextID("bundle")
+	bundle.UpdatedAt = r.store.clock()
+	r.setBundleItemIDs(bundle)
+	r.storeBundle(bundle)
+	return nil
+}
+
+func (r *promotionRepo) UpdateBundle(ctx context.Context, bundle *entity.Bundle) error {
+	if err := r.store.lock(ctx); err != nil {
+		return err
+	}
+	defer r.store.unlock()
+
+	existing, ok := r.store.bundles[bundle.ID]
+	if !ok || isDeleted(existing.DeletedAt) {
+		return nil
+	}
+
+	// replace bundle items
+	bundle.UpdatedAt = r.store.clock()
+	bundle.DeletedAt = existing.DeletedAt
+	r.setBundleItemIDs(bundle)
+	r.storeBundle(bundle)
+	return nil
+}
+
+func (r *promotionRepo) DeleteBundle(ctx context.Context, id int64) error {
+	if err := r.store.lock(ctx); err != nil {
+		return err
+	}
+	defer r.store.unlock()
+
+	if bundle, ok := r.store.bundles[id]; ok && !isDeleted(bundle.DeletedAt) {
+		bundle.DeletedAt = r.deletedAt()
+	}
+	return nil
+}
+
+// get promotions that match the filter, except soft deleted, sorted by id
+func (r *promotionRepo) findPromotions(filter func(*entity.Promotion) bool) []*entity.Promotion {
+	var result []*entity.Promotion
+	for _, promo := range r.store.promotions {
+		if !isDeleted(promo.DeletedAt) && filter(promo) {
+			result = append(result, clonePromotion(promo))
+		}
+	}
+	sortByID(result, func(p *entity.Promotion) int64 { return p.ID })
+	return result
+}
+
+// code is unique, including soft deleted coupon
+func (r *promotionRepo) couponCodeExists(code string, exceptID int64) bool {
+	for _, coupon := range r.store.coupons {
+		if coupon.Code == code && coupon.ID != exceptID {
+			return true
+		}
+	}
+	return false
+}
+
+// set new id to bundle items
+func (r *promotionRepo) setBundleItemIDs(bundle *entity.Bundle) {
+	for _, item := range bundle.Items {
+		item.ID = r.store.nextID("bundle_item")
+		item.BundleID = bundle.ID
+	}
+}
+
+// store clone of the bundle, items are sorted by product id like loaded from database
+func (r *promotionRepo) storeBundle(bundle *entity.Bundle) {
+	stored := cloneBundle(bundle)
+	sort.Slice(stored.Items, func(i, j int) bool { return stored.Items[i].ProductID < stored.Items[j].ProductID })
+	r.store.bundles[bundle.ID] = stored
+}
+
+func (r *promotionRepo) deletedAt() gorm.DeletedAt {
+	return gorm.DeletedAt{Time: r.store.clock(), Valid: true}
+}
+
+// sort promotions by product, then by priority like they are applied on checkout
+func sortPromotionsByProduct(promotions []*entity.Promotion) {
+	sort.Slice(promotions, func(i, j int) bool {
+		a, b := promotions[i], promotions[j]
+		if a.ProductID != b.ProductID {
+			return a.ProductID < b.ProductID
+		}
+		if a.Priority != b.Priority {
+			return a.Priority > b.Priority
+		}
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		return a.ID < b.ID
+	})
+}
+
+func sortCartPromotions(promotions []*entity.CartPromotion) {
+	sortByPriority(promotions,
+		func(p *entity.CartPromotion) int { return p.Priority },
+		func(p *entity.CartPromotion) int64 { return p.ID })
+}
+
+func sortBundles(bundles []*entity.Bundle) {
+	sortByPriority(bundles,
+		func(b *entity.Bundle) int { return b.Priority },
+		func(b *entity.Bundle) int64 { return b.ID })
+}
@@ -0,0 +1,181 @@
+package memoryrepository_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"hometest1/core/entity"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_GetPromotionByProducts(t *testing.T) {
+	ctx := context.Background()
+	productRepo, repo := initRepo()
+	a := createProduct(t, productRepo, "A-1", 1)
+	b := createProduct(t, productRepo, "B-1", 1)
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	promotions := []*entity.Promotion{
+		{Type: entity.PromotionType(3), ProductID: a.ID, MatchQuantity: 3, PromoValue: 10},
+		{Type: entity.PromotionType(1), ProductID: a.ID, MatchQuantity: 1, PromoProductID: b.ID, Priority: 1},
+		// expired
+		{Type: entity.PromotionType(3), ProductID: a.ID, EndsAt: &past},
+		// scheduled
+		{Type: entity.PromotionType(3), ProductID: b.ID, StartsAt: &future},
+	}
+	for _, promo := range promotions {
+		err := repo.CreatePromotion(ctx, promo)
+		assert.Nil(t, err)
+	}
+
+	t.Run("positive", func(t *testing.T) {
+		result, err := repo.GetPromotionByProducts(ctx, []*entity.Product{a, b})
+		assert.Nil(t, err)
+		assert.Len(t, result, 1)
+		// sorted by priority
+		assert.Equal(t, []int64{2, 1}, []int64{result[a.ID][0].ID, result[a.ID][1].ID})
+
+		upcoming, err := repo.GetUpcomingPromotionsByProduct(ctx, b.ID)
+		assert.Nil(t, err)
+		assert.Len(t, upcoming, 1)
+
+		freeItems, err := repo.GetFreeItemPromotions(ctx)
+		assert.Nil(t, err)
+		assert.Len(t, freeItems, 1)
+	})
+
+	t.Run("positive, deleted promotion", func(t *testing.T) {
+		err := repo.DeletePromotion(ctx, 2)
+		assert.Nil(t, err)
+		result, err := repo.GetPromotionByProducts(ctx, []*entity.Product{a})
+		assert.Nil(t, err)
+		assert.Len(t, result[a.ID], 1)
+		promo, err := repo.GetPromotion(ctx, 2)
+		assert.Nil(t, err)
+		assert.Nil(t, promo)
+		list, total, err := repo.GetPromotions(ctx, 2, 0)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(3), total)
+		assert.Len(t, list, 2)
+
+		// deleted and expired promotions of an order
+		applied, err := repo.GetPromotionByIDsWithDeleted(ctx, []int64{3, 2, 3})
+		assert.Nil(t, err)
+		assert.Len(t, applied, 2)
+		assert.Equal(t, []int64{2, 3}, []int64{applied[0].ID, applied[1].ID})
+	})
+}
+
+func Test_CartPromotionAndCoupon(t *testing.T) {
+	ctx := context.Background()
+	_, repo := initRepo()
+
+	open := &entity.CartPromotion{MinSpend: entity.NewMoney(1000), PromoValue: 10}
+	withCoupon := &entity.CartPromotion{MinSpend: entity.NewMoney(1000), PromoValue: 20, Priority: 1}
+	for _, promo := range []*entity.CartPromotion{open, withCoupon} {
+		err := repo.CreateCartPromotion(ctx, promo)
+		assert.Nil(t, err)
+	}
+	coupon := &entity.Coupon{Code: "SAVE", CartPromotionID: withCoupon.ID}
+	err := repo.CreateCoupon(ctx, coupon)
+	assert.Nil(t, err)
+
+	t.Run("positive, coupon promotion needs the code", func(t *testing.T) {
+		result, err := repo.GetActiveCartPromotions(ctx, nil)
+		assert.Nil(t, err)
+		assert.Len(t, result, 1)
+		assert.Equal(t, open.ID, result[0].ID)
+
+		result, err = repo.GetActiveCartPromotions(ctx, []int64{withCoupon.ID})
+		assert.Nil(t, err)
+		assert.Len(t, result, 2)
+		assert.Equal(t, withCoupon.ID, result[0].ID)
+	})
+
+	t.Run("negative, coupon code exists", func(t *testing.T) {
+		err := repo.CreateCoupon(ctx, &entity.Coupon{Code: "SAVE", CartPromotionID: open.ID})
+		assert.Equal(t, entity.NewError(entity.CouponCodeExists, http.StatusConflict), err)
+
+		other := &entity.Coupon{Code: "OTHER", CartPromotionID: open.ID}
+		err = repo.CreateCoupon(ctx, other)
+		assert.Nil(t, err)
+		other.Code = "SAVE"
+		err = repo.UpdateCoupon(ctx, other)
+		assert.Equal(t, entity.NewError(entity.CouponCodeExists, http.StatusConflict), err)
+	})
+
+	t.Run("positive, get coupons by codes", func(t *testing.T) {
+		result, err := repo.GetCouponsByCodes(ctx, []string{"SAVE", "UNKNOWN"})
+		assert.Nil(t, err)
+		assert.Len(t, result, 1)
+		assert.Equal(t, coupon.ID, result[0].ID)
+	})
+
+	t.Run("positive, get deleted coupon promotion by id", func(t *testing.T) {
+		err := repo.DeleteCartPromotion(ctx, withCoupon.ID)
+		assert.Nil(t, err)
+
+		result, err := repo.GetCartPromotionByIDsWithDeleted(ctx, []int64{open.ID, withCoupon.ID})
+		assert.Nil(t, err)
+		assert.Len(t, result, 2)
+		// sorted by priority
+		assert.Equal(t, withCoupon.ID, result[0].ID)
+	})
+}
+
+func Test_Bundle(t *testing.T) {
+	ctx := context.Background()
+	productRepo, repo := initRepo()
+	a := createProduct(t, productRepo, "A-1", 1)
+	b := createProduct(t, productRepo, "B-1", 1)
+
+	bundle := &entity.Bundle{
+		Name:  "Pair",
+		Price: entity.NewMoney(1500),
+		Items: []*entity.BundleItem{{ProductID: b.ID, Quantity: 1}, {ProductID: a.ID, Quantity: 1}},
+	}
+	err := repo.CreateBundle(ctx, bundle)
+	assert.Nil(t, err)
+
+	t.Run("positive", func(t *testing.T) {
+		result, err := repo.GetActiveBundlesByProducts(ctx, []int64{a.ID})
+		assert.Nil(t, err)
+		assert.Len(t, result, 1)
+		// items are sorted by product id
+		assert.Equal(t, a.ID, result[0].Items[0].ProductID)
+		assert.Equal(t, bundle.ID, result[0].Items[0].BundleID)
+	})
+
+	t.Run("positive, replace items", func(t *testing.T) {
+		bundle.Items = []*entity.BundleItem{{ProductID: b.ID, Quantity: 2}}
+		err := repo.UpdateBundle(ctx, bundle)
+		assert.Nil(t, err)
+
+		result, err := repo.GetBundle(ctx, bundle.ID)
+		assert.Nil(t, err)
+		assert.Len(t, result.Items, 1)
+		assert.Equal(t, 2, result.Items[0].Quantity)
+
+		active, err := repo.GetActiveBundlesByProducts(ctx, []int64{a.ID})
+		assert.Nil(t, err)
+		assert.Empty(t, active)
+	})
+
+	t.Run("positive, delete", func(t *testing.T) {
+		err := repo.DeleteBundle(ctx, bundle.ID)
+		assert.Nil(t, err)
+		result, total, err := repo.GetBundles(ctx, 10, 0)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(0), total)
+		assert.Empty(t, result)
+
+		result, err = repo.GetBundleByIDsWithDeleted(ctx, []int64{bundle.ID})
+		assert.Nil(t, err)
+		assert.Len(t, result, 1)
+		assert.Len(t, result[0].Items, 1)
+	})
+}
@@ -0,0 +1,64 @@
+package memoryrepository
+
+import (
+	"context"
+	"time"
+
+	"hometest1/core/entity"
+	"hometest1/core/repository"
+)
+
+type reservationRepo struct {
+	store *Store
+}
+
+// NewReservationRepo read reservations written by product repository in the same store
+func NewReservationRepo(store *Store) repository.ReservationRepo {
+	return &reservationRepo{store}
+}
+
+func (r *reservationRepo) GetReservation(ctx context.Context, id int64) (*entity.Reservation, error) {
+	if err := r.store.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.store.unlock()
+
+	reservation, ok := r.store.reservations[id]
+	if !ok {
+		return nil, nil
+	}
+	return cloneReservation(reservation), nil
+}
+
+func (r *reservationRepo) ReleaseReservation(ctx context.Context, id int64) (bool, error) {
+	if err := r.store.lock(ctx); err != nil {
+		return false, err
+	}
+	defer r.store.unlock()
+
+	// only active reservation is released, so reservation confirmed at the same time is not released
+	reservation, ok := r.store.reservations[id]
+	if !ok || reservation.Status != entity.ReservationActive {
+		return false, nil
+	}
+	reservation.Status = entity.ReservationReleased
+	reservation.UpdatedAt = r.store.clock()
+	return true, nil
+}
+
+func (r *reservationRepo) ExpireReservations(ctx context.Context, now time.Time) (int64, error) {
+	if err := r.store.lock(ctx); err != nil {
+		return 0, err
+	}
+	defer r.store.unlock()
+
+	var expired int64
+	for _, reservation := range r.store.reservations {
+		if reservation.Status == entity.ReservationActive && !reservation.ExpiresAt.After(now) {
+			reservation.Status = entity.ReservationExpired
+			reservation.UpdatedAt = r.store.clock()
+			expired++
+		}
+	}
+	return expired, nil
+}
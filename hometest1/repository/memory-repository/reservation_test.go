@@ -0,0 +1,66 @@
+package memoryrepository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"hometest1/core/entity"
+	memoryrepository "hometest1/repository/memory-repository"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Reservation(t *testing.T) {
+	ctx := context.Background()
+
+	// store with reservations that expire after 1 and 2 minutes
+	initReservations := func(t *testing.T) *memoryrepository.Store {
+		store := memoryrepository.NewStoreWithClock(func() time.Time { return now })
+		repo := memoryrepository.NewProductRepo(store)
+		product := createProduct(t, repo, "A-1", 5)
+		for i := 1; i <= 2; i++ {
+			err := repo.ReserveStock(ctx, &entity.Reservation{
+				Status:    entity.ReservationActive,
+				ExpiresAt: now.Add(time.Duration(i) * time.Minute),
+				Items:     []*entity.ReservationItem{{ProductID: product.ID, Quantity: 1, Product: product}},
+			})
+			assert.Nil(t, err)
+		}
+		return store
+	}
+
+	t.Run("positive, release active reservation once", func(t *testing.T) {
+		repo := memoryrepository.NewReservationRepo(initReservations(t))
+		released, err := repo.ReleaseReservation(ctx, 1)
+		assert.Nil(t, err)
+		assert.True(t, released)
+		released, err = repo.ReleaseReservation(ctx, 1)
+		assert.Nil(t, err)
+		assert.False(t, released)
+
+		reservation, err := repo.GetReservation(ctx, 1)
+		assert.Nil(t, err)
+		assert.Equal(t, entity.ReservationReleased, reservation.Status)
+		assert.Len(t, reservation.Items, 1)
+		assert.Nil(t, reservation.Items[0].Product)
+	})
+
+	t.Run("positive, expire reservations", func(t *testing.T) {
+		repo := memoryrepository.NewReservationRepo(initReservations(t))
+		expired, err := repo.ExpireReservations(ctx, now.Add(time.Minute))
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), expired)
+
+		reservation, err := repo.GetReservation(ctx, 2)
+		assert.Nil(t, err)
+		assert.Equal(t, entity.ReservationActive, reservation.Status)
+	})
+
+	t.Run("negative, reservation not found", func(t *testing.T) {
+		repo := memoryrepository.NewReservationRepo(memoryrepository.NewStore())
+		reservation, err := repo.GetReservation(ctx, 1)
+		assert.Nil(t, err)
+		assert.Nil(t, reservation)
+	})
+}
@@ -0,0 +1,253 @@
+package memoryrepository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"hometest1/core/entity"
+
+	"gorm.io/gorm"
+)
+
+// Store keeps tables of every repository in memory, data is lost when the process exits.
+// Every repository method holds the store lock, so checkout and reservation are validated one by one,
+// like row lock of sql repositories
+type Store struct {
+	mu    sync.Mutex
+	clock entity.Clock
+	// last id of each table
+	ids map[string]int64
+
+	products        map[int64]*entity.Product
+	quantities      map[int64]*entity.ProductQuantity // map[int64] = product id
+	thresholds      map[int64]*entity.StockThreshold  // map[int64] = product id
+	stockMovements  []*entity.StockMovement
+	carts           map[int64]*entity.Cart
+	orders          map[int64]*entity.Order
+	orderReturns    []*entity.OrderReturn
+	reservations    map[int64]*entity.Reservation
+	promotions      map[int64]*entity.Promotion
+	cartPromotions  map[int64]*entity.CartPromotion
+	coupons         map[int64]*entity.Coupon
+	redemptions     []*entity.CouponRedemption
+	bundles         map[int64]*entity.Bundle
+	taxRegions      map[int64]*entity.TaxRegion
+	idempotencyKeys map[string]*entity.IdempotencyKey
+	outbox          []*entity.Outbox
+}
+
+func NewStore() *Store {
+	return NewStoreWithClock(time.Now)
+}
+
+// create store with custom clock to filter promotion period and reservation expiry
+func NewStoreWithClock(clock entity.Clock) *Store {
+	return &Store{
+		clock:           clock,
+		ids:             make(map[string]int64),
+		products:        make(map[int64]*entity.Product),
+		quantities:      make(map[int64]*entity.ProductQuantity),
+		thresholds:      make(map[int64]*entity.StockThreshold),
+		carts:           make(map[int64]*entity.Cart),
+		orders:          make(map[int64]*entity.Order),
+		reservations:    make(map[int64]*entity.Reservation),
+		promotions:      make(map[int64]*entity.Promotion),
+		cartPromotions:  make(map[int64]*entity.CartPromotion),
+		coupons:         make(map[int64]*entity.Coupon),
+		bundles:         make(map[int64]*entity.Bundle),
+		taxRegions:      make(map[int64]*entity.TaxRegion),
+		idempotencyKeys: make(map[string]*entity.IdempotencyKey),
+	}
+}
+
+// lock the store, return error if request is already cancelled
+func (s *Store) lock(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	return nil
+}
+
+func (s *Store) unlock() {
+	s.mu.Unlock()
+}
+
+// auto increment id of the table
+func (s *Store) nextID(table string) int64 {
+	s.ids[table]++
+	return s.ids[table]
+}
+
+// get quantity row of the product, product without quantity row is treated as empty stock
+func (s *Store) productQuantity(productID int64) *entity.ProductQuantity {
+	qty, ok := s.quantities[productID]
+	if !ok {
+		qty = &entity.ProductQuantity{ID: s.nextID("product_quantity"), ProductID: productID}
+		s.quantities[productID] = qty
+	}
+	return qty
+}
+
+func (s *Store) addStockMovement(movement *entity.StockMovement) {
+	movement.ID = s.nextID("stock_movement")
+	movement.CreatedAt = s.clock()
+	s.stockMovements = append(s.stockMovements, movement)
+}
+
+// soft deleted row is hidden like gorm.DeletedAt scope
+func isDeleted(deletedAt gorm.DeletedAt) bool {
+	return deletedAt.Valid
+}
+
+// row is in period at the time, nil start or end is unbounded
+func inPeriod(startsAt, endsAt *time.Time, now time.Time) bool {
+	return (startsAt == nil || !startsAt.After(now)) && (endsAt == nil || endsAt.After(now))
+}
+
+// row is not ended at the time
+func notEnded(endsAt *time.Time, now time.Time) bool {
+	return endsAt == nil || endsAt.After(now)
+}
+
+func sortByID[T any](rows []T, id func(T) int64) {
+	sort.Slice(rows, func(i, j int) bool { return id(rows[i]) < id(rows[j]) })
+}
+
+// sort rows by priority desc, then id asc
+func sortByPriority[T any](rows []T, priority func(T) int, id func(T) int64) {
+	sort.Slice(rows, func(i, j int) bool {
+		if priority(rows[i]) != priority(rows[j]) {
+			return priority(rows[i]) > priority(rows[j])
+		}
+		return id(rows[i]) < id(rows[j])
+	})
+}
+
+// apply limit and offset to sorted rows
+func paginate[T any](rows []T, limit, offset int) []T {
+	if offset >= len(rows) {
+		return nil
+	}
+	rows = rows[offset:]
+	if limit >= 0 && limit < len(rows) {
+		rows = rows[:limit]
+	}
+	return rows
+}
+
+func copyTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	result := *t
+	return &result
+}
+
+func cloneProduct(product *entity.Product) *entity.Product {
+	result := *product
+	return &result
+}
+
+func cloneQuantity(qty *entity.ProductQuantity) *entity.ProductQuantity {
+	result := *qty
+	return &result
+}
+
+func clonePromotion(promo *entity.Promotion) *entity.Promotion {
+	result := *promo
+	result.StartsAt = copyTime(promo.StartsAt)
+	result.EndsAt = copyTime(promo.EndsAt)
+	return &result
+}
+
+func cloneCartPromotion(promo *entity.CartPromotion) *entity.CartPromotion {
+	result := *promo
+	result.StartsAt = copyTime(promo.StartsAt)
+	result.EndsAt = copyTime(promo.EndsAt)
+	return &result
+}
+
+func cloneCoupon(coupon *entity.Coupon) *entity.Coupon {
+	result := *coupon
+	result.ExpiresAt = copyTime(coupon.ExpiresAt)
+	return &result
+}
+
+// clone bundle with its items, product of item is not stored
+func cloneBundle(bundle *entity.Bundle) *entity.Bundle {
+	result := *bundle
+	result.StartsAt = copyTime(bundle.StartsAt)
+	result.EndsAt = copyTime(bundle.EndsAt)
+	result.Items = nil
+	for _, item := range bundle.Items {
+		clone := *item
+		clone.Product = nil
+		result.Items = append(result.Items, &clone)
+	}
+	return &result
+}
+
+func cloneOutbox(event *entity.Outbox) *entity.Outbox {
+	result := *event
+	result.DeliveredAt = copyTime(event.DeliveredAt)
+	return &result
+}
+
+// clone cart with its items
+func cloneCart(cart *entity.Cart) *entity.Cart {
+	result := *cart
+	result.Items = nil
+	for _, item := range cart.Items {
+		clone := *item
+		result.Items = append(result.Items, &clone)
+	}
+	return &result
+}
+
+// clone order with its items and taxes, product of item is not stored
+func cloneOrder(order *entity.Order) *entity.Order {
+	result := *order
+	result.Items = nil
+	result.Taxes = nil
+	result.Discounts = nil
+	for _, item := range order.Items {
+		clone := *item
+		clone.Product = nil
+		result.Items = append(result.Items, &clone)
+	}
+	for _, tax := range order.Taxes {
+		clone := *tax
+		result.Taxes = append(result.Taxes, &clone)
+	}
+	for _, discount := range order.Discounts {
+		clone := *discount
+		result.Discounts = append(result.Discounts, &clone)
+	}
+	return &result
+}
+
+// clone reservation with its items, product of item is not stored
+func cloneReservation(reservation *entity.Reservation) *entity.Reservation {
+	result := *reservation
+	result.Items = nil
+	for _, item := range reservation.Items {
+		clone := *item
+		clone.Product = nil
+		result.Items = append(result.Items, &clone)
+	}
+	return &result
+}
+
+// clone tax region with its rates
+func cloneTaxRegion(region *entity.TaxRegion) *entity.TaxRegion {
+	result := *region
+	result.Rates = nil
+	for _, rate := range region.Rates {
+		clone := *rate
+		result.Rates = append(result.Rates, &clone)
+	}
+	return &result
+}
@@ -0,0 +1,82 @@
+package memoryrepository
+
+import (
+	"context"
+	"sort"
+
+	"hometest1/core/entity"
+	"hometest1/core/repository"
+)
+
+type taxRepo struct {
+	store *Store
+}
+
+func NewTaxRepo(store *Store) repository.TaxRepo {
+	return &taxRepo{store}
+}
+
+func (r *taxRepo) GetTaxRegion(ctx context.Context, code string) (*entity.TaxRegion, error) {
+	if err := r.store.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.store.unlock()
+
+	for _, region := range r.store.taxRegions {
+		if (code == "" && region.IsDefault) || (code != "" && region.Code == code) {
+			return cloneTaxRegion(region), nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *taxRepo) GetTaxRegions(ctx context.Context) ([]*entity.TaxRegion, error) {
+	if err := r.store.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.store.unlock()
+
+	result := []*entity.TaxRegion{}
+	for _, region := range r.store.taxRegions {
+		result = append(result, cloneTaxRegion(region))
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Code < result[j].Code })
+	return result, nil
+}
+
+func (r *taxRepo) SaveTaxRegion(ctx context.Context, region *entity.TaxRegion) error {
+	if err := r.store.lock(ctx); err != nil {
+		return err
+	}
+	defer r.store.unlock()
+
+	// region is updated by code
+	region.ID = 0
+	for _, existing := range r.store.taxRegions {
+		if existing.Code == region.Code {
+			region.ID = existing.ID
+		}
+	}
+	if region.ID == 0 {
+		region.ID = r.store.nextID("tax_region")
+	}
+	region.UpdatedAt = r.store.clock()
+
+	if region.IsDefault {
+		for _, other := range r.store.taxRegions {
+			if other.ID != region.ID {
+				other.IsDefault = false
+			}
+		}
+	}
+
+	// replace rates, sorted by tax class
+	for _, rate := range region.Rates {
+		rate.ID = r.store.nextID("tax_rate")
+		rate.TaxRegionID = region.ID
+	}
+	stored := cloneTaxRegion(region)
+	sort.Slice(stored.Rates, func(i, j int) bool { return stored.Rates[i].TaxClass < stored.Rates[j].TaxClass })
+	r.store.taxRegions[region.ID] = stored
+	return nil
+}
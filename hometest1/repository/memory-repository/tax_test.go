@@ -0,0 +1,49 @@
+package memoryrepository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"hometest1/core/entity"
+	memoryrepository "hometest1/repository/memory-repository"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Tax(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("positive, save region by code and keep one default", func(t *testing.T) {
+		repo := memoryrepository.NewTaxRepo(memoryrepository.NewStoreWithClock(func() time.Time { return now }))
+		err := repo.SaveTaxRegion(ctx, &entity.TaxRegion{Code: "US-CA", PriceMode: entity.TaxExclusive, IsDefault: true,
+			Rates: []*entity.TaxRate{{TaxClass: entity.DefaultTaxClass, Rate: 725}}})
+		assert.Nil(t, err)
+		err = repo.SaveTaxRegion(ctx, &entity.TaxRegion{Code: "DE", PriceMode: entity.TaxInclusive, IsDefault: true,
+			Rates: []*entity.TaxRate{{TaxClass: "reduced", Rate: 700}, {TaxClass: entity.DefaultTaxClass, Rate: 1900}}})
+		assert.Nil(t, err)
+		// rates are replaced
+		err = repo.SaveTaxRegion(ctx, &entity.TaxRegion{Code: "US-CA", Name: "California", PriceMode: entity.TaxExclusive,
+			Rates: []*entity.TaxRate{{TaxClass: entity.DefaultTaxClass, Rate: 800}}})
+		assert.Nil(t, err)
+
+		region, err := repo.GetTaxRegion(ctx, "")
+		assert.Nil(t, err)
+		assert.Equal(t, "DE", region.Code)
+		// rates are sorted by tax class
+		assert.Equal(t, "reduced", region.Rates[0].TaxClass)
+
+		regions, err := repo.GetTaxRegions(ctx)
+		assert.Nil(t, err)
+		assert.Len(t, regions, 2)
+		assert.Equal(t, &entity.TaxRegion{ID: 1, Code: "US-CA", Name: "California", PriceMode: entity.TaxExclusive, UpdatedAt: now,
+			Rates: []*entity.TaxRate{{ID: 4, TaxRegionID: 1, TaxClass: entity.DefaultTaxClass, Rate: 800}}}, regions[1])
+	})
+
+	t.Run("negative, region not found", func(t *testing.T) {
+		repo := memoryrepository.NewTaxRepo(memoryrepository.NewStore())
+		region, err := repo.GetTaxRegion(ctx, "")
+		assert.Nil(t, err)
+		assert.Nil(t, region)
+	})
+}
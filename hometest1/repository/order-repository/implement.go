@@ -0,0 +1,183 @@
+package orderrepository
+
+import (
+	"context"
+	"net/http"
+
+	"hometest1/core/entity"
+	"hometest1/core/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type repo struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) repository.OrderRepo {
+	return &repo{db}
+}
+
+func (r *repo) GetOrder(ctx context.Context, id int64) (*entity.Order, error) {
+	var order entity.Order
+	err := r.db.WithContext(ctx).Where("id = ?", id).Limit(1).Find(&order).Error
+	if err != nil {
+		return nil, err
+	}
+	if order.ID == 0 {
+		return nil, nil
+	}
+
+	// get order items
+	err = r.db.WithContext(ctx).Where("order_id = ?", id).Order("id asc").Find(&order.Items).Error
+	if err != nil {
+		return nil, err
+	}
+
+	// get tax lines
+	err = r.db.WithContext(ctx).Where("order_id = ?", id).Order("tax_class asc").Find(&order.Taxes).Error
+	if err != nil {
+		return nil, err
+	}
+
+	// get applied promotions
+	err = r.db.WithContext(ctx).Where("order_id = ?", id).Order("id asc").Find(&order.Discounts).Error
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+func (r *repo) GetOrders(ctx context.Context, limit, offset int) ([]*entity.Order, int64, error) {
+	var total int64
+	err := r.db.WithContext(ctx).Model(&entity.Order{}).Count(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var result []*entity.Order
+	err = r.db.WithContext(ctx).Order("id desc").Limit(limit).Offset(offset).Find(&result).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return result, total, nil
+}
+
+func (r *repo) ReturnOrder(ctx context.Context, ret *entity.OrderReturn, status entity.OrderStatus) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// lock the order, so returns of the same order are applied one by one
+		var order entity.Order
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", ret.OrderID).
+			Limit(1).
+			Find(&order).
+			Error
+		if err != nil {
+			return err
+		}
+		if order.ID == 0 {
+			return entity.NewError(entity.OrderNotFound, http.StatusNotFound)
+		}
+		err = tx.Where("order_id = ?", ret.OrderID).Order("id asc").Find(&order.Items).Error
+		if err != nil {
+			return err
+		}
+
+		// refund is computed from the order read before, it is wrong if other return is applied in between
+		if !isSameOrderState(&order, ret.Order) {
+			return entity.NewError(entity.OrderChanged, http.StatusConflict)
+		}
+
+		err = tx.Create(ret).Error
+		if err != nil {
+			return err
+		}
+		for _, item := range ret.Items {
+			item.OrderReturnID = ret.ID
+			err = tx.Model(&entity.OrderItem{ID: item.OrderItemID}).
+				UpdateColumn("returned_quantity", gorm.Expr("returned_quantity + ?", item.Quantity)).
+				Error
+			if err != nil {
+				return err
+			}
+		}
+		err = tx.Create(&ret.Items).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Model(&order).
+			Select("status", "refunded_price").
+			Updates(&entity.Order{Status: status, RefundedPrice: order.RefundedPrice.Add(ret.RefundPrice)}).
+			Error
+		if err != nil {
+			return err
+		}
+
+		return restoreReturnedStock(ret, tx)
+	})
+}
+
+// compare status, refund and returned quantities of the order
+func isSameOrderState(order, expected *entity.Order) bool {
+	if expected == nil || order.Status != expected.Status || order.RefundedPrice != expected.RefundedPrice || len(order.Items) != len(expected.Items) {
+		return false
+	}
+	for i, item := range order.Items {
+		if item.ID != expected.Items[i].ID || item.ReturnedQuantity != expected.Items[i].ReturnedQuantity {
+			return false
+		}
+	}
+	return true
+}
+
+// add returned quantity back to product quantity and record it as return movement
+func restoreReturnedStock(ret *entity.OrderReturn, tx *gorm.DB) error {
+	// map[int64] = product id, int = returned quantity
+	returned := make(map[int64]int)
+	var productIDs []int64
+	for _, item := range ret.Items {
+		if _, ok := returned[item.ProductID]; !ok {
+			productIDs = append(productIDs, item.ProductID)
+		}
+		returned[item.ProductID] += item.Quantity
+	}
+
+	// lock for update product quantity
+	var quantities []*entity.ProductQuantity
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("product_id in (?)", productIDs).
+		Find(&quantities).
+		Error
+	if err != nil {
+		return err
+	}
+	mapQuantity := make(map[int64]*entity.ProductQuantity)
+	for _, qty := range quantities {
+		mapQuantity[qty.ProductID] = qty
+	}
+
+	reference := entity.OrderReturnReference(ret.ID)
+	var movements []*entity.StockMovement
+	for _, productID := range productIDs {
+		// product without quantity row is treated as empty stock
+		qty, ok := mapQuantity[productID]
+		if !ok {
+			qty = &entity.ProductQuantity{ProductID: productID}
+		}
+		qty.Quantity += returned[productID]
+		err = tx.Save(qty).Error
+		if err != nil {
+			return err
+		}
+
+		movements = append(movements, &entity.StockMovement{
+			ProductID: productID,
+			Quantity:  returned[productID],
+			Reason:    entity.StockReturn,
+			Reference: reference,
+		})
+	}
+	return tx.Create(&movements).Error
+}
@@ -0,0 +1,244 @@
+package orderrepository_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"net/http"
+	"regexp"
+	"testing"
+	"time"
+
+	"hometest1/core/entity"
+	"hometest1/core/repository"
+	orderrepository "hometest1/repository/order-repository"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/schema"
+)
+
+type AnyTime struct{}
+
+// Match satisfies sqlmock.Argument interface
+func (a AnyTime) Match(v driver.Value) bool {
+	_, ok := v.(time.Time)
+	return ok
+}
+
+func initRepo(db *sql.DB, mock sqlmock.Sqlmock) (repository.OrderRepo, error) {
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT VERSION()")).
+		WillReturnRows(sqlmock.NewRows([]string{"VERSION()"}).AddRow("5.7.25-log"))
+	gdb, err := gorm.Open(mysql.New(mysql.Config{
+		Conn: db,
+	}), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.LogLevel(logger.Info)),
+		NamingStrategy: schema.NamingStrategy{
+			SingularTable: true,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return orderrepository.New(gdb), nil
+}
+
+func Test_GetOrder(t *testing.T) {
+	// mock db
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	defer db.Close()
+
+	// init repo
+	repo, err := initRepo(db, mock)
+	if err != nil {
+		t.Errorf("error initRepo: %s", err.Error())
+		return
+	}
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+
+	t.Run("positive", func(t *testing.T) {
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `order` WHERE id = ? LIMIT ?")).
+			WithArgs(1, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "total_item", "total_price", "tax_region", "tax_price_mode", "tax_price", "created_at"}).
+				AddRow(1, 2, 5791.49, "US-CA", entity.TaxExclusive, 391.50, dayCreated))
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `order_item` WHERE order_id = ? ORDER BY id asc")).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "order_id", "product_id", "unit_price", "quantity", "sub_total_price", "promotion_id"}).
+				AddRow(1, 1, 2, 5399.99, 1, 5399.99, 1).
+				AddRow(2, 1, 4, 30, 1, 0, 1))
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `order_tax` WHERE order_id = ? ORDER BY tax_class asc")).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "order_id", "tax_class", "rate", "taxable_price", "tax_price"}).
+				AddRow(1, 1, "standard", 725, 5399.99, 391.50))
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `order_discount` WHERE order_id = ? ORDER BY id asc")).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "order_id", "product_id", "source", "source_id", "amount"}).
+				AddRow(1, 1, 4, "promotion", 1, 30))
+
+		resp, err := repo.GetOrder(context.Background(), 1)
+		assert.Nil(t, err)
+		assert.Equal(t, &entity.Order{
+			ID:           1,
+			TotalItem:    2,
+			TotalPrice:   entity.NewMoney(579149),
+			TaxRegion:    "US-CA",
+			TaxPriceMode: entity.TaxExclusive,
+			TaxPrice:     entity.NewMoney(39150),
+			CreatedAt:    dayCreated,
+			Items: []*entity.OrderItem{
+				{ID: 1, OrderID: 1, ProductID: 2, UnitPrice: entity.NewMoney(539999), Quantity: 1, SubTotalPrice: entity.NewMoney(539999), PromotionID: 1},
+				{ID: 2, OrderID: 1, ProductID: 4, UnitPrice: entity.NewMoney(3000), Quantity: 1, SubTotalPrice: entity.NewMoney(0), PromotionID: 1},
+			},
+			Taxes: []*entity.OrderTax{
+				{ID: 1, OrderID: 1, TaxClass: "standard", Rate: 725, TaxablePrice: entity.NewMoney(539999), TaxPrice: entity.NewMoney(39150)},
+			},
+			Discounts: []*entity.OrderDiscount{
+				{ID: 1, OrderID: 1, ProductID: 4, Source: entity.SourcePromotion, SourceID: 1, Amount: entity.NewMoney(3000)},
+			},
+		}, resp)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `order` WHERE id = ? LIMIT ?")).
+			WithArgs(2, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "total_item", "total_price", "created_at"}))
+
+		resp, err := repo.GetOrder(context.Background(), 2)
+		assert.Nil(t, err)
+		assert.Nil(t, resp)
+	})
+}
+
+func Test_GetOrders(t *testing.T) {
+	// mock db
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	defer db.Close()
+
+	// init repo
+	repo, err := initRepo(db, mock)
+	if err != nil {
+		t.Errorf("error initRepo: %s", err.Error())
+		return
+	}
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+
+	t.Run("positive", func(t *testing.T) {
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `order`")).
+			WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).AddRow(11))
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `order` ORDER BY id desc LIMIT ? OFFSET ?")).
+			WithArgs(10, 10).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "total_item", "total_price", "created_at"}).AddRow(1, 2, 5399.99, dayCreated))
+
+		resp, total, err := repo.GetOrders(context.Background(), 10, 10)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(11), total)
+		assert.Equal(t, []*entity.Order{
+			{ID: 1, TotalItem: 2, TotalPrice: entity.NewMoney(539999), CreatedAt: dayCreated},
+		}, resp)
+	})
+}
+
+func Test_ReturnOrder(t *testing.T) {
+	// mock db
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	defer db.Close()
+
+	// init repo
+	repo, err := initRepo(db, mock)
+	if err != nil {
+		t.Errorf("error initRepo: %s", err.Error())
+		return
+	}
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+	orderColumns := []string{"id", "status", "total_item", "total_price", "discount_price", "refunded_price", "created_at"}
+	itemColumns := []string{"id", "order_id", "product_id", "unit_price", "quantity", "sub_total_price", "promotion_id", "bundle_id", "returned_quantity"}
+	newReturn := func() *entity.OrderReturn {
+		return &entity.OrderReturn{
+			OrderID:     1,
+			RefundPrice: entity.NewMoney(536999),
+			Items:       []*entity.OrderReturnItem{{OrderItemID: 1, ProductID: 2, Quantity: 1}},
+			Order: &entity.Order{
+				ID: 1, Status: entity.OrderPlaced, TotalItem: 2, TotalPrice: entity.NewMoney(539999), DiscountPrice: entity.NewMoney(0), RefundedPrice: entity.NewMoney(0), CreatedAt: dayCreated,
+				Items: []*entity.OrderItem{
+					{ID: 1, OrderID: 1, ProductID: 2, UnitPrice: entity.NewMoney(539999), Quantity: 1, SubTotalPrice: entity.NewMoney(539999), PromotionID: 1},
+					{ID: 2, OrderID: 1, ProductID: 4, UnitPrice: entity.NewMoney(3000), Quantity: 1, SubTotalPrice: entity.NewMoney(0), PromotionID: 1},
+				},
+			},
+		}
+	}
+	expectLockOrder := func(returnedQuantity int) {
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `order` WHERE id = ? LIMIT ? FOR UPDATE")).
+			WithArgs(1, 1).
+			WillReturnRows(sqlmock.NewRows(orderColumns).AddRow(1, entity.OrderPlaced, 2, "5399.99", "0.00", "0.00", dayCreated))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `order_item` WHERE order_id = ? ORDER BY id asc")).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows(itemColumns).
+				AddRow(1, 1, 2, "5399.99", 1, "5399.99", 1, 0, returnedQuantity).
+				AddRow(2, 1, 4, "30.00", 1, "0.00", 1, 0, 0))
+	}
+
+	t.Run("positive", func(t *testing.T) {
+		mock.ExpectBegin()
+		expectLockOrder(0)
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `order_return` (`order_id`,`refund_price`,`created_at`) VALUES (?,?,?)")).
+			WithArgs(1, "5369.99", AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(3, 1))
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE `order_item` SET `returned_quantity`=returned_quantity + ? WHERE `id` = ?")).
+			WithArgs(1, 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `order_return_item` (`order_return_id`,`order_item_id`,`product_id`,`quantity`) VALUES (?,?,?,?)")).
+			WithArgs(3, 1, 2, 1).
+			WillReturnResult(sqlmock.NewResult(5, 1))
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE `order` SET `status`=?,`refunded_price`=? WHERE `id` = ?")).
+			WithArgs(entity.OrderPartiallyReturned, "5369.99", 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `product_quantity` WHERE product_id in (?) FOR UPDATE")).
+			WithArgs(2).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "quantity", "updated_at"}).AddRow(2, 2, 4, dayCreated))
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE `product_quantity` SET `product_id`=?,`quantity`=?,`updated_at`=? WHERE `id` = ?")).
+			WithArgs(2, 5, AnyTime{}, 2).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `stock_movement` (`product_id`,`quantity`,`reason`,`reference`,`created_at`) VALUES (?,?,?,?,?)")).
+			WithArgs(2, 1, entity.StockReturn, "return:3", AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		ret := newReturn()
+		err := repo.ReturnOrder(context.Background(), ret, entity.OrderPartiallyReturned)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(3), ret.ID)
+		assert.Equal(t, int64(3), ret.Items[0].OrderReturnID)
+	})
+
+	t.Run("negative, order is changed by other return", func(t *testing.T) {
+		mock.ExpectBegin()
+		expectLockOrder(1)
+		mock.ExpectRollback()
+
+		err := repo.ReturnOrder(context.Background(), newReturn(), entity.OrderPartiallyReturned)
+		assert.Equal(t, entity.NewError(entity.OrderChanged, http.StatusConflict), err)
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
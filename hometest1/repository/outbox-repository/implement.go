@@ -0,0 +1,52 @@
+package outboxrepository
+
+import (
+	"context"
+	"time"
+
+	"hometest1/core/entity"
+	"hometest1/core/repository"
+
+	"gorm.io/gorm"
+)
+
+type repo struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) repository.OutboxRepo {
+	return &repo{db}
+}
+
+func (r *repo) GetDueEvents(ctx context.Context, now time.Time, limit int) ([]*entity.Outbox, error) {
+	var result []*entity.Outbox
+	err := r.db.WithContext(ctx).
+		Where("delivered_at IS NULL AND next_attempt_at <= ?", now).
+		Order("id asc").
+		Limit(limit).
+		Find(&result).Error
+	return result, err
+}
+
+func (r *repo) ClaimEvent(ctx context.Context, id int64, now, leaseUntil time.Time) (bool, error) {
+	// conditional update, so only one dispatcher takes the event
+	result := r.db.WithContext(ctx).Model(&entity.Outbox{}).
+		Where("id = ? AND delivered_at IS NULL AND next_attempt_at <= ?", id, now).
+		Update("next_attempt_at", leaseUntil)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *repo) MarkDelivered(ctx context.Context, id int64, deliveredAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&entity.Outbox{}).
+		Where("id = ?", id).
+		Update("delivered_at", deliveredAt).Error
+}
+
+func (r *repo) MarkFailed(ctx context.Context, event *entity.Outbox) error {
+	return r.db.WithContext(ctx).Model(event).
+		Select("attempts", "last_error", "next_attempt_at").
+		Updates(event).Error
+}
@@ -0,0 +1,169 @@
+package outboxrepository_test
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"hometest1/core/entity"
+	"hometest1/core/repository"
+	outboxrepository "hometest1/repository/outbox-repository"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/schema"
+)
+
+func initRepo(db *sql.DB, mock sqlmock.Sqlmock) (repository.OutboxRepo, error) {
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT VERSION()")).
+		WillReturnRows(sqlmock.NewRows([]string{"VERSION()"}).AddRow("5.7.25-log"))
+	gdb, err := gorm.Open(mysql.New(mysql.Config{
+		Conn: db,
+	}), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.LogLevel(logger.Info)),
+		NamingStrategy: schema.NamingStrategy{
+			SingularTable: true,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return outboxrepository.New(gdb), nil
+}
+
+func Test_GetDueEvents(t *testing.T) {
+	// mock db
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	defer db.Close()
+
+	// init repo
+	repo, err := initRepo(db, mock)
+	if err != nil {
+		t.Errorf("error initRepo: %s", err.Error())
+		return
+	}
+	now, _ := time.Parse("2006-01-02 15:04:05", "2023-05-16 10:00:00")
+	createdAt := now.Add(-time.Minute)
+
+	t.Run("positive", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `outbox` WHERE delivered_at IS NULL AND next_attempt_at <= ? ORDER BY id asc LIMIT ?")).
+			WithArgs(now, 100).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "event_type", "aggregate_id", "payload", "attempts", "next_attempt_at", "last_error", "delivered_at", "created_at"}).
+				AddRow(3, entity.OrderPlacedEvent, 7, `{"orderId":7}`, 1, createdAt, "webhook responds 502 Bad Gateway", nil, createdAt))
+
+		resp, err := repo.GetDueEvents(context.Background(), now, 100)
+		assert.Nil(t, err)
+		assert.Equal(t, []*entity.Outbox{{
+			ID:            3,
+			EventType:     entity.OrderPlacedEvent,
+			AggregateID:   7,
+			Payload:       `{"orderId":7}`,
+			Attempts:      1,
+			NextAttemptAt: createdAt,
+			LastError:     "webhook responds 502 Bad Gateway",
+			CreatedAt:     createdAt,
+		}}, resp)
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func Test_ClaimEvent(t *testing.T) {
+	// mock db
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	defer db.Close()
+
+	// init repo
+	repo, err := initRepo(db, mock)
+	if err != nil {
+		t.Errorf("error initRepo: %s", err.Error())
+		return
+	}
+	now, _ := time.Parse("2006-01-02 15:04:05", "2023-05-16 10:00:00")
+	leaseUntil := now.Add(time.Minute)
+	query := regexp.QuoteMeta("UPDATE `outbox` SET `next_attempt_at`=? WHERE id = ? AND delivered_at IS NULL AND next_attempt_at <= ?")
+
+	t.Run("positive", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(query).
+			WithArgs(leaseUntil, 3, now).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		claimed, err := repo.ClaimEvent(context.Background(), 3, now, leaseUntil)
+		assert.Nil(t, err)
+		assert.True(t, claimed)
+	})
+
+	t.Run("positive, taken by another dispatcher", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(query).
+			WithArgs(leaseUntil, 3, now).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		claimed, err := repo.ClaimEvent(context.Background(), 3, now, leaseUntil)
+		assert.Nil(t, err)
+		assert.False(t, claimed)
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func Test_MarkOutboxEvent(t *testing.T) {
+	// mock db
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	defer db.Close()
+
+	// init repo
+	repo, err := initRepo(db, mock)
+	if err != nil {
+		t.Errorf("error initRepo: %s", err.Error())
+		return
+	}
+	now, _ := time.Parse("2006-01-02 15:04:05", "2023-05-16 10:00:00")
+
+	t.Run("positive, delivered", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE `outbox` SET `delivered_at`=? WHERE id = ?")).
+			WithArgs(now, 3).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := repo.MarkDelivered(context.Background(), 3, now)
+		assert.Nil(t, err)
+	})
+
+	t.Run("positive, failed", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE `outbox` SET `attempts`=?,`next_attempt_at`=?,`last_error`=? WHERE `id` = ?")).
+			WithArgs(2, now.Add(2*time.Second), "connection refused", 3).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := repo.MarkFailed(context.Background(), &entity.Outbox{ID: 3, Attempts: 2, NextAttemptAt: now.Add(2 * time.Second), LastError: "connection refused"})
+		assert.Nil(t, err)
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -1,8 +1,11 @@
 package productrepository
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	"hometest1/core/entity"
 	"hometest1/core/repository"
@@ -19,27 +22,155 @@ func New(db *gorm.DB) repository.ProductRepo {
 	return &repo{db}
 }
 
-func (r *repo) GetProductBySerials(serials []string) ([]*entity.Product, error) {
+func (r *repo) GetProductBySerials(ctx context.Context, serials []string) ([]*entity.Product, error) {
 	var result []*entity.Product
-	err := r.db.Where("serial in (?)", serials).Find(&result).Error
+	err := r.db.WithContext(ctx).Where("serial in (?)", serials).Find(&result).Error
 	if err != nil {
 		return nil, err
 	}
 	return result, nil
 }
 
-func (r *repo) GetProductByIDs(ids []int64) ([]*entity.Product, error) {
+func (r *repo) GetProductByIDs(ctx context.Context, ids []int64) ([]*entity.Product, error) {
 	var result []*entity.Product
-	err := r.db.Where("id in (?)", ids).Find(&result).Error
+	err := r.db.WithContext(ctx).Where("id in (?)", ids).Find(&result).Error
 	if err != nil {
 		return nil, err
 	}
 	return result, nil
 }
 
-func (r *repo) SubmitCheckout(payload *entity.Checkout) (err error) {
+func (r *repo) GetProductByIDsWithDeleted(ctx context.Context, ids []int64) ([]*entity.Product, error) {
+	var result []*entity.Product
+	err := r.db.WithContext(ctx).Unscoped().Where("id in (?)", ids).Find(&result).Error
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (r *repo) GetProducts(ctx context.Context, limit, offset int) ([]*entity.Product, int64, error) {
+	var total int64
+	err := r.db.WithContext(ctx).Model(&entity.Product{}).Count(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var result []*entity.Product
+	err = r.db.WithContext(ctx).Order("serial asc").Limit(limit).Offset(offset).Find(&result).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return result, total, nil
+}
+
+func (r *repo) CreateProduct(ctx context.Context, product *entity.Product, quantity int) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Create(product).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Create(&entity.ProductQuantity{ProductID: product.ID, Quantity: quantity}).Error
+		if err != nil || quantity == 0 {
+			return err
+		}
+
+		// record initial stock
+		return tx.Create(&entity.StockMovement{
+			ProductID: product.ID,
+			Quantity:  quantity,
+			Reason:    entity.StockRestock,
+			Reference: "initial stock",
+		}).Error
+	})
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return entity.NewError(entity.SerialExists, http.StatusConflict)
+	}
+	if err != nil {
+		return entity.NewInternalError(err)
+	}
+	return nil
+}
+
+func (r *repo) UpdateProduct(ctx context.Context, product *entity.Product) error {
+	return r.db.WithContext(ctx).Model(product).Select("name", "price", "tax_class", "updated_at").Updates(product).Error
+}
+
+func (r *repo) DeleteProduct(ctx context.Context, id int64) error {
+	return r.db.WithContext(ctx).Delete(&entity.Product{}, id).Error
+}
+
+func (r *repo) GetProductQuantityByIDs(ctx context.Context, productIDs []int64) ([]*entity.ProductQuantity, error) {
+	var result []*entity.ProductQuantity
+	err := r.db.WithContext(ctx).Where("product_id in (?)", productIDs).Find(&result).Error
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (r *repo) GetAvailableQuantityByIDs(ctx context.Context, productIDs []int64) ([]*entity.ProductQuantity, error) {
+	result, err := r.GetProductQuantityByIDs(ctx, productIDs)
+	if err != nil {
+		return nil, err
+	}
+	held, err := r.heldQuantities(productIDs, 0, r.db.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	for _, qty := range result {
+		qty.Quantity -= held[qty.ProductID]
+	}
+	return result, nil
+}
+
+func (r *repo) ReserveStock(ctx context.Context, reservation *entity.Reservation) error {
+	var productIDs []int64
+	for _, item := range reservation.Items {
+		productIDs = append(productIDs, item.ProductID)
+	}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// lock product quantity, so concurrent reservations and checkouts are validated one by one
+		mapProdQty, err := r.lockAndMapProductQuantity(productIDs, tx)
+		if err != nil {
+			return err
+		}
+		held, err := r.heldQuantities(productIDs, 0, tx)
+		if err != nil {
+			return err
+		}
+		for _, item := range reservation.Items {
+			available := -held[item.ProductID]
+			if qty, ok := mapProdQty[item.ProductID]; ok {
+				available += qty.Quantity
+			}
+			if item.Quantity > available {
+				return entity.NewError(
+					fmt.Sprintf("reserve item %s(%s) exceeds available quantity, only %d items available",
+						item.Product.Name, item.Product.Serial, max(available, 0)),
+					http.StatusBadRequest)
+			}
+		}
+
+		err = tx.Create(reservation).Error
+		if err != nil {
+			return err
+		}
+		for _, item := range reservation.Items {
+			item.ReservationID = reservation.ID
+		}
+		return tx.Create(&reservation.Items).Error
+	})
+	if _, ok := err.(entity.Err); err != nil && !ok {
+		return entity.NewInternalError(err)
+	}
+	return err
+}
+
+func (r *repo) SubmitCheckout(ctx context.Context, payload *entity.Checkout) (err error) {
 	// begin transaction
-	tx := r.db.Begin()
+	tx := r.db.WithContext(ctx).Begin()
 	defer func() {
 		if rc := recover(); rc != nil {
 			tx.Rollback()
@@ -55,6 +186,28 @@ func (r *repo) SubmitCheckout(payload *entity.Checkout) (err error) {
 	}()
 	err = tx.Error
 	if err != nil {
+		err = entity.NewInternalError(err)
+		return
+	}
+
+	// close the cart first, concurrent checkout of the same cart waits for its row lock then fails
+	err = r.closeCart(payload.CartID, tx)
+	if err != nil {
+		if _, ok := err.(entity.Err); !ok {
+			err = entity.NewInternalError(err)
+		}
+		tx.Rollback()
+		return
+	}
+
+	// lock the reservation, so it cannot be confirmed twice
+	var reservation *entity.Reservation
+	reservation, err = r.lockReservation(payload.ReservationID, tx)
+	if err != nil {
+		if _, ok := err.(entity.Err); !ok {
+			err = entity.NewInternalError(err)
+		}
+		tx.Rollback()
 		return
 	}
 
@@ -63,7 +216,16 @@ func (r *repo) SubmitCheckout(payload *entity.Checkout) (err error) {
 	productIDs := r.pluckProductIDFromCheckoutItems(payload.Items)
 	mapProdQty, err = r.lockAndMapProductQuantity(productIDs, tx)
 	if err != nil {
-		err = entity.NewError(err.Error(), http.StatusInternalServerError)
+		err = entity.NewInternalError(err)
+		tx.Rollback()
+		return
+	}
+
+	// stock held by other active reservations cannot be sold
+	var held map[int64]int
+	held, err = r.heldQuantities(productIDs, payload.ReservationID, tx)
+	if err != nil {
+		err = entity.NewInternalError(err)
 		tx.Rollback()
 		return
 	}
@@ -71,10 +233,10 @@ func (r *repo) SubmitCheckout(payload *entity.Checkout) (err error) {
 	// validate and update product quantity
 	for _, item := range payload.Items {
 		newQuantity := mapProdQty[item.Product.ID].Quantity - item.Quantity
-		if newQuantity < 0 {
+		if newQuantity < held[item.Product.ID] {
 			err = entity.NewError(
 				fmt.Sprintf("checkout item %s(%s) exceeds existing quantity, only %d items remaining",
-					item.Product.Name, item.Product.Serial, mapProdQty[item.Product.ID].Quantity),
+					item.Product.Name, item.Product.Serial, max(mapProdQty[item.Product.ID].Quantity-held[item.Product.ID], 0)),
 				http.StatusBadRequest)
 			tx.Rollback()
 			return
@@ -84,16 +246,302 @@ func (r *repo) SubmitCheckout(payload *entity.Checkout) (err error) {
 		mapProdQty[item.Product.ID].Quantity = newQuantity
 		err = tx.Save(mapProdQty[item.Product.ID]).Error
 		if err != nil {
-			err = entity.NewError(err.Error(), http.StatusInternalServerError)
+			err = entity.NewInternalError(err)
+			tx.Rollback()
+			return
+		}
+	}
+
+	// save order
+	err = r.createOrder(payload, tx)
+	if err != nil {
+		err = entity.NewInternalError(err)
+		tx.Rollback()
+		return
+	}
+
+	// save stock movement
+	err = r.createCheckoutStockMovements(payload, tx)
+	if err != nil {
+		err = entity.NewInternalError(err)
+		tx.Rollback()
+		return
+	}
+
+	// redeem coupons, locked so concurrent checkouts cannot exceed the limits
+	err = r.redeemCoupons(payload, tx)
+	if err != nil {
+		if _, ok := err.(entity.Err); !ok {
+			err = entity.NewInternalError(err)
+		}
+		tx.Rollback()
+		return
+	}
+
+	// publish the order by outbox, so the event is sent only if the order is committed
+	err = r.createOrderPlacedOutbox(payload, tx)
+	if err != nil {
+		err = entity.NewInternalError(err)
+		tx.Rollback()
+		return
+	}
+
+	// confirm the reservation, its held stock is sold
+	if reservation != nil {
+		err = tx.Model(reservation).
+			Select("status", "order_id", "updated_at").
+			Updates(&entity.Reservation{Status: entity.ReservationConfirmed, OrderID: payload.OrderID}).
+			Error
+		if err != nil {
+			err = entity.NewInternalError(err)
 			tx.Rollback()
 			return
 		}
 	}
 
 	err = tx.Commit().Error
+	if err != nil {
+		err = entity.NewInternalError(err)
+		return
+	}
+
+	payload.RemainingStock = make(map[int64]int)
+	for productID, quantity := range mapProdQty {
+		payload.RemainingStock[productID] = quantity.Quantity
+	}
 	return
 }
 
+// close open cart of checkout by conditional update, nothing is done if checkout has no cart
+func (r *repo) closeCart(cartID int64, tx *gorm.DB) error {
+	if cartID == 0 {
+		return nil
+	}
+
+	result := tx.Model(&entity.Cart{}).
+		Where("id = ? AND status = ?", cartID, entity.CartOpen).
+		Update("status", entity.CartCheckedOut)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return entity.NewError(entity.CartClosed, http.StatusBadRequest)
+	}
+	return nil
+}
+
+// lock the reservation of checkout and validate it is still active, return nil if checkout has no reservation
+func (r *repo) lockReservation(reservationID int64, tx *gorm.DB) (*entity.Reservation, error) {
+	if reservationID == 0 {
+		return nil, nil
+	}
+
+	var result entity.Reservation
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", reservationID).
+		Limit(1).
+		Find(&result).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	if result.ID == 0 {
+		return nil, entity.NewError(entity.ReservationNotFound, http.StatusNotFound)
+	}
+	if result.Status != entity.ReservationActive {
+		return nil, entity.NewError(entity.ReservationNotActive, http.StatusConflict)
+	}
+	if !result.IsActive(time.Now()) {
+		return nil, entity.NewError(entity.ReservationHasExpired, http.StatusConflict)
+	}
+	return &result, nil
+}
+
+// quantity held by active reservations, except the given reservation
+// return map[int64] where int64 = product id
+func (r *repo) heldQuantities(productIDs []int64, exceptReservationID int64, db *gorm.DB) (map[int64]int, error) {
+	var rows []struct {
+		ProductID int64
+		Quantity  int
+	}
+	err := db.Table("reservation_item").
+		Select("reservation_item.product_id, SUM(reservation_item.quantity) AS quantity").
+		Joins("JOIN reservation ON reservation.id = reservation_item.reservation_id").
+		Where("reservation_item.product_id in (?) AND reservation.status = ? AND reservation.expires_at > ? AND reservation.id <> ?",
+			productIDs, entity.ReservationActive, time.Now(), exceptReservationID).
+		Group("reservation_item.product_id").
+		Scan(&rows).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[int64]int)
+	for _, row := range rows {
+		result[row.ProductID] = row.Quantity
+	}
+	return result, nil
+}
+
+// insert order, order items and applied promotions, then set order id to checkout
+func (r *repo) createOrder(payload *entity.Checkout, tx *gorm.DB) error {
+	order := entity.Order{
+		Status:        entity.OrderPlaced,
+		TotalItem:     payload.TotalItem,
+		TotalPrice:    payload.TotalPrice,
+		DiscountPrice: payload.DiscountPrice,
+		TaxRegion:     payload.TaxRegion,
+		TaxPriceMode:  payload.TaxPriceMode,
+		TaxPrice:      payload.TaxPrice,
+	}
+	err := tx.Create(&order).Error
+	if err != nil {
+		return err
+	}
+
+	// tax lines for the invoice
+	var taxes []*entity.OrderTax
+	for _, tax := range payload.Taxes {
+		taxes = append(taxes, &entity.OrderTax{
+			OrderID:      order.ID,
+			TaxClass:     tax.TaxClass,
+			Rate:         tax.Rate,
+			TaxablePrice: tax.TaxableAmount,
+			TaxPrice:     tax.Amount,
+		})
+	}
+	if len(taxes) > 0 {
+		err = tx.Create(&taxes).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	var items []*entity.OrderItem
+	for _, item := range payload.Items {
+		items = append(items, &entity.OrderItem{
+			OrderID:       order.ID,
+			ProductID:     item.Product.ID,
+			UnitPrice:     item.Product.Price,
+			Quantity:      item.Quantity,
+			SubTotalPrice: item.SubTotalPrice,
+			PromotionID:   item.PromotionID,
+			BundleID:      item.BundleID,
+		})
+	}
+	err = tx.Create(&items).Error
+	if err != nil {
+		return err
+	}
+
+	// applied promotions, refund of a return is priced with them
+	discounts := payload.OrderDiscounts(order.ID)
+	if len(discounts) > 0 {
+		err = tx.Create(&discounts).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	payload.OrderID = order.ID
+	return nil
+}
+
+// record sold and free items as stock movement of the order
+func (r *repo) createCheckoutStockMovements(payload *entity.Checkout, tx *gorm.DB) error {
+	var movements []*entity.StockMovement
+	for _, item := range payload.Items {
+		reference := entity.OrderReference(payload.OrderID)
+		if soldQuantity := item.Quantity - item.FreeQuantity; soldQuantity > 0 {
+			movements = append(movements, &entity.StockMovement{
+				ProductID: item.Product.ID,
+				Quantity:  -soldQuantity,
+				Reason:    entity.StockSale,
+				Reference: reference,
+			})
+		}
+		if item.FreeQuantity > 0 {
+			movements = append(movements, &entity.StockMovement{
+				ProductID: item.Product.ID,
+				Quantity:  -item.FreeQuantity,
+				Reason:    entity.StockFreeItem,
+				Reference: reference,
+			})
+		}
+	}
+	if len(movements) == 0 {
+		return nil
+	}
+	return tx.Create(&movements).Error
+}
+
+// write OrderPlaced event of the order, dispatcher delivers it after commit
+func (r *repo) createOrderPlacedOutbox(payload *entity.Checkout, tx *gorm.DB) error {
+	event, err := entity.NewOrderPlacedOutbox(payload, time.Now())
+	if err != nil {
+		return err
+	}
+	return tx.Create(event).Error
+}
+
+// lock coupons, validate the redemption limits, then record redemption of the order
+func (r *repo) redeemCoupons(payload *entity.Checkout, tx *gorm.DB) error {
+	if len(payload.Coupons) == 0 {
+		return nil
+	}
+
+	var couponIDs []int64
+	for _, coupon := range payload.Coupons {
+		couponIDs = append(couponIDs, coupon.ID)
+	}
+	var coupons []*entity.Coupon
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id in (?)", couponIDs).
+		Find(&coupons).
+		Error
+	if err != nil {
+		return err
+	}
+	// coupon is deleted after checkout is rendered
+	if len(coupons) != len(payload.Coupons) {
+		return entity.NewError(entity.CouponNotFound, http.StatusBadRequest)
+	}
+
+	var redemptions []*entity.CouponRedemption
+	for _, coupon := range coupons {
+		if coupon.IsExhausted() {
+			return entity.NewError(fmt.Sprintf("%s: %s", entity.CouponExhausted, coupon.Code), http.StatusBadRequest)
+		}
+		if coupon.PerCustomerLimit > 0 {
+			var redeemed int64
+			err = tx.Model(&entity.CouponRedemption{}).
+				Where("coupon_id = ? AND customer_id = ?", coupon.ID, payload.CustomerID).
+				Count(&redeemed).
+				Error
+			if err != nil {
+				return err
+			}
+			if redeemed >= int64(coupon.PerCustomerLimit) {
+				return entity.NewError(fmt.Sprintf("%s: %s", entity.CouponCustomerUsed, coupon.Code), http.StatusBadRequest)
+			}
+		}
+
+		err = tx.Model(coupon).UpdateColumn("redeemed_count", coupon.RedeemedCount+1).Error
+		if err != nil {
+			return err
+		}
+		redemptions = append(redemptions, &entity.CouponRedemption{
+			CouponID:   coupon.ID,
+			OrderID:    payload.OrderID,
+			CustomerID: payload.CustomerID,
+		})
+	}
+	if len(redemptions) == 0 {
+		return nil
+	}
+	return tx.Create(&redemptions).Error
+}
+
 func (r *repo) pluckProductIDFromCheckoutItems(items []*entity.CheckoutItem) []int64 {
 	var result []int64
 	for _, item := range items {
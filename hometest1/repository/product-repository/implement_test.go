@@ -1,9 +1,13 @@
 package productrepository_test
 
 import (
+	"context"
 	"database/sql"
 	"database/sql/driver"
+	"errors"
+	"net/http"
 	"regexp"
+	"strings"
 	"testing"
 	"time"
 
@@ -27,6 +31,28 @@ func (a AnyTime) Match(v driver.Value) bool {
 	return ok
 }
 
+// expect quantity held by active reservations of the products, none if rows is nil
+func expectHeldQuantity(mock sqlmock.Sqlmock, productIDs []int64, exceptReservationID int64, rows *sqlmock.Rows) {
+	if rows == nil {
+		rows = sqlmock.NewRows([]string{"product_id", "quantity"})
+	}
+	var args []driver.Value
+	for _, id := range productIDs {
+		args = append(args, id)
+	}
+	args = append(args, entity.ReservationActive, AnyTime{}, exceptReservationID)
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(productIDs)), ",")
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT reservation_item.product_id, SUM(reservation_item.quantity) AS quantity FROM `reservation_item` JOIN reservation ON reservation.id = reservation_item.reservation_id WHERE reservation_item.product_id in (" + placeholders + ") AND reservation.status = ? AND reservation.expires_at > ? AND reservation.id <> ? GROUP BY `reservation_item`.`product_id`")).
+		WithArgs(args...).
+		WillReturnRows(rows)
+}
+
+// expect OrderPlaced event of the order written in checkout transaction
+func expectOrderPlacedOutbox(mock sqlmock.Sqlmock, orderID int64, payload driver.Value) *sqlmock.ExpectedExec {
+	return mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `outbox` (`event_type`,`aggregate_id`,`payload`,`attempts`,`next_attempt_at`,`last_error`,`delivered_at`,`created_at`) VALUES (?,?,?,?,?,?,?,?)")).
+		WithArgs(entity.OrderPlacedEvent, orderID, payload, 0, AnyTime{}, "", nil, AnyTime{})
+}
+
 func initRepo(db *sql.DB, mock sqlmock.Sqlmock) (repository.ProductRepo, error) {
 	mock.ExpectQuery(regexp.QuoteMeta("SELECT VERSION()")).
 		WillReturnRows(sqlmock.NewRows([]string{"VERSION()"}).AddRow("5.7.25-log"))
@@ -67,15 +93,15 @@ func Test_GetProductBySerials(t *testing.T) {
 			AddRow(3, "A304SD", "Alexa Speaker", 109.50, dayCreated)
 
 		mock.
-			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `product` WHERE serial in (?,?)")).
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `product` WHERE serial in (?,?) AND `product`.`deleted_at` IS NULL")).
 			WithArgs("120P90", "A304SD").
 			WillReturnRows(rows)
 
-		resp, err := repo.GetProductBySerials([]string{"120P90", "A304SD"})
+		resp, err := repo.GetProductBySerials(context.Background(), []string{"120P90", "A304SD"})
 		assert.Nil(t, err)
 		assert.Equal(t, []*entity.Product{
-			{ID: 1, Serial: "120P90", Name: "Google Home", Price: 49.99, UpdatedAt: dayCreated},
-			{ID: 3, Serial: "A304SD", Name: "Alexa Speaker", Price: 109.50, UpdatedAt: dayCreated},
+			{ID: 1, Serial: "120P90", Name: "Google Home", Price: entity.NewMoney(4999), UpdatedAt: dayCreated},
+			{ID: 3, Serial: "A304SD", Name: "Alexa Speaker", Price: entity.NewMoney(10950), UpdatedAt: dayCreated},
 		}, resp)
 	})
 }
@@ -103,17 +129,481 @@ func Test_GetProductByIDs(t *testing.T) {
 			AddRow(3, "A304SD", "Alexa Speaker", 109.50, dayCreated)
 
 		mock.
-			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `product` WHERE id in (?,?)")).
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `product` WHERE id in (?,?) AND `product`.`deleted_at` IS NULL")).
 			WithArgs(1, 3).
 			WillReturnRows(rows)
 
-		resp, err := repo.GetProductByIDs([]int64{1, 3})
+		resp, err := repo.GetProductByIDs(context.Background(), []int64{1, 3})
+		assert.Nil(t, err)
+		assert.Equal(t, []*entity.Product{
+			{ID: 1, Serial: "120P90", Name: "Google Home", Price: entity.NewMoney(4999), UpdatedAt: dayCreated},
+			{ID: 3, Serial: "A304SD", Name: "Alexa Speaker", Price: entity.NewMoney(10950), UpdatedAt: dayCreated},
+		}, resp)
+	})
+}
+
+func Test_GetProductByIDsWithDeleted(t *testing.T) {
+	// mock db
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	defer db.Close()
+
+	// init repo
+	repo, err := initRepo(db, mock)
+	if err != nil {
+		t.Errorf("error initRepo: %s", err.Error())
+		return
+	}
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+
+	t.Run("positive", func(t *testing.T) {
+		rows := sqlmock.
+			NewRows([]string{"id", "serial", "name", "price", "updated_at", "deleted_at"}).
+			AddRow(1, "120P90", "Google Home", 49.99, dayCreated, dayCreated)
+
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `product` WHERE id in (?)")).
+			WithArgs(1).
+			WillReturnRows(rows)
+
+		resp, err := repo.GetProductByIDsWithDeleted(context.Background(), []int64{1})
+		assert.Nil(t, err)
+		assert.Equal(t, []*entity.Product{
+			{ID: 1, Serial: "120P90", Name: "Google Home", Price: entity.NewMoney(4999), UpdatedAt: dayCreated, DeletedAt: gorm.DeletedAt{Time: dayCreated, Valid: true}},
+		}, resp)
+	})
+}
+
+func Test_GetProducts(t *testing.T) {
+	// mock db
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	defer db.Close()
+
+	// init repo
+	repo, err := initRepo(db, mock)
+	if err != nil {
+		t.Errorf("error initRepo: %s", err.Error())
+		return
+	}
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+
+	t.Run("positive", func(t *testing.T) {
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `product` WHERE `product`.`deleted_at` IS NULL")).
+			WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).AddRow(4))
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `product` WHERE `product`.`deleted_at` IS NULL ORDER BY serial asc LIMIT ? OFFSET ?")).
+			WithArgs(2, 2).
+			WillReturnRows(sqlmock.
+				NewRows([]string{"id", "serial", "name", "price", "updated_at"}).
+				AddRow(2, "43N23P", "MacBook Pro", 5399.99, dayCreated).
+				AddRow(3, "A304SD", "Alexa Speaker", 109.50, dayCreated))
+
+		resp, total, err := repo.GetProducts(context.Background(), 2, 2)
 		assert.Nil(t, err)
+		assert.Equal(t, int64(4), total)
 		assert.Equal(t, []*entity.Product{
-			{ID: 1, Serial: "120P90", Name: "Google Home", Price: 49.99, UpdatedAt: dayCreated},
-			{ID: 3, Serial: "A304SD", Name: "Alexa Speaker", Price: 109.50, UpdatedAt: dayCreated},
+			{ID: 2, Serial: "43N23P", Name: "MacBook Pro", Price: entity.NewMoney(539999), UpdatedAt: dayCreated},
+			{ID: 3, Serial: "A304SD", Name: "Alexa Speaker", Price: entity.NewMoney(10950), UpdatedAt: dayCreated},
+		}, resp)
+	})
+}
+
+func Test_CreateProduct(t *testing.T) {
+	// mock db
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	defer db.Close()
+
+	// init repo
+	repo, err := initRepo(db, mock)
+	if err != nil {
+		t.Errorf("error initRepo: %s", err.Error())
+		return
+	}
+
+	t.Run("positive", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `product` (`serial`,`name`,`price`,`tax_class`,`updated_at`,`deleted_at`) VALUES (?,?,?,?,?,?)")).
+			WithArgs("120P90", "Google Home", "49.99", "standard", AnyTime{}, nil).
+			WillReturnResult(sqlmock.NewResult(5, 1))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `product_quantity` (`product_id`,`quantity`,`updated_at`) VALUES (?,?,?)")).
+			WithArgs(5, 10, AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(5, 1))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `stock_movement` (`product_id`,`quantity`,`reason`,`reference`,`created_at`) VALUES (?,?,?,?,?)")).
+			WithArgs(5, 10, entity.StockRestock, "initial stock", AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		product := &entity.Product{Serial: "120P90", Name: "Google Home", Price: entity.NewMoney(4999), TaxClass: "standard"}
+		err := repo.CreateProduct(context.Background(), product, 10)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(5), product.ID)
+	})
+
+	t.Run("negative, duplicate serial", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `product`")).
+			WillReturnError(gorm.ErrDuplicatedKey)
+		mock.ExpectRollback()
+
+		err := repo.CreateProduct(context.Background(), &entity.Product{Serial: "120P90", Name: "Google Home", Price: entity.NewMoney(4999)}, 10)
+		assert.Equal(t, entity.NewError(entity.SerialExists, http.StatusConflict), err)
+	})
+}
+
+func Test_DeleteProduct(t *testing.T) {
+	// mock db
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	defer db.Close()
+
+	// init repo
+	repo, err := initRepo(db, mock)
+	if err != nil {
+		t.Errorf("error initRepo: %s", err.Error())
+		return
+	}
+
+	t.Run("positive, soft delete", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE `product` SET `deleted_at`=? WHERE `product`.`id` = ? AND `product`.`deleted_at` IS NULL")).
+			WithArgs(AnyTime{}, 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := repo.DeleteProduct(context.Background(), 1)
+		assert.Nil(t, err)
+	})
+}
+
+func Test_GetProductQuantityByIDs(t *testing.T) {
+	// mock db
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	defer db.Close()
+
+	// init repo
+	repo, err := initRepo(db, mock)
+	if err != nil {
+		t.Errorf("error initRepo: %s", err.Error())
+		return
+	}
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+
+	t.Run("positive", func(t *testing.T) {
+		rows := sqlmock.
+			NewRows([]string{"id", "product_id", "quantity", "updated_at"}).
+			AddRow(1, 1, 10, dayCreated).
+			AddRow(3, 3, 2, dayCreated)
+
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `product_quantity` WHERE product_id in (?,?)")).
+			WithArgs(1, 3).
+			WillReturnRows(rows)
+
+		resp, err := repo.GetProductQuantityByIDs(context.Background(), []int64{1, 3})
+		assert.Nil(t, err)
+		assert.Equal(t, []*entity.ProductQuantity{
+			{ID: 1, ProductID: 1, Quantity: 10, UpdatedAt: dayCreated},
+			{ID: 3, ProductID: 3, Quantity: 2, UpdatedAt: dayCreated},
+		}, resp)
+	})
+}
+
+func Test_GetAvailableQuantityByIDs(t *testing.T) {
+	// mock db
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	defer db.Close()
+
+	// init repo
+	repo, err := initRepo(db, mock)
+	if err != nil {
+		t.Errorf("error initRepo: %s", err.Error())
+		return
+	}
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+
+	t.Run("positive, quantity minus active reservations", func(t *testing.T) {
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `product_quantity` WHERE product_id in (?,?)")).
+			WithArgs(1, 3).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "quantity", "updated_at"}).
+				AddRow(1, 1, 10, dayCreated).
+				AddRow(3, 3, 2, dayCreated))
+		expectHeldQuantity(mock, []int64{1, 3}, 0, sqlmock.NewRows([]string{"product_id", "quantity"}).AddRow(1, 4))
+
+		resp, err := repo.GetAvailableQuantityByIDs(context.Background(), []int64{1, 3})
+		assert.Nil(t, err)
+		assert.Equal(t, []*entity.ProductQuantity{
+			{ID: 1, ProductID: 1, Quantity: 6, UpdatedAt: dayCreated},
+			{ID: 3, ProductID: 3, Quantity: 2, UpdatedAt: dayCreated},
 		}, resp)
 	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func Test_ReserveStock(t *testing.T) {
+	// mock db
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	defer db.Close()
+
+	// init repo
+	repo, err := initRepo(db, mock)
+	if err != nil {
+		t.Errorf("error initRepo: %s", err.Error())
+		return
+	}
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+	expiresAt := dayCreated.Add(15 * time.Minute)
+	product := &entity.Product{ID: 2, Serial: "43N23P", Name: "MacBook Pro", Price: entity.NewMoney(539999), UpdatedAt: dayCreated}
+	newReservation := func(quantity int) *entity.Reservation {
+		return &entity.Reservation{
+			Status:     entity.ReservationActive,
+			CustomerID: "cust-1",
+			ExpiresAt:  expiresAt,
+			Items: []*entity.ReservationItem{
+				{ProductID: 2, Quantity: quantity, OrderedQuantity: quantity, Product: product},
+			},
+		}
+	}
+
+	t.Run("positive, available stock is sufficient", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `product_quantity` WHERE product_id in (?) FOR UPDATE")).
+			WithArgs(2).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "quantity", "updated_at"}).AddRow(2, 2, 3, dayCreated))
+		expectHeldQuantity(mock, []int64{2}, 0, sqlmock.NewRows([]string{"product_id", "quantity"}).AddRow(2, 2))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `reservation` (`status`,`customer_id`,`order_id`,`expires_at`,`created_at`,`updated_at`) VALUES (?,?,?,?,?,?)")).
+			WithArgs(entity.ReservationActive, "cust-1", 0, expiresAt, AnyTime{}, AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(5, 1))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `reservation_item` (`reservation_id`,`product_id`,`quantity`,`ordered_quantity`) VALUES (?,?,?,?)")).
+			WithArgs(5, 2, 1, 1).
+			WillReturnResult(sqlmock.NewResult(9, 1))
+		mock.ExpectCommit()
+
+		reservation := newReservation(1)
+		err := repo.ReserveStock(context.Background(), reservation)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(5), reservation.ID)
+		assert.Equal(t, int64(5), reservation.Items[0].ReservationID)
+	})
+
+	t.Run("negative, stock is held by other reservation", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `product_quantity` WHERE product_id in (?) FOR UPDATE")).
+			WithArgs(2).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "quantity", "updated_at"}).AddRow(2, 2, 1, dayCreated))
+		expectHeldQuantity(mock, []int64{2}, 0, sqlmock.NewRows([]string{"product_id", "quantity"}).AddRow(2, 1))
+		mock.ExpectRollback()
+
+		err := repo.ReserveStock(context.Background(), newReservation(1))
+		assert.Equal(t, entity.NewError("reserve item MacBook Pro(43N23P) exceeds available quantity, only 0 items available", http.StatusBadRequest), err)
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func Test_SubmitCheckoutReservation(t *testing.T) {
+	// mock db
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	defer db.Close()
+
+	// init repo
+	repo, err := initRepo(db, mock)
+	if err != nil {
+		t.Errorf("error initRepo: %s", err.Error())
+		return
+	}
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+	reservationColumns := []string{"id", "status", "customer_id", "order_id", "expires_at", "created_at", "updated_at"}
+	payload := func() *entity.Checkout {
+		return &entity.Checkout{
+			ReservationID: 5,
+			Items: []*entity.CheckoutItem{
+				{
+					Product:       &entity.Product{ID: 2, Serial: "43N23P", Name: "MacBook Pro", Price: entity.NewMoney(539999), UpdatedAt: dayCreated},
+					Quantity:      1,
+					SubTotalPrice: entity.NewMoney(539999),
+				},
+			},
+			TotalItem:  1,
+			TotalPrice: entity.NewMoney(539999),
+		}
+	}
+	lockReservation := regexp.QuoteMeta("SELECT * FROM `reservation` WHERE id = ? LIMIT ? FOR UPDATE")
+
+	t.Run("positive, held stock is sold and reservation is confirmed", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockReservation).
+			WithArgs(5, 1).
+			WillReturnRows(sqlmock.NewRows(reservationColumns).AddRow(5, entity.ReservationActive, "cust-1", 0, time.Now().Add(time.Minute), dayCreated, dayCreated))
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `product_quantity` WHERE product_id in (?) FOR UPDATE")).
+			WithArgs(2).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "quantity", "updated_at"}).AddRow(2, 2, 1, dayCreated))
+		// own reservation is not counted as held
+		expectHeldQuantity(mock, []int64{2}, 5, nil)
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE `product_quantity` SET `product_id`=?,`quantity`=?,`updated_at`=? WHERE `id` = ?")).
+			WithArgs(2, 0, AnyTime{}, 2).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `order` (`status`,`total_item`,`total_price`,`discount_price`,`refunded_price`,`tax_region`,`tax_price_mode`,`tax_price`,`created_at`) VALUES (?,?,?,?,?,?,?,?,?)")).
+			WithArgs(entity.OrderPlaced, 1, "5399.99", "0.00", "0.00", "", entity.UndefinedTaxPriceMode, "0.00", AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(7, 1))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `order_item` (`order_id`,`product_id`,`unit_price`,`quantity`,`sub_total_price`,`promotion_id`,`bundle_id`,`returned_quantity`) VALUES (?,?,?,?,?,?,?,?)")).
+			WithArgs(7, 2, "5399.99", 1, "5399.99", 0, 0, 0).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `stock_movement` (`product_id`,`quantity`,`reason`,`reference`,`created_at`) VALUES (?,?,?,?,?)")).
+			WithArgs(2, -1, entity.StockSale, "order:7", AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		expectOrderPlacedOutbox(mock, 7, sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE `reservation` SET `status`=?,`order_id`=?,`updated_at`=? WHERE `id` = ?")).
+			WithArgs(entity.ReservationConfirmed, 7, AnyTime{}, 5).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		checkout := payload()
+		err := repo.SubmitCheckout(context.Background(), checkout)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(7), checkout.OrderID)
+		assert.Equal(t, map[int64]int{2: 0}, checkout.RemainingStock)
+	})
+
+	t.Run("negative, reservation is expired", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockReservation).
+			WithArgs(5, 1).
+			WillReturnRows(sqlmock.NewRows(reservationColumns).AddRow(5, entity.ReservationActive, "cust-1", 0, dayCreated, dayCreated, dayCreated))
+		mock.ExpectRollback()
+
+		err := repo.SubmitCheckout(context.Background(), payload())
+		assert.Equal(t, entity.NewError(entity.ReservationHasExpired, http.StatusConflict), err)
+	})
+
+	t.Run("negative, stock is held by other reservation", func(t *testing.T) {
+		checkout := payload()
+		checkout.ReservationID = 0
+
+		mock.ExpectBegin()
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `product_quantity` WHERE product_id in (?) FOR UPDATE")).
+			WithArgs(2).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "quantity", "updated_at"}).AddRow(2, 2, 1, dayCreated))
+		expectHeldQuantity(mock, []int64{2}, 0, sqlmock.NewRows([]string{"product_id", "quantity"}).AddRow(2, 1))
+		mock.ExpectRollback()
+
+		err := repo.SubmitCheckout(context.Background(), checkout)
+		assert.Equal(t, entity.NewError("checkout item MacBook Pro(43N23P) exceeds existing quantity, only 0 items remaining", http.StatusBadRequest), err)
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func Test_SubmitCheckoutCart(t *testing.T) {
+	// mock db
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	defer db.Close()
+
+	// init repo
+	repo, err := initRepo(db, mock)
+	if err != nil {
+		t.Errorf("error initRepo: %s", err.Error())
+		return
+	}
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+	payload := func() *entity.Checkout {
+		return &entity.Checkout{
+			CartID: 3,
+			Items: []*entity.CheckoutItem{
+				{
+					Product:       &entity.Product{ID: 1, Serial: "120P90", Name: "Google Home", Price: entity.NewMoney(4999), UpdatedAt: dayCreated},
+					Quantity:      1,
+					SubTotalPrice: entity.NewMoney(4999),
+				},
+			},
+			TotalItem:  1,
+			TotalPrice: entity.NewMoney(4999),
+		}
+	}
+	closeCart := regexp.QuoteMeta("UPDATE `cart` SET `status`=?,`updated_at`=? WHERE id = ? AND status = ?")
+
+	t.Run("positive, cart is closed in checkout transaction", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(closeCart).
+			WithArgs(entity.CartCheckedOut, AnyTime{}, 3, entity.CartOpen).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `product_quantity` WHERE product_id in (?) FOR UPDATE")).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "quantity", "updated_at"}).AddRow(1, 1, 10, dayCreated))
+		expectHeldQuantity(mock, []int64{1}, 0, nil)
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE `product_quantity` SET `product_id`=?,`quantity`=?,`updated_at`=? WHERE `id` = ?")).
+			WithArgs(1, 9, AnyTime{}, 1).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `order` (`status`,`total_item`,`total_price`,`discount_price`,`refunded_price`,`tax_region`,`tax_price_mode`,`tax_price`,`created_at`) VALUES (?,?,?,?,?,?,?,?,?)")).
+			WithArgs(entity.OrderPlaced, 1, "49.99", "0.00", "0.00", "", entity.UndefinedTaxPriceMode, "0.00", AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(7, 1))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `order_item` (`order_id`,`product_id`,`unit_price`,`quantity`,`sub_total_price`,`promotion_id`,`bundle_id`,`returned_quantity`) VALUES (?,?,?,?,?,?,?,?)")).
+			WithArgs(7, 1, "49.99", 1, "49.99", 0, 0, 0).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `stock_movement` (`product_id`,`quantity`,`reason`,`reference`,`created_at`) VALUES (?,?,?,?,?)")).
+			WithArgs(1, -1, entity.StockSale, "order:7", AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		expectOrderPlacedOutbox(mock, 7, sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		checkout := payload()
+		err := repo.SubmitCheckout(context.Background(), checkout)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(7), checkout.OrderID)
+	})
+
+	t.Run("negative, cart is already checked out by concurrent checkout", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(closeCart).
+			WithArgs(entity.CartCheckedOut, AnyTime{}, 3, entity.CartOpen).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		err := repo.SubmitCheckout(context.Background(), payload())
+		assert.Equal(t, entity.NewError(entity.CartClosed, http.StatusBadRequest), err)
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
 }
 
 func Test_SubmitCheckout(t *testing.T) {
@@ -143,24 +633,85 @@ func Test_SubmitCheckout(t *testing.T) {
 			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `product_quantity` WHERE product_id in (?) FOR UPDATE")).
 			WithArgs(1).
 			WillReturnRows(rows)
+		expectHeldQuantity(mock, []int64{1}, 0, nil)
 
 		// validate and update product quantity
 		mock.ExpectExec(regexp.QuoteMeta("UPDATE `product_quantity` SET `product_id`=?,`quantity`=?,`updated_at`=? WHERE `id` = ?")).
 			WithArgs(1, 9, AnyTime{}, 1).
 			WillReturnResult(sqlmock.NewResult(1, 1))
 
+		// save order
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `order` (`status`,`total_item`,`total_price`,`discount_price`,`refunded_price`,`tax_region`,`tax_price_mode`,`tax_price`,`created_at`) VALUES (?,?,?,?,?,?,?,?,?)")).
+			WithArgs(entity.OrderPlaced, 1, "49.99", "0.00", "0.00", "", entity.UndefinedTaxPriceMode, "0.00", AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(7, 1))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `order_item` (`order_id`,`product_id`,`unit_price`,`quantity`,`sub_total_price`,`promotion_id`,`bundle_id`,`returned_quantity`) VALUES (?,?,?,?,?,?,?,?)")).
+			WithArgs(7, 1, "49.99", 1, "49.99", 0, 0, 0).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		// save stock movement
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `stock_movement` (`product_id`,`quantity`,`reason`,`reference`,`created_at`) VALUES (?,?,?,?,?)")).
+			WithArgs(1, -1, entity.StockSale, "order:7", AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		// publish order by outbox
+		expectOrderPlacedOutbox(mock, 7, `{"orderId":7,"customerId":"","reservationId":0,"totalItem":1,"totalPrice":49.99,"discountPrice":0.00,"taxRegion":"","taxPrice":0.00,"coupons":[],`+
+			`"items":[{"serial":"120P90","name":"Google Home","unitPrice":49.99,"quantity":1,"freeQuantity":0,"subTotalPrice":49.99}]}`).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
 		mock.ExpectCommit()
 
 		// checkout 1 of 10 existing items
-		err := repo.SubmitCheckout(&entity.Checkout{
+		payload := &entity.Checkout{
 			Items: []*entity.CheckoutItem{
 				{
-					Product:  &entity.Product{ID: 1, Serial: "120P90", Name: "Google Home", Price: 49.99, UpdatedAt: dayCreated},
-					Quantity: 1,
+					Product:       &entity.Product{ID: 1, Serial: "120P90", Name: "Google Home", Price: entity.NewMoney(4999), UpdatedAt: dayCreated},
+					Quantity:      1,
+					SubTotalPrice: entity.NewMoney(4999),
 				},
 			},
-		})
+			TotalItem:  1,
+			TotalPrice: entity.NewMoney(4999),
+		}
+		err := repo.SubmitCheckout(context.Background(), payload)
 		assert.Nil(t, err)
+		assert.Equal(t, int64(7), payload.OrderID)
+	})
+
+	t.Run("negative, order is rolled back if its event is not written", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `product_quantity` WHERE product_id in (?) FOR UPDATE")).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "quantity", "updated_at"}).AddRow(1, 1, 10, dayCreated))
+		expectHeldQuantity(mock, []int64{1}, 0, nil)
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE `product_quantity` SET `product_id`=?,`quantity`=?,`updated_at`=? WHERE `id` = ?")).
+			WithArgs(1, 9, AnyTime{}, 1).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `order` (`status`,`total_item`,`total_price`,`discount_price`,`refunded_price`,`tax_region`,`tax_price_mode`,`tax_price`,`created_at`) VALUES (?,?,?,?,?,?,?,?,?)")).
+			WithArgs(entity.OrderPlaced, 1, "49.99", "0.00", "0.00", "", entity.UndefinedTaxPriceMode, "0.00", AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(8, 1))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `order_item` (`order_id`,`product_id`,`unit_price`,`quantity`,`sub_total_price`,`promotion_id`,`bundle_id`,`returned_quantity`) VALUES (?,?,?,?,?,?,?,?)")).
+			WithArgs(8, 1, "49.99", 1, "49.99", 0, 0, 0).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `stock_movement` (`product_id`,`quantity`,`reason`,`reference`,`created_at`) VALUES (?,?,?,?,?)")).
+			WithArgs(1, -1, entity.StockSale, "order:8", AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		expectOrderPlacedOutbox(mock, 8, sqlmock.AnyArg()).WillReturnError(errors.New("table outbox doesn't exist"))
+		mock.ExpectRollback()
+
+		err := repo.SubmitCheckout(context.Background(), &entity.Checkout{
+			Items: []*entity.CheckoutItem{
+				{
+					Product:       &entity.Product{ID: 1, Serial: "120P90", Name: "Google Home", Price: entity.NewMoney(4999), UpdatedAt: dayCreated},
+					Quantity:      1,
+					SubTotalPrice: entity.NewMoney(4999),
+				},
+			},
+			TotalItem:  1,
+			TotalPrice: entity.NewMoney(4999),
+		})
+		assert.Equal(t, entity.NewError("table outbox doesn't exist", http.StatusInternalServerError), err)
+		assert.Nil(t, mock.ExpectationsWereMet())
 	})
 
 	t.Run("negative, item quantity is insufficient", func(t *testing.T) {
@@ -174,19 +725,146 @@ func Test_SubmitCheckout(t *testing.T) {
 			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `product_quantity` WHERE product_id in (?) FOR UPDATE")).
 			WithArgs(1).
 			WillReturnRows(rows)
+		expectHeldQuantity(mock, []int64{1}, 0, nil)
 
 		// item is insufficient, rollback before update
 		mock.ExpectRollback()
 
 		// checkout 11 of 10 existing items
-		err := repo.SubmitCheckout(&entity.Checkout{
+		err := repo.SubmitCheckout(context.Background(), &entity.Checkout{
 			Items: []*entity.CheckoutItem{
 				{
-					Product:  &entity.Product{ID: 1, Serial: "120P90", Name: "Google Home", Price: 49.99, UpdatedAt: dayCreated},
+					Product:  &entity.Product{ID: 1, Serial: "120P90", Name: "Google Home", Price: entity.NewMoney(4999), UpdatedAt: dayCreated},
 					Quantity: 11,
 				},
 			},
 		})
 		assert.NotNil(t, err)
 	})
+
+	t.Run("negative, request is cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := repo.SubmitCheckout(ctx, &entity.Checkout{})
+		assert.Equal(t, entity.NewError(entity.RequestTimeout, http.StatusServiceUnavailable), err)
+	})
+}
+
+func Test_SubmitCheckoutCoupon(t *testing.T) {
+	// mock db
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	defer db.Close()
+
+	// init repo
+	repo, err := initRepo(db, mock)
+	if err != nil {
+		t.Errorf("error initRepo: %s", err.Error())
+		return
+	}
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+
+	// checkout 1 item with coupon discount
+	newPayload := func() *entity.Checkout {
+		return &entity.Checkout{
+			Items: []*entity.CheckoutItem{
+				{
+					Product:       &entity.Product{ID: 1, Serial: "120P90", Name: "Google Home", Price: entity.NewMoney(4999), UpdatedAt: dayCreated},
+					Quantity:      1,
+					SubTotalPrice: entity.NewMoney(4999),
+				},
+			},
+			TotalItem:  1,
+			TotalPrice: entity.NewMoney(4499),
+			Discounts: []*entity.CheckoutDiscount{
+				{CartPromotionID: 5, Type: entity.CartDiscountAmount, Amount: entity.NewMoney(500), CouponCode: "SAVE5"},
+			},
+			DiscountPrice: entity.NewMoney(500),
+			CustomerID:    "cust-1",
+			Coupons:       []*entity.Coupon{{ID: 2, Code: "SAVE5"}},
+		}
+	}
+	// stock update, order and stock movement before coupon redemption
+	expectOrder := func() {
+		mock.ExpectBegin()
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `product_quantity` WHERE product_id in (?) FOR UPDATE")).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "quantity", "updated_at"}).AddRow(1, 1, 10, dayCreated))
+		expectHeldQuantity(mock, []int64{1}, 0, nil)
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE `product_quantity` SET `product_id`=?,`quantity`=?,`updated_at`=? WHERE `id` = ?")).
+			WithArgs(1, 9, AnyTime{}, 1).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `order` (`status`,`total_item`,`total_price`,`discount_price`,`refunded_price`,`tax_region`,`tax_price_mode`,`tax_price`,`created_at`) VALUES (?,?,?,?,?,?,?,?,?)")).
+			WithArgs(entity.OrderPlaced, 1, "44.99", "5.00", "0.00", "", entity.UndefinedTaxPriceMode, "0.00", AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(7, 1))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `order_item` (`order_id`,`product_id`,`unit_price`,`quantity`,`sub_total_price`,`promotion_id`,`bundle_id`,`returned_quantity`) VALUES (?,?,?,?,?,?,?,?)")).
+			WithArgs(7, 1, "49.99", 1, "49.99", 0, 0, 0).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		// promotion unlocked by the coupon is kept with the order
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `order_discount` (`order_id`,`product_id`,`source`,`source_id`,`amount`) VALUES (?,?,?,?,?)")).
+			WithArgs(7, 0, entity.SourceCartPromotion, 5, "5.00").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `stock_movement` (`product_id`,`quantity`,`reason`,`reference`,`created_at`) VALUES (?,?,?,?,?)")).
+			WithArgs(1, -1, entity.StockSale, "order:7", AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+	}
+	couponColumns := []string{"id", "code", "cart_promotion_id", "max_redemptions", "per_customer_limit", "redeemed_count", "expires_at", "updated_at", "deleted_at"}
+
+	t.Run("positive, coupon redeemed", func(t *testing.T) {
+		expectOrder()
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `coupon` WHERE id in (?) AND `coupon`.`deleted_at` IS NULL FOR UPDATE")).
+			WithArgs(2).
+			WillReturnRows(sqlmock.NewRows(couponColumns).AddRow(2, "SAVE5", 5, 10, 1, 3, nil, dayCreated, nil))
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `coupon_redemption` WHERE coupon_id = ? AND customer_id = ?")).
+			WithArgs(2, "cust-1").
+			WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).AddRow(0))
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE `coupon` SET `redeemed_count`=? WHERE `coupon`.`deleted_at` IS NULL AND `id` = ?")).
+			WithArgs(4, 2).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `coupon_redemption` (`coupon_id`,`order_id`,`customer_id`,`created_at`) VALUES (?,?,?,?)")).
+			WithArgs(2, 7, "cust-1", AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		expectOrderPlacedOutbox(mock, 7, sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		err := repo.SubmitCheckout(context.Background(), newPayload())
+		assert.Nil(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("negative, redemption limit reached by concurrent checkout", func(t *testing.T) {
+		expectOrder()
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `coupon` WHERE id in (?) AND `coupon`.`deleted_at` IS NULL FOR UPDATE")).
+			WithArgs(2).
+			WillReturnRows(sqlmock.NewRows(couponColumns).AddRow(2, "SAVE5", 5, 10, 1, 10, nil, dayCreated, nil))
+		mock.ExpectRollback()
+
+		err := repo.SubmitCheckout(context.Background(), newPayload())
+		assert.Equal(t, entity.NewError(entity.CouponExhausted+": SAVE5", http.StatusBadRequest), err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("negative, customer already redeemed", func(t *testing.T) {
+		expectOrder()
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `coupon` WHERE id in (?) AND `coupon`.`deleted_at` IS NULL FOR UPDATE")).
+			WithArgs(2).
+			WillReturnRows(sqlmock.NewRows(couponColumns).AddRow(2, "SAVE5", 5, 10, 1, 3, nil, dayCreated, nil))
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `coupon_redemption` WHERE coupon_id = ? AND customer_id = ?")).
+			WithArgs(2, "cust-1").
+			WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).AddRow(1))
+		mock.ExpectRollback()
+
+		err := repo.SubmitCheckout(context.Background(), newPayload())
+		assert.Equal(t, entity.NewError(entity.CouponCustomerUsed+": SAVE5", http.StatusBadRequest), err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
 }
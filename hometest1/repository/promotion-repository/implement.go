@@ -1,6 +1,11 @@
 package promotionrepository
 
 import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
 	"hometest1/core/entity"
 	"hometest1/core/repository"
 
@@ -8,23 +13,36 @@ import (
 )
 
 type repo struct {
-	db *gorm.DB
+	db    *gorm.DB
+	clock entity.Clock
 }
 
 func New(db *gorm.DB) repository.PromotionRepo {
-	return &repo{db}
+	return NewWithClock(db, time.Now)
+}
+
+// create promotion repository with custom clock to filter promotion period
+func NewWithClock(db *gorm.DB, clock entity.Clock) repository.PromotionRepo {
+	return &repo{db, clock}
 }
 
-func (r *repo) GetPromotionByProducts(products []*entity.Product) (map[int64][]*entity.Promotion, error) {
+func (r *repo) GetPromotionByProducts(ctx context.Context, products []*entity.Product) (map[int64][]*entity.Promotion, error) {
 	// pluck product id
 	var ids []int64
 	for _, p := range products {
 		ids = append(ids, p.ID)
 	}
 
-	// get promotions by product id
+	// get active promotions by product id
 	var promotions []*entity.Promotion
-	err := r.db.Where("product_id in (?)", ids).Order("product_id asc, type asc").Find(&promotions).Error
+	now := r.clock()
+	err := r.db.WithContext(ctx).
+		Where("product_id in (?)", ids).
+		Where("starts_at IS NULL OR starts_at <= ?", now).
+		Where("ends_at IS NULL OR ends_at > ?", now).
+		Order("product_id asc, priority desc, type asc, id asc").
+		Find(&promotions).
+		Error
 	if err != nil {
 		return nil, err
 	}
@@ -37,3 +55,355 @@ func (r *repo) GetPromotionByProducts(products []*entity.Product) (map[int64][]*
 
 	return result, nil
 }
+
+func (r *repo) GetPromotionByIDsWithDeleted(ctx context.Context, ids []int64) ([]*entity.Promotion, error) {
+	var result []*entity.Promotion
+	err := r.db.WithContext(ctx).Unscoped().
+		Where("id in (?)", ids).
+		Order("product_id asc, priority desc, type asc, id asc").
+		Find(&result).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (r *repo) GetUpcomingPromotionsByProduct(ctx context.Context, productID int64) ([]*entity.Promotion, error) {
+	var result []*entity.Promotion
+	err := r.db.WithContext(ctx).
+		Where("product_id = ?", productID).
+		Where("ends_at IS NULL OR ends_at > ?", r.clock()).
+		Find(&result).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (r *repo) GetPromotion(ctx context.Context, id int64) (*entity.Promotion, error) {
+	var result entity.Promotion
+	err := r.db.WithContext(ctx).Where("id = ?", id).Limit(1).Find(&result).Error
+	if err != nil {
+		return nil, err
+	}
+	if result.ID == 0 {
+		return nil, nil
+	}
+	return &result, nil
+}
+
+func (r *repo) GetPromotions(ctx context.Context, limit, offset int) ([]*entity.Promotion, int64, error) {
+	var total int64
+	err := r.db.WithContext(ctx).Model(&entity.Promotion{}).Count(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var result []*entity.Promotion
+	err = r.db.WithContext(ctx).Order("id asc").Limit(limit).Offset(offset).Find(&result).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return result, total, nil
+}
+
+func (r *repo) GetFreeItemPromotions(ctx context.Context) ([]*entity.Promotion, error) {
+	var result []*entity.Promotion
+	err := r.db.WithContext(ctx).
+		Where("promo_product_id <> 0").
+		Where("ends_at IS NULL OR ends_at > ?", r.clock()).
+		Find(&result).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (r *repo) CreatePromotion(ctx context.Context, promo *entity.Promotion) error {
+	return r.db.WithContext(ctx).Create(promo).Error
+}
+
+func (r *repo) UpdatePromotion(ctx context.Context, promo *entity.Promotion) error {
+	return r.db.WithContext(ctx).Model(promo).
+		Select("type", "product_id", "match_quantity", "promo_value", "promo_product_id", "priority", "stacking", "starts_at", "ends_at", "updated_at").
+		Updates(promo).Error
+}
+
+func (r *repo) DeletePromotion(ctx context.Context, id int64) error {
+	return r.db.WithContext(ctx).Delete(&entity.Promotion{}, id).Error
+}
+
+func (r *repo) GetActiveCartPromotions(ctx context.Context, couponPromotionIDs []int64) ([]*entity.CartPromotion, error) {
+	var result []*entity.CartPromotion
+	now := r.clock()
+	query := r.db.WithContext(ctx).
+		Where("starts_at IS NULL OR starts_at <= ?", now).
+		Where("ends_at IS NULL OR ends_at > ?", now)
+
+	// promotion linked to a coupon, including deleted coupon, needs the coupon code
+	couponPromotions := r.db.WithContext(ctx).Unscoped().Model(&entity.Coupon{}).Select("cart_promotion_id")
+	if len(couponPromotionIDs) > 0 {
+		query = query.Where("id NOT IN (?) OR id IN (?)", couponPromotions, couponPromotionIDs)
+	} else {
+		query = query.Where("id NOT IN (?)", couponPromotions)
+	}
+
+	err := query.Order("priority desc, id asc").Find(&result).Error
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (r *repo) GetCartPromotionByIDsWithDeleted(ctx context.Context, ids []int64) ([]*entity.CartPromotion, error) {
+	var result []*entity.CartPromotion
+	err := r.db.WithContext(ctx).Unscoped().Where("id in (?)", ids).Order("priority desc, id asc").Find(&result).Error
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (r *repo) GetCartPromotion(ctx context.Context, id int64) (*entity.CartPromotion, error) {
+	var result entity.CartPromotion
+	err := r.db.WithContext(ctx).Where("id = ?", id).Limit(1).Find(&result).Error
+	if err != nil {
+		return nil, err
+	}
+	if result.ID == 0 {
+		return nil, nil
+	}
+	return &result, nil
+}
+
+func (r *repo) GetCartPromotions(ctx context.Context, limit, offset int) ([]*entity.CartPromotion, int64, error) {
+	var total int64
+	err := r.db.WithContext(ctx).Model(&entity.CartPromotion{}).Count(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var result []*entity.CartPromotion
+	err = r.db.WithContext(ctx).Order("id asc").Limit(limit).Offset(offset).Find(&result).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return result, total, nil
+}
+
+func (r *repo) CreateCartPromotion(ctx context.Context, promo *entity.CartPromotion) error {
+	return r.db.WithContext(ctx).Create(promo).Error
+}
+
+func (r *repo) UpdateCartPromotion(ctx context.Context, promo *entity.CartPromotion) error {
+	return r.db.WithContext(ctx).Model(promo).
+		Select("type", "min_spend", "promo_value", "promo_amount", "promo_product_id", "priority", "stacking", "starts_at", "ends_at", "updated_at").
+		Updates(promo).Error
+}
+
+func (r *repo) DeleteCartPromotion(ctx context.Context, id int64) error {
+	return r.db.WithContext(ctx).Delete(&entity.CartPromotion{}, id).Error
+}
+
+func (r *repo) GetCouponsByCodes(ctx context.Context, codes []string) ([]*entity.Coupon, error) {
+	var result []*entity.Coupon
+	err := r.db.WithContext(ctx).Where("code in (?)", codes).Find(&result).Error
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (r *repo) GetCoupon(ctx context.Context, id int64) (*entity.Coupon, error) {
+	var result entity.Coupon
+	err := r.db.WithContext(ctx).Where("id = ?", id).Limit(1).Find(&result).Error
+	if err != nil {
+		return nil, err
+	}
+	if result.ID == 0 {
+		return nil, nil
+	}
+	return &result, nil
+}
+
+func (r *repo) GetCoupons(ctx context.Context, limit, offset int) ([]*entity.Coupon, int64, error) {
+	var total int64
+	err := r.db.WithContext(ctx).Model(&entity.Coupon{}).Count(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var result []*entity.Coupon
+	err = r.db.WithContext(ctx).Order("id asc").Limit(limit).Offset(offset).Find(&result).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return result, total, nil
+}
+
+func (r *repo) CreateCoupon(ctx context.Context, coupon *entity.Coupon) error {
+	err := r.db.WithContext(ctx).Create(coupon).Error
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return entity.NewError(entity.CouponCodeExists, http.StatusConflict)
+	}
+	if err != nil {
+		return entity.NewInternalError(err)
+	}
+	return nil
+}
+
+func (r *repo) UpdateCoupon(ctx context.Context, coupon *entity.Coupon) error {
+	err := r.db.WithContext(ctx).Model(coupon).
+		Select("code", "cart_promotion_id", "max_redemptions", "per_customer_limit", "expires_at", "updated_at").
+		Updates(coupon).Error
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return entity.NewError(entity.CouponCodeExists, http.StatusConflict)
+	}
+	if err != nil {
+		return entity.NewInternalError(err)
+	}
+	return nil
+}
+
+func (r *repo) DeleteCoupon(ctx context.Context, id int64) error {
+	return r.db.WithContext(ctx).Delete(&entity.Coupon{}, id).Error
+}
+
+func (r *repo) GetActiveBundlesByProducts(ctx context.Context, productIDs []int64) ([]*entity.Bundle, error) {
+	var result []*entity.Bundle
+	now := r.clock()
+	err := r.db.WithContext(ctx).
+		Where("id IN (?)", r.db.WithContext(ctx).Model(&entity.BundleItem{}).Select("bundle_id").Where("product_id in (?)", productIDs)).
+		Where("starts_at IS NULL OR starts_at <= ?", now).
+		Where("ends_at IS NULL OR ends_at > ?", now).
+		Order("priority desc, id asc").
+		Find(&result).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	err = r.loadBundleItems(ctx, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (r *repo) GetBundleByIDsWithDeleted(ctx context.Context, ids []int64) ([]*entity.Bundle, error) {
+	var result []*entity.Bundle
+	err := r.db.WithContext(ctx).Unscoped().Where("id in (?)", ids).Order("priority desc, id asc").Find(&result).Error
+	if err != nil {
+		return nil, err
+	}
+	err = r.loadBundleItems(ctx, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (r *repo) GetBundle(ctx context.Context, id int64) (*entity.Bundle, error) {
+	var result entity.Bundle
+	err := r.db.WithContext(ctx).Where("id = ?", id).Limit(1).Find(&result).Error
+	if err != nil {
+		return nil, err
+	}
+	if result.ID == 0 {
+		return nil, nil
+	}
+	err = r.loadBundleItems(ctx, []*entity.Bundle{&result})
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (r *repo) GetBundles(ctx context.Context, limit, offset int) ([]*entity.Bundle, int64, error) {
+	var total int64
+	err := r.db.WithContext(ctx).Model(&entity.Bundle{}).Count(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var result []*entity.Bundle
+	err = r.db.WithContext(ctx).Order("id asc").Limit(limit).Offset(offset).Find(&result).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	err = r.loadBundleItems(ctx, result)
+	if err != nil {
+		return nil, 0, err
+	}
+	return result, total, nil
+}
+
+func (r *repo) CreateBundle(ctx context.Context, bundle *entity.Bundle) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Create(bundle).Error
+		if err != nil {
+			return err
+		}
+		return r.createBundleItems(bundle, tx)
+	})
+}
+
+func (r *repo) UpdateBundle(ctx context.Context, bundle *entity.Bundle) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(bundle).
+			Select("type", "name", "price", "match_quantity", "promo_value", "priority", "starts_at", "ends_at", "updated_at").
+			Updates(bundle).Error
+		if err != nil {
+			return err
+		}
+
+		// replace bundle items
+		err = tx.Where("bundle_id = ?", bundle.ID).Delete(&entity.BundleItem{}).Error
+		if err != nil {
+			return err
+		}
+		return r.createBundleItems(bundle, tx)
+	})
+}
+
+func (r *repo) DeleteBundle(ctx context.Context, id int64) error {
+	return r.db.WithContext(ctx).Delete(&entity.Bundle{}, id).Error
+}
+
+// get items of the bundles, sorted by product id
+func (r *repo) loadBundleItems(ctx context.Context, bundles []*entity.Bundle) error {
+	if len(bundles) == 0 {
+		return nil
+	}
+
+	mapBundle := make(map[int64]*entity.Bundle)
+	var ids []int64
+	for _, bundle := range bundles {
+		mapBundle[bundle.ID] = bundle
+		ids = append(ids, bundle.ID)
+	}
+
+	var items []*entity.BundleItem
+	err := r.db.WithContext(ctx).Where("bundle_id in (?)", ids).Order("bundle_id asc, product_id asc").Find(&items).Error
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		bundle := mapBundle[item.BundleID]
+		bundle.Items = append(bundle.Items, item)
+	}
+	return nil
+}
+
+func (r *repo) createBundleItems(bundle *entity.Bundle, tx *gorm.DB) error {
+	for _, item := range bundle.Items {
+		item.ID = 0
+		item.BundleID = bundle.ID
+	}
+	if len(bundle.Items) == 0 {
+		return nil
+	}
+	return tx.Create(&bundle.Items).Error
+}
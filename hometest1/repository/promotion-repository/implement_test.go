@@ -1,8 +1,10 @@
 package promotionrepository_test
 
 import (
+	"context"
 	"database/sql"
 	"database/sql/driver"
+	"net/http"
 	"regexp"
 	"testing"
 	"time"
@@ -27,6 +29,9 @@ func (a AnyTime) Match(v driver.Value) bool {
 	return ok
 }
 
+// current time of the repository clock
+var now = time.Date(2023, 5, 16, 12, 0, 0, 0, time.UTC)
+
 func initRepo(db *sql.DB, mock sqlmock.Sqlmock) (repository.PromotionRepo, error) {
 	mock.ExpectQuery(regexp.QuoteMeta("SELECT VERSION()")).
 		WillReturnRows(sqlmock.NewRows([]string{"VERSION()"}).AddRow("5.7.25-log"))
@@ -41,7 +46,7 @@ func initRepo(db *sql.DB, mock sqlmock.Sqlmock) (repository.PromotionRepo, error
 	if err != nil {
 		return nil, err
 	}
-	return promotionrepository.New(gdb), nil
+	return promotionrepository.NewWithClock(gdb, func() time.Time { return now }), nil
 }
 
 func Test_GetPromotionByProducts(t *testing.T) {
@@ -61,24 +66,371 @@ func Test_GetPromotionByProducts(t *testing.T) {
 	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
 
 	t.Run("positive", func(t *testing.T) {
+		endsAt := now.Add(time.Hour)
 		rows := sqlmock.
-			NewRows([]string{"id", "type", "product_id", "match_quantity", "promo_value", "promo_product_id", "updated_at", "deleted_at"}).
-			AddRow(1, 1, 2, 1, 1, 4, dayCreated, nil).
-			AddRow(3, 3, 3, 3, 10, 0, dayCreated, nil)
+			NewRows([]string{"id", "type", "product_id", "match_quantity", "promo_value", "promo_product_id", "starts_at", "ends_at", "updated_at", "deleted_at"}).
+			AddRow(1, 1, 2, 1, 1, 4, nil, nil, dayCreated, nil).
+			AddRow(3, 3, 3, 3, 10, 0, dayCreated, endsAt, dayCreated, nil)
 
 		mock.
-			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `promotion` WHERE product_id in (?,?) AND `promotion`.`deleted_at` IS NULL ORDER BY product_id asc, type asc")).
-			WithArgs(int64(2), int64(3)).
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `promotion` WHERE product_id in (?,?) AND (starts_at IS NULL OR starts_at <= ?) AND (ends_at IS NULL OR ends_at > ?) AND `promotion`.`deleted_at` IS NULL ORDER BY product_id asc, priority desc, type asc, id asc")).
+			WithArgs(int64(2), int64(3), now, now).
 			WillReturnRows(rows)
 
-		resp, err := repo.GetPromotionByProducts([]*entity.Product{
-			{ID: 2, Serial: "43N23P", Name: "MacBook Pro", Price: 5399.99, UpdatedAt: dayCreated},
-			{ID: 3, Serial: "A304SD", Name: "Alexa Speaker", Price: 49.99, UpdatedAt: dayCreated},
+		resp, err := repo.GetPromotionByProducts(context.Background(), []*entity.Product{
+			{ID: 2, Serial: "43N23P", Name: "MacBook Pro", Price: entity.NewMoney(539999), UpdatedAt: dayCreated},
+			{ID: 3, Serial: "A304SD", Name: "Alexa Speaker", Price: entity.NewMoney(4999), UpdatedAt: dayCreated},
 		})
 		assert.Nil(t, err)
 		assert.Equal(t, map[int64][]*entity.Promotion{
 			2: {{ID: 1, Type: 1, ProductID: 2, MatchQuantity: 1, PromoValue: 1, PromoProductID: 4, UpdatedAt: dayCreated}},
-			3: {{ID: 3, Type: 3, ProductID: 3, MatchQuantity: 3, PromoValue: 10, PromoProductID: 0, UpdatedAt: dayCreated}},
+			3: {{ID: 3, Type: 3, ProductID: 3, MatchQuantity: 3, PromoValue: 10, PromoProductID: 0, StartsAt: &dayCreated, EndsAt: &endsAt, UpdatedAt: dayCreated}},
+		}, resp)
+	})
+}
+
+func Test_GetPromotionByIDsWithDeleted(t *testing.T) {
+	// mock db
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	defer db.Close()
+
+	// init repo
+	repo, err := initRepo(db, mock)
+	if err != nil {
+		t.Errorf("error initRepo: %s", err.Error())
+		return
+	}
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+
+	t.Run("positive, deleted and expired promotions", func(t *testing.T) {
+		rows := sqlmock.
+			NewRows([]string{"id", "type", "product_id", "match_quantity", "promo_value", "promo_product_id", "starts_at", "ends_at", "updated_at", "deleted_at"}).
+			AddRow(1, 1, 2, 1, 1, 4, nil, dayCreated, dayCreated, dayCreated)
+
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `promotion` WHERE id in (?) ORDER BY product_id asc, priority desc, type asc, id asc")).
+			WithArgs(int64(1)).
+			WillReturnRows(rows)
+
+		resp, err := repo.GetPromotionByIDsWithDeleted(context.Background(), []int64{1})
+		assert.Nil(t, err)
+		assert.Equal(t, []*entity.Promotion{
+			{ID: 1, Type: 1, ProductID: 2, MatchQuantity: 1, PromoValue: 1, PromoProductID: 4, EndsAt: &dayCreated, UpdatedAt: dayCreated, DeletedAt: gorm.DeletedAt{Time: dayCreated, Valid: true}},
+		}, resp)
+	})
+}
+
+func Test_GetFreeItemPromotions(t *testing.T) {
+	// mock db
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	defer db.Close()
+
+	// init repo
+	repo, err := initRepo(db, mock)
+	if err != nil {
+		t.Errorf("error initRepo: %s", err.Error())
+		return
+	}
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+
+	t.Run("positive", func(t *testing.T) {
+		rows := sqlmock.
+			NewRows([]string{"id", "type", "product_id", "match_quantity", "promo_value", "promo_product_id", "updated_at", "deleted_at"}).
+			AddRow(1, 1, 2, 1, 1, 4, dayCreated, nil)
+
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `promotion` WHERE promo_product_id <> 0 AND (ends_at IS NULL OR ends_at > ?) AND `promotion`.`deleted_at` IS NULL")).
+			WithArgs(now).
+			WillReturnRows(rows)
+
+		resp, err := repo.GetFreeItemPromotions(context.Background())
+		assert.Nil(t, err)
+		assert.Equal(t, []*entity.Promotion{
+			{ID: 1, Type: 1, ProductID: 2, MatchQuantity: 1, PromoValue: 1, PromoProductID: 4, UpdatedAt: dayCreated},
+		}, resp)
+	})
+}
+
+func Test_GetUpcomingPromotionsByProduct(t *testing.T) {
+	// mock db
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	defer db.Close()
+
+	// init repo
+	repo, err := initRepo(db, mock)
+	if err != nil {
+		t.Errorf("error initRepo: %s", err.Error())
+		return
+	}
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+
+	t.Run("positive, include scheduled promotion", func(t *testing.T) {
+		startsAt := now.Add(24 * time.Hour)
+		rows := sqlmock.
+			NewRows([]string{"id", "type", "product_id", "match_quantity", "promo_value", "promo_product_id", "starts_at", "ends_at", "updated_at", "deleted_at"}).
+			AddRow(5, 3, 4, 1, 20, 0, startsAt, nil, dayCreated, nil)
+
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `promotion` WHERE product_id = ? AND (ends_at IS NULL OR ends_at > ?) AND `promotion`.`deleted_at` IS NULL")).
+			WithArgs(int64(4), now).
+			WillReturnRows(rows)
+
+		resp, err := repo.GetUpcomingPromotionsByProduct(context.Background(), 4)
+		assert.Nil(t, err)
+		assert.Equal(t, []*entity.Promotion{
+			{ID: 5, Type: 3, ProductID: 4, MatchQuantity: 1, PromoValue: 20, StartsAt: &startsAt, UpdatedAt: dayCreated},
+		}, resp)
+	})
+}
+
+func Test_UpdatePromotion(t *testing.T) {
+	// mock db
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	defer db.Close()
+
+	// init repo
+	repo, err := initRepo(db, mock)
+	if err != nil {
+		t.Errorf("error initRepo: %s", err.Error())
+		return
+	}
+
+	t.Run("positive", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE `promotion` SET `type`=?,`product_id`=?,`match_quantity`=?,`promo_value`=?,`promo_product_id`=?,`priority`=?,`stacking`=?,`starts_at`=?,`ends_at`=?,`updated_at`=? WHERE `promotion`.`deleted_at` IS NULL AND `id` = ?")).
+			WithArgs(3, 3, 3, 15, 0, 1, entity.Exclusive, nil, nil, AnyTime{}, 3).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := repo.UpdatePromotion(context.Background(), &entity.Promotion{ID: 3, Type: 3, ProductID: 3, MatchQuantity: 3, PromoValue: 15, Priority: 1, Stacking: entity.Exclusive})
+		assert.Nil(t, err)
+	})
+}
+
+func Test_DeletePromotion(t *testing.T) {
+	// mock db
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	defer db.Close()
+
+	// init repo
+	repo, err := initRepo(db, mock)
+	if err != nil {
+		t.Errorf("error initRepo: %s", err.Error())
+		return
+	}
+
+	t.Run("positive, soft delete", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE `promotion` SET `deleted_at`=? WHERE `promotion`.`id` = ? AND `promotion`.`deleted_at` IS NULL")).
+			WithArgs(AnyTime{}, 3).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := repo.DeletePromotion(context.Background(), 3)
+		assert.Nil(t, err)
+	})
+}
+
+func Test_GetActiveCartPromotions(t *testing.T) {
+	// mock db
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	defer db.Close()
+
+	// init repo
+	repo, err := initRepo(db, mock)
+	if err != nil {
+		t.Errorf("error initRepo: %s", err.Error())
+		return
+	}
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+
+	t.Run("positive", func(t *testing.T) {
+		rows := sqlmock.
+			NewRows([]string{"id", "type", "min_spend", "promo_value", "promo_amount", "promo_product_id", "priority", "stacking", "starts_at", "ends_at", "updated_at", "deleted_at"}).
+			AddRow(2, 3, "1000.00", 1, "0.00", 4, 1, 0, nil, nil, dayCreated, nil).
+			AddRow(1, 1, "500.00", 5, "0.00", 0, 0, 0, nil, nil, dayCreated, nil)
+
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `cart_promotion` WHERE (starts_at IS NULL OR starts_at <= ?) AND (ends_at IS NULL OR ends_at > ?) AND id NOT IN (SELECT `cart_promotion_id` FROM `coupon`) AND `cart_promotion`.`deleted_at` IS NULL ORDER BY priority desc, id asc")).
+			WithArgs(now, now).
+			WillReturnRows(rows)
+
+		resp, err := repo.GetActiveCartPromotions(context.Background(), nil)
+		assert.Nil(t, err)
+		assert.Equal(t, []*entity.CartPromotion{
+			{ID: 2, Type: entity.CartFreeItem, MinSpend: entity.NewMoney(100000), PromoValue: 1, PromoAmount: entity.NewMoney(0), PromoProductID: 4, Priority: 1, UpdatedAt: dayCreated},
+			{ID: 1, Type: entity.CartDiscountInPercent, MinSpend: entity.NewMoney(50000), PromoValue: 5, PromoAmount: entity.NewMoney(0), UpdatedAt: dayCreated},
+		}, resp)
+	})
+
+	t.Run("positive, unlocked by coupon", func(t *testing.T) {
+		rows := sqlmock.
+			NewRows([]string{"id", "type", "min_spend", "promo_value", "promo_amount", "promo_product_id", "priority", "stacking", "starts_at", "ends_at", "updated_at", "deleted_at"}).
+			AddRow(3, 2, "0.00", 0, "15.00", 0, 0, 0, nil, nil, dayCreated, nil)
+
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `cart_promotion` WHERE (starts_at IS NULL OR starts_at <= ?) AND (ends_at IS NULL OR ends_at > ?) AND (id NOT IN (SELECT `cart_promotion_id` FROM `coupon`) OR id IN (?)) AND `cart_promotion`.`deleted_at` IS NULL ORDER BY priority desc, id asc")).
+			WithArgs(now, now, 3).
+			WillReturnRows(rows)
+
+		resp, err := repo.GetActiveCartPromotions(context.Background(), []int64{3})
+		assert.Nil(t, err)
+		assert.Equal(t, []*entity.CartPromotion{
+			{ID: 3, Type: entity.CartDiscountAmount, MinSpend: entity.NewMoney(0), PromoAmount: entity.NewMoney(1500), UpdatedAt: dayCreated},
+		}, resp)
+	})
+}
+
+func Test_UpdateCartPromotion(t *testing.T) {
+	// mock db
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	defer db.Close()
+
+	// init repo
+	repo, err := initRepo(db, mock)
+	if err != nil {
+		t.Errorf("error initRepo: %s", err.Error())
+		return
+	}
+
+	t.Run("positive", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE `cart_promotion` SET `type`=?,`min_spend`=?,`promo_value`=?,`promo_amount`=?,`promo_product_id`=?,`priority`=?,`stacking`=?,`starts_at`=?,`ends_at`=?,`updated_at`=? WHERE `cart_promotion`.`deleted_at` IS NULL AND `id` = ?")).
+			WithArgs(2, "0.00", 0, "20.00", 0, 0, entity.Stackable, nil, nil, AnyTime{}, 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := repo.UpdateCartPromotion(context.Background(), &entity.CartPromotion{ID: 1, Type: entity.CartDiscountAmount, MinSpend: entity.NewMoney(0), PromoAmount: entity.NewMoney(2000)})
+		assert.Nil(t, err)
+	})
+}
+
+func Test_CreateCoupon(t *testing.T) {
+	// mock db
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	defer db.Close()
+
+	// init repo
+	repo, err := initRepo(db, mock)
+	if err != nil {
+		t.Errorf("error initRepo: %s", err.Error())
+		return
+	}
+
+	t.Run("negative, code exists", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `coupon` (`code`,`cart_promotion_id`,`max_redemptions`,`per_customer_limit`,`redeemed_count`,`expires_at`,`updated_at`,`deleted_at`) VALUES (?,?,?,?,?,?,?,?)")).
+			WithArgs("SAVE10", 3, 100, 1, 0, nil, AnyTime{}, nil).
+			WillReturnError(gorm.ErrDuplicatedKey)
+		mock.ExpectRollback()
+
+		err := repo.CreateCoupon(context.Background(), &entity.Coupon{Code: "SAVE10", CartPromotionID: 3, MaxRedemptions: 100, PerCustomerLimit: 1})
+		assert.Equal(t, entity.NewError(entity.CouponCodeExists, http.StatusConflict), err)
+	})
+}
+
+func Test_GetActiveBundlesByProducts(t *testing.T) {
+	// mock db
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	defer db.Close()
+
+	// init repo
+	repo, err := initRepo(db, mock)
+	if err != nil {
+		t.Errorf("error initRepo: %s", err.Error())
+		return
+	}
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+
+	t.Run("positive, with items", func(t *testing.T) {
+		rows := sqlmock.
+			NewRows([]string{"id", "type", "name", "price", "match_quantity", "promo_value", "priority", "starts_at", "ends_at", "updated_at", "deleted_at"}).
+			AddRow(1, 1, "MacBook Pro + Alexa Speaker", "5450.00", 0, 0, 0, nil, nil, dayCreated, nil)
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `bundle` WHERE id IN (SELECT `bundle_id` FROM `bundle_item` WHERE product_id in (?,?)) AND (starts_at IS NULL OR starts_at <= ?) AND (ends_at IS NULL OR ends_at > ?) AND `bundle`.`deleted_at` IS NULL ORDER BY priority desc, id asc")).
+			WithArgs(2, 3, now, now).
+			WillReturnRows(rows)
+
+		itemRows := sqlmock.
+			NewRows([]string{"id", "bundle_id", "product_id", "quantity"}).
+			AddRow(1, 1, 2, 1).
+			AddRow(2, 1, 3, 1)
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT * FROM `bundle_item` WHERE bundle_id in (?) ORDER BY bundle_id asc, product_id asc")).
+			WithArgs(1).
+			WillReturnRows(itemRows)
+
+		resp, err := repo.GetActiveBundlesByProducts(context.Background(), []int64{2, 3})
+		assert.Nil(t, err)
+		assert.Equal(t, []*entity.Bundle{
+			{ID: 1, Type: entity.BundleFixedPrice, Name: "MacBook Pro + Alexa Speaker", Price: entity.NewMoney(545000), UpdatedAt: dayCreated, Items: []*entity.BundleItem{
+				{ID: 1, BundleID: 1, ProductID: 2, Quantity: 1},
+				{ID: 2, BundleID: 1, ProductID: 3, Quantity: 1},
+			}},
 		}, resp)
 	})
 }
+
+func Test_UpdateBundle(t *testing.T) {
+	// mock db
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	defer db.Close()
+
+	// init repo
+	repo, err := initRepo(db, mock)
+	if err != nil {
+		t.Errorf("error initRepo: %s", err.Error())
+		return
+	}
+
+	t.Run("positive, replace items", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE `bundle` SET `type`=?,`name`=?,`price`=?,`match_quantity`=?,`promo_value`=?,`priority`=?,`starts_at`=?,`ends_at`=?,`updated_at`=? WHERE `bundle`.`deleted_at` IS NULL AND `id` = ?")).
+			WithArgs(2, "Any 3 smart home", "0.00", 3, 2, 0, nil, nil, AnyTime{}, 2).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `bundle_item` WHERE bundle_id = ?")).
+			WithArgs(2).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `bundle_item` (`bundle_id`,`product_id`,`quantity`) VALUES (?,?,?),(?,?,?)")).
+			WithArgs(2, 1, 0, 2, 4, 0).
+			WillReturnResult(sqlmock.NewResult(3, 2))
+		mock.ExpectCommit()
+
+		err := repo.UpdateBundle(context.Background(), &entity.Bundle{ID: 2, Type: entity.BundleGroupPrice, Name: "Any 3 smart home", Price: entity.NewMoney(0), MatchQuantity: 3, PromoValue: 2, Items: []*entity.BundleItem{
+			{ProductID: 1},
+			{ProductID: 4},
+		}})
+		assert.Nil(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,54 @@
+package reservationrepository
+
+import (
+	"context"
+	"time"
+
+	"hometest1/core/entity"
+	"hometest1/core/repository"
+
+	"gorm.io/gorm"
+)
+
+type repo struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) repository.ReservationRepo {
+	return &repo{db}
+}
+
+func (r *repo) GetReservation(ctx context.Context, id int64) (*entity.Reservation, error) {
+	var result entity.Reservation
+	err := r.db.WithContext(ctx).Where("id = ?", id).Limit(1).Find(&result).Error
+	if err != nil {
+		return nil, err
+	}
+	if result.ID == 0 {
+		return nil, nil
+	}
+
+	err = r.db.WithContext(ctx).Where("reservation_id = ?", id).Order("id asc").Find(&result.Items).Error
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (r *repo) ReleaseReservation(ctx context.Context, id int64) (bool, error) {
+	// conditional update, so reservation confirmed at the same time is not released
+	result := r.db.WithContext(ctx).Model(&entity.Reservation{}).
+		Where("id = ? AND status = ?", id, entity.ReservationActive).
+		Update("status", entity.ReservationReleased)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *repo) ExpireReservations(ctx context.Context, now time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&entity.Reservation{}).
+		Where("status = ? AND expires_at <= ?", entity.ReservationActive, now).
+		Update("status", entity.ReservationExpired)
+	return result.RowsAffected, result.Error
+}
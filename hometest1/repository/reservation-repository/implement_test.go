@@ -0,0 +1,184 @@
+package reservationrepository_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"regexp"
+	"testing"
+	"time"
+
+	"hometest1/core/entity"
+	"hometest1/core/repository"
+	reservationrepository "hometest1/repository/reservation-repository"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/schema"
+)
+
+type AnyTime struct{}
+
+// Match satisfies sqlmock.Argument interface
+func (a AnyTime) Match(v driver.Value) bool {
+	_, ok := v.(time.Time)
+	return ok
+}
+
+func initRepo(db *sql.DB, mock sqlmock.Sqlmock) (repository.ReservationRepo, error) {
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT VERSION()")).
+		WillReturnRows(sqlmock.NewRows([]string{"VERSION()"}).AddRow("5.7.25-log"))
+	gdb, err := gorm.Open(mysql.New(mysql.Config{
+		Conn: db,
+	}), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.LogLevel(logger.Info)),
+		NamingStrategy: schema.NamingStrategy{
+			SingularTable: true,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reservationrepository.New(gdb), nil
+}
+
+func Test_GetReservation(t *testing.T) {
+	// mock db
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	defer db.Close()
+
+	// init repo
+	repo, err := initRepo(db, mock)
+	if err != nil {
+		t.Errorf("error initRepo: %s", err.Error())
+		return
+	}
+	dayCreated, _ := time.Parse("2006-01-02", "2023-05-16")
+	expiresAt := dayCreated.Add(15 * time.Minute)
+	query := regexp.QuoteMeta("SELECT * FROM `reservation` WHERE id = ? LIMIT ?")
+	columns := []string{"id", "status", "customer_id", "order_id", "expires_at", "created_at", "updated_at"}
+
+	t.Run("positive", func(t *testing.T) {
+		mock.ExpectQuery(query).
+			WithArgs(5, 1).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(5, entity.ReservationActive, "cust-1", 0, expiresAt, dayCreated, dayCreated))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `reservation_item` WHERE reservation_id = ? ORDER BY id asc")).
+			WithArgs(5).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "reservation_id", "product_id", "quantity", "ordered_quantity"}).
+				AddRow(1, 5, 1, 1, 1).
+				AddRow(2, 5, 4, 1, 0))
+
+		resp, err := repo.GetReservation(context.Background(), 5)
+		assert.Nil(t, err)
+		assert.Equal(t, &entity.Reservation{
+			ID:         5,
+			Status:     entity.ReservationActive,
+			CustomerID: "cust-1",
+			ExpiresAt:  expiresAt,
+			CreatedAt:  dayCreated,
+			UpdatedAt:  dayCreated,
+			Items: []*entity.ReservationItem{
+				{ID: 1, ReservationID: 5, ProductID: 1, Quantity: 1, OrderedQuantity: 1},
+				{ID: 2, ReservationID: 5, ProductID: 4, Quantity: 1},
+			},
+		}, resp)
+	})
+
+	t.Run("positive, not found", func(t *testing.T) {
+		mock.ExpectQuery(query).
+			WithArgs(6, 1).
+			WillReturnRows(sqlmock.NewRows(columns))
+
+		resp, err := repo.GetReservation(context.Background(), 6)
+		assert.Nil(t, err)
+		assert.Nil(t, resp)
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func Test_ReleaseReservation(t *testing.T) {
+	// mock db
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	defer db.Close()
+
+	// init repo
+	repo, err := initRepo(db, mock)
+	if err != nil {
+		t.Errorf("error initRepo: %s", err.Error())
+		return
+	}
+	query := regexp.QuoteMeta("UPDATE `reservation` SET `status`=?,`updated_at`=? WHERE id = ? AND status = ?")
+
+	t.Run("positive", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(query).
+			WithArgs(entity.ReservationReleased, AnyTime{}, 5, entity.ReservationActive).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		released, err := repo.ReleaseReservation(context.Background(), 5)
+		assert.Nil(t, err)
+		assert.True(t, released)
+	})
+
+	t.Run("positive, reservation is no longer active", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(query).
+			WithArgs(entity.ReservationReleased, AnyTime{}, 5, entity.ReservationActive).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		released, err := repo.ReleaseReservation(context.Background(), 5)
+		assert.Nil(t, err)
+		assert.False(t, released)
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func Test_ExpireReservations(t *testing.T) {
+	// mock db
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	defer db.Close()
+
+	// init repo
+	repo, err := initRepo(db, mock)
+	if err != nil {
+		t.Errorf("error initRepo: %s", err.Error())
+		return
+	}
+	now, _ := time.Parse("2006-01-02 15:04:05", "2023-05-16 10:00:00")
+
+	t.Run("positive", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE `reservation` SET `status`=?,`updated_at`=? WHERE status = ? AND expires_at <= ?")).
+			WithArgs(entity.ReservationExpired, AnyTime{}, entity.ReservationActive, now).
+			WillReturnResult(sqlmock.NewResult(0, 3))
+		mock.ExpectCommit()
+
+		expired, err := repo.ExpireReservations(context.Background(), now)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(3), expired)
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,114 @@
+package taxrepository
+
+import (
+	"context"
+	"hometest1/core/entity"
+	"hometest1/core/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type repo struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) repository.TaxRepo {
+	return &repo{db}
+}
+
+func (r *repo) GetTaxRegion(ctx context.Context, code string) (*entity.TaxRegion, error) {
+	query := r.db.WithContext(ctx).Where("code = ?", code)
+	if code == "" {
+		query = r.db.WithContext(ctx).Where("is_default = ?", true)
+	}
+	var result entity.TaxRegion
+	err := query.Limit(1).Find(&result).Error
+	if err != nil {
+		return nil, err
+	}
+	if result.ID == 0 {
+		return nil, nil
+	}
+
+	err = r.db.WithContext(ctx).Where("tax_region_id = ?", result.ID).Order("tax_class asc").Find(&result.Rates).Error
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (r *repo) GetTaxRegions(ctx context.Context) ([]*entity.TaxRegion, error) {
+	var result []*entity.TaxRegion
+	err := r.db.WithContext(ctx).Order("code asc").Find(&result).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return result, nil
+	}
+
+	var regionIDs []int64
+	mapRegion := make(map[int64]*entity.TaxRegion)
+	for _, region := range result {
+		regionIDs = append(regionIDs, region.ID)
+		mapRegion[region.ID] = region
+	}
+	var rates []*entity.TaxRate
+	err = r.db.WithContext(ctx).Where("tax_region_id in (?)", regionIDs).Order("tax_class asc").Find(&rates).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, rate := range rates {
+		region := mapRegion[rate.TaxRegionID]
+		region.Rates = append(region.Rates, rate)
+	}
+	return result, nil
+}
+
+func (r *repo) SaveTaxRegion(ctx context.Context, region *entity.TaxRegion) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// lock existing region by code, so concurrent save updates the same row
+		var existing entity.TaxRegion
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("code = ?", region.Code).
+			Limit(1).
+			Find(&existing).
+			Error
+		if err != nil {
+			return err
+		}
+		if existing.ID == 0 {
+			err = tx.Create(region).Error
+		} else {
+			region.ID = existing.ID
+			err = tx.Model(region).Select("name", "price_mode", "is_default", "updated_at").Updates(region).Error
+		}
+		if err != nil {
+			return err
+		}
+
+		if region.IsDefault {
+			err = tx.Model(&entity.TaxRegion{}).
+				Where("id <> ? AND is_default = ?", region.ID, true).
+				UpdateColumn("is_default", false).
+				Error
+			if err != nil {
+				return err
+			}
+		}
+
+		// replace rates
+		err = tx.Where("tax_region_id = ?", region.ID).Delete(&entity.TaxRate{}).Error
+		if err != nil {
+			return err
+		}
+		for _, rate := range region.Rates {
+			rate.TaxRegionID = region.ID
+		}
+		if len(region.Rates) == 0 {
+			return nil
+		}
+		return tx.Create(&region.Rates).Error
+	})
+}
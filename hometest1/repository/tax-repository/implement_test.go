@@ -0,0 +1,188 @@
+package taxrepository_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"regexp"
+	"testing"
+	"time"
+
+	"hometest1/core/entity"
+	"hometest1/core/repository"
+	taxrepository "hometest1/repository/tax-repository"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/schema"
+)
+
+type AnyTime struct{}
+
+// Match satisfies sqlmock.Argument interface
+func (a AnyTime) Match(v driver.Value) bool {
+	_, ok := v.(time.Time)
+	return ok
+}
+
+func initRepo(db *sql.DB, mock sqlmock.Sqlmock) (repository.TaxRepo, error) {
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT VERSION()")).
+		WillReturnRows(sqlmock.NewRows([]string{"VERSION()"}).AddRow("5.7.25-log"))
+	gdb, err := gorm.Open(mysql.New(mysql.Config{
+		Conn: db,
+	}), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.LogLevel(logger.Info)),
+		NamingStrategy: schema.NamingStrategy{
+			SingularTable: true,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return taxrepository.New(gdb), nil
+}
+
+func Test_GetTaxRegion(t *testing.T) {
+	// mock db
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	defer db.Close()
+
+	// init repo
+	repo, err := initRepo(db, mock)
+	if err != nil {
+		t.Errorf("error initRepo: %s", err.Error())
+		return
+	}
+	dayUpdated, _ := time.Parse("2006-01-02", "2023-05-16")
+	columns := []string{"id", "code", "name", "price_mode", "is_default", "updated_at"}
+	rateQuery := regexp.QuoteMeta("SELECT * FROM `tax_rate` WHERE tax_region_id = ? ORDER BY tax_class asc")
+	rateColumns := []string{"id", "tax_region_id", "tax_class", "rate"}
+
+	t.Run("positive, by code", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `tax_region` WHERE code = ? LIMIT ?")).
+			WithArgs("DE", 1).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(2, "DE", "Germany", entity.TaxInclusive, false, dayUpdated))
+		mock.ExpectQuery(rateQuery).
+			WithArgs(2).
+			WillReturnRows(sqlmock.NewRows(rateColumns).
+				AddRow(4, 2, "reduced", 700).
+				AddRow(3, 2, "standard", 1900))
+
+		resp, err := repo.GetTaxRegion(context.Background(), "DE")
+		assert.Nil(t, err)
+		assert.Equal(t, &entity.TaxRegion{
+			ID:        2,
+			Code:      "DE",
+			Name:      "Germany",
+			PriceMode: entity.TaxInclusive,
+			UpdatedAt: dayUpdated,
+			Rates: []*entity.TaxRate{
+				{ID: 4, TaxRegionID: 2, TaxClass: "reduced", Rate: 700},
+				{ID: 3, TaxRegionID: 2, TaxClass: "standard", Rate: 1900},
+			},
+		}, resp)
+	})
+
+	t.Run("positive, default region", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `tax_region` WHERE is_default = ? LIMIT ?")).
+			WithArgs(true, 1).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(1, "US-CA", "California", entity.TaxExclusive, true, dayUpdated))
+		mock.ExpectQuery(rateQuery).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows(rateColumns).AddRow(1, 1, "standard", 725))
+
+		resp, err := repo.GetTaxRegion(context.Background(), "")
+		assert.Nil(t, err)
+		assert.Equal(t, "US-CA", resp.Code)
+		assert.Equal(t, 725, resp.RateOf("standard"))
+	})
+
+	t.Run("positive, not found", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `tax_region` WHERE code = ? LIMIT ?")).
+			WithArgs("XX", 1).
+			WillReturnRows(sqlmock.NewRows(columns))
+
+		resp, err := repo.GetTaxRegion(context.Background(), "XX")
+		assert.Nil(t, err)
+		assert.Nil(t, resp)
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func Test_SaveTaxRegion(t *testing.T) {
+	// mock db
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	defer db.Close()
+
+	// init repo
+	repo, err := initRepo(db, mock)
+	if err != nil {
+		t.Errorf("error initRepo: %s", err.Error())
+		return
+	}
+	selectQuery := regexp.QuoteMeta("SELECT * FROM `tax_region` WHERE code = ? LIMIT ? FOR UPDATE")
+	columns := []string{"id", "code", "name", "price_mode", "is_default", "updated_at"}
+	deleteRates := regexp.QuoteMeta("DELETE FROM `tax_rate` WHERE tax_region_id = ?")
+	insertRates := regexp.QuoteMeta("INSERT INTO `tax_rate` (`tax_region_id`,`tax_class`,`rate`) VALUES (?,?,?)")
+
+	t.Run("positive, create default region", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(selectQuery).
+			WithArgs("DE", 1).
+			WillReturnRows(sqlmock.NewRows(columns))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `tax_region` (`code`,`name`,`price_mode`,`is_default`,`updated_at`) VALUES (?,?,?,?,?)")).
+			WithArgs("DE", "Germany", entity.TaxInclusive, true, AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(2, 1))
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE `tax_region` SET `is_default`=? WHERE id <> ? AND is_default = ?")).
+			WithArgs(false, 2, true).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(deleteRates).
+			WithArgs(2).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(insertRates).
+			WithArgs(2, "standard", 1900).
+			WillReturnResult(sqlmock.NewResult(3, 1))
+		mock.ExpectCommit()
+
+		region := &entity.TaxRegion{Code: "DE", Name: "Germany", PriceMode: entity.TaxInclusive, IsDefault: true, Rates: []*entity.TaxRate{
+			{TaxClass: "standard", Rate: 1900},
+		}}
+		err := repo.SaveTaxRegion(context.Background(), region)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(2), region.ID)
+		assert.Equal(t, int64(2), region.Rates[0].TaxRegionID)
+	})
+
+	t.Run("positive, update region without rates", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(selectQuery).
+			WithArgs("DE", 1).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(2, "DE", "Germany", entity.TaxInclusive, true, time.Now()))
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE `tax_region` SET `name`=?,`price_mode`=?,`is_default`=?,`updated_at`=? WHERE `id` = ?")).
+			WithArgs("Deutschland", entity.TaxInclusive, false, AnyTime{}, 2).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(deleteRates).
+			WithArgs(2).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := repo.SaveTaxRegion(context.Background(), &entity.TaxRegion{Code: "DE", Name: "Deutschland", PriceMode: entity.TaxInclusive})
+		assert.Nil(t, err)
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -41,6 +41,14 @@ if [ "$DOCKER_NAME" == "" ]; then
 fi
 docker build -t "$DOCKER_NAME" .
 
+# apply pending migrations
+echo "Migrating database"
+docker run --rm -e MYSQL_HOST="$MYSQL_HOST" -e MYSQL_USERNAME="$MYSQL_USERNAME" -e MYSQL_DB_NAME="$MYSQL_DB_NAME" -e MYSQL_PASSWORD="$MYSQL_PASSWORD" $DOCKER_NAME migrate up
+if [ $? -ne 0 ]; then
+    echo "Error: Migration failed"
+    exit
+fi
+
 # get container name
 read -p "Enter your docker container name: " CONTAINER_NAME
 if [ "$CONTAINER_NAME" == "" ]; then 